@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/pkg"
+	"sical-go-backend/pkg/kbbundle"
+	"sical-go-backend/pkg/logger"
+)
+
+func main() {
+	// 解析命令行参数
+	var (
+		action   = flag.String("action", "", "操作类型: export, import")
+		path     = flag.String("path", "", "导出包文件路径")
+		category = flag.String("category", "", "仅导出指定类别（export）")
+		dryRun   = flag.Bool("dry-run", false, "仅校验，不写入数据（import）")
+		authorID = flag.Uint("author-id", 0, "导入操作者的用户ID（import）")
+	)
+	flag.Parse()
+
+	if *path == "" || (*action != "export" && *action != "import") || (*action == "import" && *authorID == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 加载配置
+	config, err := pkg.LoadConfig()
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	// 初始化全局日志器
+	if err := logger.Init(&logger.Config{Level: "info", Format: "json", Output: "stdout"}); err != nil {
+		log.Fatalf("初始化全局日志器失败: %v", err)
+	}
+
+	// 初始化数据库连接
+	db, err := gorm.Open(postgres.Open(config.GetDSN()), &gorm.Config{})
+	if err != nil {
+		logger.Fatal("数据库连接失败", logger.Err(err))
+	}
+
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	txManager := repositories.NewTransactionManager(db)
+	knowledgePointService := services.NewKnowledgePointService(
+		knowledgePointRepo,
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		txManager,
+	)
+	bundleService := services.NewKnowledgeBundleService(
+		knowledgePointRepo,
+		repositories.NewFlashcardRepository(db),
+		knowledgePointService,
+		txManager,
+	)
+
+	ctx := context.Background()
+	switch *action {
+	case "export":
+		runExport(ctx, bundleService, *path, *category, *authorID)
+	case "import":
+		runImport(ctx, bundleService, *path, *dryRun, *authorID)
+	}
+}
+
+// runExport 导出知识库到文件，命令行导出以管理员身份执行
+func runExport(ctx context.Context, bundleService *services.KnowledgeBundleService, path, category string, authorID uint) {
+	file, err := os.Create(path)
+	if err != nil {
+		logger.Fatal("创建导出文件失败", logger.Err(err))
+	}
+	actor := &services.KnowledgePointActor{UserID: authorID, Role: string(entities.RoleAdmin)}
+	if err := bundleService.Export(ctx, category, file, actor); err != nil {
+		file.Close()
+		logger.Fatal("导出知识库失败", logger.Err(err))
+	}
+	if err := file.Close(); err != nil {
+		logger.Fatal("写入导出文件失败", logger.Err(err))
+	}
+	fmt.Println("导出完成:", path)
+}
+
+// runImport 从文件导入知识库导出包，命令行导入以管理员身份执行
+func runImport(ctx context.Context, bundleService *services.KnowledgeBundleService, path string, dryRun bool, authorID uint) {
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Fatal("打开导出包失败", logger.Err(err))
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		logger.Fatal("读取导出包失败", logger.Err(err))
+	}
+	bundle, err := kbbundle.Read(file, info.Size())
+	if err != nil {
+		logger.Fatal("读取导出包失败", logger.Err(err))
+	}
+
+	actor := &services.KnowledgePointActor{UserID: authorID, Role: string(entities.RoleAdmin)}
+	report, err := bundleService.Import(ctx, bundle, dryRun, actor)
+	if err != nil {
+		logger.Fatal("导入知识库导出包失败", logger.Err(err))
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/pkg"
+	"sical-go-backend/pkg/kpimport"
+	"sical-go-backend/pkg/logger"
+)
+
+func main() {
+	// 解析命令行参数
+	var (
+		format   = flag.String("format", "", "导入格式: csv, jsonl, markdown（markdown时path为目录）")
+		path     = flag.String("path", "", "导入文件或目录路径")
+		dryRun   = flag.Bool("dry-run", false, "仅校验，不写入数据")
+		upsert   = flag.Bool("upsert", false, "外部键已存在时更新知识点")
+		authorID = flag.Uint("author-id", 0, "导入操作者的用户ID")
+	)
+	flag.Parse()
+
+	if *path == "" || *authorID == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 加载配置
+	config, err := pkg.LoadConfig()
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	// 初始化全局日志器
+	if err := logger.Init(&logger.Config{Level: "info", Format: "json", Output: "stdout"}); err != nil {
+		log.Fatalf("初始化全局日志器失败: %v", err)
+	}
+
+	records, parseErrors, err := readRecords(*format, *path)
+	if err != nil {
+		logger.Fatal("读取导入数据失败", logger.Err(err))
+	}
+
+	// 初始化数据库连接
+	db, err := gorm.Open(postgres.Open(config.GetDSN()), &gorm.Config{})
+	if err != nil {
+		logger.Fatal("数据库连接失败", logger.Err(err))
+	}
+
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	txManager := repositories.NewTransactionManager(db)
+	knowledgePointService := services.NewKnowledgePointService(
+		knowledgePointRepo,
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		txManager,
+	)
+	importService := services.NewKnowledgeImportService(knowledgePointRepo, knowledgePointService, txManager)
+
+	// 命令行导入以管理员身份执行
+	report, err := importService.Import(context.Background(), records, parseErrors, &services.KnowledgeImportOptions{
+		DryRun: *dryRun,
+		Upsert: *upsert,
+		Actor:  &services.KnowledgePointActor{UserID: *authorID, Role: string(entities.RoleAdmin)},
+	})
+	if err != nil {
+		logger.Fatal("批量导入知识点失败", logger.Err(err))
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+// readRecords 按格式读取导入数据，未指定格式时根据路径推断
+func readRecords(format, path string) ([]kpimport.Record, []kpimport.RowError, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if format == "" {
+		switch {
+		case info.IsDir():
+			format = kpimport.FormatMarkdown
+		case filepath.Ext(path) == ".csv":
+			format = kpimport.FormatCSV
+		default:
+			format = kpimport.FormatJSONL
+		}
+	}
+
+	if format == kpimport.FormatMarkdown {
+		if info.IsDir() {
+			records, errs := kpimport.ParseMarkdownFS(os.DirFS(path))
+			return records, errs, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		record, err := kpimport.ParseMarkdown(data, filepath.Base(path))
+		if err != nil {
+			return nil, []kpimport.RowError{kpimport.AsRowError(err, path)}, nil
+		}
+		return []kpimport.Record{record}, nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	name := filepath.Base(path)
+	switch format {
+	case kpimport.FormatCSV:
+		records, errs := kpimport.ParseCSV(file, name)
+		return records, errs, nil
+	case kpimport.FormatJSONL:
+		records, errs := kpimport.ParseJSONL(file, name)
+		return records, errs, nil
+	}
+	flag.Usage()
+	os.Exit(2)
+	return nil, nil, nil
+}
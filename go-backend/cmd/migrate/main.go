@@ -1,13 +1,15 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	"sical-go-backend/internal/domain/entities"
-	"sical-go-backend/internal/infrastructure/database"
+	database "sical-go-backend/internal/infrastructure"
+	"sical-go-backend/internal/infrastructure/repositories"
 	"sical-go-backend/internal/pkg"
 	"sical-go-backend/pkg/logger"
 )
@@ -15,7 +17,7 @@ import (
 func main() {
 	// 解析命令行参数
 	var (
-		action = flag.String("action", "migrate", "迁移操作: migrate, rollback, seed")
+		action = flag.String("action", "migrate", "迁移操作: migrate, rollback, seed, reindex")
 		env    = flag.String("env", "development", "环境: development, production, test")
 	)
 	flag.Parse()
@@ -75,6 +77,10 @@ func main() {
 		if err := runSeed(db); err != nil {
 			logger.Fatal("种子数据创建失败", logger.Err(err))
 		}
+	case "reindex":
+		if err := runReindex(db); err != nil {
+			logger.Fatal("重建检索索引失败", logger.Err(err))
+		}
 	case "rollback":
 		logger.Warn("回滚功能暂未实现")
 	default:
@@ -96,22 +102,71 @@ func runMigration(db *database.Database) error {
 		&entities.GoalAnalysis{},
 		&entities.LearningPath{},
 		&entities.KnowledgePoint{},
+		&entities.KnowledgePointRevision{},
+		&entities.KnowledgePointReview{},
+		&entities.KnowledgePointLink{},
+		&entities.GlossaryTerm{},
+		&entities.KnowledgePointFingerprint{},
+		&entities.KnowledgePointTranslation{},
+		&entities.KnowledgePointTransition{},
+		&entities.Rating{},
+		&entities.Comment{},
+		&entities.CommentLike{},
+		&entities.Notification{},
+		&entities.Report{},
+		&entities.ModerationAction{},
+		&entities.Annotation{},
+		&entities.Collection{},
+		&entities.CollectionItem{},
+		&entities.LearningPathTemplate{},
+		&entities.LearningPathTemplateStep{},
+		&entities.PathEnrollment{},
+		&entities.PathEnrollmentStep{},
+		&entities.Flashcard{},
+		&entities.FlashcardReview{},
+		&entities.Attachment{},
+		&entities.AttachmentReference{},
 	}
 
+	// 知识点状态列默认为草稿；首次添加该列时，存量知识点视为已发布
+	migrator := db.GetDB().Migrator()
+	backfillStatus := migrator.HasTable(&entities.KnowledgePoint{}) && !migrator.HasColumn(&entities.KnowledgePoint{}, "Status")
+
 	// 执行自动迁移
 	for _, model := range models {
 		modelName := fmt.Sprintf("%T", model)
 		logger.Info("迁移模型", logger.String("model", modelName))
-		
+
 		if err := db.Migrate(model); err != nil {
 			return fmt.Errorf("迁移模型 %s 失败: %w", modelName, err)
 		}
 	}
 
+	if backfillStatus {
+		if err := db.GetDB().Model(&entities.KnowledgePoint{}).Where("1 = 1").
+			Update("status", string(entities.KnowledgePointStatusPublished)).Error; err != nil {
+			return fmt.Errorf("设置存量知识点状态失败: %w", err)
+		}
+	}
+
 	logger.Info("数据库迁移成功完成")
 	return nil
 }
 
+// runReindex 重建知识点全文检索索引
+func runReindex(db *database.Database) error {
+	logger.Info("开始重建知识点检索索引...")
+
+	knowledgeRepo := repositories.NewKnowledgePointRepository(db.GetDB())
+	count, err := knowledgeRepo.RebuildSearchIndex(context.Background())
+	if err != nil {
+		return err
+	}
+
+	logger.Info("知识点检索索引重建完成", logger.Int64("count", count))
+	return nil
+}
+
 // runSeed 执行种子数据创建
 func runSeed(db *database.Database) error {
 	logger.Info("开始创建种子数据...")
@@ -121,4 +176,4 @@ func runSeed(db *database.Database) error {
 
 	logger.Info("种子数据创建完成")
 	return nil
-}
\ No newline at end of file
+}
@@ -15,10 +15,13 @@ import (
 	"gorm.io/gorm"
 
 	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
 	"sical-go-backend/internal/interfaces/http/routes"
 	"sical-go-backend/internal/pkg"
 	"sical-go-backend/pkg/jwt"
 	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/storage"
 )
 
 func main() {
@@ -85,7 +88,7 @@ func main() {
 	})
 
 	// 初始化中间件
-	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager, repositories.NewUserRepository(db))
 
 	// 设置Gin模式
 	if config.App.Environment == "production" {
@@ -126,8 +129,99 @@ func main() {
 	// 设置学习路径路由
 	routes.SetupLearningPathRoutes(engine, db)
 	
-	// 设置知识点路由
-	routes.SetupKnowledgePointRoutes(engine, db)
+	// 设置知识点路由（可选认证，写操作在路由内单独要求认证）
+	knowledgeGroup := engine.Group("/api/v1")
+	knowledgeGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupKnowledgePointRoutes(knowledgeGroup, db, authMiddleware)
+
+	// 设置术语表路由（查询公开，维护仅限管理员）
+	glossaryGroup := engine.Group("/api/v1")
+	glossaryGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupGlossaryRoutes(glossaryGroup, db, authMiddleware)
+
+	// 设置评分与评价路由（评价列表公开，评分在路由内单独要求认证）
+	ratingGroup := engine.Group("/api/v1")
+	ratingGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupRatingRoutes(ratingGroup, db, authMiddleware)
+
+	// 设置评论路由（评论可匿名浏览，发表与管理在路由内单独要求认证）
+	commentGroup := engine.Group("/api/v1")
+	commentGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupCommentRoutes(commentGroup, db, authMiddleware)
+
+	// 设置站内通知路由
+	notificationGroup := engine.Group("/api/v1")
+	notificationGroup.Use(authMiddleware.RequireAuth())
+	routes.SetupNotificationRoutes(notificationGroup, db)
+
+	// 设置内容举报与审核路由
+	moderationGroup := engine.Group("/api/v1")
+	moderationGroup.Use(authMiddleware.RequireAuth())
+	routes.SetupModerationRoutes(moderationGroup, db, authMiddleware)
+
+	// 设置学习笔记与高亮路由
+	annotationGroup := engine.Group("/api/v1")
+	annotationGroup.Use(authMiddleware.RequireAuth())
+	routes.SetupAnnotationRoutes(annotationGroup, db)
+
+	// 设置收藏夹路由
+	collectionGroup := engine.Group("/api/v1")
+	collectionGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupCollectionRoutes(collectionGroup, db, authMiddleware)
+
+	// 设置学习路径模板路由
+	templateGroup := engine.Group("/api/v1")
+	templateGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupLearningPathTemplateRoutes(templateGroup, db, authMiddleware)
+
+	// 设置公开学习路径报名路由
+	enrollmentGroup := engine.Group("/api/v1")
+	enrollmentGroup.Use(authMiddleware.OptionalAuth())
+	routes.SetupEnrollmentRoutes(enrollmentGroup, db, authMiddleware)
+
+	// 初始化附件存储
+	store, err := storage.New(config.GetStorageConfig())
+	if err != nil {
+		logger.Fatal("初始化附件存储失败", logger.Err(err))
+	}
+
+	// 设置闪卡路由
+	flashcardGroup := engine.Group("/api/v1")
+	flashcardGroup.Use(authMiddleware.RequireAuth())
+	routes.SetupFlashcardRoutes(flashcardGroup, db, store, config.Storage.URLExpiry)
+
+	// 设置学习推荐路由
+	recommendationGroup := engine.Group("/api/v1")
+	recommendationGroup.Use(authMiddleware.RequireAuth())
+	routes.SetupRecommendationRoutes(recommendationGroup, db, authMiddleware)
+
+	// 设置附件路由
+	attachmentGroup := engine.Group("/api/v1")
+	routes.SetupAttachmentRoutes(attachmentGroup, db, authMiddleware, store, config.Storage.URLExpiry, config.Storage.GCGracePeriod)
+
+	// 后台任务
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// 定期清理未引用的附件
+	if config.Storage.GCInterval > 0 {
+		attachmentService := services.NewAttachmentService(
+			repositories.NewAttachmentRepository(db),
+			repositories.NewUserProfileRepository(db),
+			store,
+			config.Storage.URLExpiry,
+		)
+		go runAttachmentGC(jobCtx, attachmentService, config.Storage.GCInterval, config.Storage.GCGracePeriod)
+	}
+
+	// 定期重新计算学习推荐模型
+	if config.Recommendation.Interval > 0 {
+		recommendationService := services.NewKnowledgeRecommendationService(
+			repositories.NewKnowledgeRecommendationRepository(db),
+			repositories.NewKnowledgePointRepository(db),
+		)
+		go runRecommendationJob(jobCtx, recommendationService, config.Recommendation.Interval)
+	}
 
 	// 创建HTTP服务器
 	server := &http.Server{
@@ -167,4 +261,38 @@ func main() {
 	}
 
 	logger.Info("服务器已关闭")
-}
\ No newline at end of file
+}
+
+// runAttachmentGC 按固定间隔清理超过宽限期且无引用的附件
+func runAttachmentGC(ctx context.Context, attachmentService *services.AttachmentService, interval, gracePeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := attachmentService.CollectGarbage(ctx, gracePeriod, services.SystemActor()); err != nil {
+				logger.Error("清理未引用附件失败", logger.Err(err))
+			}
+		}
+	}
+}
+
+// runRecommendationJob 启动时及之后按固定间隔根据全部学习者的学习记录重新计算推荐模型
+func runRecommendationJob(ctx context.Context, recommendationService *services.KnowledgeRecommendationService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := recommendationService.RecomputeTransitions(ctx, services.SystemActor()); err != nil {
+			logger.Error("计算学习推荐模型失败", logger.Err(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
 	"sical-go-backend/pkg/jwt"
 	"sical-go-backend/pkg/response"
 )
@@ -13,50 +15,65 @@ import (
 // AuthMiddleware JWT认证中间件
 type AuthMiddleware struct {
 	jwtManager *jwt.JWTManager
+	userRepo   repositories.UserRepository
 }
 
-// NewAuthMiddleware 创建认证中间件
-func NewAuthMiddleware(jwtManager *jwt.JWTManager) *AuthMiddleware {
+// NewAuthMiddleware 创建认证中间件；userRepo用于校验账号状态，停用的账号在令牌过期前也不能继续访问
+func NewAuthMiddleware(jwtManager *jwt.JWTManager, userRepo repositories.UserRepository) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtManager: jwtManager,
+		userRepo:   userRepo,
 	}
 }
 
 // RequireAuth 需要认证的中间件
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 从Header中提取Token
-		token := m.extractTokenFromHeader(c)
-		if token == "" {
-			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "缺少访问令牌")
-			c.Abort()
+		if !m.authenticate(c) {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// 验证Token
-		claims, err := m.jwtManager.ValidateToken(token)
-		if err != nil {
-			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "无效的访问令牌")
-			c.Abort()
-			return
-		}
+// authenticate 校验访问令牌并将用户信息存储到上下文，失败时写入响应并中止请求
+func (m *AuthMiddleware) authenticate(c *gin.Context) bool {
+	// 从Header中提取Token
+	token := m.extractTokenFromHeader(c)
+	if token == "" {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "缺少访问令牌")
+		c.Abort()
+		return false
+	}
 
-		// 将用户信息存储到上下文
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("user_role", claims.Role)
-		c.Set("token_id", claims.TokenID)
+	// 验证Token
+	claims, err := m.jwtManager.ValidateToken(token)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "无效的访问令牌")
+		c.Abort()
+		return false
+	}
 
-		c.Next()
+	// 检查账号状态
+	if !m.isActive(c, claims.UserID) {
+		response.Error(c, http.StatusForbidden, response.CodeForbidden, "账号未激活或已停用")
+		c.Abort()
+		return false
 	}
+
+	// 将用户信息存储到上下文
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("user_role", claims.Role)
+	c.Set("token_id", claims.TokenID)
+	return true
 }
 
 // RequireRole 需要特定角色的中间件
 func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 先执行认证
-		m.RequireAuth()(c)
-		if c.IsAborted() {
+		// 先执行认证（此时不放行后续处理器，角色校验通过后再调用c.Next）
+		if !m.authenticate(c) {
 			return
 		}
 
@@ -109,6 +126,12 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 账号已停用时按匿名请求处理
+		if !m.isActive(c, claims.UserID) {
+			c.Next()
+			return
+		}
+
 		// 将用户信息存储到上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
@@ -119,6 +142,12 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
+// isActive 检查令牌对应的账号是否存在且处于正常状态
+func (m *AuthMiddleware) isActive(c *gin.Context, userID uint) bool {
+	user, err := m.userRepo.GetByID(c.Request.Context(), userID)
+	return err == nil && user.Status == string(entities.StatusActive)
+}
+
 // extractTokenFromHeader 从请求头中提取Token
 func (m *AuthMiddleware) extractTokenFromHeader(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
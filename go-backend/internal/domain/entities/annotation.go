@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Annotation 学习者对知识点内容的私人笔记或高亮；高亮及划线笔记以引文锚定到内容中的文本区间，
+// 内容修订后按引文与上下文重新定位
+type Annotation struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uint      `gorm:"not null;index:idx_annotations_user_point" json:"user_id"`
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;not null;index:idx_annotations_user_point" json:"knowledge_point_id"`
+	Type             string    `gorm:"type:varchar(20);not null" json:"type"` // note, highlight
+	Body             string    `gorm:"type:text" json:"body"`                 // 笔记正文（Markdown）
+	Color            string    `gorm:"type:varchar(20)" json:"color,omitempty"`
+	Exact            string    `gorm:"type:text" json:"exact,omitempty"` // 锚定的引文，为空时为整篇笔记
+	Prefix           string    `gorm:"type:varchar(255)" json:"prefix,omitempty"`
+	Suffix           string    `gorm:"type:varchar(255)" json:"suffix,omitempty"`
+	StartOffset      int       `gorm:"not null;default:0" json:"start_offset"` // 创建时的位置，仅作为定位提示
+	EndOffset        int       `gorm:"not null;default:0" json:"end_offset"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AnnotationType 批注类型常量
+type AnnotationType string
+
+const (
+	AnnotationTypeNote      AnnotationType = "note"      // 笔记，可锚定到文本区间
+	AnnotationTypeHighlight AnnotationType = "highlight" // 高亮，必须锚定，可附带笔记
+)
+
+// 高亮颜色
+var AnnotationColors = []string{"yellow", "green", "blue", "pink", "purple"}
+
+// IsAnchored 检查批注是否锚定到文本区间
+func (a *Annotation) IsAnchored() bool {
+	return a.Exact != ""
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentURIPrefix 内容中引用附件的URI前缀，如 ![心电图](attachment:<id>)
+const AttachmentURIPrefix = "attachment:"
+
+// Attachment 上传的附件
+type Attachment struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OwnerID      uint      `gorm:"not null;index" json:"owner_id"`
+	StorageKey   string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	ThumbnailKey *string   `gorm:"type:varchar(255)" json:"-"`
+	Filename     string    `gorm:"type:varchar(255);not null" json:"filename"`
+	ContentType  string    `gorm:"type:varchar(100);not null" json:"content_type"` // 根据文件内容识别
+	Size         int64     `gorm:"not null" json:"size"`
+	Checksum     string    `gorm:"type:char(64);not null;index" json:"checksum"` // SHA-256
+	Width        int       `gorm:"not null;default:0" json:"width"`
+	Height       int       `gorm:"not null;default:0" json:"height"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+
+	// 关联关系
+	References []AttachmentReference `gorm:"foreignKey:AttachmentID;constraint:OnDelete:CASCADE" json:"references,omitempty"`
+}
+
+// AttachmentReference 附件引用记录，无引用的附件在宽限期后被清理
+type AttachmentReference struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AttachmentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_attachment_refs_unique" json:"attachment_id"`
+	RefType      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_attachment_refs_unique;index:idx_attachment_refs_owner" json:"ref_type"`
+	RefID        string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_attachment_refs_unique;index:idx_attachment_refs_owner" json:"ref_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// AttachmentRefType 附件引用类型常量
+type AttachmentRefType string
+
+const (
+	AttachmentRefKnowledgePoint AttachmentRefType = "knowledge_point"
+	AttachmentRefUserAvatar     AttachmentRefType = "user_avatar"
+	AttachmentRefFlashcard      AttachmentRefType = "flashcard"
+)
+
+// IsImage 检查附件是否为图片
+func (a *Attachment) IsImage() bool {
+	return a.Width > 0 && a.Height > 0
+}
+
+// URI 获取在内容中引用附件的URI
+func (a *Attachment) URI() string {
+	return AttachmentURIPrefix + a.ID.String()
+}
+
+// attachmentURIRegex 匹配内容中的附件URI
+var attachmentURIRegex = regexp.MustCompile(`attachment:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+
+// ExtractAttachmentIDs 提取内容中引用的附件ID（去重）
+func ExtractAttachmentIDs(content string) []uuid.UUID {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, match := range attachmentURIRegex.FindAllStringSubmatch(content, -1) {
+		id, err := uuid.Parse(match[1])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
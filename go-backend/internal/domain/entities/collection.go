@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection 用户的收藏夹，收藏知识点与学习路径；生成分享令牌后可通过链接只读访问
+type Collection struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
+	Description  string     `gorm:"type:text" json:"description"`
+	ShareToken   *string    `gorm:"type:varchar(64);uniqueIndex" json:"-"`     // 分享令牌，为空表示未分享
+	ClonedFromID *uuid.UUID `gorm:"type:uuid" json:"cloned_from_id,omitempty"` // 从他人分享的收藏夹复制而来
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CollectionItem 收藏夹中的条目，同一对象在同一收藏夹中仅出现一次
+type CollectionItem struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CollectionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_collection_items_target;index:idx_collection_items_position" json:"collection_id"`
+	TargetType   string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_collection_items_target;index:idx_collection_items_object" json:"target_type"` // knowledge_point, learning_path
+	TargetID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_collection_items_target;index:idx_collection_items_object" json:"target_id"`
+	Position     int       `gorm:"not null;default:0;index:idx_collection_items_position" json:"position"` // 收藏夹内的排列顺序
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CollectionItemType 收藏对象类型常量
+type CollectionItemType string
+
+const (
+	CollectionItemKnowledgePoint CollectionItemType = "knowledge_point"
+	CollectionItemLearningPath   CollectionItemType = "learning_path"
+)
+
+// IsOwnedBy 检查收藏夹是否属于指定用户
+func (c *Collection) IsOwnedBy(userID uint) bool {
+	return c.UserID == userID
+}
+
+// IsShared 检查收藏夹是否已开启链接分享
+func (c *Collection) IsShared() bool {
+	return c.ShareToken != nil && *c.ShareToken != ""
+}
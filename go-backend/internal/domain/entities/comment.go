@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment 知识点下的讨论评论；回复通过ParentID形成树，ThreadID指向所在讨论串的顶层评论。
+// 删除为软删除：保留记录以维持讨论串结构，仅清空正文
+type Comment struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID  `gorm:"type:uuid;not null;index:idx_comments_point_created" json:"knowledge_point_id"`
+	ParentID         *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
+	ThreadID         *uuid.UUID `gorm:"type:uuid;index" json:"thread_id"` // 顶层评论为nil
+	AuthorID         uint       `gorm:"not null;index" json:"author_id"`
+	Body             string     `gorm:"type:text;not null" json:"body"` // Markdown正文
+	LikesCount       int        `gorm:"not null;default:0" json:"likes_count"`
+	ReplyCount       int        `gorm:"not null;default:0" json:"reply_count"` // 直接回复数（含已删除的回复）
+	EditedAt         *time.Time `json:"edited_at"`
+	DeletedAt        *time.Time `gorm:"index" json:"deleted_at"`
+	DeletedBy        *uint      `json:"deleted_by,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime;index:idx_comments_point_created" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CommentLike 评论点赞
+type CommentLike struct {
+	CommentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"comment_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// IsDeleted 检查评论是否已删除
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsAuthoredBy 检查评论是否由指定用户发表
+func (c *Comment) IsAuthoredBy(userID uint) bool {
+	return c.AuthorID == userID
+}
+
+// RootID 获取评论所在讨论串的顶层评论ID
+func (c *Comment) RootID() uuid.UUID {
+	if c.ThreadID != nil {
+		return *c.ThreadID
+	}
+	return c.ID
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PathEnrollment 学习者对公开学习路径的报名记录，学习进度按报名分别记录
+type PathEnrollment struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID         uint       `gorm:"not null;uniqueIndex:idx_path_enrollments_user_path" json:"user_id"`
+	LearningPathID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_path_enrollments_user_path;index" json:"learning_path_id"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'active';index" json:"status"` // active, completed
+	StartedAt      *time.Time `json:"started_at"`                                                     // 首次开始学习任一步骤的时间
+	CompletedAt    *time.Time `json:"completed_at"`                                                   // 全部步骤完成的时间
+	LastActivityAt *time.Time `json:"last_activity_at"`
+	TimeSpent      int        `gorm:"not null;default:0" json:"time_spent"` // 累计学习时长(秒)
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`     // 报名时间
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PathEnrollmentStep 报名记录中单个步骤（路径中的一个知识点）的学习进度
+type PathEnrollmentStep struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EnrollmentID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_path_enrollment_steps_point" json:"enrollment_id"`
+	KnowledgePointID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_path_enrollment_steps_point;index" json:"knowledge_point_id"`
+	Position         int        `gorm:"not null" json:"position"`
+	Status           string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"` // pending, in_progress, completed
+	StartedAt        *time.Time `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	TimeSpent        int        `gorm:"not null;default:0" json:"time_spent"` // 学习时长(秒)
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// EnrollmentStatus 报名状态常量
+type EnrollmentStatus string
+
+const (
+	EnrollmentStatusActive    EnrollmentStatus = "active"
+	EnrollmentStatusCompleted EnrollmentStatus = "completed"
+)
+
+// EnrollmentStepStatus 步骤进度状态常量
+type EnrollmentStepStatus string
+
+const (
+	EnrollmentStepPending    EnrollmentStepStatus = "pending"
+	EnrollmentStepInProgress EnrollmentStepStatus = "in_progress"
+	EnrollmentStepCompleted  EnrollmentStepStatus = "completed"
+)
+
+// IsCompleted 检查步骤是否已完成
+func (s *PathEnrollmentStep) IsCompleted() bool {
+	return s.Status == string(EnrollmentStepCompleted)
+}
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"sical-go-backend/pkg/cloze"
+)
+
+// Flashcard 闪卡
+type Flashcard struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID      `gorm:"type:uuid;not null;index" json:"knowledge_point_id"`
+	CardType         string         `gorm:"type:varchar(20);not null;default:'basic'" json:"card_type"` // basic, cloze
+	Front            string         `gorm:"type:text;not null" json:"front"`                            // 正面；cloze卡片为挖空标记原文
+	Back             string         `gorm:"type:text" json:"back"`                                      // 背面；cloze卡片为补充说明
+	ClozeIndex       int            `gorm:"not null;default:0" json:"cloze_index"`                      // cloze卡片对应的挖空序号
+	CreatedBy        uint           `gorm:"index" json:"created_by"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联关系
+	KnowledgePoint KnowledgePoint `gorm:"foreignKey:KnowledgePointID" json:"knowledge_point,omitempty"`
+}
+
+// FlashcardReview 用户的闪卡复习调度状态
+type FlashcardReview struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	FlashcardID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_flashcard_reviews_user_card" json:"flashcard_id"`
+	UserID         uint       `gorm:"not null;uniqueIndex:idx_flashcard_reviews_user_card;index" json:"user_id"`
+	EaseFactor     float64    `gorm:"type:decimal(4,2);not null;default:2.5" json:"ease_factor"`
+	Interval       int        `gorm:"not null;default:0" json:"interval"` // 复习间隔(天)
+	Repetitions    int        `gorm:"not null;default:0" json:"repetitions"`
+	Lapses         int        `gorm:"not null;default:0" json:"lapses"`
+	DueAt          time.Time  `gorm:"not null;index" json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联关系
+	Flashcard Flashcard `gorm:"foreignKey:FlashcardID" json:"flashcard,omitempty"`
+}
+
+// FlashcardType 闪卡类型常量
+type FlashcardType string
+
+const (
+	FlashcardTypeBasic FlashcardType = "basic"
+	FlashcardTypeCloze FlashcardType = "cloze"
+)
+
+// ReviewRating 复习评分常量
+type ReviewRating int
+
+const (
+	ReviewRatingAgain ReviewRating = 1
+	ReviewRatingHard  ReviewRating = 2
+	ReviewRatingGood  ReviewRating = 3
+	ReviewRatingEasy  ReviewRating = 4
+)
+
+// IsCloze 检查是否为挖空卡片
+func (f *Flashcard) IsCloze() bool {
+	return f.CardType == string(FlashcardTypeCloze)
+}
+
+// Question 获取卡片问题面
+func (f *Flashcard) Question() string {
+	if f.IsCloze() {
+		return cloze.RenderQuestion(f.Front, f.ClozeIndex)
+	}
+	return f.Front
+}
+
+// Answer 获取卡片答案面
+func (f *Flashcard) Answer() string {
+	if f.IsCloze() {
+		return cloze.RenderAnswer(f.Front)
+	}
+	return f.Back
+}
+
+// IsDue 检查卡片是否到期需要复习
+func (r *FlashcardReview) IsDue() bool {
+	return !time.Now().Before(r.DueAt)
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/pkg/textsearch"
+)
+
+// GlossaryTerm 医学术语表条目：标准名称、释义、同义词与缩写
+type GlossaryTerm struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Term          string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"term"` // 标准名称
+	Definition    string    `gorm:"type:text" json:"definition"`
+	Synonyms      string    `gorm:"type:jsonb;default:'[]'" json:"synonyms"`      // 同义词列表
+	Abbreviations string    `gorm:"type:jsonb;default:'[]'" json:"abbreviations"` // 缩写列表
+	Category      string    `gorm:"type:varchar(100);index" json:"category"`
+	Variants      string    `gorm:"type:jsonb;default:'[]';index:idx_glossary_terms_variants,type:gin" json:"-"` // 规范化后的全部名称，由仓储维护
+	CreatedBy     *uint     `gorm:"index" json:"created_by"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SynonymList 获取同义词列表
+func (t *GlossaryTerm) SynonymList() []string {
+	synonyms := []string{}
+	if t.Synonyms != "" {
+		json.Unmarshal([]byte(t.Synonyms), &synonyms)
+	}
+	return synonyms
+}
+
+// SetSynonyms 设置同义词列表，去除空白与重复项
+func (t *GlossaryTerm) SetSynonyms(synonyms []string) {
+	t.Synonyms = marshalStringList(synonyms)
+}
+
+// AbbreviationList 获取缩写列表
+func (t *GlossaryTerm) AbbreviationList() []string {
+	abbreviations := []string{}
+	if t.Abbreviations != "" {
+		json.Unmarshal([]byte(t.Abbreviations), &abbreviations)
+	}
+	return abbreviations
+}
+
+// SetAbbreviations 设置缩写列表，去除空白与重复项
+func (t *GlossaryTerm) SetAbbreviations(abbreviations []string) {
+	t.Abbreviations = marshalStringList(abbreviations)
+}
+
+// Names 获取条目的全部名称：标准名称、同义词与缩写
+func (t *GlossaryTerm) Names() []string {
+	names := append([]string{t.Term}, t.SynonymList()...)
+	return append(names, t.AbbreviationList()...)
+}
+
+// NormalizedNames 获取规范化后去重的全部名称
+func (t *GlossaryTerm) NormalizedNames() []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, name := range t.Names() {
+		key := textsearch.NormalizeTerm(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, key)
+	}
+	return normalized
+}
+
+// RefreshVariants 根据当前名称更新规范化名称索引
+func (t *GlossaryTerm) RefreshVariants() {
+	data, _ := json.Marshal(t.NormalizedNames())
+	t.Variants = string(data)
+}
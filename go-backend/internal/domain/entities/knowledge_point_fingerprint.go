@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgePointFingerprint 知识点查重指纹：规范化标题与内容的MinHash签名，
+// 由知识点写入时自动生成，用于重复与近似重复检测
+type KnowledgePointFingerprint struct {
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;primary_key" json:"knowledge_point_id"`
+	TitleKey         string    `gorm:"type:varchar(255);not null;index" json:"title_key"` // 规范化标题
+	Signature        string    `gorm:"type:text" json:"-"`                                // 十六进制编码的MinHash签名
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
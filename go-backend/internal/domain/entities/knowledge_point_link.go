@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgePointLink 知识点关联：来源知识点的内容提及了目标知识点的标题或别名。
+// 关联由内容自动识别生成，与前置关系（Prerequisites）相互独立
+type KnowledgePointLink struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SourceID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_kp_links_source_target" json:"source_id"`
+	TargetID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_kp_links_source_target;index" json:"target_id"`
+	Mention     string    `gorm:"type:varchar(255);not null" json:"mention"` // 首次出现的提及文本
+	Occurrences int       `gorm:"not null;default:1" json:"occurrences"`     // 提及次数
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgePointReview 知识点审核记录（审核意见与状态流转）
+type KnowledgePointReview struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;not null;index" json:"knowledge_point_id"`
+	ReviewerID       uint      `gorm:"not null;index" json:"reviewer_id"`
+	Action           string    `gorm:"type:varchar(30);not null" json:"action"` // submit, approve, request_changes, publish, archive, reopen, comment
+	Comment          string    `gorm:"type:text" json:"comment"`
+	FromStatus       string    `gorm:"type:varchar(20)" json:"from_status"`
+	ToStatus         string    `gorm:"type:varchar(20)" json:"to_status"`
+	Version          int       `gorm:"not null;default:0" json:"version"` // 审核时的修订版本
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	KnowledgePoint KnowledgePoint `gorm:"foreignKey:KnowledgePointID" json:"knowledge_point,omitempty"`
+}
+
+// ReviewAction 审核操作常量
+type ReviewAction string
+
+const (
+	ReviewActionSubmit         ReviewAction = "submit"
+	ReviewActionApprove        ReviewAction = "approve"
+	ReviewActionRequestChanges ReviewAction = "request_changes"
+	ReviewActionPublish        ReviewAction = "publish"
+	ReviewActionArchive        ReviewAction = "archive"
+	ReviewActionReopen         ReviewAction = "reopen"
+	ReviewActionComment        ReviewAction = "comment"
+)
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgePointRevision 知识点修订版本（不可变快照）
+type KnowledgePointRevision struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_kp_revisions_point_version" json:"knowledge_point_id"`
+	Version          int       `gorm:"not null;uniqueIndex:idx_kp_revisions_point_version" json:"version"`
+	AuthorID         *uint     `gorm:"index" json:"author_id"` // 匿名修改时为空
+	Title            string    `gorm:"type:varchar(255);not null" json:"title"`
+	Description      string    `gorm:"type:text" json:"description"`
+	Category         string    `gorm:"type:varchar(100);not null" json:"category"`
+	Difficulty       string    `gorm:"type:varchar(50);not null" json:"difficulty"`
+	Content          string    `gorm:"type:text" json:"content"`
+	Resources        string    `gorm:"type:jsonb" json:"resources"`
+	Prerequisites    string    `gorm:"type:jsonb" json:"prerequisites"`
+	Tags             string    `gorm:"type:jsonb;default:'[]'" json:"tags"`
+	Aliases          string    `gorm:"type:jsonb;default:'[]'" json:"aliases"`
+	ChangedFields    string    `gorm:"type:jsonb;default:'[]'" json:"changed_fields"` // 相对上一版本变更的字段
+	ContentDiff      string    `gorm:"type:text" json:"content_diff"`                 // 相对上一版本的内容差异（unified diff）
+	RestoredFrom     *int      `json:"restored_from"`                                 // 由历史版本恢复时记录来源版本
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	KnowledgePoint KnowledgePoint `gorm:"foreignKey:KnowledgePointID" json:"knowledge_point,omitempty"`
+}
+
+// NewKnowledgePointRevision 根据知识点当前内容创建修订快照
+func NewKnowledgePointRevision(point *KnowledgePoint) *KnowledgePointRevision {
+	return &KnowledgePointRevision{
+		ID:               uuid.New(),
+		KnowledgePointID: point.ID,
+		Title:            point.Title,
+		Description:      point.Description,
+		Category:         point.Category,
+		Difficulty:       point.Difficulty,
+		Content:          point.Content,
+		Resources:        point.Resources,
+		Prerequisites:    point.Prerequisites,
+		Tags:             point.Tags,
+		Aliases:          point.Aliases,
+	}
+}
+
+// ChangedFieldList 获取变更字段列表
+func (r *KnowledgePointRevision) ChangedFieldList() []string {
+	fields := []string{}
+	if r.ChangedFields != "" {
+		json.Unmarshal([]byte(r.ChangedFields), &fields)
+	}
+	return fields
+}
+
+// ApplyTo 将修订内容写回知识点
+func (r *KnowledgePointRevision) ApplyTo(point *KnowledgePoint) {
+	point.Title = r.Title
+	point.Description = r.Description
+	point.Category = r.Category
+	point.Difficulty = r.Difficulty
+	point.Content = r.Content
+	point.Resources = r.Resources
+	point.Prerequisites = r.Prerequisites
+	point.Tags = r.Tags
+	point.Aliases = r.Aliases
+}
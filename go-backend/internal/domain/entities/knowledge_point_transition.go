@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgePointTransition 知识点之间的学习转移统计：学习过源知识点的学习者随后学习目标知识点的情况，
+// 由推荐任务根据全部学习者的复习记录定期重新计算
+type KnowledgePointTransition struct {
+	SourceID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"source_id"`
+	TargetID   uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"target_id"`
+	Learners   int       `gorm:"not null" json:"learners"`   // 先学习源知识点、随后学习目标知识点的学习者数
+	Confidence float64   `gorm:"not null" json:"confidence"` // Learners占学习过源知识点的学习者的比例
+	Score      float64   `gorm:"not null" json:"score"`      // 以目标知识点复习表现加权后的转移强度
+	ComputedAt time.Time `gorm:"not null" json:"computed_at"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgePointTranslation 知识点的其他语言译文；知识点自身字段为原文（默认语言）
+type KnowledgePointTranslation struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_kp_translations_point_locale" json:"knowledge_point_id"`
+	Locale           string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_kp_translations_point_locale;index" json:"locale"` // BCP 47语言标签
+	Title            string    `gorm:"type:varchar(255);not null" json:"title"`
+	Description      string    `gorm:"type:text" json:"description"`
+	Content          string    `gorm:"type:text" json:"content"`
+	Status           string    `gorm:"type:varchar(20);not null;default:'draft'" json:"status"` // draft, published
+	SourceVersion    int       `gorm:"not null;default:0" json:"source_version"`                // 翻译所依据的原文修订版本号
+	TranslatorID     *uint     `gorm:"index" json:"translator_id"`
+	SearchVector     string    `gorm:"type:tsvector;index:idx_kp_translations_search,type:gin;->:false" json:"-"` // 全文检索向量，由仓储维护
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TranslationStatus 译文状态常量
+type TranslationStatus string
+
+const (
+	TranslationStatusDraft     TranslationStatus = "draft"
+	TranslationStatusPublished TranslationStatus = "published"
+)
+
+// IsPublished 检查译文是否已发布
+func (t *KnowledgePointTranslation) IsPublished() bool {
+	return t.Status == string(TranslationStatusPublished)
+}
+
+// ApplyTo 将译文字段覆盖到知识点，用于按语言返回内容
+func (t *KnowledgePointTranslation) ApplyTo(point *KnowledgePoint) {
+	point.Title = t.Title
+	point.Description = t.Description
+	point.Content = t.Content
+}
@@ -1,6 +1,8 @@
 package entities
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -47,11 +49,15 @@ type GoalAnalysis struct {
 type LearningPath struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	GoalID      uuid.UUID `gorm:"type:uuid;not null;index" json:"goal_id"`
+	TemplateID  *uuid.UUID `gorm:"type:uuid;index" json:"template_id,omitempty"` // 由模板实例化时记录来源模板
 	Title       string    `gorm:"type:varchar(255);not null" json:"title"`
 	Description string    `gorm:"type:text" json:"description"`
 	Order       int       `gorm:"not null" json:"order"`
 	EstimatedDuration int `gorm:"not null" json:"estimated_duration"` // 预估学习时间(小时)
-	Status      string    `gorm:"type:varchar(50);not null;default:'pending'" json:"status"` // pending, in_progress, completed
+	Status      string    `gorm:"type:varchar(50);not null;default:'pending'" json:"status"` // pending, in_progress, completed；公开路径的学习进度见PathEnrollment
+	IsPublic    bool      `gorm:"not null;default:false;index" json:"is_public"` // 公开路径可供任意学习者报名
+	AverageRating float64 `gorm:"type:decimal(3,2);not null;default:0;<-:create" json:"average_rating"` // 平均评分（不含已隐藏的评价），由评分仓储维护
+	RatingsCount  int     `gorm:"not null;default:0;<-:create" json:"ratings_count"`                  // 评分人数
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -69,12 +75,107 @@ type KnowledgePoint struct {
 	Category    string    `gorm:"type:varchar(100);not null" json:"category"`
 	Difficulty  string    `gorm:"type:varchar(50);not null" json:"difficulty"`
 	Content     string    `gorm:"type:text" json:"content"`
-	Resources   string    `gorm:"type:jsonb" json:"resources"` // 学习资源链接等
+	Resources   string    `gorm:"type:jsonb" json:"resources"` // 学习资源列表，见LearningResource
 	Prerequisites string  `gorm:"type:jsonb" json:"prerequisites"` // 前置知识点
+	Tags          string  `gorm:"type:jsonb;default:'[]';index:idx_knowledge_points_tags,type:gin" json:"tags"` // 标签列表
+	Aliases       string  `gorm:"type:jsonb;default:'[]'" json:"aliases"` // 别名列表（同义名称、缩写），用于识别内容中的提及
+	SearchVector  string  `gorm:"type:tsvector;index:idx_knowledge_points_search,type:gin;->:false" json:"-"` // 全文检索向量，由仓储维护
+	Status        string     `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"` // draft, in_review, published, archived；存量数据迁移后视为已发布
+	AuthorID      *uint      `gorm:"index" json:"author_id"`
+	ExternalKey   *string    `gorm:"type:varchar(255);uniqueIndex" json:"external_key,omitempty"` // 外部系统标识，批量导入时用于更新匹配
+	ApprovedBy    *uint      `json:"approved_by"` // 当前提交的审核通过人
+	PublishedAt   *time.Time `json:"published_at"`
+	AverageRating float64    `gorm:"type:decimal(3,2);not null;default:0;index;<-:create" json:"average_rating"` // 平均评分（不含已隐藏的评价），由评分仓储维护
+	RatingsCount  int        `gorm:"not null;default:0;<-:create" json:"ratings_count"`                        // 评分人数
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联关系
 	LearningPaths []LearningPath `gorm:"many2many:path_knowledge_points;" json:"learning_paths,omitempty"`
+	Flashcards    []Flashcard    `gorm:"foreignKey:KnowledgePointID" json:"flashcards,omitempty"`
+}
+
+// KnowledgePointStatus 知识点状态常量
+type KnowledgePointStatus string
+
+const (
+	KnowledgePointStatusDraft     KnowledgePointStatus = "draft"
+	KnowledgePointStatusInReview  KnowledgePointStatus = "in_review"
+	KnowledgePointStatusPublished KnowledgePointStatus = "published"
+	KnowledgePointStatusArchived  KnowledgePointStatus = "archived"
+)
+
+// IsPublished 检查知识点是否已发布
+func (kp *KnowledgePoint) IsPublished() bool {
+	return kp.Status == string(KnowledgePointStatusPublished)
+}
+
+// IsAuthoredBy 检查知识点是否由指定用户创建
+func (kp *KnowledgePoint) IsAuthoredBy(userID uint) bool {
+	return kp.AuthorID != nil && *kp.AuthorID == userID
+}
+
+// TagList 获取标签列表
+func (kp *KnowledgePoint) TagList() []string {
+	tags := []string{}
+	if kp.Tags != "" {
+		json.Unmarshal([]byte(kp.Tags), &tags)
+	}
+	return tags
+}
+
+// SetTags 设置标签列表，去除空白与重复标签
+func (kp *KnowledgePoint) SetTags(tags []string) {
+	kp.Tags = marshalStringList(tags)
+}
+
+// AliasList 获取别名列表
+func (kp *KnowledgePoint) AliasList() []string {
+	aliases := []string{}
+	if kp.Aliases != "" {
+		json.Unmarshal([]byte(kp.Aliases), &aliases)
+	}
+	return aliases
+}
+
+// SetAliases 设置别名列表，去除空白与重复别名
+func (kp *KnowledgePoint) SetAliases(aliases []string) {
+	kp.Aliases = marshalStringList(aliases)
+}
+
+// MentionTerms 获取可在其他知识点内容中被识别为提及的名称（标题与别名）
+func (kp *KnowledgePoint) MentionTerms() []string {
+	return append([]string{kp.Title}, kp.AliasList()...)
+}
+
+// PrerequisiteIDs 获取前置知识点ID，忽略无法解析为ID的历史数据
+func (kp *KnowledgePoint) PrerequisiteIDs() []uuid.UUID {
+	var refs []string
+	if kp.Prerequisites != "" {
+		json.Unmarshal([]byte(kp.Prerequisites), &refs)
+	}
+	ids := make([]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		if id, err := uuid.Parse(ref); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// marshalStringList 去除空白与重复项后序列化为JSON数组
+func marshalStringList(values []string) string {
+	cleaned := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		cleaned = append(cleaned, value)
+	}
+	data, _ := json.Marshal(cleaned)
+	return string(data)
 }
\ No newline at end of file
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LearningPathTemplate 学习路径模板，由管理员编排的有序步骤，可为任意学习目标实例化为学习路径
+type LearningPathTemplate struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Title       string         `gorm:"type:varchar(255);not null" json:"title"`
+	Description string         `gorm:"type:text" json:"description"`
+	Category    string         `gorm:"type:varchar(100);not null;index" json:"category"`
+	Difficulty  string         `gorm:"type:varchar(50);not null;index" json:"difficulty"`             // beginner, intermediate, advanced
+	Status      string         `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"` // draft, published；仅已发布的模板出现在目录中
+	AuthorID    uint           `gorm:"not null" json:"author_id"`
+	UsageCount  int            `gorm:"not null;default:0;<-:create" json:"usage_count"` // 实例化次数，由仓储维护
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联关系
+	Steps []LearningPathTemplateStep `gorm:"foreignKey:TemplateID" json:"steps,omitempty"`
+}
+
+// LearningPathTemplateStep 模板步骤，实例化后对应学习目标下的一条学习路径
+type LearningPathTemplateStep struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TemplateID        uuid.UUID `gorm:"type:uuid;not null;index" json:"template_id"`
+	Position          int       `gorm:"not null" json:"position"`
+	Title             string    `gorm:"type:varchar(255);not null" json:"title"`
+	Description       string    `gorm:"type:text" json:"description"`
+	EstimatedDuration int       `gorm:"not null" json:"estimated_duration"`                       // 预估学习时间(小时)
+	KnowledgePoints   string    `gorm:"type:jsonb;not null;default:'[]'" json:"knowledge_points"` // 按学习顺序排列的知识点ID
+}
+
+// LearningPathTemplateStatus 模板状态常量
+type LearningPathTemplateStatus string
+
+const (
+	LearningPathTemplateStatusDraft     LearningPathTemplateStatus = "draft"
+	LearningPathTemplateStatusPublished LearningPathTemplateStatus = "published"
+)
+
+// IsPublished 检查模板是否已发布
+func (t *LearningPathTemplate) IsPublished() bool {
+	return t.Status == string(LearningPathTemplateStatusPublished)
+}
+
+// KnowledgePointIDs 获取步骤的知识点ID列表
+func (s *LearningPathTemplateStep) KnowledgePointIDs() []uuid.UUID {
+	var refs []string
+	if s.KnowledgePoints != "" {
+		json.Unmarshal([]byte(s.KnowledgePoints), &refs)
+	}
+	ids := make([]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		if id, err := uuid.Parse(ref); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SetKnowledgePointIDs 设置步骤的知识点ID列表，去除重复项并保持顺序
+func (s *LearningPathTemplateStep) SetKnowledgePointIDs(ids []uuid.UUID) {
+	refs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, id.String())
+	}
+	s.KnowledgePoints = marshalStringList(refs)
+}
@@ -0,0 +1,161 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// LearningResource 知识点的学习资源，以JSON数组形式保存在KnowledgePoint.Resources中
+type LearningResource struct {
+	ID              uuid.UUID `json:"id"`
+	Type            string    `json:"type"` // video, article, textbook_chapter, pdf, link
+	Title           string    `json:"title"`
+	URL             string    `json:"url,omitempty"`
+	Locator         string    `json:"locator,omitempty"`          // 非URL定位，如教材章节与页码
+	DurationMinutes int       `json:"duration_minutes,omitempty"` // 预计学习时长(分钟)
+	Language        string    `json:"language,omitempty"`         // BCP 47语言标签，如zh-CN、en
+	License         string    `json:"license,omitempty"`          // 许可协议，如CC-BY-4.0
+}
+
+// ResourceType 学习资源类型常量
+type ResourceType string
+
+const (
+	ResourceTypeVideo           ResourceType = "video"
+	ResourceTypeArticle         ResourceType = "article"
+	ResourceTypeTextbookChapter ResourceType = "textbook_chapter"
+	ResourceTypePDF             ResourceType = "pdf"
+	ResourceTypeLink            ResourceType = "link"
+)
+
+// 学习资源字段限制
+const (
+	maxResourceTitleLength   = 255
+	maxResourceURLLength     = 2048
+	maxResourceLocatorLength = 255
+	maxResourceLicenseLength = 100
+	maxResourceDuration      = 100 * 60
+)
+
+// languageTagRegex 匹配BCP 47语言标签
+var languageTagRegex = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// Validate 校验学习资源
+func (r *LearningResource) Validate() error {
+	switch ResourceType(r.Type) {
+	case ResourceTypeVideo, ResourceTypeArticle, ResourceTypeTextbookChapter, ResourceTypePDF, ResourceTypeLink:
+	default:
+		return fmt.Errorf("无效的资源类型: %s", r.Type)
+	}
+
+	if strings.TrimSpace(r.Title) == "" {
+		return errors.New("资源标题不能为空")
+	}
+	if utf8.RuneCountInString(r.Title) > maxResourceTitleLength {
+		return fmt.Errorf("资源标题长度不能超过%d个字符", maxResourceTitleLength)
+	}
+
+	// 教材章节可仅提供定位信息，其余类型必须提供URL
+	if r.URL == "" {
+		if ResourceType(r.Type) != ResourceTypeTextbookChapter {
+			return errors.New("资源URL不能为空")
+		}
+		if strings.TrimSpace(r.Locator) == "" {
+			return errors.New("教材章节需提供URL或定位信息")
+		}
+	} else if err := validateResourceURL(r.URL); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(r.Locator) > maxResourceLocatorLength {
+		return fmt.Errorf("定位信息长度不能超过%d个字符", maxResourceLocatorLength)
+	}
+
+	if r.DurationMinutes < 0 || r.DurationMinutes > maxResourceDuration {
+		return fmt.Errorf("资源时长需在0到%d分钟之间", maxResourceDuration)
+	}
+	if r.Language != "" && !languageTagRegex.MatchString(r.Language) {
+		return fmt.Errorf("无效的语言标签: %s", r.Language)
+	}
+	if utf8.RuneCountInString(r.License) > maxResourceLicenseLength {
+		return fmt.Errorf("许可协议长度不能超过%d个字符", maxResourceLicenseLength)
+	}
+	return nil
+}
+
+// validateResourceURL 校验资源URL，仅允许http与https
+func validateResourceURL(raw string) error {
+	if len(raw) > maxResourceURLLength {
+		return fmt.Errorf("资源URL长度不能超过%d个字符", maxResourceURLLength)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("无效的资源URL: %s", raw)
+	}
+	return nil
+}
+
+// ParseResources 解析学习资源JSON；兼容早期以URL字符串数组保存的数据，
+// 缺少ID的资源按位置与内容生成稳定ID
+func ParseResources(value string) []LearningResource {
+	resources := []LearningResource{}
+	if strings.TrimSpace(value) == "" {
+		return resources
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(value), &items); err != nil {
+		return resources
+	}
+	for i, item := range items {
+		var resource LearningResource
+		var link string
+		if err := json.Unmarshal(item, &link); err == nil {
+			resource = LearningResource{Type: string(ResourceTypeLink), Title: link, URL: link}
+		} else if err := json.Unmarshal(item, &resource); err != nil {
+			continue
+		}
+		if resource.Type == "" {
+			resource.Type = string(ResourceTypeLink)
+		}
+		if resource.ID == uuid.Nil {
+			resource.ID = uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%d:%s:%s", i, resource.URL, resource.Title)))
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// ResourceList 获取学习资源列表
+func (kp *KnowledgePoint) ResourceList() []LearningResource {
+	return ParseResources(kp.Resources)
+}
+
+// SetResources 设置学习资源列表，为缺少ID的资源分配ID
+func (kp *KnowledgePoint) SetResources(resources []LearningResource) {
+	if resources == nil {
+		resources = []LearningResource{}
+	}
+	for i := range resources {
+		if resources[i].ID == uuid.Nil {
+			resources[i].ID = uuid.New()
+		}
+	}
+	data, _ := json.Marshal(resources)
+	kp.Resources = string(data)
+}
+
+// ResourceMinutes 获取全部学习资源的预计时长(分钟)
+func (kp *KnowledgePoint) ResourceMinutes() int {
+	total := 0
+	for _, resource := range kp.ResourceList() {
+		total += resource.DurationMinutes
+	}
+	return total
+}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uint       `gorm:"not null;index:idx_notifications_user_read" json:"user_id"` // 接收者
+	Type             string     `gorm:"type:varchar(30);not null" json:"type"`
+	ActorID          *uint      `json:"actor_id"` // 触发通知的用户
+	KnowledgePointID *uuid.UUID `gorm:"type:uuid" json:"knowledge_point_id,omitempty"`
+	CommentID        *uuid.UUID `gorm:"type:uuid" json:"comment_id,omitempty"`
+	Message          string     `gorm:"type:varchar(500);not null" json:"message"`
+	ReadAt           *time.Time `gorm:"index:idx_notifications_user_read" json:"read_at"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// NotificationType 通知类型常量
+type NotificationType string
+
+const (
+	NotificationTypeMention NotificationType = "mention" // 在评论中被@提及
+	NotificationTypeReply   NotificationType = "reply"   // 评论收到回复
+	NotificationTypeWarning NotificationType = "warning" // 审核人员的警告
+)
+
+// IsRead 检查通知是否已读
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rating 用户对知识点或学习路径的评分与评价，每个用户对每个对象仅保留一条
+type Rating struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TargetType   string     `gorm:"type:varchar(30);not null;uniqueIndex:idx_ratings_target_user;index:idx_ratings_target" json:"target_type"` // knowledge_point, learning_path
+	TargetID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_ratings_target_user;index:idx_ratings_target" json:"target_id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_ratings_target_user;index" json:"user_id"`
+	Score        int        `gorm:"not null" json:"score"` // 1-5星
+	Review       string     `gorm:"type:text" json:"review"`
+	Hidden       bool       `gorm:"not null;default:false;index" json:"hidden"` // 被审核人员隐藏的评价不公开展示，也不计入平均评分
+	HiddenBy     *uint      `json:"hidden_by,omitempty"`
+	HiddenReason string     `gorm:"type:varchar(500)" json:"hidden_reason,omitempty"`
+	HiddenAt     *time.Time `json:"hidden_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// RatingTargetType 评分对象类型常量
+type RatingTargetType string
+
+const (
+	RatingTargetKnowledgePoint RatingTargetType = "knowledge_point"
+	RatingTargetLearningPath   RatingTargetType = "learning_path"
+)
+
+// 评分范围
+const (
+	MinRatingScore = 1
+	MaxRatingScore = 5
+)
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report 用户对评论、评价或知识点的举报
+type Report struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TargetType string     `gorm:"type:varchar(30);not null;index:idx_reports_target" json:"target_type"` // comment, rating, knowledge_point
+	TargetID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_reports_target" json:"target_id"`
+	ReporterID uint       `gorm:"not null;index" json:"reporter_id"`
+	Reason     string     `gorm:"type:varchar(30);not null" json:"reason"`
+	Details    string     `gorm:"type:text" json:"details"`
+	Status     string     `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	Resolution string     `gorm:"type:varchar(30)" json:"resolution,omitempty"` // 处理举报时采取的审核操作
+	ResolvedBy *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ModerationAction 审核操作记录，每次审核决定都会留下一条不可修改的记录
+type ModerationAction struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ModeratorID     uint      `gorm:"not null;index" json:"moderator_id"`
+	Action          string    `gorm:"type:varchar(30);not null" json:"action"`
+	TargetType      string    `gorm:"type:varchar(30);not null;index:idx_moderation_actions_target" json:"target_type"`
+	TargetID        uuid.UUID `gorm:"type:uuid;not null;index:idx_moderation_actions_target" json:"target_id"`
+	SubjectUserID   *uint     `gorm:"index" json:"subject_user_id"` // 被处理内容的作者
+	Note            string    `gorm:"type:text" json:"note"`
+	ContentHidden   bool      `gorm:"not null;default:false" json:"content_hidden"` // 是否同时隐藏了被举报内容
+	ReportsResolved int       `gorm:"not null;default:0" json:"reports_resolved"`
+	CreatedAt       time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// ReportTargetType 举报对象类型常量
+type ReportTargetType string
+
+const (
+	ReportTargetComment        ReportTargetType = "comment"
+	ReportTargetRating         ReportTargetType = "rating"
+	ReportTargetKnowledgePoint ReportTargetType = "knowledge_point"
+)
+
+// ReportReason 举报原因常量
+type ReportReason string
+
+const (
+	ReportReasonSpam          ReportReason = "spam"          // 垃圾广告
+	ReportReasonAbuse         ReportReason = "abuse"         // 辱骂骚扰
+	ReportReasonInappropriate ReportReason = "inappropriate" // 不当内容
+	ReportReasonInaccurate    ReportReason = "inaccurate"    // 内容错误
+	ReportReasonOther         ReportReason = "other"
+)
+
+// ReportStatus 举报状态常量
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"  // 已处理
+	ReportStatusDismissed ReportStatus = "dismissed" // 已驳回
+)
+
+// ModerationActionType 审核操作类型常量
+type ModerationActionType string
+
+const (
+	ModerationActionDismiss     ModerationActionType = "dismiss"      // 驳回举报
+	ModerationActionHideContent ModerationActionType = "hide_content" // 隐藏内容
+	ModerationActionWarnUser    ModerationActionType = "warn_user"    // 警告作者
+	ModerationActionSuspendUser ModerationActionType = "suspend_user" // 停用作者账号
+)
+
+// IsOpen 检查举报是否待处理
+func (r *Report) IsOpen() bool {
+	return r.Status == string(ReportStatusOpen)
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// AnnotationFilter 批注查询条件
+type AnnotationFilter struct {
+	UserID           uint
+	Type             string     // 为空时不限类型
+	KnowledgePointID *uuid.UUID // 为nil时不限知识点
+	Query            string     // 在笔记正文与引文中查找，不区分大小写
+	Offset, Limit    int        // Limit为0时不分页
+}
+
+// AnnotationRepository 笔记与高亮仓储接口
+type AnnotationRepository interface {
+	// Create 创建批注
+	Create(ctx context.Context, annotation *entities.Annotation) error
+
+	// GetByID 根据ID获取批注
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Annotation, error)
+
+	// Update 更新批注的笔记正文与颜色
+	Update(ctx context.Context, annotation *entities.Annotation) error
+
+	// Delete 删除批注
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List 按条件获取用户的批注，按更新时间倒序，返回批注与总数
+	List(ctx context.Context, filter *AnnotationFilter) ([]*entities.Annotation, int64, error)
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// AttachmentRepository 附件仓储接口
+type AttachmentRepository interface {
+	// Create 创建附件记录
+	Create(ctx context.Context, attachment *entities.Attachment) error
+
+	// GetByID 根据ID获取附件
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Attachment, error)
+
+	// GetByIDs 根据ID批量获取附件
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Attachment, error)
+
+	// Delete 删除附件记录及其引用
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// AddReferences 添加引用，已存在的引用忽略；不存在的附件ID忽略
+	AddReferences(ctx context.Context, refType entities.AttachmentRefType, refID string, attachmentIDs []uuid.UUID) error
+
+	// ReplaceReferences 将引用方的引用替换为给定附件
+	ReplaceReferences(ctx context.Context, refType entities.AttachmentRefType, refID string, attachmentIDs []uuid.UUID) error
+
+	// GetOrphans 获取在指定时间前创建且无任何引用的附件
+	GetOrphans(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.Attachment, error)
+}
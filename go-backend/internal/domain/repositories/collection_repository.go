@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// CollectionRepository 收藏夹仓储接口
+type CollectionRepository interface {
+	// Create 创建收藏夹
+	Create(ctx context.Context, collection *entities.Collection) error
+
+	// GetByID 根据ID获取收藏夹
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Collection, error)
+
+	// GetByShareToken 根据分享令牌获取收藏夹
+	GetByShareToken(ctx context.Context, token string) (*entities.Collection, error)
+
+	// ListByUser 获取用户的全部收藏夹
+	ListByUser(ctx context.Context, userID uint) ([]*entities.Collection, error)
+
+	// CountItems 批量统计收藏夹的条目数
+	CountItems(ctx context.Context, collectionIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+
+	// Update 更新收藏夹名称与描述
+	Update(ctx context.Context, collection *entities.Collection) error
+
+	// SetShareToken 设置或清除分享令牌
+	SetShareToken(ctx context.Context, id uuid.UUID, token *string) error
+
+	// Delete 删除收藏夹及其条目
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetItems 按排列顺序获取收藏夹条目
+	GetItems(ctx context.Context, collectionID uuid.UUID) ([]*entities.CollectionItem, error)
+
+	// GetItem 获取收藏夹中的条目
+	GetItem(ctx context.Context, collectionID, itemID uuid.UUID) (*entities.CollectionItem, error)
+
+	// AddItem 将条目追加到收藏夹末尾；对象已在收藏夹中时返回已有条目
+	AddItem(ctx context.Context, item *entities.CollectionItem) (*entities.CollectionItem, error)
+
+	// RemoveItem 从收藏夹移除条目
+	RemoveItem(ctx context.Context, collectionID, itemID uuid.UUID) error
+
+	// ReorderItems 按给定的条目ID顺序重排收藏夹
+	ReorderItems(ctx context.Context, collectionID uuid.UUID, itemIDs []uuid.UUID) error
+
+	// ListContaining 获取用户收藏了指定对象的收藏夹ID
+	ListContaining(ctx context.Context, userID uint, targetType string, targetID uuid.UUID) ([]uuid.UUID, error)
+
+	// Clone 在同一事务中创建收藏夹并复制条目
+	Clone(ctx context.Context, collection *entities.Collection, items []*entities.CollectionItem) error
+}
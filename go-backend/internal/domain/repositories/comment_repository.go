@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// CommentRepository 知识点评论仓储接口
+type CommentRepository interface {
+	// Create 创建评论；为回复时在同一事务中增加父评论的回复数
+	Create(ctx context.Context, comment *entities.Comment) error
+
+	// GetByID 根据ID获取评论（含已删除的评论）
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error)
+
+	// Update 更新评论正文
+	Update(ctx context.Context, comment *entities.Comment) error
+
+	// SoftDelete 软删除评论：清空正文并记录删除时间与删除人，保留记录以维持讨论串结构
+	SoftDelete(ctx context.Context, comment *entities.Comment) error
+
+	// ListThreads 分页获取知识点的顶层评论，按创建时间倒序，返回评论与总数
+	ListThreads(ctx context.Context, knowledgePointID uuid.UUID, offset, limit int) ([]*entities.Comment, int64, error)
+
+	// GetByThreads 获取讨论串中的全部回复，按创建时间正序
+	GetByThreads(ctx context.Context, threadIDs []uuid.UUID) ([]*entities.Comment, error)
+
+	// ToggleLike 切换用户对评论的点赞，返回切换后的点赞状态与点赞数
+	ToggleLike(ctx context.Context, commentID uuid.UUID, userID uint) (bool, int, error)
+
+	// GetLikedIDs 获取指定评论中用户已点赞的评论ID
+	GetLikedIDs(ctx context.Context, userID uint, commentIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// PathEnrollmentStats 学习路径的报名统计
+type PathEnrollmentStats struct {
+	LearningPathID uuid.UUID
+	Enrolled       int64
+	Completed      int64
+}
+
+// EnrollmentProgressCount 报名记录的步骤完成情况
+type EnrollmentProgressCount struct {
+	EnrollmentID uuid.UUID
+	Total        int64
+	Completed    int64
+}
+
+// EnrollmentRepository 学习路径报名仓储接口
+type EnrollmentRepository interface {
+	// Create 在同一事务中创建报名记录及其步骤
+	Create(ctx context.Context, enrollment *entities.PathEnrollment, steps []*entities.PathEnrollmentStep) error
+
+	// Get 获取用户对学习路径的报名记录
+	Get(ctx context.Context, userID uint, pathID uuid.UUID) (*entities.PathEnrollment, error)
+
+	// ListByUser 获取用户的报名记录，status为空时不限状态
+	ListByUser(ctx context.Context, userID uint, status string) ([]*entities.PathEnrollment, error)
+
+	// Delete 删除报名记录及其步骤进度
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetSteps 按顺序获取报名记录的步骤进度
+	GetSteps(ctx context.Context, enrollmentID uuid.UUID) ([]*entities.PathEnrollmentStep, error)
+
+	// AddSteps 追加路径中新增的步骤；有新增步骤时已完成的报名恢复为学习中
+	AddSteps(ctx context.Context, enrollmentID uuid.UUID, steps []*entities.PathEnrollmentStep) error
+
+	// UpdateStep 在同一事务中保存步骤进度、累计学习时长并根据全部步骤的完成情况更新报名状态，返回更新后的报名记录
+	UpdateStep(ctx context.Context, step *entities.PathEnrollmentStep, addSeconds int) (*entities.PathEnrollment, error)
+
+	// CountProgress 批量统计报名记录的步骤总数与已完成数
+	CountProgress(ctx context.Context, enrollmentIDs []uuid.UUID) (map[uuid.UUID]*EnrollmentProgressCount, error)
+
+	// GetPathStats 批量统计学习路径的报名人数与完成人数
+	GetPathStats(ctx context.Context, pathIDs []uuid.UUID) (map[uuid.UUID]*PathEnrollmentStats, error)
+}
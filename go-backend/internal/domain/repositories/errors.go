@@ -0,0 +1,24 @@
+package repositories
+
+import "errors"
+
+// ErrNotFound 记录不存在；仓储未找到记录时返回的错误可通过errors.Is与其比较
+var ErrNotFound = errors.New("记录不存在")
+
+// notFoundError 保留具体提示信息的记录不存在错误
+type notFoundError string
+
+// Error 返回提示信息
+func (e notFoundError) Error() string {
+	return string(e)
+}
+
+// Is 与ErrNotFound视为同一错误
+func (e notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// NotFound 创建带提示信息的记录不存在错误
+func NotFound(message string) error {
+	return notFoundError(message)
+}
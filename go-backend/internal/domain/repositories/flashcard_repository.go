@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// FlashcardRepository 闪卡仓储接口
+type FlashcardRepository interface {
+	// Create 创建闪卡
+	Create(ctx context.Context, card *entities.Flashcard) error
+
+	// CreateBatch 批量创建闪卡
+	CreateBatch(ctx context.Context, cards []*entities.Flashcard) error
+
+	// GetByID 根据ID获取闪卡
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Flashcard, error)
+
+	// GetByIDs 根据ID批量获取闪卡
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Flashcard, error)
+
+	// GetByKnowledgePointID 获取知识点下的闪卡
+	GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.Flashcard, error)
+
+	// GetByKnowledgePointIDs 批量获取多个知识点下的闪卡
+	GetByKnowledgePointIDs(ctx context.Context, knowledgePointIDs []uuid.UUID) ([]*entities.Flashcard, error)
+
+	// Update 更新闪卡
+	Update(ctx context.Context, card *entities.Flashcard) error
+
+	// Delete 删除闪卡
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// FlashcardReviewRepository 闪卡复习调度仓储接口
+type FlashcardReviewRepository interface {
+	// GetByUserAndFlashcard 获取用户对某张闪卡的调度状态，不存在时返回nil
+	GetByUserAndFlashcard(ctx context.Context, userID uint, flashcardID uuid.UUID) (*entities.FlashcardReview, error)
+
+	// GetFlashcardIDsByUser 获取用户已加入复习计划的闪卡ID（限定在给定闪卡范围内）
+	GetFlashcardIDsByUser(ctx context.Context, userID uint, flashcardIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// GetByUserID 获取用户的全部调度状态
+	GetByUserID(ctx context.Context, userID uint) ([]*entities.FlashcardReview, error)
+
+	// GetDue 获取用户在指定时间前到期的闪卡
+	GetDue(ctx context.Context, userID uint, before time.Time, limit int) ([]*entities.FlashcardReview, error)
+
+	// CreateBatch 批量创建调度状态（已存在的记录将被忽略）
+	CreateBatch(ctx context.Context, reviews []*entities.FlashcardReview) error
+
+	// Save 保存调度状态
+	Save(ctx context.Context, review *entities.FlashcardReview) error
+
+	// DeleteByFlashcardID 删除闪卡的全部调度状态
+	DeleteByFlashcardID(ctx context.Context, flashcardID uuid.UUID) error
+}
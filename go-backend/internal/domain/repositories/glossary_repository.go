@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// GlossaryFilter 术语表筛选条件
+type GlossaryFilter struct {
+	Query    string // 按名称或释义模糊匹配
+	Category string
+	Offset   int
+	Limit    int
+}
+
+// GlossaryRepository 术语表仓储接口
+type GlossaryRepository interface {
+	// Create 创建术语
+	Create(ctx context.Context, term *entities.GlossaryTerm) error
+
+	// GetByID 根据ID获取术语
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.GlossaryTerm, error)
+
+	// Update 更新术语
+	Update(ctx context.Context, term *entities.GlossaryTerm) error
+
+	// Delete 删除术语
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List 按筛选条件分页获取术语，返回术语与总数
+	List(ctx context.Context, filter *GlossaryFilter) ([]*entities.GlossaryTerm, int64, error)
+
+	// GetAll 获取全部术语
+	GetAll(ctx context.Context) ([]*entities.GlossaryTerm, error)
+
+	// FindByNames 获取任一名称（标准名称、同义词或缩写）与给定规范化名称相同的术语
+	FindByNames(ctx context.Context, names []string) ([]*entities.GlossaryTerm, error)
+}
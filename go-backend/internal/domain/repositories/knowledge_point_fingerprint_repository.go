@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgePointFingerprintRepository 知识点查重指纹仓储接口
+type KnowledgePointFingerprintRepository interface {
+	// Save 创建或更新指纹
+	Save(ctx context.Context, fingerprint *entities.KnowledgePointFingerprint) error
+
+	// GetAll 获取全部指纹
+	GetAll(ctx context.Context) ([]*entities.KnowledgePointFingerprint, error)
+
+	// Delete 删除知识点的指纹
+	Delete(ctx context.Context, knowledgePointID uuid.UUID) error
+}
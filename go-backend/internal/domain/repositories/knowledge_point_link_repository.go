@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgePointLinkRepository 知识点关联仓储接口
+type KnowledgePointLinkRepository interface {
+	// ReplaceOutgoing 替换来源知识点的全部关联
+	ReplaceOutgoing(ctx context.Context, sourceID uuid.UUID, links []*entities.KnowledgePointLink) error
+
+	// GetOutgoing 获取知识点提及的其他知识点（按提及次数降序）
+	GetOutgoing(ctx context.Context, sourceID uuid.UUID) ([]*entities.KnowledgePointLink, error)
+
+	// GetIncoming 获取提及该知识点的反向链接（按提及次数降序）
+	GetIncoming(ctx context.Context, targetID uuid.UUID) ([]*entities.KnowledgePointLink, error)
+
+	// GetAll 获取全部关联
+	GetAll(ctx context.Context) ([]*entities.KnowledgePointLink, error)
+
+	// DeleteByKnowledgePoint 删除知识点作为来源或目标的全部关联
+	DeleteByKnowledgePoint(ctx context.Context, id uuid.UUID) error
+
+	// MergeInto 将被合并知识点作为来源或目标的关联改为指向保留的知识点；
+	// 保留的知识点已有相同关联时保留原关联，合并后成为自身关联的记录被删除
+	MergeInto(ctx context.Context, survivorID, duplicateID uuid.UUID) error
+}
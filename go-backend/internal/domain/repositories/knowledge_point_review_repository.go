@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgePointReviewRepository 知识点审核记录仓储接口
+type KnowledgePointReviewRepository interface {
+	// Create 创建审核记录
+	Create(ctx context.Context, review *entities.KnowledgePointReview) error
+
+	// GetByKnowledgePointID 获取知识点的审核记录（按时间正序）
+	GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.KnowledgePointReview, error)
+}
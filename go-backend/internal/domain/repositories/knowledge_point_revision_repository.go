@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgePointRevisionRepository 知识点修订版本仓储接口
+type KnowledgePointRevisionRepository interface {
+	// Create 创建修订版本，版本号自动递增
+	Create(ctx context.Context, revision *entities.KnowledgePointRevision) error
+
+	// GetLatest 获取知识点的最新修订版本，不存在时返回nil
+	GetLatest(ctx context.Context, knowledgePointID uuid.UUID) (*entities.KnowledgePointRevision, error)
+
+	// GetByVersion 根据版本号获取修订版本
+	GetByVersion(ctx context.Context, knowledgePointID uuid.UUID, version int) (*entities.KnowledgePointRevision, error)
+
+	// GetByKnowledgePointID 获取知识点的全部修订版本（按版本号倒序）
+	GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.KnowledgePointRevision, error)
+}
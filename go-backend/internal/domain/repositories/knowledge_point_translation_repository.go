@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgePointTranslationRepository 知识点译文仓储接口
+type KnowledgePointTranslationRepository interface {
+	// Save 创建或更新译文，并更新其全文检索向量
+	Save(ctx context.Context, translation *entities.KnowledgePointTranslation) error
+
+	// Get 获取知识点指定语言的译文，不存在时返回nil
+	Get(ctx context.Context, knowledgePointID uuid.UUID, locale string) (*entities.KnowledgePointTranslation, error)
+
+	// GetByKnowledgePoint 获取知识点的全部译文
+	GetByKnowledgePoint(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.KnowledgePointTranslation, error)
+
+	// GetPublishedByKnowledgePoints 批量获取知识点已发布的译文
+	GetPublishedByKnowledgePoints(ctx context.Context, knowledgePointIDs []uuid.UUID) ([]*entities.KnowledgePointTranslation, error)
+
+	// GetLocales 获取已有已发布译文的全部语言
+	GetLocales(ctx context.Context) ([]string, error)
+
+	// Delete 删除知识点指定语言的译文
+	Delete(ctx context.Context, knowledgePointID uuid.UUID, locale string) error
+
+	// Search 在指定语言已发布的译文中全文检索已发布的知识点，按相关度排序，返回命中结果与总数；
+	// 命中结果的Point字段为已覆盖译文的知识点
+	Search(ctx context.Context, locale, query string, synonyms map[string][]string, offset, limit int) ([]*KnowledgePointSearchHit, int64, error)
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// LearnerKnowledgeState 学习者在某一知识点上的学习状态，由其闪卡复习、学习路径步骤完成与评分记录汇总
+type LearnerKnowledgeState struct {
+	UserID           uint
+	KnowledgePointID uuid.UUID
+	FirstStudiedAt   time.Time // 首次复习闪卡、完成学习路径步骤或评分的时间
+	MinInterval      int       // 各闪卡复习间隔的最小值(天)，未复习闪卡时为0
+	AvgEase          float64   // 各闪卡难度系数的平均值，反映复习评分，未复习闪卡时为0
+	Lapses           int       // 遗忘次数合计
+	Rating           int       // 学习者对知识点的评分（1-5），未评分时为0
+}
+
+// KnowledgePointPopularity 知识点的学习人数
+type KnowledgePointPopularity struct {
+	KnowledgePointID uuid.UUID
+	Learners         int
+}
+
+// KnowledgeRecommendationRepository 知识点推荐仓储接口
+type KnowledgeRecommendationRepository interface {
+	// GetLearnerStates 获取学习者的知识点学习状态，按用户与首次学习时间排序；userID为nil时获取全部学习者
+	GetLearnerStates(ctx context.Context, userID *uint) ([]*LearnerKnowledgeState, error)
+
+	// ReplaceTransitions 在同一事务中以新的计算结果替换全部转移统计
+	ReplaceTransitions(ctx context.Context, transitions []*entities.KnowledgePointTransition) error
+
+	// GetTransitionsFrom 获取从任一源知识点出发的转移统计
+	GetTransitionsFrom(ctx context.Context, sourceIDs []uuid.UUID) ([]*entities.KnowledgePointTransition, error)
+
+	// GetPopular 获取学习人数最多的知识点
+	GetPopular(ctx context.Context, limit int) ([]*KnowledgePointPopularity, error)
+
+	// GetLastComputedAt 获取转移统计的最近计算时间，从未计算时返回nil
+	GetLastComputedAt(ctx context.Context) (*time.Time, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"sical-go-backend/internal/domain/entities"
@@ -63,6 +64,9 @@ type LearningPathRepository interface {
 	// GetByGoalID 根据目标ID获取学习路径
 	GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.LearningPath, error)
 
+	// GetByIDs 根据ID批量获取学习路径（不含关联）
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.LearningPath, error)
+
 	// Update 更新学习路径
 	Update(ctx context.Context, path *entities.LearningPath) error
 
@@ -71,6 +75,59 @@ type LearningPathRepository interface {
 
 	// UpdateStatus 更新学习路径状态
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+
+	// ListPublic 获取公开的学习路径（标题或描述匹配query），不含关联
+	ListPublic(ctx context.Context, query string, offset, limit int) ([]*entities.LearningPath, int64, error)
+
+	// SetPublic 设置学习路径是否公开
+	SetPublic(ctx context.Context, id uuid.UUID, public bool) error
+}
+
+// KnowledgePointSearchHit 知识点检索命中结果
+type KnowledgePointSearchHit struct {
+	Point *entities.KnowledgePoint
+	Rank  float64
+}
+
+// 知识点目录排序字段
+const (
+	KnowledgePointSortCreatedAt = "created_at"
+	KnowledgePointSortUpdatedAt = "updated_at"
+	KnowledgePointSortTitle     = "title"
+	KnowledgePointSortRating    = "average_rating"
+)
+
+// KnowledgePointCursor 知识点目录分页游标，记录上一页最后一条的排序值与ID
+type KnowledgePointCursor struct {
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// KnowledgePointFilter 知识点目录筛选条件，同一维度内为或，不同维度之间为且
+type KnowledgePointFilter struct {
+	Categories      []string
+	Difficulties    []string
+	Tags            []string
+	HasPrerequisite *bool
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	SortBy          string
+	SortDesc        bool
+	Cursor          *KnowledgePointCursor
+	Limit           int
+}
+
+// KnowledgePointPage 知识点目录分页结果
+type KnowledgePointPage struct {
+	Points     []*entities.KnowledgePoint
+	Total      int64
+	NextCursor *KnowledgePointCursor
+}
+
+// KnowledgePointFacets 知识点目录分面统计
+type KnowledgePointFacets struct {
+	Categories   map[string]int64 `json:"categories"`
+	Difficulties map[string]int64 `json:"difficulties"`
 }
 
 // KnowledgePointRepository 知识点仓储接口
@@ -81,11 +138,24 @@ type KnowledgePointRepository interface {
 	// GetByID 根据ID获取知识点
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error)
 
-	// GetByCategory 根据类别获取知识点
+	// GetByIDForUpdate 获取知识点并锁定该行直至当前事务结束，须在事务中调用
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error)
+
+	// GetByCategory 根据类别获取已发布的知识点
 	GetByCategory(ctx context.Context, category string) ([]*entities.KnowledgePoint, error)
 
-	// Search 搜索知识点
-	Search(ctx context.Context, keyword string) ([]*entities.KnowledgePoint, error)
+	// Search 全文检索已发布的知识点，按相关度排序，返回命中结果与总数；
+	// synonyms为以规范化词为键的同义词表，命中的查询词扩展为同义词之间的或
+	Search(ctx context.Context, query string, synonyms map[string][]string, offset, limit int) ([]*KnowledgePointSearchHit, int64, error)
+
+	// List 按筛选条件分页获取已发布的知识点目录
+	List(ctx context.Context, filter *KnowledgePointFilter) (*KnowledgePointPage, error)
+
+	// GetFacets 获取分面统计，每个维度的统计忽略该维度自身的筛选条件
+	GetFacets(ctx context.Context, filter *KnowledgePointFilter) (*KnowledgePointFacets, error)
+
+	// RebuildSearchIndex 重建全部知识点的全文检索向量
+	RebuildSearchIndex(ctx context.Context) (int64, error)
 
 	// Update 更新知识点
 	Update(ctx context.Context, point *entities.KnowledgePoint) error
@@ -93,6 +163,40 @@ type KnowledgePointRepository interface {
 	// Delete 删除知识点
 	Delete(ctx context.Context, id uuid.UUID) error
 
-	// GetByDifficulty 根据难度获取知识点
+	// GetByDifficulty 根据难度获取已发布的知识点
 	GetByDifficulty(ctx context.Context, difficulty string) ([]*entities.KnowledgePoint, error)
+
+	// GetByAuthor 获取用户创建的全部知识点（不限状态）
+	GetByAuthor(ctx context.Context, authorID uint) ([]*entities.KnowledgePoint, error)
+
+	// GetByIDs 根据ID批量获取知识点（不限状态）
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.KnowledgePoint, error)
+
+	// GetByExternalKeys 根据外部键批量获取知识点（不限状态）
+	GetByExternalKeys(ctx context.Context, keys []string) ([]*entities.KnowledgePoint, error)
+
+	// GetByTitles 根据标题批量获取知识点（不限状态）
+	GetByTitles(ctx context.Context, titles []string) ([]*entities.KnowledgePoint, error)
+
+	// GetMentionIndex 获取全部知识点的ID、标题与别名，用于识别内容中的提及
+	GetMentionIndex(ctx context.Context) ([]*entities.KnowledgePoint, error)
+
+	// GetByPrerequisite 获取前置知识点中包含指定知识点的知识点（不限状态）
+	GetByPrerequisite(ctx context.Context, prerequisiteID uuid.UUID) ([]*entities.KnowledgePoint, error)
+
+	// MergeInto 在同一事务中将被合并知识点的闪卡、评论、评分、译文、笔记、收藏条目、报名学习进度、学习路径关联与模板步骤
+	// 迁移到保留的知识点，重新计算保留知识点的平均评分，并删除被合并的知识点；其他知识点的前置引用由调用方随修订版本一并改写
+	MergeInto(ctx context.Context, survivorID, duplicateID uuid.UUID) error
+
+	// GetGraphNodes 获取全部知识点的图谱字段（不含内容），用于知识图谱查询
+	GetGraphNodes(ctx context.Context) ([]*entities.KnowledgePoint, error)
+
+	// GetByContentMentions 获取内容中包含任一名称的知识点（不区分大小写，不限状态）
+	GetByContentMentions(ctx context.Context, terms []string) ([]*entities.KnowledgePoint, error)
+
+	// GetByStatus 根据状态获取知识点
+	GetByStatus(ctx context.Context, status string) ([]*entities.KnowledgePoint, error)
+
+	// GetPublishedVersion 获取已发布知识点的数量与最近更新时间，用于判断进程内派生索引是否过期
+	GetPublishedVersion(ctx context.Context) (int64, time.Time, error)
 }
\ No newline at end of file
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// LearningPathTemplateFilter 模板目录筛选条件
+type LearningPathTemplateFilter struct {
+	Category   string
+	Difficulty string
+	Status     string // 为空时不限状态
+	Query      string // 匹配标题与描述
+	Offset     int
+	Limit      int
+}
+
+// LearningPathTemplateRepository 学习路径模板仓储接口
+type LearningPathTemplateRepository interface {
+	// Create 创建模板及其步骤
+	Create(ctx context.Context, template *entities.LearningPathTemplate) error
+
+	// GetByID 根据ID获取模板，步骤按顺序排列
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.LearningPathTemplate, error)
+
+	// List 按条件获取模板，步骤按顺序排列
+	List(ctx context.Context, filter *LearningPathTemplateFilter) ([]*entities.LearningPathTemplate, int64, error)
+
+	// Update 在同一事务中更新模板并替换全部步骤
+	Update(ctx context.Context, template *entities.LearningPathTemplate) error
+
+	// Delete 删除模板，已实例化的学习路径不受影响
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Instantiate 在同一事务中创建学习路径、关联知识点并累计模板的实例化次数；
+	// knowledgePointIDs[i]为paths[i]按顺序关联的知识点
+	Instantiate(ctx context.Context, templateID uuid.UUID, paths []*entities.LearningPath, knowledgePointIDs [][]uuid.UUID) error
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// ReportedTarget 审核队列中的被举报对象，同一对象的待处理举报合并为一项
+type ReportedTarget struct {
+	TargetType      string    `json:"target_type"`
+	TargetID        uuid.UUID `json:"target_id"`
+	ReportCount     int64     `json:"report_count"`
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	LastReportedAt  time.Time `json:"last_reported_at"`
+}
+
+// ModerationActionFilter 审核记录查询条件
+type ModerationActionFilter struct {
+	ModeratorID   *uint
+	SubjectUserID *uint
+	TargetType    string
+	TargetID      *uuid.UUID
+	Offset, Limit int
+}
+
+// ModerationRepository 举报与审核记录仓储接口
+type ModerationRepository interface {
+	// CreateReport 创建举报
+	CreateReport(ctx context.Context, report *entities.Report) error
+
+	// GetReport 根据ID获取举报
+	GetReport(ctx context.Context, id uuid.UUID) (*entities.Report, error)
+
+	// HasOpenReport 检查用户是否已举报对象且尚未处理
+	HasOpenReport(ctx context.Context, targetType string, targetID uuid.UUID, reporterID uint) (bool, error)
+
+	// ListReportsByReporter 分页获取用户提交的举报，按创建时间倒序，返回举报与总数
+	ListReportsByReporter(ctx context.Context, reporterID uint, offset, limit int) ([]*entities.Report, int64, error)
+
+	// ListReportedTargets 分页获取有待处理举报的对象，按举报数与最早举报时间排序，返回对象与总数；
+	// targetType为空时不限对象类型
+	ListReportedTargets(ctx context.Context, targetType string, offset, limit int) ([]*ReportedTarget, int64, error)
+
+	// GetOpenReports 获取对象的全部待处理举报，按创建时间正序
+	GetOpenReports(ctx context.Context, targetType string, targetIDs []uuid.UUID) ([]*entities.Report, error)
+
+	// ResolveReports 在同一事务中将对象的全部待处理举报标记为status，并记录审核操作及其处理的举报数
+	ResolveReports(ctx context.Context, action *entities.ModerationAction, status entities.ReportStatus) error
+
+	// ListActions 按条件分页获取审核记录，按创建时间倒序，返回记录与总数
+	ListActions(ctx context.Context, filter *ModerationActionFilter) ([]*entities.ModerationAction, int64, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// NotificationRepository 站内通知仓储接口
+type NotificationRepository interface {
+	// CreateBatch 批量创建通知
+	CreateBatch(ctx context.Context, notifications []*entities.Notification) error
+
+	// ListByUser 分页获取用户的通知，按创建时间倒序，返回通知与总数
+	ListByUser(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]*entities.Notification, int64, error)
+
+	// CountUnread 获取用户的未读通知数
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+
+	// MarkRead 将用户的指定通知标记为已读，返回更新数量
+	MarkRead(ctx context.Context, userID uint, ids []uuid.UUID) (int64, error)
+
+	// MarkAllRead 将用户的全部通知标记为已读，返回更新数量
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// RatingFilter 评价查询条件
+type RatingFilter struct {
+	TargetType    string     // 为空时不限对象类型
+	TargetID      *uuid.UUID // 为nil时不限对象
+	Hidden        *bool      // 为nil时不限隐藏状态
+	WithReview    bool       // 仅包含文字评价
+	Offset, Limit int
+}
+
+// RatingSummary 评分汇总
+type RatingSummary struct {
+	Average      float64       `json:"average"`
+	Count        int64         `json:"count"`
+	Distribution map[int]int64 `json:"distribution"` // 各星级的评分人数
+}
+
+// RatingRepository 评分仓储接口；写操作在同一事务中更新对象上的平均评分与评分人数
+type RatingRepository interface {
+	// Save 创建或更新用户对对象的评分
+	Save(ctx context.Context, rating *entities.Rating) error
+
+	// GetByID 根据ID获取评分
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Rating, error)
+
+	// GetByUser 获取用户对对象的评分，不存在时返回nil
+	GetByUser(ctx context.Context, targetType string, targetID uuid.UUID, userID uint) (*entities.Rating, error)
+
+	// List 按条件分页获取评价，按更新时间倒序，返回评价与总数
+	List(ctx context.Context, filter *RatingFilter) ([]*entities.Rating, int64, error)
+
+	// GetSummary 获取对象未隐藏评分的汇总
+	GetSummary(ctx context.Context, targetType string, targetID uuid.UUID) (*RatingSummary, error)
+
+	// Delete 删除评分
+	Delete(ctx context.Context, rating *entities.Rating) error
+
+	// SetHidden 隐藏或恢复评价
+	SetHidden(ctx context.Context, rating *entities.Rating) error
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "context"
+
+// TransactionManager 事务管理器；fn中以传入的ctx调用仓储方法时共享同一数据库事务
+type TransactionManager interface {
+	// WithinTransaction 在事务中执行fn，fn返回错误时回滚；已处于事务中时嵌套执行
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -22,6 +22,8 @@ type UserRepository interface {
 	Search(ctx context.Context, keyword string, offset, limit int) ([]*entities.User, int64, error)
 	GetByRole(ctx context.Context, role string, offset, limit int) ([]*entities.User, int64, error)
 	GetByStatus(ctx context.Context, status string, offset, limit int) ([]*entities.User, int64, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]*entities.User, error)
+	GetByUsernames(ctx context.Context, usernames []string) ([]*entities.User, error)
 
 	// 验证操作
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
@@ -0,0 +1,573 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/anki"
+	"sical-go-backend/pkg/cloze"
+	"sical-go-backend/pkg/imaging"
+	"sical-go-backend/pkg/logger"
+)
+
+// ankiRootDeck 导出牌组的根名称
+const ankiRootDeck = "SiCal"
+
+// markdownAttachmentImageRegex 匹配内容中以Markdown图片语法引用的附件
+var markdownAttachmentImageRegex = regexp.MustCompile(`!\[([^\]]*)\]\(attachment:([0-9a-fA-F-]{36})\)`)
+
+// AnkiService Anki牌组导入导出服务
+type AnkiService struct {
+	flashcardRepo  repositories.FlashcardRepository
+	reviewRepo     repositories.FlashcardReviewRepository
+	knowledgeRepo  repositories.KnowledgePointRepository
+	pathRepo       repositories.LearningPathRepository
+	enrollmentRepo repositories.EnrollmentRepository
+	attachmentRepo repositories.AttachmentRepository
+	txManager      repositories.TransactionManager
+
+	knowledgePointService *KnowledgePointService
+	attachmentService     *AttachmentService
+}
+
+// NewAnkiService 创建Anki牌组导入导出服务
+func NewAnkiService(
+	flashcardRepo repositories.FlashcardRepository,
+	reviewRepo repositories.FlashcardReviewRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	pathRepo repositories.LearningPathRepository,
+	enrollmentRepo repositories.EnrollmentRepository,
+	attachmentRepo repositories.AttachmentRepository,
+	knowledgePointService *KnowledgePointService,
+	attachmentService *AttachmentService,
+	txManager repositories.TransactionManager,
+) *AnkiService {
+	return &AnkiService{
+		flashcardRepo:         flashcardRepo,
+		reviewRepo:            reviewRepo,
+		knowledgeRepo:         knowledgeRepo,
+		pathRepo:              pathRepo,
+		enrollmentRepo:        enrollmentRepo,
+		attachmentRepo:        attachmentRepo,
+		txManager:             txManager,
+		knowledgePointService: knowledgePointService,
+		attachmentService:     attachmentService,
+	}
+}
+
+// AnkiImportRequest Anki导入请求
+type AnkiImportRequest struct {
+	Actor      *KnowledgePointActor
+	Category   string // 导入卡片所属的知识点类别
+	Difficulty string // 新建知识点使用的难度
+}
+
+// AnkiImportResult Anki导入结果
+type AnkiImportResult struct {
+	Notes                  int `json:"notes"`
+	CardsImported          int `json:"cards_imported"`
+	CardsSkipped           int `json:"cards_skipped"`
+	KnowledgePointsCreated int `json:"knowledge_points_created"`
+	MediaImported          int `json:"media_imported"` // 卡片引用并保存为附件的媒体文件数
+	MediaSkipped           int `json:"media_skipped"`  // 未被引用、超出大小上限或类型不支持的媒体文件数
+}
+
+// ExportUserFlashcards 导出用户复习计划中仍可查看的闪卡（含调度状态）
+func (s *AnkiService) ExportUserFlashcards(ctx context.Context, actor *KnowledgePointActor, w io.Writer) error {
+	if actor == nil {
+		return ErrFlashcardForbidden
+	}
+	reviews, err := s.reviewRepo.GetByUserID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+
+	cards := make([]*entities.Flashcard, 0, len(reviews))
+	schedules := make(map[uuid.UUID]*entities.FlashcardReview, len(reviews))
+	for _, review := range reviews {
+		card := review.Flashcard
+		cards = append(cards, &card)
+		schedules[card.ID] = review
+	}
+
+	return s.export(ctx, actor, cards, schedules, w)
+}
+
+// ExportLearningPathFlashcards 导出学习路径下可查看知识点的闪卡，附带该用户的调度状态；
+// 仅公开路径或操作者已报名的路径可导出，其余按不存在处理
+func (s *AnkiService) ExportLearningPathFlashcards(ctx context.Context, pathID uuid.UUID, actor *KnowledgePointActor, w io.Writer) error {
+	if actor == nil {
+		return ErrFlashcardForbidden
+	}
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return fmt.Errorf("获取学习路径失败: %w", err)
+	}
+	if err := s.ensurePathReadable(ctx, path, actor); err != nil {
+		return err
+	}
+
+	var cards []*entities.Flashcard
+	for _, point := range path.KnowledgePoints {
+		pointCards, err := s.flashcardRepo.GetByKnowledgePointID(ctx, point.ID)
+		if err != nil {
+			return err
+		}
+		cards = append(cards, pointCards...)
+	}
+
+	reviews, err := s.reviewRepo.GetByUserID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	schedules := make(map[uuid.UUID]*entities.FlashcardReview, len(reviews))
+	for _, review := range reviews {
+		schedules[review.FlashcardID] = review
+	}
+
+	return s.export(ctx, actor, cards, schedules, w)
+}
+
+// ensurePathReadable 检查操作者可导出学习路径：公开路径、已报名的路径，或审核人员；
+// 学习目标以UUID记录用户，无法与账号对应，因此以报名记录判断归属
+func (s *AnkiService) ensurePathReadable(ctx context.Context, path *entities.LearningPath, actor *KnowledgePointActor) error {
+	if path.IsPublic || actor.IsModerator() {
+		return nil
+	}
+	if _, err := s.enrollmentRepo.Get(ctx, actor.UserID, path.ID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return repositories.NotFound("学习路径不存在")
+		}
+		return err
+	}
+	return nil
+}
+
+// ImportPackage 导入.apkg牌组，每个子牌组对应类别下的一个知识点
+func (s *AnkiService) ImportPackage(ctx context.Context, req *AnkiImportRequest, r io.ReaderAt, size int64) (*AnkiImportResult, error) {
+	if req.Actor == nil {
+		return nil, ErrKnowledgePointForbidden
+	}
+	userID := req.Actor.UserID
+	pkg, err := anki.Read(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// 匹配类别下已发布的知识点，以及用户自己创建的草稿
+	points, err := s.knowledgeRepo.GetByCategory(ctx, req.Category)
+	if err != nil {
+		return nil, err
+	}
+	ownPoints, err := s.knowledgeRepo.GetByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pointsByTitle := make(map[string]*entities.KnowledgePoint, len(points)+len(ownPoints))
+	for _, point := range points {
+		pointsByTitle[point.Title] = point
+	}
+	for _, point := range ownPoints {
+		if point.Category == req.Category {
+			pointsByTitle[point.Title] = point
+		}
+	}
+
+	result := &AnkiImportResult{Notes: len(pkg.Notes)}
+	existingKeys := make(map[uuid.UUID]map[string]bool)
+	now := time.Now()
+
+	var newPoints []*entities.KnowledgePoint
+	var cards []*entities.Flashcard
+	var reviews []*entities.FlashcardReview
+	for _, note := range pkg.Notes {
+		title := s.deckLeaf(note.Deck)
+		point, ok := pointsByTitle[title]
+		switch {
+		case !ok:
+			point = &entities.KnowledgePoint{
+				ID:            uuid.New(),
+				Title:         title,
+				Category:      req.Category,
+				Difficulty:    req.Difficulty,
+				Resources:     "[]",
+				Prerequisites: "[]",
+			}
+			pointsByTitle[title] = point
+			existingKeys[point.ID] = make(map[string]bool)
+			newPoints = append(newPoints, point)
+		case !s.knowledgePointService.CanEdit(point, req.Actor):
+			// 同名知识点已存在但无权编辑，拒绝导入而不是创建同名知识点
+			return nil, fmt.Errorf("%w: %s", ErrFlashcardForbidden, title)
+		}
+
+		keys, ok := existingKeys[point.ID]
+		if !ok {
+			keys, err = s.flashcardKeys(ctx, point.ID)
+			if err != nil {
+				return nil, err
+			}
+			existingKeys[point.ID] = keys
+		}
+
+		for _, ankiCard := range note.Cards {
+			card := s.noteToFlashcard(note, ankiCard, point.ID, userID)
+			if card == nil || keys[s.flashcardKey(card)] {
+				result.CardsSkipped++
+				continue
+			}
+			keys[s.flashcardKey(card)] = true
+			cards = append(cards, card)
+			if !ankiCard.Suspended {
+				reviews = append(reviews, s.scheduleFromAnki(userID, card.ID, ankiCard, now))
+			}
+		}
+	}
+
+	// 存储不参与数据库事务，媒体先保存为附件；事务失败时附件未被引用，由清理任务回收
+	result.MediaImported, err = s.importMedia(ctx, userID, pkg.Media, cards)
+	if err != nil {
+		return nil, err
+	}
+	result.MediaSkipped = pkg.MediaCount - result.MediaImported
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, point := range newPoints {
+			if err := s.knowledgePointService.CreateKnowledgePoint(ctx, point, req.Actor); err != nil {
+				return err
+			}
+		}
+		if err := s.flashcardRepo.CreateBatch(ctx, cards); err != nil {
+			return err
+		}
+		if err := s.reviewRepo.CreateBatch(ctx, reviews); err != nil {
+			return err
+		}
+		for _, card := range cards {
+			ids := entities.ExtractAttachmentIDs(card.Front + "\n" + card.Back)
+			if len(ids) == 0 {
+				continue
+			}
+			if err := s.attachmentRepo.AddReferences(ctx, entities.AttachmentRefFlashcard, card.ID.String(), ids); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.CardsImported = len(cards)
+	result.KnowledgePointsCreated = len(newPoints)
+
+	logger.Info("Anki牌组导入完成",
+		logger.Uint("user_id", userID),
+		logger.String("category", req.Category),
+		logger.Int("cards_imported", result.CardsImported),
+		logger.Int("cards_skipped", result.CardsSkipped),
+		logger.Int("media_imported", result.MediaImported))
+
+	return result, nil
+}
+
+// importMedia 将卡片引用的媒体文件保存为附件，并把卡片中的引用替换为附件URI，返回保存的附件数；
+// 类型不支持或超出大小上限的文件保留原引用
+func (s *AnkiService) importMedia(ctx context.Context, userID uint, media map[string][]byte, cards []*entities.Flashcard) (int, error) {
+	uris := make(map[string]string)
+	imported := 0
+	var saveErr error
+	replace := func(name string) string {
+		if uri, ok := uris[name]; ok || saveErr != nil {
+			return uri
+		}
+		uris[name] = ""
+		data, ok := media[name]
+		if !ok {
+			return ""
+		}
+		attachment, err := s.attachmentService.save(ctx, userID, name, data, false, thumbnailMaxSize)
+		switch {
+		case err == nil:
+			uris[name] = attachment.URI()
+			imported++
+		case errors.Is(err, ErrUnsupportedAttachmentType), errors.Is(err, ErrAttachmentTooLarge), errors.Is(err, imaging.ErrTooLarge):
+			logger.Warn("跳过Anki媒体文件", logger.String("filename", name), logger.String("error", err.Error()))
+		default:
+			saveErr = err
+		}
+		return uris[name]
+	}
+
+	for _, card := range cards {
+		card.Front = anki.ReplaceMediaRefs(card.Front, replace)
+		card.Back = anki.ReplaceMediaRefs(card.Back, replace)
+	}
+	return imported, saveErr
+}
+
+// export 将操作者可查看的闪卡转换为Anki笔记并写出，卡片引用的附件一并写入牌组媒体
+func (s *AnkiService) export(ctx context.Context, actor *KnowledgePointActor, cards []*entities.Flashcard, schedules map[uuid.UUID]*entities.FlashcardReview, w io.Writer) error {
+	points := make(map[uuid.UUID]*entities.KnowledgePoint)
+	visible := make([]*entities.Flashcard, 0, len(cards))
+	for _, card := range cards {
+		point, ok := points[card.KnowledgePointID]
+		if !ok {
+			var err error
+			point, err = s.knowledgeRepo.GetByID(ctx, card.KnowledgePointID)
+			if err != nil {
+				return fmt.Errorf("获取知识点失败: %w", err)
+			}
+			points[card.KnowledgePointID] = point
+		}
+		if s.knowledgePointService.CanView(point, actor) {
+			visible = append(visible, card)
+		}
+	}
+
+	media, mediaNames, err := s.exportMedia(ctx, visible)
+	if err != nil {
+		return err
+	}
+
+	clozeNotes := make(map[string]int)
+	var notes []anki.Note
+	for _, card := range visible {
+		point := points[card.KnowledgePointID]
+		fields := []string{s.exportField(card.Front, mediaNames), s.exportField(card.Back, mediaNames)}
+		ankiCard := s.scheduleToAnki(card, schedules[card.ID])
+
+		// 同一段挖空文本的多张卡片合并为一条cloze笔记
+		if card.IsCloze() {
+			key := card.KnowledgePointID.String() + "|" + card.Front
+			if i, ok := clozeNotes[key]; ok {
+				notes[i].Cards = append(notes[i].Cards, ankiCard)
+				continue
+			}
+			clozeNotes[key] = len(notes)
+			notes = append(notes, anki.Note{
+				GUID:   anki.GUID(key),
+				Cloze:  true,
+				Fields: fields,
+				Tags:   s.noteTags(point),
+				Deck:   s.deckName(point),
+				Cards:  []anki.Card{ankiCard},
+			})
+			continue
+		}
+
+		notes = append(notes, anki.Note{
+			GUID:   anki.GUID(card.ID.String()),
+			Fields: fields,
+			Tags:   s.noteTags(point),
+			Deck:   s.deckName(point),
+			Cards:  []anki.Card{ankiCard},
+		})
+	}
+
+	return anki.Write(w, &anki.Package{Notes: notes, Media: media})
+}
+
+// exportMedia 读取卡片引用的附件，返回牌组媒体及附件ID到媒体文件名的映射；已删除的附件被忽略
+func (s *AnkiService) exportMedia(ctx context.Context, cards []*entities.Flashcard) (map[string][]byte, map[uuid.UUID]string, error) {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, card := range cards {
+		for _, id := range entities.ExtractAttachmentIDs(card.Front + "\n" + card.Back) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	media := make(map[string][]byte)
+	names := make(map[uuid.UUID]string)
+	if len(ids) == 0 {
+		return media, names, nil
+	}
+
+	attachments, err := s.attachmentRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, attachment := range attachments {
+		data, err := s.attachmentService.readContent(ctx, attachment)
+		if err != nil {
+			return nil, nil, err
+		}
+		ext, ok := attachmentExtensions[attachment.ContentType]
+		if !ok {
+			ext = filepath.Ext(attachment.Filename)
+		}
+		name := attachment.ID.String() + ext
+		media[name] = data
+		names[attachment.ID] = name
+	}
+	return media, names, nil
+}
+
+// exportField 将字段中的附件引用替换为牌组媒体文件名，Markdown图片转换为<img>标签
+func (s *AnkiService) exportField(field string, mediaNames map[uuid.UUID]string) string {
+	field = markdownAttachmentImageRegex.ReplaceAllStringFunc(field, func(match string) string {
+		parts := markdownAttachmentImageRegex.FindStringSubmatch(match)
+		id, err := uuid.Parse(parts[2])
+		if err != nil || mediaNames[id] == "" {
+			return match
+		}
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, mediaNames[id], html.EscapeString(parts[1]))
+	})
+	for id, name := range mediaNames {
+		field = strings.ReplaceAll(field, entities.AttachmentURIPrefix+id.String(), name)
+	}
+	return field
+}
+
+// scheduleToAnki 将调度状态转换为Anki卡片
+func (s *AnkiService) scheduleToAnki(card *entities.Flashcard, review *entities.FlashcardReview) anki.Card {
+	ankiCard := anki.Card{State: anki.CardStateNew}
+	if card.IsCloze() {
+		ankiCard.Ord = card.ClozeIndex - 1
+	}
+	if review == nil || review.LastReviewedAt == nil {
+		return ankiCard
+	}
+
+	ankiCard.DueAt = review.DueAt
+	ankiCard.Interval = review.Interval
+	ankiCard.EaseFactor = review.EaseFactor
+	ankiCard.Reps = review.Repetitions
+	ankiCard.Lapses = review.Lapses
+	if review.Interval > 0 {
+		ankiCard.State = anki.CardStateReview
+	} else {
+		ankiCard.State = anki.CardStateLearning
+	}
+	return ankiCard
+}
+
+// scheduleFromAnki 根据Anki卡片创建调度状态
+func (s *AnkiService) scheduleFromAnki(userID uint, flashcardID uuid.UUID, card anki.Card, now time.Time) *entities.FlashcardReview {
+	review := &entities.FlashcardReview{
+		ID:          uuid.New(),
+		FlashcardID: flashcardID,
+		UserID:      userID,
+		EaseFactor:  defaultEaseFactor,
+		DueAt:       now,
+	}
+	if card.State == anki.CardStateNew {
+		return review
+	}
+
+	if card.EaseFactor >= minEaseFactor {
+		review.EaseFactor = card.EaseFactor
+	}
+	review.Repetitions = card.Reps
+	review.Lapses = card.Lapses
+	review.LastReviewedAt = &now
+	if !card.DueAt.IsZero() {
+		review.DueAt = card.DueAt
+	}
+	if card.State == anki.CardStateReview {
+		review.Interval = card.Interval
+	}
+	return review
+}
+
+// noteToFlashcard 将Anki笔记中的一张卡片转换为闪卡，无法转换时返回nil
+func (s *AnkiService) noteToFlashcard(note anki.Note, card anki.Card, knowledgePointID uuid.UUID, userID uint) *entities.Flashcard {
+	fields := make([]string, 2)
+	copy(fields, note.Fields)
+
+	flashcard := &entities.Flashcard{
+		ID:               uuid.New(),
+		KnowledgePointID: knowledgePointID,
+		CardType:         string(entities.FlashcardTypeBasic),
+		Front:            fields[0],
+		Back:             fields[1],
+		CreatedBy:        userID,
+	}
+
+	switch {
+	case note.Cloze:
+		flashcard.CardType = string(entities.FlashcardTypeCloze)
+		flashcard.ClozeIndex = card.Ord + 1
+		if !cloze.HasOrdinal(flashcard.Front, flashcard.ClozeIndex) {
+			return nil
+		}
+	case card.Ord == 1:
+		// "正反面"笔记类型的反向卡片
+		flashcard.Front, flashcard.Back = fields[1], fields[0]
+	case card.Ord > 1:
+		return nil
+	}
+
+	if strings.TrimSpace(anki.StripHTML(flashcard.Front)) == "" {
+		return nil
+	}
+	return flashcard
+}
+
+// flashcardKeys 获取知识点下已有闪卡的去重键
+func (s *AnkiService) flashcardKeys(ctx context.Context, knowledgePointID uuid.UUID) (map[string]bool, error) {
+	existing, err := s.flashcardRepo.GetByKnowledgePointID(ctx, knowledgePointID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(existing))
+	for _, card := range existing {
+		keys[s.flashcardKey(card)] = true
+	}
+	return keys, nil
+}
+
+// flashcardKey 闪卡去重键；导入时媒体引用会被替换为附件URI，因此忽略引用的文件名
+func (s *AnkiService) flashcardKey(card *entities.Flashcard) string {
+	front, back := s.withoutMediaNames(card.Front), s.withoutMediaNames(card.Back)
+	if card.IsCloze() {
+		return fmt.Sprintf("cloze|%d|%s", card.ClozeIndex, front)
+	}
+	return "basic|" + front + "|" + back
+}
+
+// withoutMediaNames 将字段中引用的媒体文件名替换为占位符
+func (s *AnkiService) withoutMediaNames(field string) string {
+	return anki.ReplaceMediaRefs(field, func(string) string { return "media" })
+}
+
+// deckName 知识点对应的牌组名称
+func (s *AnkiService) deckName(point *entities.KnowledgePoint) string {
+	clean := func(name string) string {
+		return strings.ReplaceAll(strings.TrimSpace(name), "::", ":")
+	}
+	return strings.Join([]string{ankiRootDeck, clean(point.Category), clean(point.Title)}, "::")
+}
+
+// deckLeaf 牌组名称的最后一级，作为知识点标题
+func (s *AnkiService) deckLeaf(deck string) string {
+	parts := strings.Split(deck, "::")
+	leaf := strings.TrimSpace(parts[len(parts)-1])
+	if leaf == "" || leaf == "Default" {
+		return "Anki导入"
+	}
+	return leaf
+}
+
+// noteTags 笔记标签
+func (s *AnkiService) noteTags(point *entities.KnowledgePoint) []string {
+	tags := []string{ankiRootDeck}
+	for _, tag := range []string{point.Category, point.Difficulty} {
+		if tag = strings.Join(strings.Fields(tag), "_"); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/anki"
+	"sical-go-backend/pkg/storage"
+)
+
+// ankiFixture 基于内存仓储与本地存储的Anki导入导出服务
+type ankiFixture struct {
+	*knowledgeFixture
+	cards       *fakeFlashcardRepo
+	cardReviews *fakeFlashcardReviewRepo
+	paths       *fakePathRepo
+	enrollments *fakeEnrollmentRepo
+	service     *AnkiService
+}
+
+func newAnkiFixture(t *testing.T, points ...*entities.KnowledgePoint) *ankiFixture {
+	t.Helper()
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost/files", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	kf := newKnowledgeFixture(points...)
+	f := &ankiFixture{
+		knowledgeFixture: kf,
+		cards:            &fakeFlashcardRepo{db: kf.db, cards: make(map[uuid.UUID]*entities.Flashcard)},
+		cardReviews:      &fakeFlashcardReviewRepo{db: kf.db},
+		paths:            &fakePathRepo{paths: make(map[uuid.UUID]*entities.LearningPath)},
+		enrollments:      &fakeEnrollmentRepo{enrolled: make(map[string]bool)},
+	}
+	f.service = NewAnkiService(
+		f.cards,
+		f.cardReviews,
+		kf.points,
+		f.paths,
+		f.enrollments,
+		kf.attachments,
+		kf.service,
+		NewAttachmentService(kf.attachments, nil, store, 0),
+		kf.tx,
+	)
+	return f
+}
+
+// testPNG 生成测试用PNG图片
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testApkg 写出包含给定笔记与媒体的牌组包
+func testApkg(t *testing.T, pkg *anki.Package) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := anki.Write(&buf, pkg); err != nil {
+		t.Fatalf("anki.Write() error = %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestAnkiServiceImportPackage(t *testing.T) {
+	note := anki.Note{
+		Fields: []string{`心电图<img src="ecg.png">`, "ST段抬高"},
+		Deck:   "SiCal::测试::心肌梗死",
+		Cards:  []anki.Card{{State: anki.CardStateNew}},
+	}
+	media := map[string][]byte{"ecg.png": testPNG(t), "unused.png": testPNG(t)}
+
+	tests := []struct {
+		name        string
+		existing    *entities.KnowledgePoint
+		reviewErr   error
+		wantErr     error
+		wantCreated int
+	}{
+		{name: "导入到自己的草稿", existing: testKnowledgePoint("心肌梗死", entities.KnowledgePointStatusDraft, testAuthor.UserID)},
+		{name: "不存在同名知识点时新建草稿", wantCreated: 1},
+		{name: "同名知识点已发布时无权编辑", existing: testKnowledgePoint("心肌梗死", entities.KnowledgePointStatusPublished, testOtherUser.UserID), wantErr: ErrFlashcardForbidden},
+		{name: "写入调度状态失败时整体回滚", reviewErr: errors.New("写入失败"), wantErr: errors.New("写入失败")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var points []*entities.KnowledgePoint
+			if tt.existing != nil {
+				points = append(points, tt.existing)
+			}
+			f := newAnkiFixture(t, points...)
+			f.cardReviews.createErr = tt.reviewErr
+
+			r := testApkg(t, &anki.Package{Notes: []anki.Note{note}, Media: media})
+			req := &AnkiImportRequest{Actor: testAuthor, Category: "测试", Difficulty: "beginner"}
+			result, err := f.service.ImportPackage(context.Background(), req, r, r.Size())
+			if tt.wantErr != nil {
+				if err == nil || (errors.Is(tt.wantErr, ErrFlashcardForbidden) && !errors.Is(err, ErrFlashcardForbidden)) {
+					t.Fatalf("ImportPackage() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(f.db.writes) != 0 || len(f.cards.cards) != 0 || len(f.points.points) != len(points) {
+					t.Fatalf("after failed ImportPackage() writes = %v, cards = %d, points = %d", f.db.writes, len(f.cards.cards), len(f.points.points))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportPackage() error = %v", err)
+			}
+			if result.CardsImported != 1 || result.KnowledgePointsCreated != tt.wantCreated || result.MediaImported != 1 || result.MediaSkipped != 1 {
+				t.Fatalf("result = %+v", result)
+			}
+			if len(f.db.outside) != 0 {
+				t.Fatalf("writes outside transaction: %v", f.db.outside)
+			}
+
+			var card *entities.Flashcard
+			for _, c := range f.cards.cards {
+				card = c
+			}
+			ids := entities.ExtractAttachmentIDs(card.Front)
+			if len(ids) != 1 || strings.Contains(card.Front, "ecg.png") {
+				t.Fatalf("card front = %q, want media reference replaced by attachment URI", card.Front)
+			}
+			refs := f.attachments.references[string(entities.AttachmentRefFlashcard)+":"+card.ID.String()]
+			if len(refs) != 1 || refs[0] != ids[0] {
+				t.Fatalf("flashcard attachment references = %v, want [%s]", refs, ids[0])
+			}
+			if len(f.cardReviews.reviews) != 1 {
+				t.Fatalf("reviews = %d, want 1", len(f.cardReviews.reviews))
+			}
+		})
+	}
+}
+
+func TestAnkiServiceExportLearningPathFlashcards(t *testing.T) {
+	published := testKnowledgePoint("心肌梗死", entities.KnowledgePointStatusPublished, testOtherUser.UserID)
+	draft := testKnowledgePoint("心律失常", entities.KnowledgePointStatusDraft, testOtherUser.UserID)
+
+	tests := []struct {
+		name      string
+		public    bool
+		enrolled  bool
+		missing   bool
+		wantErr   error
+		wantNotes int
+	}{
+		{name: "公开路径只导出可查看的知识点", public: true, wantNotes: 1},
+		{name: "已报名的路径", enrolled: true, wantNotes: 1},
+		{name: "未公开且未报名的路径按不存在处理", wantErr: repositories.ErrNotFound},
+		{name: "路径不存在", missing: true, wantErr: repositories.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newAnkiFixture(t, published, draft)
+
+			attachment, err := f.service.attachmentService.save(ctx, testOtherUser.UserID, "ecg.png", testPNG(t), false, thumbnailMaxSize)
+			if err != nil {
+				t.Fatalf("save() error = %v", err)
+			}
+			for _, card := range []*entities.Flashcard{
+				{ID: uuid.New(), KnowledgePointID: published.ID, CardType: string(entities.FlashcardTypeBasic), Front: "心电图表现 ![心电图](" + attachment.URI() + ")", Back: "ST段抬高"},
+				{ID: uuid.New(), KnowledgePointID: draft.ID, CardType: string(entities.FlashcardTypeBasic), Front: "未发布", Back: "草稿"},
+			} {
+				f.cards.cards[card.ID] = card
+			}
+
+			path := &entities.LearningPath{ID: uuid.New(), IsPublic: tt.public, KnowledgePoints: []entities.KnowledgePoint{*published, *draft}}
+			if !tt.missing {
+				f.paths.paths[path.ID] = path
+			}
+			if tt.enrolled {
+				f.enrollments.enrolled[fmt.Sprintf("%d:%s", testAuthor.UserID, path.ID)] = true
+			}
+
+			var buf bytes.Buffer
+			err = f.service.ExportLearningPathFlashcards(ctx, path.ID, testAuthor, &buf)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ExportLearningPathFlashcards() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportLearningPathFlashcards() error = %v", err)
+			}
+
+			pkg, err := anki.Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("anki.Read() error = %v", err)
+			}
+			if len(pkg.Notes) != tt.wantNotes {
+				t.Fatalf("notes = %d, want %d", len(pkg.Notes), tt.wantNotes)
+			}
+			name := attachment.ID.String() + ".png"
+			if !strings.Contains(pkg.Notes[0].Fields[0], `<img src="`+name+`"`) {
+				t.Fatalf("front = %q, want <img> referencing %s", pkg.Notes[0].Fields[0], name)
+			}
+			if len(pkg.Media[name]) == 0 {
+				t.Fatalf("media = %v, want %s", pkg.Media, name)
+			}
+		})
+	}
+}
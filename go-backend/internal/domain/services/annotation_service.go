@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/anchor"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// 笔记限制
+const (
+	maxAnnotationBodyLength  = 10000 // 笔记正文的最大长度（字符）
+	maxAnnotationQuoteLength = 2000  // 引文的最大长度（字符）
+)
+
+// ErrInvalidAnnotation 笔记无效
+var ErrInvalidAnnotation = errors.New("笔记无效")
+
+// AnnotationInput 创建笔记或高亮的参数；锚定位置可以是可见文本中的区间[Start, End)，也可以直接提供引文及其前后文
+type AnnotationInput struct {
+	Type   entities.AnnotationType
+	Body   string
+	Color  string
+	Start  *int
+	End    *int
+	Exact  string
+	Prefix string
+	Suffix string
+}
+
+// AnchoredAnnotation 在知识点当前内容中重新定位后的笔记
+type AnchoredAnnotation struct {
+	Annotation     *entities.Annotation
+	KnowledgePoint *entities.KnowledgePoint
+	Start          *int // 当前可见文本中的位置，未锚定或无法定位时为nil
+	End            *int
+	Orphaned       bool // 引文在当前内容中已无法定位
+}
+
+// AnnotationService 学习笔记与高亮服务；笔记仅作者本人可见
+type AnnotationService struct {
+	annotationRepo        repositories.AnnotationRepository
+	knowledgeRepo         repositories.KnowledgePointRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewAnnotationService 创建学习笔记与高亮服务
+func NewAnnotationService(
+	annotationRepo repositories.AnnotationRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	knowledgePointService *KnowledgePointService,
+) *AnnotationService {
+	return &AnnotationService{
+		annotationRepo:        annotationRepo,
+		knowledgeRepo:         knowledgeRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// CreateAnnotation 在知识点上创建笔记或高亮；高亮必须锚定到内容中的文本
+func (s *AnnotationService) CreateAnnotation(ctx context.Context, knowledgePointID uuid.UUID, input *AnnotationInput, actor *KnowledgePointActor) (*AnchoredAnnotation, error) {
+	if actor == nil {
+		return nil, ErrKnowledgePointForbidden
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := &entities.Annotation{
+		UserID:           actor.UserID,
+		KnowledgePointID: knowledgePointID,
+		Type:             string(input.Type),
+	}
+	switch input.Type {
+	case entities.AnnotationTypeNote, entities.AnnotationTypeHighlight:
+	default:
+		return nil, fmt.Errorf("%w: 不支持的笔记类型 %s", ErrInvalidAnnotation, input.Type)
+	}
+	if err := applyAnnotationContent(annotation, input.Body, input.Color); err != nil {
+		return nil, err
+	}
+
+	selector, err := s.resolveSelector(point, input)
+	if err != nil {
+		return nil, err
+	}
+	if selector != nil {
+		annotation.Exact = selector.Exact
+		annotation.Prefix = selector.Prefix
+		annotation.Suffix = selector.Suffix
+		annotation.StartOffset = selector.Start
+		annotation.EndOffset = selector.End
+	}
+	if input.Type == entities.AnnotationTypeHighlight && !annotation.IsAnchored() {
+		return nil, fmt.Errorf("%w: 高亮需要选择内容中的文本", ErrInvalidAnnotation)
+	}
+	if input.Type == entities.AnnotationTypeNote && annotation.Body == "" {
+		return nil, fmt.Errorf("%w: 笔记内容不能为空", ErrInvalidAnnotation)
+	}
+
+	if err := s.annotationRepo.Create(ctx, annotation); err != nil {
+		return nil, err
+	}
+
+	logger.Info("笔记创建成功",
+		logger.String("annotation_id", annotation.ID.String()),
+		logger.String("knowledge_point_id", knowledgePointID.String()),
+		logger.String("type", annotation.Type))
+	return locateAnnotation(annotation, point, anchorText(point)), nil
+}
+
+// UpdateAnnotation 修改笔记正文或高亮颜色，锚定位置不可修改
+func (s *AnnotationService) UpdateAnnotation(ctx context.Context, id uuid.UUID, body, color *string, actor *KnowledgePointActor) (*AnchoredAnnotation, error) {
+	annotation, err := s.getOwned(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	newBody, newColor := annotation.Body, annotation.Color
+	if body != nil {
+		newBody = *body
+	}
+	if color != nil {
+		newColor = *color
+	}
+	if err := applyAnnotationContent(annotation, newBody, newColor); err != nil {
+		return nil, err
+	}
+	if annotation.Type == string(entities.AnnotationTypeNote) && annotation.Body == "" {
+		return nil, fmt.Errorf("%w: 笔记内容不能为空", ErrInvalidAnnotation)
+	}
+	if err := s.annotationRepo.Update(ctx, annotation); err != nil {
+		return nil, err
+	}
+
+	point, err := s.knowledgeRepo.GetByID(ctx, annotation.KnowledgePointID)
+	if err != nil {
+		return nil, err
+	}
+	return locateAnnotation(annotation, point, anchorText(point)), nil
+}
+
+// DeleteAnnotation 删除笔记或高亮
+func (s *AnnotationService) DeleteAnnotation(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	if _, err := s.getOwned(ctx, id, actor); err != nil {
+		return err
+	}
+	return s.annotationRepo.Delete(ctx, id)
+}
+
+// ListForKnowledgePoint 获取当前用户在知识点上的全部笔记与高亮，按在内容中的位置排序：
+// 整篇笔记在前，无法定位的高亮在后
+func (s *AnnotationService) ListForKnowledgePoint(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) ([]*AnchoredAnnotation, error) {
+	if actor == nil {
+		return nil, ErrKnowledgePointForbidden
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+	annotations, _, err := s.annotationRepo.List(ctx, &repositories.AnnotationFilter{
+		UserID:           actor.UserID,
+		KnowledgePointID: &knowledgePointID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text := anchorText(point)
+	results := make([]*AnchoredAnnotation, 0, len(annotations))
+	for _, annotation := range annotations {
+		results = append(results, locateAnnotation(annotation, point, text))
+	}
+	sortByPosition(results)
+	return results, nil
+}
+
+// ListMyAnnotations 按条件分页获取当前用户在整个知识库中的笔记与高亮，按更新时间倒序
+func (s *AnnotationService) ListMyAnnotations(ctx context.Context, filter *repositories.AnnotationFilter, actor *KnowledgePointActor) ([]*AnchoredAnnotation, int64, error) {
+	if actor == nil {
+		return nil, 0, ErrKnowledgePointForbidden
+	}
+	filter.UserID = actor.UserID
+	annotations, total, err := s.annotationRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	results, err := s.locateAll(ctx, annotations)
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// ExportMarkdown 将当前用户的笔记与高亮导出为Markdown，按知识点分节；knowledgePointID不为nil时只导出该知识点
+func (s *AnnotationService) ExportMarkdown(ctx context.Context, knowledgePointID *uuid.UUID, actor *KnowledgePointActor) (string, error) {
+	if actor == nil {
+		return "", ErrKnowledgePointForbidden
+	}
+	annotations, _, err := s.annotationRepo.List(ctx, &repositories.AnnotationFilter{
+		UserID:           actor.UserID,
+		KnowledgePointID: knowledgePointID,
+	})
+	if err != nil {
+		return "", err
+	}
+	results, err := s.locateAll(ctx, annotations)
+	if err != nil {
+		return "", err
+	}
+
+	// 按知识点分组，知识点按标题排序
+	groups := make(map[uuid.UUID][]*AnchoredAnnotation)
+	var points []*entities.KnowledgePoint
+	for _, result := range results {
+		id := result.KnowledgePoint.ID
+		if _, ok := groups[id]; !ok {
+			points = append(points, result.KnowledgePoint)
+		}
+		groups[id] = append(groups[id], result)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Title < points[j].Title
+	})
+
+	var buf strings.Builder
+	buf.WriteString("# 我的学习笔记\n\n")
+	fmt.Fprintf(&buf, "导出时间：%s，共%d条\n", time.Now().Format("2006-01-02 15:04"), len(results))
+	for _, point := range points {
+		items := groups[point.ID]
+		sortByPosition(items)
+		fmt.Fprintf(&buf, "\n## %s\n", point.Title)
+		for _, item := range items {
+			buf.WriteString("\n")
+			annotation := item.Annotation
+			if annotation.IsAnchored() {
+				for _, line := range strings.Split(annotation.Exact, "\n") {
+					fmt.Fprintf(&buf, "> %s\n", line)
+				}
+				if item.Orphaned {
+					buf.WriteString(">\n> （原文已修改）\n")
+				}
+				if annotation.Body != "" {
+					buf.WriteString("\n")
+				}
+			}
+			if annotation.Body != "" {
+				buf.WriteString(annotation.Body)
+				buf.WriteString("\n")
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+// getOwned 获取当前用户自己的笔记；他人的笔记视为不存在
+func (s *AnnotationService) getOwned(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.Annotation, error) {
+	if actor == nil {
+		return nil, ErrKnowledgePointForbidden
+	}
+	annotation, err := s.annotationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if annotation.UserID != actor.UserID {
+		return nil, fmt.Errorf("笔记不存在")
+	}
+	return annotation, nil
+}
+
+// resolveSelector 根据参数在知识点当前内容中确定引文；未提供位置与引文时返回nil
+func (s *AnnotationService) resolveSelector(point *entities.KnowledgePoint, input *AnnotationInput) (*anchor.Selector, error) {
+	text := anchorText(point)
+	var selector *anchor.Selector
+	switch {
+	case input.Start != nil || input.End != nil:
+		if input.Start == nil || input.End == nil {
+			return nil, fmt.Errorf("%w: 需要同时提供起止位置", ErrInvalidAnnotation)
+		}
+		var err error
+		selector, err = anchor.FromRange(text, *input.Start, *input.End)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAnnotation, err)
+		}
+		if exact := anchor.Normalize(input.Exact); exact != "" && exact != selector.Exact {
+			return nil, fmt.Errorf("%w: 引文与所选位置的内容不一致，内容可能已更新", ErrInvalidAnnotation)
+		}
+	case strings.TrimSpace(input.Exact) != "":
+		start, end, ok := anchor.Locate(text, &anchor.Selector{
+			Exact:  input.Exact,
+			Prefix: input.Prefix,
+			Suffix: input.Suffix,
+			Start:  -1,
+		})
+		if !ok {
+			return nil, fmt.Errorf("%w: 内容中找不到所选文本", ErrInvalidAnnotation)
+		}
+		selector, _ = anchor.FromRange(text, start, end)
+	default:
+		return nil, nil
+	}
+	if utf8.RuneCountInString(selector.Exact) > maxAnnotationQuoteLength {
+		return nil, fmt.Errorf("%w: 所选文本不能超过%d个字符", ErrInvalidAnnotation, maxAnnotationQuoteLength)
+	}
+	return selector, nil
+}
+
+// locateAll 批量获取笔记所属的知识点并重新定位
+func (s *AnnotationService) locateAll(ctx context.Context, annotations []*entities.Annotation) ([]*AnchoredAnnotation, error) {
+	ids := make([]uuid.UUID, 0, len(annotations))
+	seen := make(map[uuid.UUID]bool)
+	for _, annotation := range annotations {
+		if !seen[annotation.KnowledgePointID] {
+			seen[annotation.KnowledgePointID] = true
+			ids = append(ids, annotation.KnowledgePointID)
+		}
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	pointByID := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	texts := make(map[uuid.UUID]string, len(points))
+	for _, point := range points {
+		pointByID[point.ID] = point
+		texts[point.ID] = anchorText(point)
+	}
+
+	results := make([]*AnchoredAnnotation, 0, len(annotations))
+	for _, annotation := range annotations {
+		point, ok := pointByID[annotation.KnowledgePointID]
+		if !ok {
+			// 知识点已删除
+			continue
+		}
+		results = append(results, locateAnnotation(annotation, point, texts[point.ID]))
+	}
+	return results, nil
+}
+
+// applyAnnotationContent 校验并设置笔记正文与高亮颜色；正文与知识点内容一样拒绝不安全的Markdown
+func applyAnnotationContent(annotation *entities.Annotation, body, color string) error {
+	body = strings.TrimSpace(body)
+	if utf8.RuneCountInString(body) > maxAnnotationBodyLength {
+		return fmt.Errorf("%w: 笔记内容不能超过%d个字符", ErrInvalidAnnotation, maxAnnotationBodyLength)
+	}
+	if body != "" {
+		if err := markdown.Validate(body); err != nil {
+			return err
+		}
+	}
+	if color != "" {
+		valid := false
+		for _, candidate := range entities.AnnotationColors {
+			if color == candidate {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("%w: 不支持的高亮颜色 %s", ErrInvalidAnnotation, color)
+		}
+	}
+	annotation.Body = body
+	annotation.Color = color
+	return nil
+}
+
+// anchorText 获取知识点内容的规范化可见文本，笔记的位置均相对于该文本
+func anchorText(point *entities.KnowledgePoint) string {
+	return anchor.Normalize(markdown.VisibleText(point.Content))
+}
+
+// locateAnnotation 在知识点当前内容中重新定位笔记
+func locateAnnotation(annotation *entities.Annotation, point *entities.KnowledgePoint, text string) *AnchoredAnnotation {
+	result := &AnchoredAnnotation{Annotation: annotation, KnowledgePoint: point}
+	if !annotation.IsAnchored() {
+		return result
+	}
+	start, end, ok := anchor.Locate(text, &anchor.Selector{
+		Exact:  annotation.Exact,
+		Prefix: annotation.Prefix,
+		Suffix: annotation.Suffix,
+		Start:  annotation.StartOffset,
+		End:    annotation.EndOffset,
+	})
+	if !ok {
+		result.Orphaned = true
+		return result
+	}
+	result.Start, result.End = &start, &end
+	return result
+}
+
+// sortByPosition 按在内容中的位置排序：整篇笔记在前，无法定位的在后，其余按起始位置
+func sortByPosition(annotations []*AnchoredAnnotation) {
+	rank := func(item *AnchoredAnnotation) int {
+		switch {
+		case !item.Annotation.IsAnchored():
+			return 0
+		case item.Orphaned:
+			return 2
+		default:
+			return 1
+		}
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		a, b := annotations[i], annotations[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.Start != nil && b.Start != nil && *a.Start != *b.Start {
+			return *a.Start < *b.Start
+		}
+		return a.Annotation.CreatedAt.Before(b.Annotation.CreatedAt)
+	})
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/imaging"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/storage"
+)
+
+// 附件大小与缩略图参数
+const (
+	MaxAttachmentSize   = 50 << 20
+	maxAvatarSize       = 5 << 20
+	thumbnailMaxSize    = 320
+	avatarThumbnailSize = 256
+	attachmentGCBatch   = 100
+)
+
+// attachmentSizeLimits 允许上传的内容类型及其大小上限
+var attachmentSizeLimits = map[string]int64{
+	"image/png":       10 << 20,
+	"image/jpeg":      10 << 20,
+	"image/gif":       10 << 20,
+	"image/webp":      10 << 20,
+	"application/pdf": MaxAttachmentSize,
+}
+
+// attachmentExtensions 内容类型对应的存储扩展名
+var attachmentExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// 附件错误
+var (
+	ErrAttachmentTooLarge        = errors.New("附件过大")
+	ErrUnsupportedAttachmentType = errors.New("不支持的附件类型")
+	ErrAttachmentForbidden       = errors.New("无权清理附件")
+)
+
+// AttachmentService 附件服务
+type AttachmentService struct {
+	attachmentRepo repositories.AttachmentRepository
+	profileRepo    repositories.UserProfileRepository
+	store          storage.Storage
+	urlExpiry      time.Duration
+}
+
+// NewAttachmentService 创建附件服务
+func NewAttachmentService(
+	attachmentRepo repositories.AttachmentRepository,
+	profileRepo repositories.UserProfileRepository,
+	store storage.Storage,
+	urlExpiry time.Duration,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		profileRepo:    profileRepo,
+		store:          store,
+		urlExpiry:      urlExpiry,
+	}
+}
+
+// AttachmentURLs 附件的限时下载地址
+type AttachmentURLs struct {
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Upload 上传附件；内容类型根据文件内容识别，图片生成缩略图。
+// 附件在被知识点内容或头像引用前视为未引用，超过宽限期后会被清理
+func (s *AttachmentService) Upload(ctx context.Context, ownerID uint, filename string, r io.Reader) (*entities.Attachment, error) {
+	data, err := readLimited(r, MaxAttachmentSize)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, ownerID, filename, data, false, thumbnailMaxSize)
+}
+
+// SetAvatar 上传图片并设置为用户头像，原头像失去引用后由清理任务回收
+func (s *AttachmentService) SetAvatar(ctx context.Context, userID uint, filename string, r io.Reader) (*entities.Attachment, error) {
+	data, err := readLimited(r, maxAvatarSize)
+	if err != nil {
+		return nil, err
+	}
+	attachment, err := s.save(ctx, userID, filename, data, true, avatarThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	refID := strconv.FormatUint(uint64(userID), 10)
+	if err := s.attachmentRepo.ReplaceReferences(ctx, entities.AttachmentRefUserAvatar, refID, []uuid.UUID{attachment.ID}); err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.UpdateAvatar(ctx, userID, attachment.URI()); err != nil {
+		return nil, fmt.Errorf("更新头像失败: %w", err)
+	}
+	return attachment, nil
+}
+
+// GetAttachment 获取附件
+func (s *AttachmentService) GetAttachment(ctx context.Context, id uuid.UUID) (*entities.Attachment, error) {
+	return s.attachmentRepo.GetByID(ctx, id)
+}
+
+// SignedURLs 生成附件及其缩略图的限时下载地址
+func (s *AttachmentService) SignedURLs(ctx context.Context, attachment *entities.Attachment) (*AttachmentURLs, error) {
+	expiresAt := time.Now().Add(s.urlExpiry)
+	url, err := s.store.SignedURL(ctx, attachment.StorageKey, s.urlExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("生成下载地址失败: %w", err)
+	}
+	urls := &AttachmentURLs{URL: url, ExpiresAt: expiresAt}
+	if attachment.ThumbnailKey != nil {
+		if urls.ThumbnailURL, err = s.store.SignedURL(ctx, *attachment.ThumbnailKey, s.urlExpiry); err != nil {
+			return nil, fmt.Errorf("生成下载地址失败: %w", err)
+		}
+	}
+	return urls, nil
+}
+
+// CollectGarbage 清理超过宽限期且无引用的附件，仅限管理员，返回清理数量
+func (s *AttachmentService) CollectGarbage(ctx context.Context, gracePeriod time.Duration, actor *KnowledgePointActor) (int, error) {
+	if !actor.IsAdmin() {
+		return 0, ErrAttachmentForbidden
+	}
+	cutoff := time.Now().Add(-gracePeriod)
+	removed := 0
+	for {
+		orphans, err := s.attachmentRepo.GetOrphans(ctx, cutoff, attachmentGCBatch)
+		if err != nil {
+			return removed, err
+		}
+
+		batchRemoved := 0
+		for _, attachment := range orphans {
+			if err := s.remove(ctx, attachment); err != nil {
+				logger.Warn("清理附件失败",
+					logger.String("attachment_id", attachment.ID.String()),
+					logger.String("error", err.Error()),
+				)
+				continue
+			}
+			batchRemoved++
+		}
+		removed += batchRemoved
+
+		// 本批次全部失败时停止，避免反复处理同一批附件
+		if len(orphans) < attachmentGCBatch || batchRemoved == 0 {
+			break
+		}
+	}
+
+	if removed > 0 {
+		logger.Info("未引用附件清理完成", logger.Int("removed", removed))
+	}
+	return removed, nil
+}
+
+// save 识别内容类型、生成缩略图并保存附件
+func (s *AttachmentService) save(ctx context.Context, ownerID uint, filename string, data []byte, imageOnly bool, thumbSize int) (*entities.Attachment, error) {
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	limit, ok := attachmentSizeLimits[contentType]
+	if !ok || (imageOnly && !strings.HasPrefix(contentType, "image/")) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAttachmentType, contentType)
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	sum := sha256.Sum256(data)
+	attachment := &entities.Attachment{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		Filename:    sanitizeFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+	}
+	prefix := fmt.Sprintf("attachments/%s/%s", time.Now().UTC().Format("2006/01"), attachment.ID)
+	attachment.StorageKey = prefix + attachmentExtensions[contentType]
+
+	// WebP无标准库解码器，不读取尺寸也不生成缩略图
+	var thumbnail []byte
+	var thumbnailType string
+	if strings.HasPrefix(contentType, "image/") && contentType != "image/webp" {
+		width, height, _, err := imaging.Size(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 图片已损坏", ErrUnsupportedAttachmentType)
+		}
+		if width*height > imaging.MaxPixels {
+			return nil, imaging.ErrTooLarge
+		}
+		attachment.Width, attachment.Height = width, height
+
+		thumbnail, thumbnailType, err = imaging.Thumbnail(data, thumbSize)
+		if err != nil {
+			logger.Warn("生成缩略图失败", logger.String("error", err.Error()))
+			thumbnail = nil
+		}
+	}
+
+	if err := s.store.Put(ctx, attachment.StorageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, fmt.Errorf("保存附件失败: %w", err)
+	}
+	if thumbnail != nil {
+		key := prefix + "_thumb" + attachmentExtensions[thumbnailType]
+		if err := s.store.Put(ctx, key, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType); err != nil {
+			s.store.Delete(ctx, attachment.StorageKey)
+			return nil, fmt.Errorf("保存缩略图失败: %w", err)
+		}
+		attachment.ThumbnailKey = &key
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		s.removeObjects(ctx, attachment)
+		return nil, err
+	}
+
+	logger.Info("附件上传成功",
+		logger.String("attachment_id", attachment.ID.String()),
+		logger.String("content_type", contentType),
+		logger.Int("size", len(data)),
+	)
+	return attachment, nil
+}
+
+// readContent 读取附件内容
+func (s *AttachmentService) readContent(ctx context.Context, attachment *entities.Attachment) ([]byte, error) {
+	rc, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取附件失败: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MaxAttachmentSize))
+	if err != nil {
+		return nil, fmt.Errorf("读取附件失败: %w", err)
+	}
+	return data, nil
+}
+
+// remove 删除附件对象与记录
+func (s *AttachmentService) remove(ctx context.Context, attachment *entities.Attachment) error {
+	if err := s.removeObjects(ctx, attachment); err != nil {
+		return err
+	}
+	return s.attachmentRepo.Delete(ctx, attachment.ID)
+}
+
+// removeObjects 删除附件及缩略图的存储对象
+func (s *AttachmentService) removeObjects(ctx context.Context, attachment *entities.Attachment) error {
+	if attachment.ThumbnailKey != nil {
+		if err := s.store.Delete(ctx, *attachment.ThumbnailKey); err != nil {
+			return err
+		}
+	}
+	return s.store.Delete(ctx, attachment.StorageKey)
+}
+
+// trackKnowledgePointAttachments 为知识点内容与描述中引用的附件添加引用记录
+func trackKnowledgePointAttachments(ctx context.Context, attachmentRepo repositories.AttachmentRepository, point *entities.KnowledgePoint) error {
+	ids := entities.ExtractAttachmentIDs(point.Description + "\n" + point.Content)
+	if len(ids) == 0 {
+		return nil
+	}
+	return attachmentRepo.AddReferences(ctx, entities.AttachmentRefKnowledgePoint, point.ID.String(), ids)
+}
+
+// readLimited 读取上传内容，超过上限时返回ErrAttachmentTooLarge
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取上传内容失败: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return nil, errors.New("上传内容为空")
+	}
+	return data, nil
+}
+
+// sanitizeFilename 清理上传文件名，仅保留基本名称并限制长度
+func sanitizeFilename(filename string) string {
+	name := strings.TrimSpace(filepath.Base(strings.ReplaceAll(filename, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	for utf8.RuneCountInString(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// 收藏夹限制
+const (
+	maxCollectionNameLength        = 100  // 名称的最大长度（字符）
+	maxCollectionDescriptionLength = 2000 // 描述的最大长度（字符）
+	maxCollectionItems             = 500  // 单个收藏夹的最大条目数
+	shareTokenBytes                = 24   // 分享令牌的随机字节数
+)
+
+// 收藏夹错误
+var (
+	ErrInvalidCollection   = errors.New("收藏夹无效")
+	ErrCollectionForbidden = errors.New("无权管理该收藏夹")
+)
+
+// CollectionSummary 收藏夹及其条目数
+type CollectionSummary struct {
+	Collection *entities.Collection
+	ItemsCount int64
+}
+
+// CollectionEntry 收藏条目及其对象；对象已删除或对查看者不可见时Available为false
+type CollectionEntry struct {
+	Item           *entities.CollectionItem
+	KnowledgePoint *entities.KnowledgePoint
+	LearningPath   *entities.LearningPath
+	Available      bool
+}
+
+// CollectionDetail 收藏夹及其按顺序排列的条目
+type CollectionDetail struct {
+	Collection *entities.Collection
+	Entries    []*CollectionEntry
+}
+
+// CollectionService 收藏夹服务；收藏夹仅所有者可管理，开启分享后持有链接的用户可只读访问与复制
+type CollectionService struct {
+	collectionRepo        repositories.CollectionRepository
+	knowledgeRepo         repositories.KnowledgePointRepository
+	pathRepo              repositories.LearningPathRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewCollectionService 创建收藏夹服务
+func NewCollectionService(
+	collectionRepo repositories.CollectionRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	pathRepo repositories.LearningPathRepository,
+	knowledgePointService *KnowledgePointService,
+) *CollectionService {
+	return &CollectionService{
+		collectionRepo:        collectionRepo,
+		knowledgeRepo:         knowledgeRepo,
+		pathRepo:              pathRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// ListMyCollections 获取当前用户的收藏夹
+func (s *CollectionService) ListMyCollections(ctx context.Context, actor *KnowledgePointActor) ([]*CollectionSummary, error) {
+	if actor == nil {
+		return nil, ErrCollectionForbidden
+	}
+	collections, err := s.collectionRepo.ListByUser(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, collections)
+}
+
+// ListCollectionsContaining 获取当前用户收藏了指定对象的收藏夹ID，用于展示收藏状态
+func (s *CollectionService) ListCollectionsContaining(ctx context.Context, targetType entities.CollectionItemType, targetID uuid.UUID, actor *KnowledgePointActor) ([]uuid.UUID, error) {
+	if actor == nil {
+		return nil, ErrCollectionForbidden
+	}
+	if err := validateCollectionItemType(targetType); err != nil {
+		return nil, err
+	}
+	return s.collectionRepo.ListContaining(ctx, actor.UserID, string(targetType), targetID)
+}
+
+// CreateCollection 创建收藏夹
+func (s *CollectionService) CreateCollection(ctx context.Context, name, description string, actor *KnowledgePointActor) (*entities.Collection, error) {
+	if actor == nil {
+		return nil, ErrCollectionForbidden
+	}
+	collection := &entities.Collection{UserID: actor.UserID}
+	if err := applyCollectionFields(collection, name, description); err != nil {
+		return nil, err
+	}
+	if err := s.collectionRepo.Create(ctx, collection); err != nil {
+		return nil, err
+	}
+
+	logger.Info("收藏夹创建成功",
+		logger.String("collection_id", collection.ID.String()),
+		logger.Int("user_id", int(actor.UserID)))
+	return collection, nil
+}
+
+// GetCollection 获取当前用户的收藏夹及条目
+func (s *CollectionService) GetCollection(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*CollectionDetail, error) {
+	collection, err := s.getOwned(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadDetail(ctx, collection, actor)
+}
+
+// GetSharedCollection 通过分享令牌只读获取收藏夹；对查看者不可见的知识点标记为不可用
+func (s *CollectionService) GetSharedCollection(ctx context.Context, token string, actor *KnowledgePointActor) (*CollectionDetail, error) {
+	collection, err := s.getShared(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadDetail(ctx, collection, actor)
+}
+
+// UpdateCollection 修改收藏夹名称或描述
+func (s *CollectionService) UpdateCollection(ctx context.Context, id uuid.UUID, name, description *string, actor *KnowledgePointActor) (*entities.Collection, error) {
+	collection, err := s.getOwned(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	newName, newDescription := collection.Name, collection.Description
+	if name != nil {
+		newName = *name
+	}
+	if description != nil {
+		newDescription = *description
+	}
+	if err := applyCollectionFields(collection, newName, newDescription); err != nil {
+		return nil, err
+	}
+	if err := s.collectionRepo.Update(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// DeleteCollection 删除收藏夹，已复制出的收藏夹不受影响
+func (s *CollectionService) DeleteCollection(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	if _, err := s.getOwned(ctx, id, actor); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Info("收藏夹删除成功", logger.String("collection_id", id.String()))
+	return nil
+}
+
+// SetSharing 开启或关闭链接分享；已分享时再次开启保留原链接，关闭后原链接失效
+func (s *CollectionService) SetSharing(ctx context.Context, id uuid.UUID, enabled bool, actor *KnowledgePointActor) (*entities.Collection, error) {
+	collection, err := s.getOwned(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if enabled == collection.IsShared() {
+		return collection, nil
+	}
+
+	var token *string
+	if enabled {
+		value, err := generateShareToken()
+		if err != nil {
+			return nil, err
+		}
+		token = &value
+	}
+	if err := s.collectionRepo.SetShareToken(ctx, id, token); err != nil {
+		return nil, err
+	}
+	collection.ShareToken = token
+
+	logger.Info("收藏夹分享状态已更新",
+		logger.String("collection_id", id.String()),
+		logger.Bool("shared", enabled))
+	return collection, nil
+}
+
+// AddItem 将知识点或学习路径加入收藏夹末尾；已收藏的对象保持原位置
+func (s *CollectionService) AddItem(ctx context.Context, id uuid.UUID, targetType entities.CollectionItemType, targetID uuid.UUID, actor *KnowledgePointActor) (*entities.CollectionItem, error) {
+	collection, err := s.getOwned(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureTarget(ctx, targetType, targetID, actor); err != nil {
+		return nil, err
+	}
+	counts, err := s.collectionRepo.CountItems(ctx, []uuid.UUID{collection.ID})
+	if err != nil {
+		return nil, err
+	}
+	if counts[collection.ID] >= maxCollectionItems {
+		return nil, fmt.Errorf("%w: 每个收藏夹最多收藏%d项", ErrInvalidCollection, maxCollectionItems)
+	}
+
+	return s.collectionRepo.AddItem(ctx, &entities.CollectionItem{
+		CollectionID: collection.ID,
+		TargetType:   string(targetType),
+		TargetID:     targetID,
+	})
+}
+
+// RemoveItem 从收藏夹移除条目
+func (s *CollectionService) RemoveItem(ctx context.Context, id, itemID uuid.UUID, actor *KnowledgePointActor) error {
+	if _, err := s.getOwned(ctx, id, actor); err != nil {
+		return err
+	}
+	if _, err := s.collectionRepo.GetItem(ctx, id, itemID); err != nil {
+		return err
+	}
+	return s.collectionRepo.RemoveItem(ctx, id, itemID)
+}
+
+// ReorderItems 按给定顺序重排收藏夹，itemIDs须包含收藏夹的全部条目且不重复
+func (s *CollectionService) ReorderItems(ctx context.Context, id uuid.UUID, itemIDs []uuid.UUID, actor *KnowledgePointActor) (*CollectionDetail, error) {
+	collection, err := s.getOwned(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.collectionRepo.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(itemIDs) != len(items) {
+		return nil, fmt.Errorf("%w: 排序须包含收藏夹的全部%d个条目", ErrInvalidCollection, len(items))
+	}
+	existing := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		existing[item.ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if !existing[itemID] {
+			return nil, fmt.Errorf("%w: 条目 %s 不在收藏夹中", ErrInvalidCollection, itemID)
+		}
+		if seen[itemID] {
+			return nil, fmt.Errorf("%w: 条目 %s 重复", ErrInvalidCollection, itemID)
+		}
+		seen[itemID] = true
+	}
+
+	if err := s.collectionRepo.ReorderItems(ctx, id, itemIDs); err != nil {
+		return nil, err
+	}
+	return s.loadDetail(ctx, collection, actor)
+}
+
+// CloneSharedCollection 将他人分享的收藏夹复制到当前用户名下；仅复制查看者可见的条目，名称为空时沿用原名称
+func (s *CollectionService) CloneSharedCollection(ctx context.Context, token, name string, actor *KnowledgePointActor) (*CollectionDetail, error) {
+	if actor == nil {
+		return nil, ErrCollectionForbidden
+	}
+	source, err := s.getShared(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		name = source.Name
+	}
+	collection := &entities.Collection{
+		UserID:       actor.UserID,
+		ClonedFromID: &source.ID,
+	}
+	if err := applyCollectionFields(collection, name, source.Description); err != nil {
+		return nil, err
+	}
+
+	detail, err := s.loadDetail(ctx, source, actor)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*entities.CollectionItem, 0, len(detail.Entries))
+	for _, entry := range detail.Entries {
+		if entry.Available {
+			items = append(items, entry.Item)
+		}
+	}
+	if err := s.collectionRepo.Clone(ctx, collection, items); err != nil {
+		return nil, err
+	}
+
+	logger.Info("收藏夹复制成功",
+		logger.String("source_id", source.ID.String()),
+		logger.String("collection_id", collection.ID.String()),
+		logger.Int("items", len(items)))
+	return s.loadDetail(ctx, collection, actor)
+}
+
+// getOwned 获取当前用户的收藏夹；他人的收藏夹视为不存在
+func (s *CollectionService) getOwned(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.Collection, error) {
+	if actor == nil {
+		return nil, ErrCollectionForbidden
+	}
+	collection, err := s.collectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !collection.IsOwnedBy(actor.UserID) {
+		return nil, fmt.Errorf("收藏夹不存在")
+	}
+	return collection, nil
+}
+
+// getShared 根据分享令牌获取已分享的收藏夹
+func (s *CollectionService) getShared(ctx context.Context, token string) (*entities.Collection, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("收藏夹不存在")
+	}
+	return s.collectionRepo.GetByShareToken(ctx, token)
+}
+
+// ensureTarget 检查收藏对象存在且对操作者可见
+func (s *CollectionService) ensureTarget(ctx context.Context, targetType entities.CollectionItemType, targetID uuid.UUID, actor *KnowledgePointActor) error {
+	if err := validateCollectionItemType(targetType); err != nil {
+		return err
+	}
+	if targetType == entities.CollectionItemKnowledgePoint {
+		_, err := s.knowledgePointService.GetKnowledgePoint(ctx, targetID, actor)
+		return err
+	}
+	_, err := s.pathRepo.GetByID(ctx, targetID)
+	return err
+}
+
+// summarize 为收藏夹附加条目数
+func (s *CollectionService) summarize(ctx context.Context, collections []*entities.Collection) ([]*CollectionSummary, error) {
+	ids := make([]uuid.UUID, 0, len(collections))
+	for _, collection := range collections {
+		ids = append(ids, collection.ID)
+	}
+	counts, err := s.collectionRepo.CountItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*CollectionSummary, 0, len(collections))
+	for _, collection := range collections {
+		summaries = append(summaries, &CollectionSummary{Collection: collection, ItemsCount: counts[collection.ID]})
+	}
+	return summaries, nil
+}
+
+// loadDetail 加载收藏夹条目并批量解析收藏对象
+func (s *CollectionService) loadDetail(ctx context.Context, collection *entities.Collection, actor *KnowledgePointActor) (*CollectionDetail, error) {
+	items, err := s.collectionRepo.GetItems(ctx, collection.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pointIDs, pathIDs []uuid.UUID
+	for _, item := range items {
+		switch entities.CollectionItemType(item.TargetType) {
+		case entities.CollectionItemKnowledgePoint:
+			pointIDs = append(pointIDs, item.TargetID)
+		case entities.CollectionItemLearningPath:
+			pathIDs = append(pathIDs, item.TargetID)
+		}
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, pointIDs)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := s.pathRepo.GetByIDs(ctx, pathIDs)
+	if err != nil {
+		return nil, err
+	}
+	pointMap := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		if s.knowledgePointService.CanView(point, actor) {
+			pointMap[point.ID] = point
+		}
+	}
+	pathMap := make(map[uuid.UUID]*entities.LearningPath, len(paths))
+	for _, path := range paths {
+		pathMap[path.ID] = path
+	}
+
+	entries := make([]*CollectionEntry, 0, len(items))
+	for _, item := range items {
+		entry := &CollectionEntry{Item: item}
+		switch entities.CollectionItemType(item.TargetType) {
+		case entities.CollectionItemKnowledgePoint:
+			entry.KnowledgePoint = pointMap[item.TargetID]
+			entry.Available = entry.KnowledgePoint != nil
+		case entities.CollectionItemLearningPath:
+			entry.LearningPath = pathMap[item.TargetID]
+			entry.Available = entry.LearningPath != nil
+		}
+		entries = append(entries, entry)
+	}
+	return &CollectionDetail{Collection: collection, Entries: entries}, nil
+}
+
+// validateCollectionItemType 检查收藏对象类型
+func validateCollectionItemType(targetType entities.CollectionItemType) error {
+	switch targetType {
+	case entities.CollectionItemKnowledgePoint, entities.CollectionItemLearningPath:
+		return nil
+	default:
+		return fmt.Errorf("%w: 不支持的收藏对象类型 %s", ErrInvalidCollection, targetType)
+	}
+}
+
+// applyCollectionFields 校验并设置收藏夹名称与描述
+func applyCollectionFields(collection *entities.Collection, name, description string) error {
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	if name == "" {
+		return fmt.Errorf("%w: 名称不能为空", ErrInvalidCollection)
+	}
+	if utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return fmt.Errorf("%w: 名称不能超过%d个字符", ErrInvalidCollection, maxCollectionNameLength)
+	}
+	if utf8.RuneCountInString(description) > maxCollectionDescriptionLength {
+		return fmt.Errorf("%w: 描述不能超过%d个字符", ErrInvalidCollection, maxCollectionDescriptionLength)
+	}
+	collection.Name = name
+	collection.Description = description
+	return nil
+}
+
+// generateShareToken 生成URL安全的随机分享令牌
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成分享令牌失败: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
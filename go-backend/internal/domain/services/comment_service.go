@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// 评论限制
+const (
+	maxCommentLength   = 5000 // 评论正文的最大长度（字符）
+	maxCommentMentions = 20   // 单条评论最多通知的被提及用户数
+)
+
+// 评论错误
+var (
+	ErrInvalidComment   = errors.New("评论无效")
+	ErrCommentForbidden = errors.New("无权操作该评论")
+)
+
+// mentionPattern 匹配评论中的@用户名，@前不能是用户名字符以排除邮箱地址
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@-])@([A-Za-z0-9_.-]{2,50})`)
+
+// CommentNode 评论及其回复组成的树节点
+type CommentNode struct {
+	Comment *entities.Comment
+	Replies []*CommentNode
+}
+
+// CommentPage 知识点评论分页结果
+type CommentPage struct {
+	Threads []*CommentNode
+	Total   int64
+	Authors map[uint]*entities.User // 评论作者
+	Liked   map[uuid.UUID]bool      // 当前用户已点赞的评论
+}
+
+// CommentService 知识点评论服务
+type CommentService struct {
+	commentRepo           repositories.CommentRepository
+	notificationRepo      repositories.NotificationRepository
+	userRepo              repositories.UserRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewCommentService 创建知识点评论服务
+func NewCommentService(
+	commentRepo repositories.CommentRepository,
+	notificationRepo repositories.NotificationRepository,
+	userRepo repositories.UserRepository,
+	knowledgePointService *KnowledgePointService,
+) *CommentService {
+	return &CommentService{
+		commentRepo:           commentRepo,
+		notificationRepo:      notificationRepo,
+		userRepo:              userRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// ListComments 分页获取知识点的讨论串，每个顶层评论附带完整的回复树
+func (s *CommentService) ListComments(ctx context.Context, knowledgePointID uuid.UUID, offset, limit int, actor *KnowledgePointActor) (*CommentPage, error) {
+	if _, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor); err != nil {
+		return nil, err
+	}
+
+	roots, total, err := s.commentRepo.ListThreads(ctx, knowledgePointID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	threadIDs := make([]uuid.UUID, 0, len(roots))
+	for _, root := range roots {
+		threadIDs = append(threadIDs, root.ID)
+	}
+	replies, err := s.commentRepo.GetByThreads(ctx, threadIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CommentPage{Threads: buildCommentTree(roots, replies), Total: total}
+	if err := s.decorate(ctx, page, append(roots, replies...), actor); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetComment 获取评论及其回复树
+func (s *CommentService) GetComment(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*CommentPage, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.knowledgePointService.GetKnowledgePoint(ctx, comment.KnowledgePointID, actor); err != nil {
+		return nil, err
+	}
+
+	// 获取所在讨论串的全部回复，以评论自身为根构建子树
+	replies, err := s.commentRepo.GetByThreads(ctx, []uuid.UUID{comment.RootID()})
+	if err != nil {
+		return nil, err
+	}
+	node := buildCommentTree([]*entities.Comment{comment}, replies)[0]
+	page := &CommentPage{Threads: []*CommentNode{node}, Total: 1}
+	if err := s.decorate(ctx, page, append(replies, comment), actor); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// AddComment 在知识点下发表顶层评论
+func (s *CommentService) AddComment(ctx context.Context, knowledgePointID uuid.UUID, body string, actor *KnowledgePointActor) (*entities.Comment, error) {
+	if actor == nil {
+		return nil, ErrCommentForbidden
+	}
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &entities.Comment{
+		KnowledgePointID: knowledgePointID,
+		AuthorID:         actor.UserID,
+		Body:             body,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	logger.Info("评论发表成功",
+		logger.String("comment_id", comment.ID.String()),
+		logger.String("knowledge_point_id", knowledgePointID.String()),
+		logger.Uint("author_id", actor.UserID))
+	s.notify(ctx, point, comment, nil)
+	return comment, nil
+}
+
+// Reply 回复评论；已删除的评论仍保留在讨论串中但不能再被回复
+func (s *CommentService) Reply(ctx context.Context, parentID uuid.UUID, body string, actor *KnowledgePointActor) (*entities.Comment, error) {
+	if actor == nil {
+		return nil, ErrCommentForbidden
+	}
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := s.commentRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.IsDeleted() {
+		return nil, fmt.Errorf("%w: 不能回复已删除的评论", ErrInvalidComment)
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, parent.KnowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	threadID := parent.RootID()
+	comment := &entities.Comment{
+		KnowledgePointID: parent.KnowledgePointID,
+		ParentID:         &parent.ID,
+		ThreadID:         &threadID,
+		AuthorID:         actor.UserID,
+		Body:             body,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	logger.Info("评论回复成功",
+		logger.String("comment_id", comment.ID.String()),
+		logger.String("parent_id", parentID.String()),
+		logger.Uint("author_id", actor.UserID))
+	s.notify(ctx, point, comment, parent)
+	return comment, nil
+}
+
+// UpdateComment 修改评论正文，仅作者或审核人员可修改；修改后仅通知新增的被提及用户
+func (s *CommentService) UpdateComment(ctx context.Context, id uuid.UUID, body string, actor *KnowledgePointActor) (*entities.Comment, error) {
+	comment, err := s.getManageable(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, fmt.Errorf("%w: 不能修改已删除的评论", ErrInvalidComment)
+	}
+	body, err = normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if body == comment.Body {
+		return comment, nil
+	}
+
+	previous := comment.Body
+	now := time.Now()
+	comment.Body = body
+	comment.EditedAt = &now
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	logger.Info("评论修改成功",
+		logger.String("comment_id", id.String()),
+		logger.Uint("editor_id", actor.UserID))
+	if actor.UserID == comment.AuthorID {
+		if point, err := s.knowledgePointService.GetKnowledgePoint(ctx, comment.KnowledgePointID, actor); err == nil {
+			excluded := make(map[string]bool)
+			for _, name := range parseMentions(previous) {
+				excluded[strings.ToLower(name)] = true
+			}
+			s.notifyMentions(ctx, point, comment, s.authorName(ctx, actor.UserID), excluded)
+		}
+	}
+	return comment, nil
+}
+
+// DeleteComment 软删除评论，仅作者或审核人员可删除；回复仍保留在讨论串中
+func (s *CommentService) DeleteComment(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	comment, err := s.getManageable(ctx, id, actor)
+	if err != nil {
+		return err
+	}
+	if comment.IsDeleted() {
+		return nil
+	}
+
+	now := time.Now()
+	comment.Body = ""
+	comment.DeletedAt = &now
+	comment.DeletedBy = actor.userID()
+	if err := s.commentRepo.SoftDelete(ctx, comment); err != nil {
+		return err
+	}
+
+	logger.Info("评论删除成功",
+		logger.String("comment_id", id.String()),
+		logger.Uint("operator_id", actor.UserID))
+	return nil
+}
+
+// ToggleLike 切换当前用户对评论的点赞，返回点赞状态与点赞数
+func (s *CommentService) ToggleLike(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (bool, int, error) {
+	if actor == nil {
+		return false, 0, ErrCommentForbidden
+	}
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, 0, err
+	}
+	if comment.IsDeleted() {
+		return false, 0, fmt.Errorf("%w: 不能点赞已删除的评论", ErrInvalidComment)
+	}
+	if _, err := s.knowledgePointService.GetKnowledgePoint(ctx, comment.KnowledgePointID, actor); err != nil {
+		return false, 0, err
+	}
+	return s.commentRepo.ToggleLike(ctx, id, actor.UserID)
+}
+
+// getManageable 获取操作者可修改或删除的评论
+func (s *CommentService) getManageable(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.Comment, error) {
+	if actor == nil {
+		return nil, ErrCommentForbidden
+	}
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !comment.IsAuthoredBy(actor.UserID) && !actor.IsModerator() {
+		return nil, ErrCommentForbidden
+	}
+	return comment, nil
+}
+
+// decorate 补充评论作者与当前用户的点赞状态
+func (s *CommentService) decorate(ctx context.Context, page *CommentPage, comments []*entities.Comment, actor *KnowledgePointActor) error {
+	authorIDs := make([]uint, 0, len(comments))
+	commentIDs := make([]uuid.UUID, 0, len(comments))
+	seen := make(map[uint]bool)
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+		if !seen[comment.AuthorID] {
+			seen[comment.AuthorID] = true
+			authorIDs = append(authorIDs, comment.AuthorID)
+		}
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
+		return fmt.Errorf("获取评论作者失败: %w", err)
+	}
+	page.Authors = make(map[uint]*entities.User, len(users))
+	for _, user := range users {
+		page.Authors[user.ID] = user
+	}
+
+	page.Liked = make(map[uuid.UUID]bool)
+	if actor != nil {
+		liked, err := s.commentRepo.GetLikedIDs(ctx, actor.UserID, commentIDs)
+		if err != nil {
+			return err
+		}
+		page.Liked = liked
+	}
+	return nil
+}
+
+// notify 通知被提及的用户与被回复评论的作者；通知失败只记录日志，不影响评论本身
+func (s *CommentService) notify(ctx context.Context, point *entities.KnowledgePoint, comment, parent *entities.Comment) {
+	actorName := s.authorName(ctx, comment.AuthorID)
+	excluded := make(map[string]bool)
+	if parent != nil && parent.AuthorID != comment.AuthorID {
+		actorID := comment.AuthorID
+		err := s.notificationRepo.CreateBatch(ctx, []*entities.Notification{{
+			UserID:           parent.AuthorID,
+			Type:             string(entities.NotificationTypeReply),
+			ActorID:          &actorID,
+			KnowledgePointID: &point.ID,
+			CommentID:        &comment.ID,
+			Message:          fmt.Sprintf("%s 回复了你在「%s」下的评论", actorName, point.Title),
+		}})
+		if err != nil {
+			logger.Error("创建回复通知失败", logger.Err(err))
+		}
+		// 被回复者已收到回复通知，不再重复通知提及
+		if author, err := s.userRepo.GetByID(ctx, parent.AuthorID); err == nil && author != nil {
+			excluded[strings.ToLower(author.Username)] = true
+		}
+	}
+	s.notifyMentions(ctx, point, comment, actorName, excluded)
+}
+
+// notifyMentions 通知评论中被@提及的用户，跳过作者本人、非正常状态的用户以及excluded中的用户名（小写）
+func (s *CommentService) notifyMentions(ctx context.Context, point *entities.KnowledgePoint, comment *entities.Comment, actorName string, excluded map[string]bool) {
+	var usernames []string
+	for _, name := range parseMentions(comment.Body) {
+		if !excluded[strings.ToLower(name)] {
+			usernames = append(usernames, name)
+		}
+	}
+	if len(usernames) == 0 {
+		return
+	}
+	users, err := s.userRepo.GetByUsernames(ctx, usernames)
+	if err != nil {
+		logger.Error("查找被提及用户失败", logger.Err(err))
+		return
+	}
+
+	actorID := comment.AuthorID
+	message := fmt.Sprintf("%s 在「%s」的评论中提到了你", actorName, point.Title)
+	var notifications []*entities.Notification
+	for _, user := range users {
+		if user.ID == comment.AuthorID || user.DeletedAt != nil || user.Status != string(entities.StatusActive) {
+			continue
+		}
+		notifications = append(notifications, &entities.Notification{
+			UserID:           user.ID,
+			Type:             string(entities.NotificationTypeMention),
+			ActorID:          &actorID,
+			KnowledgePointID: &point.ID,
+			CommentID:        &comment.ID,
+			Message:          message,
+		})
+	}
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		logger.Error("创建提及通知失败", logger.Err(err))
+	}
+}
+
+// authorName 获取用户名，查询失败时返回占位名称
+func (s *CommentService) authorName(ctx context.Context, userID uint) string {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return "有人"
+	}
+	return user.Username
+}
+
+// normalizeCommentBody 校验评论正文：不能为空、不超过长度限制，并与知识点内容一样拒绝不安全的Markdown
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: 评论内容不能为空", ErrInvalidComment)
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("%w: 评论内容不能超过%d个字符", ErrInvalidComment, maxCommentLength)
+	}
+	if err := markdown.Validate(body); err != nil {
+		return "", err
+	}
+	return body, nil
+}
+
+// parseMentions 解析正文中的@用户名，按出现顺序去重（不区分大小写），最多返回maxCommentMentions个
+func parseMentions(body string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// 句末的点号不属于用户名
+		name := strings.TrimRight(match[1], ".")
+		key := strings.ToLower(name)
+		if len(name) < 2 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+		if len(names) == maxCommentMentions {
+			break
+		}
+	}
+	return names
+}
+
+// buildCommentTree 将回复按父评论挂到对应的顶层评论下，保持各自的排序
+func buildCommentTree(roots, replies []*entities.Comment) []*CommentNode {
+	nodes := make(map[uuid.UUID]*CommentNode, len(roots)+len(replies))
+	threads := make([]*CommentNode, 0, len(roots))
+	for _, root := range roots {
+		node := &CommentNode{Comment: root, Replies: []*CommentNode{}}
+		nodes[root.ID] = node
+		threads = append(threads, node)
+	}
+	for _, reply := range replies {
+		if _, ok := nodes[reply.ID]; !ok {
+			nodes[reply.ID] = &CommentNode{Comment: reply, Replies: []*CommentNode{}}
+		}
+	}
+	for _, reply := range replies {
+		if reply.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, nodes[reply.ID])
+		}
+	}
+	return threads
+}
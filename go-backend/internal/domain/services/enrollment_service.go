@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxStudySecondsPerReport 单次上报的最大学习时长(秒)，超出部分不计入
+const maxStudySecondsPerReport = 4 * 60 * 60
+
+// 报名错误
+var (
+	ErrInvalidEnrollment   = errors.New("学习进度无效")
+	ErrAlreadyEnrolled     = errors.New("已报名该学习路径")
+	ErrEnrollmentForbidden = errors.New("无权管理学习路径的公开状态")
+)
+
+// PublicLearningPath 公开学习路径及其报名统计
+type PublicLearningPath struct {
+	Path      *entities.LearningPath
+	Enrolled  int64
+	Completed int64
+}
+
+// EnrollmentStepProgress 步骤进度及其知识点
+type EnrollmentStepProgress struct {
+	Step           *entities.PathEnrollmentStep
+	KnowledgePoint *entities.KnowledgePoint // 知识点已删除时为nil
+}
+
+// EnrollmentProgress 报名记录及全部步骤进度
+type EnrollmentProgress struct {
+	Enrollment     *entities.PathEnrollment
+	Path           *entities.LearningPath
+	Steps          []*EnrollmentStepProgress
+	CompletedSteps int
+}
+
+// EnrollmentSummary 报名记录及进度汇总
+type EnrollmentSummary struct {
+	Enrollment     *entities.PathEnrollment
+	Path           *entities.LearningPath // 学习路径已删除时为nil
+	TotalSteps     int64
+	CompletedSteps int64
+}
+
+// EnrollmentService 公开学习路径报名服务；每位学习者的步骤进度独立记录，互不影响
+type EnrollmentService struct {
+	enrollmentRepo repositories.EnrollmentRepository
+	pathRepo       repositories.LearningPathRepository
+	knowledgeRepo  repositories.KnowledgePointRepository
+}
+
+// NewEnrollmentService 创建公开学习路径报名服务
+func NewEnrollmentService(
+	enrollmentRepo repositories.EnrollmentRepository,
+	pathRepo repositories.LearningPathRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+) *EnrollmentService {
+	return &EnrollmentService{
+		enrollmentRepo: enrollmentRepo,
+		pathRepo:       pathRepo,
+		knowledgeRepo:  knowledgeRepo,
+	}
+}
+
+// ListPublicPaths 获取公开学习路径目录及报名统计
+func (s *EnrollmentService) ListPublicPaths(ctx context.Context, query string, offset, limit int) ([]*PublicLearningPath, int64, error) {
+	paths, total, err := s.pathRepo.ListPublic(ctx, query, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]uuid.UUID, 0, len(paths))
+	for _, path := range paths {
+		ids = append(ids, path.ID)
+	}
+	stats, err := s.enrollmentRepo.GetPathStats(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*PublicLearningPath, 0, len(paths))
+	for _, path := range paths {
+		result := &PublicLearningPath{Path: path}
+		if stat, ok := stats[path.ID]; ok {
+			result.Enrolled = stat.Enrolled
+			result.Completed = stat.Completed
+		}
+		results = append(results, result)
+	}
+	return results, total, nil
+}
+
+// SetPathPublic 设置学习路径是否公开，仅限管理员；取消公开后已报名的学习者仍可继续学习
+func (s *EnrollmentService) SetPathPublic(ctx context.Context, pathID uuid.UUID, public bool, actor *KnowledgePointActor) (*entities.LearningPath, error) {
+	if !actor.IsAdmin() {
+		return nil, ErrEnrollmentForbidden
+	}
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return nil, err
+	}
+	if path.IsPublic != public {
+		if err := s.pathRepo.SetPublic(ctx, pathID, public); err != nil {
+			return nil, err
+		}
+		path.IsPublic = public
+	}
+
+	logger.Info("学习路径公开状态已更新",
+		logger.String("path_id", pathID.String()),
+		logger.Bool("public", public))
+	return path, nil
+}
+
+// Enroll 报名公开学习路径，按路径中已发布的知识点生成步骤
+func (s *EnrollmentService) Enroll(ctx context.Context, pathID uuid.UUID, actor *KnowledgePointActor) (*EnrollmentProgress, error) {
+	if actor == nil {
+		return nil, ErrEnrollmentForbidden
+	}
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return nil, err
+	}
+	if !path.IsPublic {
+		return nil, fmt.Errorf("%w: 该学习路径未公开", ErrInvalidEnrollment)
+	}
+	if _, err := s.enrollmentRepo.Get(ctx, actor.UserID, pathID); err == nil {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	points := orderPathPoints(path.KnowledgePoints)
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: 该学习路径暂无可学习的知识点", ErrInvalidEnrollment)
+	}
+	enrollment := &entities.PathEnrollment{
+		UserID:         actor.UserID,
+		LearningPathID: pathID,
+		Status:         string(entities.EnrollmentStatusActive),
+	}
+	steps := make([]*entities.PathEnrollmentStep, 0, len(points))
+	for i, point := range points {
+		steps = append(steps, &entities.PathEnrollmentStep{
+			KnowledgePointID: point.ID,
+			Position:         i,
+			Status:           string(entities.EnrollmentStepPending),
+		})
+	}
+	if err := s.enrollmentRepo.Create(ctx, enrollment, steps); err != nil {
+		return nil, err
+	}
+
+	logger.Info("学习路径报名成功",
+		logger.String("path_id", pathID.String()),
+		logger.Int("user_id", int(actor.UserID)),
+		logger.Int("steps", len(steps)))
+	return s.buildProgress(ctx, enrollment, path, steps)
+}
+
+// Unenroll 取消报名并删除学习进度
+func (s *EnrollmentService) Unenroll(ctx context.Context, pathID uuid.UUID, actor *KnowledgePointActor) error {
+	if actor == nil {
+		return ErrEnrollmentForbidden
+	}
+	enrollment, err := s.enrollmentRepo.Get(ctx, actor.UserID, pathID)
+	if err != nil {
+		return err
+	}
+	if err := s.enrollmentRepo.Delete(ctx, enrollment.ID); err != nil {
+		return err
+	}
+
+	logger.Info("已取消学习路径报名",
+		logger.String("path_id", pathID.String()),
+		logger.Int("user_id", int(actor.UserID)))
+	return nil
+}
+
+// GetProgress 获取当前用户在学习路径上的进度；路径新增的知识点追加为新步骤
+func (s *EnrollmentService) GetProgress(ctx context.Context, pathID uuid.UUID, actor *KnowledgePointActor) (*EnrollmentProgress, error) {
+	enrollment, path, steps, err := s.load(ctx, pathID, actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildProgress(ctx, enrollment, path, steps)
+}
+
+// ListMyEnrollments 获取当前用户的报名记录及进度汇总（status筛选）
+func (s *EnrollmentService) ListMyEnrollments(ctx context.Context, status string, actor *KnowledgePointActor) ([]*EnrollmentSummary, error) {
+	if actor == nil {
+		return nil, ErrEnrollmentForbidden
+	}
+	switch entities.EnrollmentStatus(status) {
+	case "", entities.EnrollmentStatusActive, entities.EnrollmentStatusCompleted:
+	default:
+		return nil, fmt.Errorf("%w: 不支持的报名状态 %s", ErrInvalidEnrollment, status)
+	}
+	enrollments, err := s.enrollmentRepo.ListByUser(ctx, actor.UserID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollmentIDs := make([]uuid.UUID, 0, len(enrollments))
+	pathIDs := make([]uuid.UUID, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		enrollmentIDs = append(enrollmentIDs, enrollment.ID)
+		pathIDs = append(pathIDs, enrollment.LearningPathID)
+	}
+	counts, err := s.enrollmentRepo.CountProgress(ctx, enrollmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := s.pathRepo.GetByIDs(ctx, pathIDs)
+	if err != nil {
+		return nil, err
+	}
+	pathMap := make(map[uuid.UUID]*entities.LearningPath, len(paths))
+	for _, path := range paths {
+		pathMap[path.ID] = path
+	}
+
+	summaries := make([]*EnrollmentSummary, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		summary := &EnrollmentSummary{Enrollment: enrollment, Path: pathMap[enrollment.LearningPathID]}
+		if count, ok := counts[enrollment.ID]; ok {
+			summary.TotalSteps = count.Total
+			summary.CompletedSteps = count.Completed
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// StartStep 开始学习步骤
+func (s *EnrollmentService) StartStep(ctx context.Context, pathID, knowledgePointID uuid.UUID, actor *KnowledgePointActor) (*EnrollmentProgress, error) {
+	return s.updateStep(ctx, pathID, knowledgePointID, 0, false, actor)
+}
+
+// RecordStudyTime 记录步骤的学习时长，尚未开始的步骤同时标记为学习中
+func (s *EnrollmentService) RecordStudyTime(ctx context.Context, pathID, knowledgePointID uuid.UUID, seconds int, actor *KnowledgePointActor) (*EnrollmentProgress, error) {
+	if seconds <= 0 {
+		return nil, fmt.Errorf("%w: 学习时长须大于0", ErrInvalidEnrollment)
+	}
+	return s.updateStep(ctx, pathID, knowledgePointID, seconds, false, actor)
+}
+
+// CompleteStep 完成步骤并记录本次学习时长；全部步骤完成时报名记录标记为已完成
+func (s *EnrollmentService) CompleteStep(ctx context.Context, pathID, knowledgePointID uuid.UUID, seconds int, actor *KnowledgePointActor) (*EnrollmentProgress, error) {
+	if seconds < 0 {
+		return nil, fmt.Errorf("%w: 学习时长不能为负数", ErrInvalidEnrollment)
+	}
+	return s.updateStep(ctx, pathID, knowledgePointID, seconds, true, actor)
+}
+
+// updateStep 更新当前用户的步骤进度
+func (s *EnrollmentService) updateStep(ctx context.Context, pathID, knowledgePointID uuid.UUID, seconds int, complete bool, actor *KnowledgePointActor) (*EnrollmentProgress, error) {
+	enrollment, path, steps, err := s.load(ctx, pathID, actor)
+	if err != nil {
+		return nil, err
+	}
+	var step *entities.PathEnrollmentStep
+	for _, candidate := range steps {
+		if candidate.KnowledgePointID == knowledgePointID {
+			step = candidate
+			break
+		}
+	}
+	if step == nil {
+		return nil, fmt.Errorf("%w: 该知识点不在学习路径中", ErrInvalidEnrollment)
+	}
+
+	now := time.Now()
+	if step.StartedAt == nil {
+		step.StartedAt = &now
+	}
+	if step.Status == string(entities.EnrollmentStepPending) {
+		step.Status = string(entities.EnrollmentStepInProgress)
+	}
+	if complete && !step.IsCompleted() {
+		step.Status = string(entities.EnrollmentStepCompleted)
+		step.CompletedAt = &now
+	}
+	seconds = min(seconds, maxStudySecondsPerReport)
+
+	updated, err := s.enrollmentRepo.UpdateStep(ctx, step, seconds)
+	if err != nil {
+		return nil, err
+	}
+	step.TimeSpent += seconds
+	if updated.Status == string(entities.EnrollmentStatusCompleted) && enrollment.Status != updated.Status {
+		logger.Info("学习路径已完成",
+			logger.String("path_id", pathID.String()),
+			logger.Int("user_id", int(actor.UserID)))
+	}
+	return s.buildProgress(ctx, updated, path, steps)
+}
+
+// load 加载当前用户的报名记录与步骤进度，并追加路径中新增的知识点
+func (s *EnrollmentService) load(ctx context.Context, pathID uuid.UUID, actor *KnowledgePointActor) (*entities.PathEnrollment, *entities.LearningPath, []*entities.PathEnrollmentStep, error) {
+	if actor == nil {
+		return nil, nil, nil, ErrEnrollmentForbidden
+	}
+	enrollment, err := s.enrollmentRepo.Get(ctx, actor.UserID, pathID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	steps, err := s.enrollmentRepo.GetSteps(ctx, enrollment.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	existing := make(map[uuid.UUID]bool, len(steps))
+	position := 0
+	for _, step := range steps {
+		existing[step.KnowledgePointID] = true
+		position = max(position, step.Position+1)
+	}
+	var added []*entities.PathEnrollmentStep
+	for _, point := range orderPathPoints(path.KnowledgePoints) {
+		if existing[point.ID] {
+			continue
+		}
+		added = append(added, &entities.PathEnrollmentStep{
+			KnowledgePointID: point.ID,
+			Position:         position,
+			Status:           string(entities.EnrollmentStepPending),
+		})
+		position++
+	}
+	if len(added) > 0 {
+		if err := s.enrollmentRepo.AddSteps(ctx, enrollment.ID, added); err != nil {
+			return nil, nil, nil, err
+		}
+		if enrollment, err = s.enrollmentRepo.Get(ctx, actor.UserID, pathID); err != nil {
+			return nil, nil, nil, err
+		}
+		if steps, err = s.enrollmentRepo.GetSteps(ctx, enrollment.ID); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return enrollment, path, steps, nil
+}
+
+// buildProgress 组装步骤进度与知识点
+func (s *EnrollmentService) buildProgress(ctx context.Context, enrollment *entities.PathEnrollment, path *entities.LearningPath, steps []*entities.PathEnrollmentStep) (*EnrollmentProgress, error) {
+	ids := make([]uuid.UUID, 0, len(steps))
+	for _, step := range steps {
+		ids = append(ids, step.KnowledgePointID)
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	pointMap := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		pointMap[point.ID] = point
+	}
+
+	progress := &EnrollmentProgress{Enrollment: enrollment, Path: path}
+	for _, step := range steps {
+		progress.Steps = append(progress.Steps, &EnrollmentStepProgress{Step: step, KnowledgePoint: pointMap[step.KnowledgePointID]})
+		if step.IsCompleted() {
+			progress.CompletedSteps++
+		}
+	}
+	return progress, nil
+}
+
+// orderPathPoints 按学习顺序排列路径中已发布的知识点：路径内的前置知识点排在前面，其余按标题排序
+func orderPathPoints(points []entities.KnowledgePoint) []*entities.KnowledgePoint {
+	members := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for i := range points {
+		if points[i].IsPublished() {
+			members[points[i].ID] = &points[i]
+		}
+	}
+
+	// 路径内的前置关系
+	indegree := make(map[uuid.UUID]int, len(members))
+	dependents := make(map[uuid.UUID][]uuid.UUID, len(members))
+	for id, point := range members {
+		indegree[id] += 0
+		for _, prerequisite := range point.PrerequisiteIDs() {
+			if _, ok := members[prerequisite]; ok && prerequisite != id {
+				indegree[id]++
+				dependents[prerequisite] = append(dependents[prerequisite], id)
+			}
+		}
+	}
+
+	byTitle := func(ids []uuid.UUID) {
+		sort.Slice(ids, func(i, j int) bool {
+			if members[ids[i]].Title != members[ids[j]].Title {
+				return members[ids[i]].Title < members[ids[j]].Title
+			}
+			return ids[i].String() < ids[j].String()
+		})
+	}
+	var ready []uuid.UUID
+	for id, degree := range indegree {
+		if degree == 0 {
+			ready = append(ready, id)
+		}
+	}
+	ordered := make([]*entities.KnowledgePoint, 0, len(members))
+	placed := make(map[uuid.UUID]bool, len(members))
+	for len(ready) > 0 {
+		byTitle(ready)
+		id := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, members[id])
+		placed[id] = true
+		for _, dependent := range dependents[id] {
+			indegree[dependent]--
+			if indegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	// 存在循环前置时，剩余知识点按标题追加
+	var rest []uuid.UUID
+	for id := range members {
+		if !placed[id] {
+			rest = append(rest, id)
+		}
+	}
+	byTitle(rest)
+	for _, id := range rest {
+		ordered = append(ordered, members[id])
+	}
+	return ordered
+}
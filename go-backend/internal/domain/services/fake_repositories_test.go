@@ -0,0 +1,580 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// fakeTxKey 标记上下文处于测试事务中
+type fakeTxKey struct{}
+
+// fakeDB 记录仓储写入；事务回滚时按相反顺序撤销事务内的写入
+type fakeDB struct {
+	writes  []string // 已生效的写入
+	outside []string // 在事务外执行的写入
+	undo    []func()
+}
+
+// write 记录一次写入及其撤销操作
+func (db *fakeDB) write(ctx context.Context, op string, undo func()) {
+	db.writes = append(db.writes, op)
+	if ctx.Value(fakeTxKey{}) == nil {
+		db.outside = append(db.outside, op)
+	}
+	if undo == nil {
+		undo = func() {}
+	}
+	db.undo = append(db.undo, undo)
+}
+
+// fakeTxManager 模拟事务：fn返回错误时撤销其中的全部写入，嵌套调用等同于保存点
+type fakeTxManager struct {
+	db *fakeDB
+}
+
+// WithinTransaction 在模拟事务中执行fn
+func (m *fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	undoMark, writeMark := len(m.db.undo), len(m.db.writes)
+	if err := fn(context.WithValue(ctx, fakeTxKey{}, true)); err != nil {
+		for i := len(m.db.undo) - 1; i >= undoMark; i-- {
+			m.db.undo[i]()
+		}
+		m.db.undo = m.db.undo[:undoMark]
+		m.db.writes = m.db.writes[:writeMark]
+		return err
+	}
+	return nil
+}
+
+// fakeKnowledgeRepo 内存中的知识点仓储，未实现的方法调用时panic
+type fakeKnowledgeRepo struct {
+	repositories.KnowledgePointRepository
+	db       *fakeDB
+	points   map[uuid.UUID]*entities.KnowledgePoint
+	mergeErr error
+}
+
+func newFakeKnowledgeRepo(db *fakeDB, points ...*entities.KnowledgePoint) *fakeKnowledgeRepo {
+	r := &fakeKnowledgeRepo{db: db, points: make(map[uuid.UUID]*entities.KnowledgePoint)}
+	for _, point := range points {
+		clone := *point
+		r.points[point.ID] = &clone
+	}
+	return r
+}
+
+func (r *fakeKnowledgeRepo) Create(ctx context.Context, point *entities.KnowledgePoint) error {
+	if point.ID == uuid.Nil {
+		point.ID = uuid.New()
+	}
+	clone := *point
+	r.points[point.ID] = &clone
+	r.db.write(ctx, "create knowledge_point "+point.Title, func() { delete(r.points, clone.ID) })
+	return nil
+}
+
+func (r *fakeKnowledgeRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error) {
+	point, ok := r.points[id]
+	if !ok {
+		return nil, repositories.NotFound("知识点不存在")
+	}
+	clone := *point
+	return &clone, nil
+}
+
+func (r *fakeKnowledgeRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeKnowledgeRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	for _, id := range ids {
+		if point, err := r.GetByID(ctx, id); err == nil {
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
+
+func (r *fakeKnowledgeRepo) Update(ctx context.Context, point *entities.KnowledgePoint) error {
+	previous, ok := r.points[point.ID]
+	if !ok {
+		return repositories.NotFound("知识点不存在")
+	}
+	clone := *point
+	r.points[point.ID] = &clone
+	r.db.write(ctx, "update knowledge_point "+point.Title, func() { r.points[previous.ID] = previous })
+	return nil
+}
+
+func (r *fakeKnowledgeRepo) GetByCategory(ctx context.Context, category string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	for _, point := range r.points {
+		if point.Category == category && point.IsPublished() {
+			clone := *point
+			points = append(points, &clone)
+		}
+	}
+	return points, nil
+}
+
+func (r *fakeKnowledgeRepo) GetByAuthor(ctx context.Context, authorID uint) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	for _, point := range r.points {
+		if point.IsAuthoredBy(authorID) {
+			clone := *point
+			points = append(points, &clone)
+		}
+	}
+	return points, nil
+}
+
+func (r *fakeKnowledgeRepo) GetByPrerequisite(ctx context.Context, prerequisiteID uuid.UUID) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	for _, point := range r.points {
+		for _, id := range point.PrerequisiteIDs() {
+			if id == prerequisiteID {
+				clone := *point
+				points = append(points, &clone)
+				break
+			}
+		}
+	}
+	return points, nil
+}
+
+func (r *fakeKnowledgeRepo) MergeInto(ctx context.Context, survivorID, duplicateID uuid.UUID) error {
+	if r.mergeErr != nil {
+		return r.mergeErr
+	}
+	duplicate := r.points[duplicateID]
+	delete(r.points, duplicateID)
+	r.db.write(ctx, "merge knowledge_point", func() { r.points[duplicateID] = duplicate })
+	return nil
+}
+
+func (r *fakeKnowledgeRepo) GetMentionIndex(ctx context.Context) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	for _, point := range r.points {
+		clone := *point
+		points = append(points, &clone)
+	}
+	return points, nil
+}
+
+func (r *fakeKnowledgeRepo) GetByContentMentions(ctx context.Context, terms []string) ([]*entities.KnowledgePoint, error) {
+	return nil, nil
+}
+
+// fakeRevisionRepo 内存中的修订版本仓储
+type fakeRevisionRepo struct {
+	repositories.KnowledgePointRevisionRepository
+	db        *fakeDB
+	revisions []*entities.KnowledgePointRevision
+}
+
+func (r *fakeRevisionRepo) Create(ctx context.Context, revision *entities.KnowledgePointRevision) error {
+	revision.Version = len(r.byPoint(revision.KnowledgePointID)) + 1
+	r.revisions = append(r.revisions, revision)
+	n := len(r.revisions) - 1
+	r.db.write(ctx, "create revision", func() { r.revisions = r.revisions[:n] })
+	return nil
+}
+
+func (r *fakeRevisionRepo) GetLatest(ctx context.Context, knowledgePointID uuid.UUID) (*entities.KnowledgePointRevision, error) {
+	revisions := r.byPoint(knowledgePointID)
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+	return revisions[len(revisions)-1], nil
+}
+
+func (r *fakeRevisionRepo) byPoint(knowledgePointID uuid.UUID) []*entities.KnowledgePointRevision {
+	var revisions []*entities.KnowledgePointRevision
+	for _, revision := range r.revisions {
+		if revision.KnowledgePointID == knowledgePointID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// fakeReviewRepo 内存中的审核记录仓储
+type fakeReviewRepo struct {
+	repositories.KnowledgePointReviewRepository
+	db        *fakeDB
+	reviews   []*entities.KnowledgePointReview
+	createErr error
+}
+
+func (r *fakeReviewRepo) Create(ctx context.Context, review *entities.KnowledgePointReview) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.reviews = append(r.reviews, review)
+	n := len(r.reviews) - 1
+	r.db.write(ctx, "create review", func() { r.reviews = r.reviews[:n] })
+	return nil
+}
+
+// fakeLinkRepo 知识点关联仓储；关联为派生数据，仅记录合并与删除
+type fakeLinkRepo struct {
+	repositories.KnowledgePointLinkRepository
+	db *fakeDB
+}
+
+func (r *fakeLinkRepo) ReplaceOutgoing(ctx context.Context, sourceID uuid.UUID, links []*entities.KnowledgePointLink) error {
+	return nil
+}
+
+func (r *fakeLinkRepo) GetIncoming(ctx context.Context, targetID uuid.UUID) ([]*entities.KnowledgePointLink, error) {
+	return nil, nil
+}
+
+func (r *fakeLinkRepo) DeleteByKnowledgePoint(ctx context.Context, id uuid.UUID) error {
+	r.db.write(ctx, "delete links", nil)
+	return nil
+}
+
+func (r *fakeLinkRepo) MergeInto(ctx context.Context, survivorID, duplicateID uuid.UUID) error {
+	r.db.write(ctx, "merge links", nil)
+	return nil
+}
+
+// fakeFingerprintRepo 查重指纹仓储；指纹为派生数据，仅记录删除
+type fakeFingerprintRepo struct {
+	repositories.KnowledgePointFingerprintRepository
+	db *fakeDB
+}
+
+func (r *fakeFingerprintRepo) Save(ctx context.Context, fingerprint *entities.KnowledgePointFingerprint) error {
+	return nil
+}
+
+func (r *fakeFingerprintRepo) Delete(ctx context.Context, knowledgePointID uuid.UUID) error {
+	r.db.write(ctx, "delete fingerprint", nil)
+	return nil
+}
+
+// fakeGlossaryRepo 空术语表
+type fakeGlossaryRepo struct {
+	repositories.GlossaryRepository
+}
+
+func (r *fakeGlossaryRepo) GetAll(ctx context.Context) ([]*entities.GlossaryTerm, error) {
+	return nil, nil
+}
+
+func (r *fakeGlossaryRepo) FindByNames(ctx context.Context, names []string) ([]*entities.GlossaryTerm, error) {
+	return nil, nil
+}
+
+// fakeAttachmentRepo 内存中的附件仓储，引用以"类型:ID"为键记录
+type fakeAttachmentRepo struct {
+	repositories.AttachmentRepository
+	db          *fakeDB
+	attachments map[uuid.UUID]*entities.Attachment
+	references  map[string][]uuid.UUID
+}
+
+func newFakeAttachmentRepo(db *fakeDB) *fakeAttachmentRepo {
+	return &fakeAttachmentRepo{
+		db:          db,
+		attachments: make(map[uuid.UUID]*entities.Attachment),
+		references:  make(map[string][]uuid.UUID),
+	}
+}
+
+func (r *fakeAttachmentRepo) Create(ctx context.Context, attachment *entities.Attachment) error {
+	r.attachments[attachment.ID] = attachment
+	return nil
+}
+
+func (r *fakeAttachmentRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Attachment, error) {
+	var attachments []*entities.Attachment
+	for _, id := range ids {
+		if attachment, ok := r.attachments[id]; ok {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (r *fakeAttachmentRepo) AddReferences(ctx context.Context, refType entities.AttachmentRefType, refID string, attachmentIDs []uuid.UUID) error {
+	key := string(refType) + ":" + refID
+	previous := r.references[key]
+	r.references[key] = append(previous, attachmentIDs...)
+	r.db.write(ctx, "add attachment references", func() { r.references[key] = previous })
+	return nil
+}
+
+// fakeFlashcardRepo 内存中的闪卡仓储
+type fakeFlashcardRepo struct {
+	repositories.FlashcardRepository
+	db    *fakeDB
+	cards map[uuid.UUID]*entities.Flashcard
+}
+
+func (r *fakeFlashcardRepo) CreateBatch(ctx context.Context, cards []*entities.Flashcard) error {
+	for _, card := range cards {
+		if card.ID == uuid.Nil {
+			card.ID = uuid.New()
+		}
+		id := card.ID
+		r.cards[id] = card
+		r.db.write(ctx, "create flashcard", func() { delete(r.cards, id) })
+	}
+	return nil
+}
+
+func (r *fakeFlashcardRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Flashcard, error) {
+	card, ok := r.cards[id]
+	if !ok {
+		return nil, repositories.NotFound("闪卡不存在")
+	}
+	return card, nil
+}
+
+func (r *fakeFlashcardRepo) GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.Flashcard, error) {
+	var cards []*entities.Flashcard
+	for _, card := range r.cards {
+		if card.KnowledgePointID == knowledgePointID {
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+// fakeFlashcardReviewRepo 内存中的闪卡调度状态仓储
+type fakeFlashcardReviewRepo struct {
+	repositories.FlashcardReviewRepository
+	db        *fakeDB
+	reviews   []*entities.FlashcardReview
+	createErr error
+}
+
+func (r *fakeFlashcardReviewRepo) GetByUserAndFlashcard(ctx context.Context, userID uint, flashcardID uuid.UUID) (*entities.FlashcardReview, error) {
+	for _, review := range r.reviews {
+		if review.UserID == userID && review.FlashcardID == flashcardID {
+			return review, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeFlashcardReviewRepo) GetFlashcardIDsByUser(ctx context.Context, userID uint, flashcardIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ids := make(map[uuid.UUID]bool)
+	for _, id := range flashcardIDs {
+		if review, _ := r.GetByUserAndFlashcard(ctx, userID, id); review != nil {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeFlashcardReviewRepo) GetByUserID(ctx context.Context, userID uint) ([]*entities.FlashcardReview, error) {
+	var reviews []*entities.FlashcardReview
+	for _, review := range r.reviews {
+		if review.UserID == userID {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (r *fakeFlashcardReviewRepo) CreateBatch(ctx context.Context, reviews []*entities.FlashcardReview) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	n := len(r.reviews)
+	r.reviews = append(r.reviews, reviews...)
+	r.db.write(ctx, "create flashcard reviews", func() { r.reviews = r.reviews[:n] })
+	return nil
+}
+
+func (r *fakeFlashcardReviewRepo) Save(ctx context.Context, review *entities.FlashcardReview) error {
+	if existing, _ := r.GetByUserAndFlashcard(ctx, review.UserID, review.FlashcardID); existing == nil {
+		r.reviews = append(r.reviews, review)
+	}
+	r.db.write(ctx, "save flashcard review", nil)
+	return nil
+}
+
+// fakePathRepo 内存中的学习路径仓储
+type fakePathRepo struct {
+	repositories.LearningPathRepository
+	paths map[uuid.UUID]*entities.LearningPath
+}
+
+func (r *fakePathRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.LearningPath, error) {
+	path, ok := r.paths[id]
+	if !ok {
+		return nil, repositories.NotFound("学习路径不存在")
+	}
+	return path, nil
+}
+
+// fakeEnrollmentRepo 内存中的报名记录仓储，以"用户ID:路径ID"为键
+type fakeEnrollmentRepo struct {
+	repositories.EnrollmentRepository
+	enrolled map[string]bool
+}
+
+func (r *fakeEnrollmentRepo) Get(ctx context.Context, userID uint, pathID uuid.UUID) (*entities.PathEnrollment, error) {
+	if !r.enrolled[fmt.Sprintf("%d:%s", userID, pathID)] {
+		return nil, repositories.NotFound("尚未报名该学习路径")
+	}
+	return &entities.PathEnrollment{UserID: userID, LearningPathID: pathID}, nil
+}
+
+// fakeModerationRepo 内存中的举报与审核记录仓储
+type fakeModerationRepo struct {
+	repositories.ModerationRepository
+	db         *fakeDB
+	reports    map[uuid.UUID]*entities.Report
+	actions    []*entities.ModerationAction
+	resolveErr error
+}
+
+func (r *fakeModerationRepo) GetReport(ctx context.Context, id uuid.UUID) (*entities.Report, error) {
+	report, ok := r.reports[id]
+	if !ok {
+		return nil, repositories.NotFound("举报不存在")
+	}
+	clone := *report
+	return &clone, nil
+}
+
+func (r *fakeModerationRepo) ResolveReports(ctx context.Context, action *entities.ModerationAction, status entities.ReportStatus) error {
+	if r.resolveErr != nil {
+		return r.resolveErr
+	}
+	for _, report := range r.reports {
+		if report.TargetID == action.TargetID && report.IsOpen() {
+			report.Status = string(status)
+			action.ReportsResolved++
+		}
+	}
+	r.actions = append(r.actions, action)
+	r.db.write(ctx, "resolve reports", nil)
+	return nil
+}
+
+// fakeCommentRepo 内存中的评论仓储
+type fakeCommentRepo struct {
+	repositories.CommentRepository
+	db       *fakeDB
+	comments map[uuid.UUID]*entities.Comment
+}
+
+func (r *fakeCommentRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
+	comment, ok := r.comments[id]
+	if !ok {
+		return nil, repositories.NotFound("评论不存在")
+	}
+	clone := *comment
+	return &clone, nil
+}
+
+func (r *fakeCommentRepo) SoftDelete(ctx context.Context, comment *entities.Comment) error {
+	previous := r.comments[comment.ID]
+	clone := *comment
+	r.comments[comment.ID] = &clone
+	r.db.write(ctx, "delete comment", func() { r.comments[previous.ID] = previous })
+	return nil
+}
+
+// fakeUserRepo 内存中的用户仓储
+type fakeUserRepo struct {
+	repositories.UserRepository
+	db    *fakeDB
+	users map[uint]*entities.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*entities.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repositories.NotFound("用户不存在")
+	}
+	clone := *user
+	return &clone, nil
+}
+
+func (r *fakeUserRepo) UpdateStatus(ctx context.Context, id uint, status string) error {
+	user := r.users[id]
+	previous := user.Status
+	user.Status = status
+	r.db.write(ctx, "update user status", func() { user.Status = previous })
+	return nil
+}
+
+// fakeNotificationRepo 内存中的通知仓储
+type fakeNotificationRepo struct {
+	repositories.NotificationRepository
+	notifications []*entities.Notification
+}
+
+func (r *fakeNotificationRepo) CreateBatch(ctx context.Context, notifications []*entities.Notification) error {
+	r.notifications = append(r.notifications, notifications...)
+	return nil
+}
+
+// knowledgeFixture 基于内存仓储的知识点服务
+type knowledgeFixture struct {
+	db          *fakeDB
+	tx          *fakeTxManager
+	points      *fakeKnowledgeRepo
+	revisions   *fakeRevisionRepo
+	reviews     *fakeReviewRepo
+	attachments *fakeAttachmentRepo
+	service     *KnowledgePointService
+}
+
+func newKnowledgeFixture(points ...*entities.KnowledgePoint) *knowledgeFixture {
+	db := &fakeDB{}
+	f := &knowledgeFixture{
+		db:          db,
+		tx:          &fakeTxManager{db: db},
+		points:      newFakeKnowledgeRepo(db, points...),
+		revisions:   &fakeRevisionRepo{db: db},
+		reviews:     &fakeReviewRepo{db: db},
+		attachments: newFakeAttachmentRepo(db),
+	}
+	f.service = NewKnowledgePointService(
+		f.points,
+		f.revisions,
+		f.reviews,
+		f.attachments,
+		&fakeLinkRepo{db: db},
+		&fakeGlossaryRepo{},
+		&fakeFingerprintRepo{db: db},
+		f.tx,
+	)
+	return f
+}
+
+// testKnowledgePoint 创建测试用知识点
+func testKnowledgePoint(title string, status entities.KnowledgePointStatus, authorID uint) *entities.KnowledgePoint {
+	return &entities.KnowledgePoint{
+		ID:            uuid.New(),
+		Title:         title,
+		Category:      "测试",
+		Difficulty:    "beginner",
+		Content:       title + "的内容",
+		Resources:     "[]",
+		Prerequisites: "[]",
+		Tags:          "[]",
+		Aliases:       "[]",
+		Status:        string(status),
+		AuthorID:      &authorID,
+	}
+}
+
+// uintPtr 返回指向v的指针
+func uintPtr(v uint) *uint {
+	return &v
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/cloze"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/textsearch"
+)
+
+const (
+	// defaultEaseFactor 新卡片的默认难度系数
+	defaultEaseFactor = 2.5
+	// minEaseFactor 难度系数下限
+	minEaseFactor = 1.3
+	// relearnDelay 遗忘后重新学习的等待时间
+	relearnDelay = 10 * time.Minute
+)
+
+// ErrInvalidClozeAnswer 挖空作答无效
+var ErrInvalidClozeAnswer = errors.New("挖空作答无效")
+
+// ErrFlashcardForbidden 无权编辑闪卡
+var ErrFlashcardForbidden = errors.New("无权编辑该知识点的闪卡")
+
+// FlashcardService 闪卡服务
+type FlashcardService struct {
+	flashcardRepo repositories.FlashcardRepository
+	reviewRepo    repositories.FlashcardReviewRepository
+	knowledgeRepo repositories.KnowledgePointRepository
+	glossaryRepo  repositories.GlossaryRepository
+
+	knowledgePointService *KnowledgePointService
+}
+
+// NewFlashcardService 创建闪卡服务
+func NewFlashcardService(
+	flashcardRepo repositories.FlashcardRepository,
+	reviewRepo repositories.FlashcardReviewRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	glossaryRepo repositories.GlossaryRepository,
+	knowledgePointService *KnowledgePointService,
+) *FlashcardService {
+	return &FlashcardService{
+		flashcardRepo:         flashcardRepo,
+		reviewRepo:            reviewRepo,
+		knowledgeRepo:         knowledgeRepo,
+		glossaryRepo:          glossaryRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// ensureEditable 检查操作者可编辑闪卡所属的知识点
+func (s *FlashcardService) ensureEditable(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) (*entities.KnowledgePoint, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, knowledgePointID)
+	if err != nil {
+		return nil, fmt.Errorf("获取知识点失败: %w", err)
+	}
+	if !s.knowledgePointService.CanEdit(point, actor) {
+		return nil, ErrFlashcardForbidden
+	}
+	return point, nil
+}
+
+// getVisibleFlashcard 获取闪卡，所属知识点对操作者不可见时按不存在处理
+func (s *FlashcardService) getVisibleFlashcard(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.Flashcard, error) {
+	card, err := s.flashcardRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.knowledgePointService.GetKnowledgePoint(ctx, card.KnowledgePointID, actor); err != nil {
+		return nil, repositories.NotFound("闪卡不存在")
+	}
+	return card, nil
+}
+
+// CreateFlashcards 创建闪卡；cloze卡片会按挖空序号各生成一张，需有知识点的编辑权限
+func (s *FlashcardService) CreateFlashcards(ctx context.Context, card *entities.Flashcard, actor *KnowledgePointActor) ([]*entities.Flashcard, error) {
+	if _, err := s.ensureEditable(ctx, card.KnowledgePointID, actor); err != nil {
+		return nil, err
+	}
+	card.CreatedBy = actor.UserID
+
+	if !card.IsCloze() {
+		card.CardType = string(entities.FlashcardTypeBasic)
+		card.ClozeIndex = 0
+		if err := s.flashcardRepo.Create(ctx, card); err != nil {
+			return nil, err
+		}
+		return []*entities.Flashcard{card}, nil
+	}
+
+	cards := s.buildClozeCards(card.KnowledgePointID, card.Front, card.Back, card.CreatedBy)
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("cloze卡片缺少挖空标记")
+	}
+	if err := s.flashcardRepo.CreateBatch(ctx, cards); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// GenerateClozeCards 根据知识点内容中的挖空标记批量生成闪卡，已存在的卡片会被跳过；需有知识点的编辑权限
+func (s *FlashcardService) GenerateClozeCards(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) ([]*entities.Flashcard, error) {
+	point, err := s.ensureEditable(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.flashcardRepo.GetByKnowledgePointID(ctx, knowledgePointID)
+	if err != nil {
+		return nil, err
+	}
+	existingKeys := make(map[string]bool)
+	for _, card := range existing {
+		if card.IsCloze() {
+			existingKeys[fmt.Sprintf("%d:%s", card.ClozeIndex, card.Front)] = true
+		}
+	}
+
+	var cards []*entities.Flashcard
+	for _, paragraph := range cloze.Split(point.Content) {
+		for _, card := range s.buildClozeCards(knowledgePointID, paragraph, "", actor.UserID) {
+			if !existingKeys[fmt.Sprintf("%d:%s", card.ClozeIndex, card.Front)] {
+				cards = append(cards, card)
+			}
+		}
+	}
+
+	if err := s.flashcardRepo.CreateBatch(ctx, cards); err != nil {
+		return nil, err
+	}
+
+	logger.Info("挖空闪卡生成完成",
+		logger.String("knowledge_point_id", knowledgePointID.String()),
+		logger.Int("cards_count", len(cards)))
+
+	return cards, nil
+}
+
+// GetFlashcard 获取操作者可查看的单张闪卡
+func (s *FlashcardService) GetFlashcard(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.Flashcard, error) {
+	return s.getVisibleFlashcard(ctx, id, actor)
+}
+
+// GetFlashcardsByKnowledgePoint 获取操作者可查看的知识点下的闪卡
+func (s *FlashcardService) GetFlashcardsByKnowledgePoint(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) ([]*entities.Flashcard, error) {
+	if _, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor); err != nil {
+		return nil, err
+	}
+	return s.flashcardRepo.GetByKnowledgePointID(ctx, knowledgePointID)
+}
+
+// UpdateFlashcard 更新闪卡，需有知识点的编辑权限
+func (s *FlashcardService) UpdateFlashcard(ctx context.Context, card *entities.Flashcard, actor *KnowledgePointActor) error {
+	if _, err := s.ensureEditable(ctx, card.KnowledgePointID, actor); err != nil {
+		return err
+	}
+	if card.IsCloze() && !cloze.HasOrdinal(card.Front, card.ClozeIndex) {
+		return fmt.Errorf("挖空序号 c%d 不存在于卡片内容中", card.ClozeIndex)
+	}
+	return s.flashcardRepo.Update(ctx, card)
+}
+
+// DeleteFlashcard 删除闪卡及其调度状态，需有知识点的编辑权限
+func (s *FlashcardService) DeleteFlashcard(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	card, err := s.flashcardRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.ensureEditable(ctx, card.KnowledgePointID, actor); err != nil {
+		return err
+	}
+	if err := s.reviewRepo.DeleteByFlashcardID(ctx, id); err != nil {
+		return err
+	}
+	return s.flashcardRepo.Delete(ctx, id)
+}
+
+// StudyKnowledgePoint 将操作者可查看的知识点下的全部闪卡加入其复习计划，返回新加入的数量
+func (s *FlashcardService) StudyKnowledgePoint(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) (int, error) {
+	if actor == nil {
+		return 0, ErrFlashcardForbidden
+	}
+	if _, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor); err != nil {
+		return 0, err
+	}
+	userID := actor.UserID
+
+	cards, err := s.flashcardRepo.GetByKnowledgePointID(ctx, knowledgePointID)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(cards))
+	for _, card := range cards {
+		ids = append(ids, card.ID)
+	}
+	existing, err := s.reviewRepo.GetFlashcardIDsByUser(ctx, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var reviews []*entities.FlashcardReview
+	for _, card := range cards {
+		if !existing[card.ID] {
+			reviews = append(reviews, s.newReview(userID, card.ID, now))
+		}
+	}
+
+	if err := s.reviewRepo.CreateBatch(ctx, reviews); err != nil {
+		return 0, err
+	}
+	return len(reviews), nil
+}
+
+// GetDueFlashcards 获取用户当前到期的闪卡
+func (s *FlashcardService) GetDueFlashcards(ctx context.Context, userID uint, limit int) ([]*entities.FlashcardReview, error) {
+	return s.reviewRepo.GetDue(ctx, userID, time.Now(), limit)
+}
+
+// ReviewFlashcard 记录操作者对可查看闪卡的一次复习并按SM-2算法安排下次复习
+func (s *FlashcardService) ReviewFlashcard(ctx context.Context, flashcardID uuid.UUID, rating entities.ReviewRating, actor *KnowledgePointActor) (*entities.FlashcardReview, error) {
+	if actor == nil {
+		return nil, ErrFlashcardForbidden
+	}
+	if rating < entities.ReviewRatingAgain || rating > entities.ReviewRatingEasy {
+		return nil, fmt.Errorf("无效的评分: %d", rating)
+	}
+
+	card, err := s.getVisibleFlashcard(ctx, flashcardID, actor)
+	if err != nil {
+		return nil, err
+	}
+	userID := actor.UserID
+
+	now := time.Now()
+	review, err := s.reviewRepo.GetByUserAndFlashcard(ctx, userID, flashcardID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		review = s.newReview(userID, flashcardID, now)
+	}
+
+	s.schedule(review, rating, now)
+
+	if err := s.reviewRepo.Save(ctx, review); err != nil {
+		return nil, err
+	}
+	review.Flashcard = *card
+
+	return review, nil
+}
+
+// ClozeBlankResult 单个挖空的判定结果
+type ClozeBlankResult struct {
+	Expected string `json:"expected"`
+	Given    string `json:"given"`
+	Correct  bool   `json:"correct"`
+	Synonym  bool   `json:"synonym"` // 作答为术语表中的同义词或缩写
+}
+
+// ClozeCheckResult 挖空作答判定结果
+type ClozeCheckResult struct {
+	Correct         bool                  `json:"correct"`
+	Blanks          []ClozeBlankResult    `json:"blanks"`
+	SuggestedRating entities.ReviewRating `json:"suggested_rating"`
+}
+
+// CheckClozeAnswer 判定挖空卡片的作答：answers按出现顺序对应本卡片序号的各个挖空，
+// 忽略大小写、全半角、空白与标点，术语表中的同义词与缩写同样视为正确
+func (s *FlashcardService) CheckClozeAnswer(ctx context.Context, flashcardID uuid.UUID, answers []string, actor *KnowledgePointActor) (*ClozeCheckResult, error) {
+	card, err := s.getVisibleFlashcard(ctx, flashcardID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !card.IsCloze() {
+		return nil, fmt.Errorf("%w: 闪卡不是挖空卡片", ErrInvalidClozeAnswer)
+	}
+	expected := cloze.Answers(card.Front, card.ClozeIndex)
+	if len(answers) != len(expected) {
+		return nil, fmt.Errorf("%w: 作答数量不匹配，需要 %d 个，实际 %d 个", ErrInvalidClozeAnswer, len(expected), len(answers))
+	}
+
+	synonyms, err := lookupSynonyms(ctx, s.glossaryRepo, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ClozeCheckResult{Correct: true, Blanks: make([]ClozeBlankResult, 0, len(expected))}
+	for i, answer := range expected {
+		blank := ClozeBlankResult{Expected: answer, Given: answers[i]}
+		given := cloze.NormalizeAnswer(answers[i])
+		if given != "" && given == cloze.NormalizeAnswer(answer) {
+			blank.Correct = true
+		} else if given != "" {
+			for _, synonym := range synonyms[textsearch.NormalizeTerm(answer)] {
+				if given == cloze.NormalizeAnswer(synonym) {
+					blank.Correct, blank.Synonym = true, true
+					break
+				}
+			}
+		}
+		result.Correct = result.Correct && blank.Correct
+		result.Blanks = append(result.Blanks, blank)
+	}
+
+	result.SuggestedRating = entities.ReviewRatingAgain
+	if result.Correct {
+		result.SuggestedRating = entities.ReviewRatingGood
+	}
+	return result, nil
+}
+
+// schedule 按SM-2算法更新调度状态
+func (s *FlashcardService) schedule(review *entities.FlashcardReview, rating entities.ReviewRating, now time.Time) {
+	review.LastReviewedAt = &now
+
+	if rating == entities.ReviewRatingAgain {
+		review.Lapses++
+		review.Repetitions = 0
+		review.Interval = 0
+		review.EaseFactor = math.Max(minEaseFactor, review.EaseFactor-0.2)
+		review.DueAt = now.Add(relearnDelay)
+		return
+	}
+
+	review.Repetitions++
+	switch rating {
+	case entities.ReviewRatingHard:
+		review.EaseFactor = math.Max(minEaseFactor, review.EaseFactor-0.15)
+		review.Interval = int(math.Max(1, math.Round(float64(review.Interval)*1.2)))
+	case entities.ReviewRatingGood:
+		review.Interval = s.nextInterval(review, false)
+	case entities.ReviewRatingEasy:
+		review.EaseFactor += 0.15
+		review.Interval = s.nextInterval(review, true)
+	}
+
+	review.DueAt = now.AddDate(0, 0, review.Interval)
+}
+
+// nextInterval 计算下次复习间隔(天)，easy为true时给予额外奖励
+func (s *FlashcardService) nextInterval(review *entities.FlashcardReview, easy bool) int {
+	bonus := 1.0
+	if easy {
+		bonus = 1.3
+	}
+
+	switch review.Repetitions {
+	case 1:
+		if easy {
+			return 4
+		}
+		return 1
+	case 2:
+		return int(math.Round(6 * bonus))
+	default:
+		next := math.Round(float64(review.Interval) * review.EaseFactor * bonus)
+		return int(math.Max(float64(review.Interval+1), next))
+	}
+}
+
+// newReview 创建新的调度状态，立即到期
+func (s *FlashcardService) newReview(userID uint, flashcardID uuid.UUID, now time.Time) *entities.FlashcardReview {
+	return &entities.FlashcardReview{
+		ID:          uuid.New(),
+		FlashcardID: flashcardID,
+		UserID:      userID,
+		EaseFactor:  defaultEaseFactor,
+		DueAt:       now,
+	}
+}
+
+// buildClozeCards 为一段挖空文本的每个序号构建一张卡片
+func (s *FlashcardService) buildClozeCards(knowledgePointID uuid.UUID, text, extra string, userID uint) []*entities.Flashcard {
+	var cards []*entities.Flashcard
+	for _, ordinal := range cloze.Ordinals(text) {
+		cards = append(cards, &entities.Flashcard{
+			ID:               uuid.New(),
+			KnowledgePointID: knowledgePointID,
+			CardType:         string(entities.FlashcardTypeCloze),
+			Front:            text,
+			Back:             extra,
+			ClozeIndex:       ordinal,
+			CreatedBy:        userID,
+		})
+	}
+	return cards
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+func TestFlashcardServiceSchedule(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	// state 调度前后的复习状态
+	type state struct {
+		ease        float64
+		interval    int
+		repetitions int
+		lapses      int
+	}
+	tests := []struct {
+		name    string
+		before  state
+		rating  entities.ReviewRating
+		want    state
+		wantDue time.Duration
+	}{
+		{
+			name:    "新卡片记得",
+			before:  state{ease: defaultEaseFactor},
+			rating:  entities.ReviewRatingGood,
+			want:    state{ease: 2.5, interval: 1, repetitions: 1},
+			wantDue: day,
+		},
+		{
+			name:    "新卡片简单",
+			before:  state{ease: defaultEaseFactor},
+			rating:  entities.ReviewRatingEasy,
+			want:    state{ease: 2.65, interval: 4, repetitions: 1},
+			wantDue: 4 * day,
+		},
+		{
+			name:    "新卡片困难至少间隔一天",
+			before:  state{ease: defaultEaseFactor},
+			rating:  entities.ReviewRatingHard,
+			want:    state{ease: 2.35, interval: 1, repetitions: 1},
+			wantDue: day,
+		},
+		{
+			name:    "第二次记得",
+			before:  state{ease: 2.5, interval: 1, repetitions: 1},
+			rating:  entities.ReviewRatingGood,
+			want:    state{ease: 2.5, interval: 6, repetitions: 2},
+			wantDue: 6 * day,
+		},
+		{
+			name:    "第二次简单",
+			before:  state{ease: 2.5, interval: 1, repetitions: 1},
+			rating:  entities.ReviewRatingEasy,
+			want:    state{ease: 2.65, interval: 8, repetitions: 2},
+			wantDue: 8 * day,
+		},
+		{
+			name:    "之后记得按难度系数增长",
+			before:  state{ease: 2.5, interval: 6, repetitions: 2},
+			rating:  entities.ReviewRatingGood,
+			want:    state{ease: 2.5, interval: 15, repetitions: 3},
+			wantDue: 15 * day,
+		},
+		{
+			name:    "之后简单给予额外奖励",
+			before:  state{ease: 2.5, interval: 6, repetitions: 2},
+			rating:  entities.ReviewRatingEasy,
+			want:    state{ease: 2.65, interval: 21, repetitions: 3},
+			wantDue: 21 * day,
+		},
+		{
+			name:    "困难降低难度系数并小幅延长间隔",
+			before:  state{ease: 2.5, interval: 6, repetitions: 2},
+			rating:  entities.ReviewRatingHard,
+			want:    state{ease: 2.35, interval: 7, repetitions: 3},
+			wantDue: 7 * day,
+		},
+		{
+			name:    "低难度系数时间隔至少增加一天",
+			before:  state{ease: minEaseFactor, interval: 1, repetitions: 3},
+			rating:  entities.ReviewRatingGood,
+			want:    state{ease: minEaseFactor, interval: 2, repetitions: 4},
+			wantDue: 2 * day,
+		},
+		{
+			name:    "困难时难度系数不低于下限",
+			before:  state{ease: 1.4, interval: 10, repetitions: 4},
+			rating:  entities.ReviewRatingHard,
+			want:    state{ease: minEaseFactor, interval: 12, repetitions: 5},
+			wantDue: 12 * day,
+		},
+		{
+			name:    "忘记后重新学习",
+			before:  state{ease: 2.5, interval: 15, repetitions: 3, lapses: 1},
+			rating:  entities.ReviewRatingAgain,
+			want:    state{ease: 2.3, interval: 0, repetitions: 0, lapses: 2},
+			wantDue: relearnDelay,
+		},
+		{
+			name:    "忘记时难度系数不低于下限",
+			before:  state{ease: minEaseFactor, interval: 3, repetitions: 2},
+			rating:  entities.ReviewRatingAgain,
+			want:    state{ease: minEaseFactor, interval: 0, repetitions: 0, lapses: 1},
+			wantDue: relearnDelay,
+		},
+	}
+
+	service := &FlashcardService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := &entities.FlashcardReview{
+				EaseFactor:  tt.before.ease,
+				Interval:    tt.before.interval,
+				Repetitions: tt.before.repetitions,
+				Lapses:      tt.before.lapses,
+			}
+			service.schedule(review, tt.rating, now)
+
+			got := state{
+				ease:        review.EaseFactor,
+				interval:    review.Interval,
+				repetitions: review.Repetitions,
+				lapses:      review.Lapses,
+			}
+			if math.Abs(got.ease-tt.want.ease) > 1e-9 || got.interval != tt.want.interval ||
+				got.repetitions != tt.want.repetitions || got.lapses != tt.want.lapses {
+				t.Fatalf("schedule() = %+v, want %+v", got, tt.want)
+			}
+			if due := review.DueAt.Sub(now); due != tt.wantDue {
+				t.Fatalf("schedule() due in %v, want %v", due, tt.wantDue)
+			}
+			if review.LastReviewedAt == nil || !review.LastReviewedAt.Equal(now) {
+				t.Fatalf("schedule() LastReviewedAt = %v, want %v", review.LastReviewedAt, now)
+			}
+		})
+	}
+}
+
+func TestFlashcardServiceNextInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    int
+		repetitions int
+		ease        float64
+		easy        bool
+		want        int
+	}{
+		{name: "首次记得", repetitions: 1, ease: 2.5, want: 1},
+		{name: "首次简单", repetitions: 1, ease: 2.5, easy: true, want: 4},
+		{name: "第二次记得", interval: 1, repetitions: 2, ease: 2.5, want: 6},
+		{name: "第二次简单", interval: 1, repetitions: 2, ease: 2.5, easy: true, want: 8},
+		{name: "按难度系数增长并四舍五入", interval: 15, repetitions: 3, ease: 2.5, want: 38},
+		{name: "简单奖励", interval: 15, repetitions: 3, ease: 2.5, easy: true, want: 49},
+		{name: "至少比上次多一天", interval: 1, repetitions: 5, ease: minEaseFactor, want: 2},
+	}
+
+	service := &FlashcardService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := &entities.FlashcardReview{
+				Interval:    tt.interval,
+				Repetitions: tt.repetitions,
+				EaseFactor:  tt.ease,
+			}
+			if got := service.nextInterval(review, tt.easy); got != tt.want {
+				t.Fatalf("nextInterval() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlashcardServiceVisibility(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      entities.KnowledgePointStatus
+		actor       *KnowledgePointActor
+		wantVisible bool
+	}{
+		{name: "已发布的知识点对其他用户可见", status: entities.KnowledgePointStatusPublished, actor: testOtherUser, wantVisible: true},
+		{name: "作者可见自己的草稿", status: entities.KnowledgePointStatusDraft, actor: testAuthor, wantVisible: true},
+		{name: "审核人员可见审核中的知识点", status: entities.KnowledgePointStatusInReview, actor: testModerator, wantVisible: true},
+		{name: "其他用户不可见草稿", status: entities.KnowledgePointStatusDraft, actor: testOtherUser},
+		{name: "其他用户不可见审核中的知识点", status: entities.KnowledgePointStatusInReview, actor: testOtherUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			point := testKnowledgePoint("心肌梗死", tt.status, testAuthor.UserID)
+			f := newKnowledgeFixture(point)
+			card := &entities.Flashcard{
+				ID:               uuid.New(),
+				KnowledgePointID: point.ID,
+				CardType:         string(entities.FlashcardTypeCloze),
+				Front:            "{{c1::肌钙蛋白}}是心肌损伤的标志物",
+				ClozeIndex:       1,
+			}
+			cards := &fakeFlashcardRepo{db: f.db, cards: map[uuid.UUID]*entities.Flashcard{card.ID: card}}
+			reviews := &fakeFlashcardReviewRepo{db: f.db}
+			service := NewFlashcardService(cards, reviews, f.points, &fakeGlossaryRepo{}, f.service)
+
+			calls := map[string]func() error{
+				"GetFlashcard": func() error {
+					_, err := service.GetFlashcard(ctx, card.ID, tt.actor)
+					return err
+				},
+				"GetFlashcardsByKnowledgePoint": func() error {
+					_, err := service.GetFlashcardsByKnowledgePoint(ctx, point.ID, tt.actor)
+					return err
+				},
+				"StudyKnowledgePoint": func() error {
+					_, err := service.StudyKnowledgePoint(ctx, point.ID, tt.actor)
+					return err
+				},
+				"ReviewFlashcard": func() error {
+					_, err := service.ReviewFlashcard(ctx, card.ID, entities.ReviewRatingGood, tt.actor)
+					return err
+				},
+				"CheckClozeAnswer": func() error {
+					_, err := service.CheckClozeAnswer(ctx, card.ID, []string{"肌钙蛋白"}, tt.actor)
+					return err
+				},
+			}
+			for name, call := range calls {
+				err := call()
+				if tt.wantVisible && err != nil {
+					t.Fatalf("%s() error = %v", name, err)
+				}
+				if !tt.wantVisible && !errors.Is(err, repositories.ErrNotFound) {
+					t.Fatalf("%s() error = %v, want not found", name, err)
+				}
+			}
+			if !tt.wantVisible && len(f.db.writes) != 0 {
+				t.Fatalf("writes = %v, want no writes", f.db.writes)
+			}
+		})
+	}
+}
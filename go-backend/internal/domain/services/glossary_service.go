@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/textsearch"
+)
+
+// 术语表错误
+var (
+	ErrGlossaryNameConflict = errors.New("术语名称已被其他条目使用")
+	ErrGlossaryForbidden    = errors.New("无权维护术语表")
+)
+
+// GlossaryService 术语表服务
+type GlossaryService struct {
+	glossaryRepo          repositories.GlossaryRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewGlossaryService 创建术语表服务
+func NewGlossaryService(
+	glossaryRepo repositories.GlossaryRepository,
+	knowledgePointService *KnowledgePointService,
+) *GlossaryService {
+	return &GlossaryService{
+		glossaryRepo:          glossaryRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// CreateTerm 创建术语，仅限管理员；任一名称不得与其他条目重复
+func (s *GlossaryService) CreateTerm(ctx context.Context, term *entities.GlossaryTerm, actor *KnowledgePointActor) error {
+	if !actor.IsAdmin() {
+		return ErrGlossaryForbidden
+	}
+	if err := s.checkNames(ctx, term); err != nil {
+		return err
+	}
+	term.CreatedBy = actor.userID()
+	if err := s.glossaryRepo.Create(ctx, term); err != nil {
+		return err
+	}
+	s.knowledgePointService.RefreshMentions(ctx, term.Names())
+
+	logger.Info("术语创建成功", logger.String("glossary_term_id", term.ID.String()))
+	return nil
+}
+
+// GetTerm 获取术语
+func (s *GlossaryService) GetTerm(ctx context.Context, id uuid.UUID) (*entities.GlossaryTerm, error) {
+	return s.glossaryRepo.GetByID(ctx, id)
+}
+
+// ListTerms 分页获取术语
+func (s *GlossaryService) ListTerms(ctx context.Context, filter *repositories.GlossaryFilter) ([]*entities.GlossaryTerm, int64, error) {
+	return s.glossaryRepo.List(ctx, filter)
+}
+
+// UpdateTerm 更新术语，仅限管理员；previousNames为更新前的全部名称，用于更新受影响的知识点关联
+func (s *GlossaryService) UpdateTerm(ctx context.Context, term *entities.GlossaryTerm, previousNames []string, actor *KnowledgePointActor) error {
+	if !actor.IsAdmin() {
+		return ErrGlossaryForbidden
+	}
+	if err := s.checkNames(ctx, term); err != nil {
+		return err
+	}
+	if err := s.glossaryRepo.Update(ctx, term); err != nil {
+		return err
+	}
+	s.knowledgePointService.RefreshMentions(ctx, append(previousNames, term.Names()...))
+	return nil
+}
+
+// DeleteTerm 删除术语，仅限管理员
+func (s *GlossaryService) DeleteTerm(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	if !actor.IsAdmin() {
+		return ErrGlossaryForbidden
+	}
+	term, err := s.glossaryRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.glossaryRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.knowledgePointService.RefreshMentions(ctx, term.Names())
+	return nil
+}
+
+// checkNames 检查术语的名称是否与其他条目冲突
+func (s *GlossaryService) checkNames(ctx context.Context, term *entities.GlossaryTerm) error {
+	if strings.TrimSpace(term.Term) == "" {
+		return fmt.Errorf("术语名称不能为空")
+	}
+	others, err := s.glossaryRepo.FindByNames(ctx, term.NormalizedNames())
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	for _, name := range term.NormalizedNames() {
+		names[name] = true
+	}
+	for _, other := range others {
+		if other.ID == term.ID {
+			continue
+		}
+		for _, name := range other.NormalizedNames() {
+			if names[name] {
+				return fmt.Errorf("%w: %s（%s）", ErrGlossaryNameConflict, name, other.Term)
+			}
+		}
+	}
+	return nil
+}
+
+// lookupSynonyms 查找名称的同义词，返回以规范化名称为键、该条目其余名称为值的映射
+func lookupSynonyms(ctx context.Context, glossaryRepo repositories.GlossaryRepository, names []string) (map[string][]string, error) {
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		if key := textsearch.NormalizeTerm(name); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	terms, err := glossaryRepo.FindByNames(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+	synonyms := make(map[string][]string)
+	for _, term := range terms {
+		for _, name := range term.Names() {
+			key := textsearch.NormalizeTerm(name)
+			if !wanted[key] || synonyms[key] != nil {
+				continue
+			}
+			for _, other := range term.Names() {
+				if textsearch.NormalizeTerm(other) != key {
+					synonyms[key] = append(synonyms[key], other)
+				}
+			}
+		}
+	}
+	return synonyms, nil
+}
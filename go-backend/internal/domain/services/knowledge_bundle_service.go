@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/kbbundle"
+	"sical-go-backend/pkg/kpimport"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// 导出包内的数据文件名，用于定位错误
+const (
+	bundleSourceKnowledgePoints = "knowledge_points.json"
+	bundleSourcePrerequisites   = "prerequisites.json"
+	bundleSourceFlashcards      = "flashcards.json"
+)
+
+// ErrKnowledgeBundleForbidden 无权导入或导出知识库
+var ErrKnowledgeBundleForbidden = errors.New("无权导入或导出知识库")
+
+// KnowledgeBundleService 知识库导出包服务
+type KnowledgeBundleService struct {
+	knowledgeRepo         repositories.KnowledgePointRepository
+	flashcardRepo         repositories.FlashcardRepository
+	knowledgePointService *KnowledgePointService
+	txManager             repositories.TransactionManager
+}
+
+// NewKnowledgeBundleService 创建知识库导出包服务
+func NewKnowledgeBundleService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	flashcardRepo repositories.FlashcardRepository,
+	knowledgePointService *KnowledgePointService,
+	txManager repositories.TransactionManager,
+) *KnowledgeBundleService {
+	return &KnowledgeBundleService{
+		knowledgeRepo:         knowledgeRepo,
+		flashcardRepo:         flashcardRepo,
+		knowledgePointService: knowledgePointService,
+		txManager:             txManager,
+	}
+}
+
+// BundleImportCounts 单类数据的导入统计
+type BundleImportCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// KnowledgeBundleImportReport 导出包导入报告
+type KnowledgeBundleImportReport struct {
+	DryRun          bool                `json:"dry_run"`
+	SchemaVersion   int                 `json:"schema_version"`
+	KnowledgePoints BundleImportCounts  `json:"knowledge_points"`
+	Flashcards      BundleImportCounts  `json:"flashcards"`
+	Errors          []kpimport.RowError `json:"errors"`
+}
+
+// Export 导出已发布的知识点及其前置关系与闪卡，仅限审核人员；category为空时导出整个知识库
+func (s *KnowledgeBundleService) Export(ctx context.Context, category string, w io.Writer, actor *KnowledgePointActor) error {
+	if !actor.IsModerator() {
+		return ErrKnowledgeBundleForbidden
+	}
+	var points []*entities.KnowledgePoint
+	var err error
+	if category != "" {
+		points, err = s.knowledgeRepo.GetByCategory(ctx, category)
+	} else {
+		points, err = s.knowledgeRepo.GetByStatus(ctx, string(entities.KnowledgePointStatusPublished))
+	}
+	if err != nil {
+		return err
+	}
+	// 固定输出顺序，便于对比不同时间的导出包
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Category != points[j].Category {
+			return points[i].Category < points[j].Category
+		}
+		if points[i].Title != points[j].Title {
+			return points[i].Title < points[j].Title
+		}
+		return points[i].ID.String() < points[j].ID.String()
+	})
+
+	bundle := &kbbundle.Bundle{
+		Manifest: kbbundle.Manifest{ExportedAt: time.Now().UTC(), Category: category},
+	}
+	ids := make([]uuid.UUID, 0, len(points))
+	for _, point := range points {
+		ids = append(ids, point.ID)
+		bundle.KnowledgePoints = append(bundle.KnowledgePoints, s.exportKnowledgePoint(point))
+		for _, ref := range prerequisiteIDs(point.Prerequisites) {
+			bundle.Prerequisites = append(bundle.Prerequisites, kbbundle.Prerequisite{From: ref, To: point.ID.String()})
+		}
+	}
+
+	cards, err := s.flashcardRepo.GetByKnowledgePointIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, card := range cards {
+		bundle.Flashcards = append(bundle.Flashcards, kbbundle.Flashcard{
+			ID:               card.ID.String(),
+			KnowledgePointID: card.KnowledgePointID.String(),
+			CardType:         card.CardType,
+			Front:            card.Front,
+			Back:             card.Back,
+			ClozeIndex:       card.ClozeIndex,
+		})
+	}
+
+	if err := kbbundle.Write(w, bundle); err != nil {
+		return fmt.Errorf("写入导出包失败: %w", err)
+	}
+	logger.Info("知识库导出完成",
+		logger.String("category", category),
+		logger.Int("knowledge_points", len(bundle.KnowledgePoints)),
+		logger.Int("flashcards", len(bundle.Flashcards)),
+	)
+	return nil
+}
+
+// Import 校验并导入导出包，仅限管理员（导入会直接设置知识点的发布状态）；
+// 知识点与闪卡按ID新增或更新，无变化的数据不写入，存在任何错误时不写入数据
+func (s *KnowledgeBundleService) Import(ctx context.Context, bundle *kbbundle.Bundle, dryRun bool, actor *KnowledgePointActor) (*KnowledgeBundleImportReport, error) {
+	if !actor.IsAdmin() {
+		return nil, ErrKnowledgeBundleForbidden
+	}
+	report := &KnowledgeBundleImportReport{
+		DryRun:        dryRun,
+		SchemaVersion: bundle.Manifest.SchemaVersion,
+		Errors:        []kpimport.RowError{},
+	}
+	fail := func(source, key, format string, args ...interface{}) {
+		report.Errors = append(report.Errors, kpimport.RowError{Source: source, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// 校验知识点
+	points := make(map[uuid.UUID]*entities.KnowledgePoint, len(bundle.KnowledgePoints))
+	var pointIDs []uuid.UUID
+	var externalKeys []string
+	seenKeys := make(map[string]bool)
+	for _, item := range bundle.KnowledgePoints {
+		id, err := uuid.Parse(item.ID)
+		if err != nil {
+			fail(bundleSourceKnowledgePoints, item.ID, "知识点ID格式无效")
+			continue
+		}
+		if points[id] != nil {
+			fail(bundleSourceKnowledgePoints, item.ID, "知识点ID重复")
+			continue
+		}
+		point, message := s.importKnowledgePoint(id, item)
+		if message != "" {
+			fail(bundleSourceKnowledgePoints, item.ID, "%s", message)
+			continue
+		}
+		if item.ExternalKey != "" {
+			if seenKeys[item.ExternalKey] {
+				fail(bundleSourceKnowledgePoints, item.ID, "外部键重复: %s", item.ExternalKey)
+				continue
+			}
+			seenKeys[item.ExternalKey] = true
+			externalKeys = append(externalKeys, item.ExternalKey)
+		}
+		points[id] = point
+		pointIDs = append(pointIDs, id)
+	}
+
+	// 外部键不能被目标环境中的其他知识点占用
+	keyOwners, err := s.knowledgeRepo.GetByExternalKeys(ctx, externalKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, owner := range keyOwners {
+		if points[owner.ID] == nil {
+			fail(bundleSourceKnowledgePoints, *owner.ExternalKey, "外部键已被知识点%s占用", owner.ID)
+		}
+	}
+
+	// 校验前置关系：前置知识点须在导出包中或已存在于目标环境
+	prereqs := make(map[uuid.UUID][]string)
+	var outside []uuid.UUID
+	for _, edge := range bundle.Prerequisites {
+		from, errFrom := uuid.Parse(edge.From)
+		to, errTo := uuid.Parse(edge.To)
+		if errFrom != nil || errTo != nil {
+			fail(bundleSourcePrerequisites, edge.To, "前置关系ID格式无效")
+			continue
+		}
+		if points[to] == nil {
+			fail(bundleSourcePrerequisites, edge.To, "前置关系引用了导出包之外的知识点")
+			continue
+		}
+		if from == to {
+			fail(bundleSourcePrerequisites, edge.To, "知识点不能以自身为前置")
+			continue
+		}
+		if points[from] == nil {
+			outside = append(outside, from)
+		}
+		prereqs[to] = append(prereqs[to], from.String())
+	}
+	existingOutside, err := s.knowledgeRepo.GetByIDs(ctx, outside)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existingOutside))
+	for _, point := range existingOutside {
+		known[point.ID.String()] = true
+	}
+	for _, id := range outside {
+		if !known[id.String()] {
+			fail(bundleSourcePrerequisites, id.String(), "前置知识点不存在")
+		}
+	}
+	for id, point := range points {
+		point.Prerequisites = jsonList(prereqs[id])
+	}
+
+	// 校验闪卡
+	cards := make(map[uuid.UUID]*entities.Flashcard, len(bundle.Flashcards))
+	var cardIDs []uuid.UUID
+	for _, item := range bundle.Flashcards {
+		id, errID := uuid.Parse(item.ID)
+		pointID, errPoint := uuid.Parse(item.KnowledgePointID)
+		switch {
+		case errID != nil || errPoint != nil:
+			fail(bundleSourceFlashcards, item.ID, "闪卡ID格式无效")
+		case cards[id] != nil:
+			fail(bundleSourceFlashcards, item.ID, "闪卡ID重复")
+		case points[pointID] == nil:
+			fail(bundleSourceFlashcards, item.ID, "闪卡引用了导出包之外的知识点")
+		case item.CardType != string(entities.FlashcardTypeBasic) && item.CardType != string(entities.FlashcardTypeCloze):
+			fail(bundleSourceFlashcards, item.ID, "无效的闪卡类型: %s", item.CardType)
+		case strings.TrimSpace(item.Front) == "":
+			fail(bundleSourceFlashcards, item.ID, "闪卡正面不能为空")
+		default:
+			cards[id] = &entities.Flashcard{
+				ID:               id,
+				KnowledgePointID: pointID,
+				CardType:         item.CardType,
+				Front:            item.Front,
+				Back:             item.Back,
+				ClozeIndex:       item.ClozeIndex,
+			}
+			cardIDs = append(cardIDs, id)
+		}
+	}
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	// 对比已存在的数据
+	existingPoints, err := s.knowledgeRepo.GetByIDs(ctx, pointIDs)
+	if err != nil {
+		return nil, err
+	}
+	existingPointByID := make(map[uuid.UUID]*entities.KnowledgePoint, len(existingPoints))
+	for _, point := range existingPoints {
+		existingPointByID[point.ID] = point
+	}
+	existingCards, err := s.flashcardRepo.GetByIDs(ctx, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	existingCardByID := make(map[uuid.UUID]*entities.Flashcard, len(existingCards))
+	for _, card := range existingCards {
+		existingCardByID[card.ID] = card
+	}
+
+	// 全部写入在同一事务中进行，任一写入失败时整体回滚
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, id := range pointIDs {
+			point := points[id]
+			existing := existingPointByID[id]
+			switch {
+			case existing == nil:
+				report.KnowledgePoints.Created++
+				if !dryRun {
+					status := point.Status
+					if err := s.knowledgePointService.CreateKnowledgePoint(ctx, point, actor); err != nil {
+						return err
+					}
+					if err := s.applyStatus(ctx, point, status, actor); err != nil {
+						return err
+					}
+				}
+			case sameKnowledgePoint(existing, point):
+				report.KnowledgePoints.Unchanged++
+			default:
+				report.KnowledgePoints.Updated++
+				if !dryRun {
+					if err := s.updateKnowledgePoint(ctx, existing, point, actor); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for _, id := range cardIDs {
+			card := cards[id]
+			existing := existingCardByID[id]
+			switch {
+			case existing == nil:
+				report.Flashcards.Created++
+				if !dryRun {
+					card.CreatedBy = actor.UserID
+					if err := s.flashcardRepo.Create(ctx, card); err != nil {
+						return err
+					}
+				}
+			case existing.KnowledgePointID == card.KnowledgePointID && existing.CardType == card.CardType &&
+				existing.Front == card.Front && existing.Back == card.Back && existing.ClozeIndex == card.ClozeIndex:
+				report.Flashcards.Unchanged++
+			default:
+				report.Flashcards.Updated++
+				if !dryRun {
+					existing.KnowledgePointID = card.KnowledgePointID
+					existing.CardType = card.CardType
+					existing.Front = card.Front
+					existing.Back = card.Back
+					existing.ClozeIndex = card.ClozeIndex
+					if err := s.flashcardRepo.Update(ctx, existing); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("知识库导出包导入完成",
+		logger.Bool("dry_run", dryRun),
+		logger.Int("knowledge_points_created", report.KnowledgePoints.Created),
+		logger.Int("knowledge_points_updated", report.KnowledgePoints.Updated),
+		logger.Int("flashcards_created", report.Flashcards.Created),
+		logger.Int("flashcards_updated", report.Flashcards.Updated),
+	)
+	return report, nil
+}
+
+// exportKnowledgePoint 转换为导出包中的知识点
+func (s *KnowledgeBundleService) exportKnowledgePoint(point *entities.KnowledgePoint) kbbundle.KnowledgePoint {
+	resources, _ := json.Marshal(point.ResourceList())
+	item := kbbundle.KnowledgePoint{
+		ID:          point.ID.String(),
+		Title:       point.Title,
+		Description: point.Description,
+		Category:    point.Category,
+		Difficulty:  point.Difficulty,
+		Content:     point.Content,
+		Tags:        point.TagList(),
+		Aliases:     point.AliasList(),
+		Resources:   resources,
+		Status:      point.Status,
+	}
+	if point.ExternalKey != nil {
+		item.ExternalKey = *point.ExternalKey
+	}
+	return item
+}
+
+// importKnowledgePoint 校验导出包中的知识点并转换为实体，校验失败时返回错误信息
+func (s *KnowledgeBundleService) importKnowledgePoint(id uuid.UUID, item kbbundle.KnowledgePoint) (*entities.KnowledgePoint, string) {
+	switch {
+	case strings.TrimSpace(item.Title) == "":
+		return nil, "标题不能为空"
+	case strings.TrimSpace(item.Category) == "":
+		return nil, "分类不能为空"
+	case item.Difficulty != "beginner" && item.Difficulty != "intermediate" && item.Difficulty != "advanced":
+		return nil, "无效的难度值: " + item.Difficulty
+	}
+	switch entities.KnowledgePointStatus(item.Status) {
+	case entities.KnowledgePointStatusDraft, entities.KnowledgePointStatusInReview,
+		entities.KnowledgePointStatusPublished, entities.KnowledgePointStatusArchived:
+	default:
+		return nil, "无效的状态: " + item.Status
+	}
+
+	if err := markdown.Validate(item.Content); err != nil {
+		return nil, err.Error()
+	}
+
+	resources := entities.ParseResources(string(item.Resources))
+	for _, resource := range resources {
+		if err := resource.Validate(); err != nil {
+			return nil, err.Error()
+		}
+	}
+
+	point := &entities.KnowledgePoint{
+		ID:          id,
+		Title:       item.Title,
+		Description: item.Description,
+		Category:    item.Category,
+		Difficulty:  item.Difficulty,
+		Content:     item.Content,
+		Status:      item.Status,
+	}
+	point.SetResources(resources)
+	point.SetTags(item.Tags)
+	point.SetAliases(item.Aliases)
+	if item.ExternalKey != "" {
+		key := item.ExternalKey
+		point.ExternalKey = &key
+	}
+	return point, ""
+}
+
+// updateKnowledgePoint 使用导出包中的内容更新已有知识点
+func (s *KnowledgeBundleService) updateKnowledgePoint(ctx context.Context, existing, point *entities.KnowledgePoint, actor *KnowledgePointActor) error {
+	existing.Title = point.Title
+	existing.Description = point.Description
+	existing.Category = point.Category
+	existing.Difficulty = point.Difficulty
+	existing.Content = point.Content
+	existing.Resources = point.Resources
+	existing.Prerequisites = point.Prerequisites
+	existing.Tags = point.Tags
+	existing.Aliases = point.Aliases
+	existing.ExternalKey = point.ExternalKey
+	if _, err := s.knowledgePointService.UpdateKnowledgePoint(ctx, existing, actor); err != nil {
+		return err
+	}
+	return s.applyStatus(ctx, existing, point.Status, actor)
+}
+
+// applyStatus 将知识点状态设置为导出包中的状态；导入仅限管理员，因此可跳过审核流程直接发布
+func (s *KnowledgeBundleService) applyStatus(ctx context.Context, point *entities.KnowledgePoint, status string, actor *KnowledgePointActor) error {
+	if point.Status == status {
+		return nil
+	}
+	point.Status = status
+	if status == string(entities.KnowledgePointStatusPublished) && point.PublishedAt == nil {
+		now := time.Now()
+		point.PublishedAt = &now
+		point.ApprovedBy = actor.userID()
+	}
+	return s.knowledgeRepo.Update(ctx, point)
+}
+
+// sameKnowledgePoint 检查知识点内容与状态是否一致
+func sameKnowledgePoint(a, b *entities.KnowledgePoint) bool {
+	return a.Title == b.Title &&
+		a.Description == b.Description &&
+		a.Category == b.Category &&
+		a.Difficulty == b.Difficulty &&
+		a.Content == b.Content &&
+		a.Status == b.Status &&
+		normalizeJSON(a.Resources) == normalizeJSON(b.Resources) &&
+		normalizeJSON(a.Prerequisites) == normalizeJSON(b.Prerequisites) &&
+		normalizeJSON(a.Tags) == normalizeJSON(b.Tags) &&
+		normalizeJSON(a.Aliases) == normalizeJSON(b.Aliases) &&
+		stringValue(a.ExternalKey) == stringValue(b.ExternalKey)
+}
+
+// prerequisiteIDs 解析前置知识点字段中的知识点ID，忽略无法识别的条目
+func prerequisiteIDs(prerequisites string) []string {
+	var refs []string
+	if err := json.Unmarshal([]byte(prerequisites), &refs); err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if id, err := uuid.Parse(ref); err == nil {
+			ids = append(ids, id.String())
+		}
+	}
+	return ids
+}
+
+// stringValue 获取字符串指针的值，nil时返回空字符串
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
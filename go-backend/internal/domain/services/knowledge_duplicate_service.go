@@ -0,0 +1,423 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+	"sical-go-backend/pkg/minhash"
+)
+
+// DefaultDuplicateThreshold 内容相似度达到该值时视为疑似重复
+const DefaultDuplicateThreshold = 0.7
+
+// fingerprintRebuildBatchSize 重建指纹时每批处理的知识点数
+const fingerprintRebuildBatchSize = 200
+
+// DuplicateCandidate 疑似重复的知识点
+type DuplicateCandidate struct {
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	SameTitle  bool      `json:"same_title"` // 规范化后的标题相同
+	Similarity float64   `json:"similarity"` // 内容相似度估计（0-1）
+}
+
+// DuplicatePair 重复簇中的一对疑似重复知识点
+type DuplicatePair struct {
+	A          uuid.UUID `json:"a"`
+	B          uuid.UUID `json:"b"`
+	SameTitle  bool      `json:"same_title"`
+	Similarity float64   `json:"similarity"`
+}
+
+// DuplicateCluster 疑似重复的知识点簇
+type DuplicateCluster struct {
+	Points []*entities.KnowledgePoint
+	Pairs  []DuplicatePair
+}
+
+// fingerprint 解码后的查重指纹
+type fingerprint struct {
+	id        uuid.UUID
+	titleKey  string
+	signature minhash.Signature
+}
+
+// DetectDuplicates 检测知识点与已有知识点之间的疑似重复，返回以知识点ID为键的候选列表，仅包含操作者可见的知识点
+func (s *KnowledgePointService) DetectDuplicates(ctx context.Context, points []*entities.KnowledgePoint, actor *KnowledgePointActor) (map[uuid.UUID][]*DuplicateCandidate, error) {
+	existing, err := s.loadFingerprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make(map[uuid.UUID][]DuplicatePair)
+	var ids []uuid.UUID
+	for _, point := range points {
+		target := newFingerprint(point)
+		for _, other := range existing {
+			if other.id == point.ID {
+				continue
+			}
+			if pair, ok := compareFingerprints(target, other, DefaultDuplicateThreshold); ok {
+				matches[point.ID] = append(matches[point.ID], pair)
+				ids = append(ids, other.id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return map[uuid.UUID][]*DuplicateCandidate{}, nil
+	}
+
+	others, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entities.KnowledgePoint, len(others))
+	for _, other := range others {
+		byID[other.ID] = other
+	}
+
+	result := make(map[uuid.UUID][]*DuplicateCandidate, len(matches))
+	for id, pairs := range matches {
+		candidates := []*DuplicateCandidate{}
+		for _, pair := range pairs {
+			other := byID[pair.B]
+			if other == nil || !s.CanView(other, actor) {
+				continue
+			}
+			candidates = append(candidates, &DuplicateCandidate{
+				ID:         other.ID,
+				Title:      other.Title,
+				Status:     other.Status,
+				SameTitle:  pair.SameTitle,
+				Similarity: pair.Similarity,
+			})
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].SameTitle != candidates[j].SameTitle {
+				return candidates[i].SameTitle
+			}
+			return candidates[i].Similarity > candidates[j].Similarity
+		})
+		if len(candidates) > 0 {
+			result[id] = candidates
+		}
+	}
+	return result, nil
+}
+
+// FindDuplicates 检测单个知识点的疑似重复
+func (s *KnowledgePointService) FindDuplicates(ctx context.Context, point *entities.KnowledgePoint, actor *KnowledgePointActor) ([]*DuplicateCandidate, error) {
+	result, err := s.DetectDuplicates(ctx, []*entities.KnowledgePoint{point}, actor)
+	if err != nil {
+		return nil, err
+	}
+	if candidates := result[point.ID]; candidates != nil {
+		return candidates, nil
+	}
+	return []*DuplicateCandidate{}, nil
+}
+
+// GetDuplicateClusters 获取全部疑似重复簇，仅限管理员：标题规范化后相同或内容相似度不低于threshold的知识点归为一簇
+func (s *KnowledgePointService) GetDuplicateClusters(ctx context.Context, threshold float64, actor *KnowledgePointActor) ([]*DuplicateCluster, error) {
+	if !actor.IsAdmin() {
+		return nil, ErrKnowledgePointForbidden
+	}
+	fingerprints, err := s.loadFingerprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 通过标题与LSH分段筛选候选对，再逐对计算相似度
+	buckets := make(map[string][]int)
+	for i, fp := range fingerprints {
+		if fp.titleKey != "" {
+			buckets["title:"+fp.titleKey] = append(buckets["title:"+fp.titleKey], i)
+		}
+		for _, key := range fp.signature.BandKeys() {
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+	parent := make([]int, len(fingerprints))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	var pairs []DuplicatePair
+	checked := make(map[[2]int]bool)
+	for _, members := range buckets {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				i, j := members[x], members[y]
+				if checked[[2]int{i, j}] {
+					continue
+				}
+				checked[[2]int{i, j}] = true
+				if pair, ok := compareFingerprints(fingerprints[i], fingerprints[j], threshold); ok {
+					pairs = append(pairs, pair)
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	// 按并查集归簇
+	index := make(map[uuid.UUID]int, len(fingerprints))
+	for i, fp := range fingerprints {
+		index[fp.id] = i
+	}
+	groups := make(map[int]*DuplicateCluster)
+	var ids []uuid.UUID
+	for _, pair := range pairs {
+		root := find(index[pair.A])
+		if groups[root] == nil {
+			groups[root] = &DuplicateCluster{}
+		}
+		groups[root].Pairs = append(groups[root].Pairs, pair)
+		ids = append(ids, pair.A, pair.B)
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range points {
+		if i, ok := index[point.ID]; ok && groups[find(i)] != nil {
+			cluster := groups[find(i)]
+			cluster.Points = append(cluster.Points, point)
+		}
+	}
+
+	clusters := make([]*DuplicateCluster, 0, len(groups))
+	for _, cluster := range groups {
+		sort.Slice(cluster.Points, func(i, j int) bool { return cluster.Points[i].CreatedAt.Before(cluster.Points[j].CreatedAt) })
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Points) != len(clusters[j].Points) {
+			return len(clusters[i].Points) > len(clusters[j].Points)
+		}
+		return clusters[i].Points[0].CreatedAt.Before(clusters[j].Points[0].CreatedAt)
+	})
+	return clusters, nil
+}
+
+// MergeKnowledgePoints 将重复的知识点合并到保留的知识点：迁移闪卡、评分、译文、学习路径关联、知识点关联与其他知识点的前置引用，
+// 合并前置知识点与学习资源，并将被合并知识点的标题与别名加入保留知识点的别名后删除被合并的知识点，
+// 全部写入在同一事务中完成，仅管理员可操作
+func (s *KnowledgePointService) MergeKnowledgePoints(ctx context.Context, survivorID, duplicateID uuid.UUID, actor *KnowledgePointActor) (*entities.KnowledgePoint, error) {
+	if !actor.IsAdmin() {
+		return nil, ErrKnowledgePointForbidden
+	}
+	if survivorID == duplicateID {
+		return nil, fmt.Errorf("不能将知识点合并到自身")
+	}
+	survivor, err := s.knowledgeRepo.GetByID(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.knowledgeRepo.GetByID(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合并别名与前置知识点
+	survivor.SetAliases(append(survivor.AliasList(), duplicate.MentionTerms()...))
+	seen := map[uuid.UUID]bool{survivorID: true, duplicateID: true}
+	var prerequisites []string
+	for _, id := range append(survivor.PrerequisiteIDs(), duplicate.PrerequisiteIDs()...) {
+		if !seen[id] {
+			seen[id] = true
+			prerequisites = append(prerequisites, id.String())
+		}
+	}
+	survivor.Prerequisites = jsonList(prerequisites)
+	survivor.SetResources(mergeResources(survivor.ResourceList(), duplicate.ResourceList()))
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.replacePrerequisite(ctx, duplicateID, survivorID, actor); err != nil {
+			return err
+		}
+		if err := s.knowledgeRepo.MergeInto(ctx, survivorID, duplicateID); err != nil {
+			return err
+		}
+		if err := s.linkRepo.MergeInto(ctx, survivorID, duplicateID); err != nil {
+			return err
+		}
+		if err := s.fingerprintRepo.Delete(ctx, duplicateID); err != nil {
+			return err
+		}
+		_, err := s.UpdateKnowledgePoint(ctx, survivor, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("知识点合并成功",
+		logger.String("survivor_id", survivorID.String()),
+		logger.String("duplicate_id", duplicateID.String()),
+	)
+	return survivor, nil
+}
+
+// replacePrerequisite 将其他知识点前置引用中的被合并知识点改为保留的知识点，每处改写记录修订版本；须在事务中调用
+func (s *KnowledgePointService) replacePrerequisite(ctx context.Context, duplicateID, survivorID uuid.UUID, actor *KnowledgePointActor) error {
+	dependents, err := s.knowledgeRepo.GetByPrerequisite(ctx, duplicateID)
+	if err != nil {
+		return err
+	}
+	for _, dependent := range dependents {
+		// 保留的知识点的前置知识点随合并一并更新，被合并的知识点将被删除
+		if dependent.ID == survivorID || dependent.ID == duplicateID {
+			continue
+		}
+		point, err := s.knowledgeRepo.GetByIDForUpdate(ctx, dependent.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureBaseline(ctx, point.ID); err != nil {
+			return err
+		}
+
+		seen := make(map[uuid.UUID]bool)
+		refs := []string{}
+		for _, id := range point.PrerequisiteIDs() {
+			if id == duplicateID {
+				id = survivorID
+			}
+			if id == point.ID || seen[id] {
+				continue
+			}
+			seen[id] = true
+			refs = append(refs, id.String())
+		}
+		point.Prerequisites = jsonList(refs)
+		if err := s.knowledgeRepo.Update(ctx, point); err != nil {
+			return err
+		}
+		if _, err := s.recordRevision(ctx, point, actor.userID(), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeResources 合并两组学习资源，按URL与定位信息去重，保留在前的资源
+func mergeResources(resources, others []entities.LearningResource) []entities.LearningResource {
+	seen := make(map[string]bool, len(resources)+len(others))
+	merged := make([]entities.LearningResource, 0, len(resources)+len(others))
+	for _, resource := range append(resources, others...) {
+		key := resource.URL + "\x00" + resource.Locator
+		if resource.URL == "" && resource.Locator == "" {
+			key = resource.ID.String()
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, resource)
+	}
+	return merged
+}
+
+// RebuildFingerprints 重新生成全部知识点的查重指纹，仅限管理员，返回处理的知识点数
+func (s *KnowledgePointService) RebuildFingerprints(ctx context.Context, actor *KnowledgePointActor) (int, error) {
+	if !actor.IsAdmin() {
+		return 0, ErrKnowledgePointForbidden
+	}
+	index, err := s.knowledgeRepo.GetMentionIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for start := 0; start < len(index); start += fingerprintRebuildBatchSize {
+		end := min(start+fingerprintRebuildBatchSize, len(index))
+		ids := make([]uuid.UUID, 0, end-start)
+		for _, point := range index[start:end] {
+			ids = append(ids, point.ID)
+		}
+		points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return count, err
+		}
+		for _, point := range points {
+			if err := s.saveFingerprint(ctx, point); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+
+	logger.Info("知识点查重指纹重建完成", logger.Int("count", count))
+	return count, nil
+}
+
+// refreshFingerprint 知识点写入后更新查重指纹；指纹为派生数据，失败时仅记录日志，可通过重建恢复
+func (s *KnowledgePointService) refreshFingerprint(ctx context.Context, point *entities.KnowledgePoint) {
+	if err := s.saveFingerprint(ctx, point); err != nil {
+		logger.Error("更新知识点指纹失败",
+			logger.String("knowledge_point_id", point.ID.String()),
+			logger.String("error", err.Error()),
+		)
+	}
+}
+
+// saveFingerprint 计算并保存知识点的查重指纹
+func (s *KnowledgePointService) saveFingerprint(ctx context.Context, point *entities.KnowledgePoint) error {
+	fp := newFingerprint(point)
+	return s.fingerprintRepo.Save(ctx, &entities.KnowledgePointFingerprint{
+		KnowledgePointID: point.ID,
+		TitleKey:         fp.titleKey,
+		Signature:        fp.signature.Encode(),
+	})
+}
+
+// loadFingerprints 加载并解码全部查重指纹，忽略无法解码的指纹
+func (s *KnowledgePointService) loadFingerprints(ctx context.Context) ([]*fingerprint, error) {
+	stored, err := s.fingerprintRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fingerprints := make([]*fingerprint, 0, len(stored))
+	for _, item := range stored {
+		signature, err := minhash.Decode(item.Signature)
+		if err != nil {
+			continue
+		}
+		fingerprints = append(fingerprints, &fingerprint{id: item.KnowledgePointID, titleKey: item.TitleKey, signature: signature})
+	}
+	return fingerprints, nil
+}
+
+// newFingerprint 计算知识点的查重指纹：规范化标题与正文文本的MinHash签名
+func newFingerprint(point *entities.KnowledgePoint) *fingerprint {
+	return &fingerprint{
+		id:        point.ID,
+		titleKey:  minhash.NormalizeTitle(point.Title),
+		signature: minhash.New(markdown.PlainText(point.Content)),
+	}
+}
+
+// compareFingerprints 比较两个指纹，标题相同或内容相似度达到阈值时返回疑似重复对
+func compareFingerprints(a, b *fingerprint, threshold float64) (DuplicatePair, bool) {
+	pair := DuplicatePair{
+		A:          a.id,
+		B:          b.id,
+		SameTitle:  a.titleKey != "" && a.titleKey == b.titleKey,
+		Similarity: minhash.Similarity(a.signature, b.signature),
+	}
+	return pair, pair.SameTitle || pair.Similarity >= threshold
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+func TestKnowledgePointServiceMergeRecordsDependentRevisions(t *testing.T) {
+	survivor := testKnowledgePoint("二叉树", entities.KnowledgePointStatusPublished, testAuthor.UserID)
+	duplicate := testKnowledgePoint("二叉树（重复）", entities.KnowledgePointStatusPublished, testAuthor.UserID)
+	other := testKnowledgePoint("链表", entities.KnowledgePointStatusPublished, testAuthor.UserID)
+	// 同时以两者为前置的知识点，改写后去重
+	both := testKnowledgePoint("平衡树", entities.KnowledgePointStatusPublished, testAuthor.UserID)
+	both.Prerequisites = jsonList([]string{survivor.ID.String(), duplicate.ID.String()})
+	dependent := testKnowledgePoint("堆", entities.KnowledgePointStatusDraft, testOtherUser.UserID)
+	dependent.Prerequisites = jsonList([]string{other.ID.String(), duplicate.ID.String()})
+
+	t.Run("前置引用改写并记录修订版本", func(t *testing.T) {
+		f := newKnowledgeFixture(survivor, duplicate, other, both, dependent)
+		if _, err := f.service.MergeKnowledgePoints(context.Background(), survivor.ID, duplicate.ID, testAdmin); err != nil {
+			t.Fatalf("MergeKnowledgePoints() error = %v", err)
+		}
+
+		for _, tt := range []struct {
+			point *entities.KnowledgePoint
+			want  []uuid.UUID
+		}{
+			{point: both, want: []uuid.UUID{survivor.ID}},
+			{point: dependent, want: []uuid.UUID{other.ID, survivor.ID}},
+		} {
+			if got := f.points.points[tt.point.ID].PrerequisiteIDs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s prerequisites = %v, want %v", tt.point.Title, got, tt.want)
+			}
+			// 基线版本与合并产生的版本
+			revisions := f.revisions.byPoint(tt.point.ID)
+			if len(revisions) != 2 {
+				t.Fatalf("%s revisions = %d, want 2", tt.point.Title, len(revisions))
+			}
+			latest := revisions[1]
+			if latest.Prerequisites != f.points.points[tt.point.ID].Prerequisites || latest.AuthorID == nil || *latest.AuthorID != testAdmin.UserID {
+				t.Errorf("%s latest revision = {prerequisites: %s, author: %v}, want merged prerequisites by admin", tt.point.Title, latest.Prerequisites, latest.AuthorID)
+			}
+		}
+		if _, ok := f.points.points[duplicate.ID]; ok {
+			t.Errorf("duplicate still exists after merge")
+		}
+		if !containsString(f.db.writes, "merge links") || containsString(f.db.writes, "delete links") {
+			t.Errorf("writes = %v, want links moved to the survivor instead of deleted", f.db.writes)
+		}
+		if len(f.db.outside) != 0 {
+			t.Errorf("writes outside transaction: %v", f.db.outside)
+		}
+	})
+
+	t.Run("合并失败时前置引用与修订版本一并回滚", func(t *testing.T) {
+		f := newKnowledgeFixture(survivor, duplicate, other, both, dependent)
+		f.points.mergeErr = errors.New("迁移失败")
+		if _, err := f.service.MergeKnowledgePoints(context.Background(), survivor.ID, duplicate.ID, testAdmin); err == nil {
+			t.Fatalf("MergeKnowledgePoints() error = nil, want error")
+		}
+		if len(f.db.writes) != 0 || len(f.revisions.revisions) != 0 {
+			t.Fatalf("writes after rollback = %v, revisions = %d, want none", f.db.writes, len(f.revisions.revisions))
+		}
+		if got := f.points.points[dependent.ID].Prerequisites; got != dependent.Prerequisites {
+			t.Fatalf("dependent prerequisites = %s, want unchanged %s", got, dependent.Prerequisites)
+		}
+	})
+
+	t.Run("非管理员不能合并", func(t *testing.T) {
+		f := newKnowledgeFixture(survivor, duplicate, dependent)
+		if _, err := f.service.MergeKnowledgePoints(context.Background(), survivor.ID, duplicate.ID, testModerator); !errors.Is(err, ErrKnowledgePointForbidden) {
+			t.Fatalf("MergeKnowledgePoints() error = %v, want ErrKnowledgePointForbidden", err)
+		}
+		if len(f.db.writes) != 0 {
+			t.Fatalf("MergeKnowledgePoints() wrote %v, want no writes", f.db.writes)
+		}
+	})
+}
+
+// containsString 检查列表中是否包含指定字符串
+func containsString(items []string, want string) bool {
+	for _, item := range items {
+		if item == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/graph"
+)
+
+// 知识图谱边类型
+const (
+	GraphEdgePrerequisite = "prerequisite" // 前置知识点 -> 依赖它的知识点
+	GraphEdgeRelated      = "related"      // 内容提及 -> 被提及的知识点
+)
+
+// 遍历深度
+const (
+	DefaultGraphDepth = 3
+	MaxGraphDepth     = 10
+)
+
+// ErrNoPrerequisiteChain 两个知识点之间不存在前置关系链
+var ErrNoPrerequisiteChain = errors.New("两个知识点之间不存在前置关系链")
+
+// KnowledgeGraphService 知识图谱服务：以前置关系与内容关联为边查询知识点
+type KnowledgeGraphService struct {
+	knowledgeRepo         repositories.KnowledgePointRepository
+	linkRepo              repositories.KnowledgePointLinkRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewKnowledgeGraphService 创建知识图谱服务
+func NewKnowledgeGraphService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	linkRepo repositories.KnowledgePointLinkRepository,
+	knowledgePointService *KnowledgePointService,
+) *KnowledgeGraphService {
+	return &KnowledgeGraphService{
+		knowledgeRepo:         knowledgeRepo,
+		linkRepo:              linkRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// knowledgeGraph 操作者可见的知识图谱
+type knowledgeGraph struct {
+	points        map[uuid.UUID]*entities.KnowledgePoint
+	order         []uuid.UUID               // 按创建时间排列的知识点ID
+	prerequisites map[uuid.UUID][]uuid.UUID // 知识点 -> 前置知识点
+	dependents    map[uuid.UUID][]uuid.UUID // 前置知识点 -> 依赖它的知识点
+	links         []*entities.KnowledgePointLink
+}
+
+// GetAncestors 获取知识点在指定深度内的全部前置知识点
+func (s *KnowledgeGraphService) GetAncestors(ctx context.Context, id uuid.UUID, depth int, includeRelated bool, actor *KnowledgePointActor) (*graph.Graph, error) {
+	return s.traverse(ctx, id, depth, includeRelated, actor, func(g *knowledgeGraph) map[uuid.UUID][]uuid.UUID { return g.prerequisites })
+}
+
+// GetDescendants 获取在指定深度内以该知识点为前置的全部知识点
+func (s *KnowledgeGraphService) GetDescendants(ctx context.Context, id uuid.UUID, depth int, includeRelated bool, actor *KnowledgePointActor) (*graph.Graph, error) {
+	return s.traverse(ctx, id, depth, includeRelated, actor, func(g *knowledgeGraph) map[uuid.UUID][]uuid.UUID { return g.dependents })
+}
+
+// GetPrerequisiteChain 获取从from到to的最短前置关系链：链中每个知识点都是下一个的前置
+func (s *KnowledgeGraphService) GetPrerequisiteChain(ctx context.Context, from, to uuid.UUID, actor *KnowledgePointActor) (*graph.Graph, error) {
+	g, err := s.load(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if g.points[from] == nil || g.points[to] == nil {
+		return nil, fmt.Errorf("知识点不存在")
+	}
+
+	path := graph.ShortestPath(from.String(), to.String(), neighbors(g.dependents))
+	if path == nil {
+		return nil, ErrNoPrerequisiteChain
+	}
+	result := &graph.Graph{Nodes: make([]graph.Node, 0, len(path)), Edges: make([]graph.Edge, 0, len(path)-1)}
+	for i, ref := range path {
+		id := uuid.MustParse(ref)
+		result.Nodes = append(result.Nodes, graphNode(g.points[id], map[string]string{"step": strconv.Itoa(i)}))
+		if i > 0 {
+			result.Edges = append(result.Edges, graph.Edge{Source: path[i-1], Target: ref, Kind: GraphEdgePrerequisite})
+		}
+	}
+	return result, nil
+}
+
+// GetCategoryGraph 获取分类下全部知识点及其之间的边
+func (s *KnowledgeGraphService) GetCategoryGraph(ctx context.Context, category string, includeRelated bool, actor *KnowledgePointActor) (*graph.Graph, error) {
+	g, err := s.load(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[uuid.UUID]map[string]string)
+	for id, point := range g.points {
+		if point.Category == category {
+			members[id] = nil
+		}
+	}
+	return g.subgraph(members, includeRelated), nil
+}
+
+// traverse 沿指定方向遍历，返回起点与可达知识点组成的子图
+func (s *KnowledgeGraphService) traverse(
+	ctx context.Context,
+	id uuid.UUID,
+	depth int,
+	includeRelated bool,
+	actor *KnowledgePointActor,
+	direction func(*knowledgeGraph) map[uuid.UUID][]uuid.UUID,
+) (*graph.Graph, error) {
+	if depth <= 0 {
+		depth = DefaultGraphDepth
+	}
+	depth = min(depth, MaxGraphDepth)
+
+	g, err := s.load(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if g.points[id] == nil {
+		return nil, fmt.Errorf("知识点不存在")
+	}
+
+	members := map[uuid.UUID]map[string]string{id: {"depth": "0"}}
+	for ref, distance := range graph.Traverse(id.String(), depth, neighbors(direction(g))) {
+		members[uuid.MustParse(ref)] = map[string]string{"depth": strconv.Itoa(distance)}
+	}
+	return g.subgraph(members, includeRelated), nil
+}
+
+// load 加载操作者可见的知识点及其前置关系与内容关联；指向不可见知识点的边被忽略
+func (s *KnowledgeGraphService) load(ctx context.Context, actor *KnowledgePointActor) (*knowledgeGraph, error) {
+	points, err := s.knowledgeRepo.GetGraphNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g := &knowledgeGraph{
+		points:        make(map[uuid.UUID]*entities.KnowledgePoint, len(points)),
+		prerequisites: make(map[uuid.UUID][]uuid.UUID),
+		dependents:    make(map[uuid.UUID][]uuid.UUID),
+	}
+	for _, point := range points {
+		if s.knowledgePointService.CanView(point, actor) {
+			g.points[point.ID] = point
+			g.order = append(g.order, point.ID)
+		}
+	}
+	for _, id := range g.order {
+		for _, prerequisite := range g.points[id].PrerequisiteIDs() {
+			if g.points[prerequisite] == nil || prerequisite == id {
+				continue
+			}
+			g.prerequisites[id] = append(g.prerequisites[id], prerequisite)
+			g.dependents[prerequisite] = append(g.dependents[prerequisite], id)
+		}
+	}
+
+	links, err := s.linkRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if g.points[link.SourceID] != nil && g.points[link.TargetID] != nil {
+			g.links = append(g.links, link)
+		}
+	}
+	return g, nil
+}
+
+// subgraph 生成由指定知识点组成的子图，members的值为节点的附加属性
+func (g *knowledgeGraph) subgraph(members map[uuid.UUID]map[string]string, includeRelated bool) *graph.Graph {
+	result := &graph.Graph{Nodes: []graph.Node{}, Edges: []graph.Edge{}}
+	for _, id := range g.order {
+		attrs, ok := members[id]
+		if !ok {
+			continue
+		}
+		result.Nodes = append(result.Nodes, graphNode(g.points[id], attrs))
+		for _, prerequisite := range g.prerequisites[id] {
+			if _, ok := members[prerequisite]; ok {
+				result.Edges = append(result.Edges, graph.Edge{Source: prerequisite.String(), Target: id.String(), Kind: GraphEdgePrerequisite})
+			}
+		}
+	}
+	if includeRelated {
+		for _, link := range g.links {
+			_, source := members[link.SourceID]
+			_, target := members[link.TargetID]
+			if source && target {
+				result.Edges = append(result.Edges, graph.Edge{Source: link.SourceID.String(), Target: link.TargetID.String(), Kind: GraphEdgeRelated})
+			}
+		}
+	}
+	return result
+}
+
+// graphNode 将知识点转换为图节点
+func graphNode(point *entities.KnowledgePoint, extra map[string]string) graph.Node {
+	attrs := map[string]string{
+		"category":   point.Category,
+		"difficulty": point.Difficulty,
+		"status":     point.Status,
+	}
+	for key, value := range extra {
+		attrs[key] = value
+	}
+	return graph.Node{ID: point.ID.String(), Label: point.Title, Attrs: attrs}
+}
+
+// neighbors 将邻接表转换为遍历函数
+func neighbors(adjacency map[uuid.UUID][]uuid.UUID) graph.Neighbors {
+	return func(ref string) []string {
+		id, err := uuid.Parse(ref)
+		if err != nil {
+			return nil
+		}
+		next := make([]string, 0, len(adjacency[id]))
+		for _, neighbor := range adjacency[id] {
+			next = append(next, neighbor.String())
+		}
+		return next
+	}
+}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/kpimport"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// 导入操作
+const (
+	importActionCreate = "create"
+	importActionUpdate = "update"
+)
+
+// errImportRolledBack 存在写入失败的记录，导入已整体回滚
+var errImportRolledBack = errors.New("导入已回滚")
+
+// KnowledgeImportService 知识点批量导入服务
+type KnowledgeImportService struct {
+	knowledgeRepo         repositories.KnowledgePointRepository
+	knowledgePointService *KnowledgePointService
+	txManager             repositories.TransactionManager
+}
+
+// NewKnowledgeImportService 创建知识点批量导入服务
+func NewKnowledgeImportService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	knowledgePointService *KnowledgePointService,
+	txManager repositories.TransactionManager,
+) *KnowledgeImportService {
+	return &KnowledgeImportService{
+		knowledgeRepo:         knowledgeRepo,
+		knowledgePointService: knowledgePointService,
+		txManager:             txManager,
+	}
+}
+
+// KnowledgeImportOptions 导入选项
+type KnowledgeImportOptions struct {
+	DryRun bool                 // 仅校验，不写入
+	Upsert bool                 // 外部键已存在时更新，否则报错
+	Actor  *KnowledgePointActor // 导入操作者
+}
+
+// KnowledgeImportItem 单条导入结果
+type KnowledgeImportItem struct {
+	Source      string `json:"source"`
+	Line        int    `json:"line,omitempty"`
+	ExternalKey string `json:"external_key,omitempty"`
+	Title       string `json:"title"`
+	Action      string `json:"action"` // create, update
+	ID          string `json:"id"`
+
+	Duplicates []*DuplicateCandidate `json:"duplicates,omitempty"` // 新建时与已有知识点疑似重复的警告，不阻止导入
+}
+
+// KnowledgeImportReport 导入报告
+type KnowledgeImportReport struct {
+	DryRun  bool                  `json:"dry_run"`
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Items   []KnowledgeImportItem `json:"items"`
+	Errors  []kpimport.RowError   `json:"errors"`
+}
+
+// importPlan 单条记录的导入计划
+type importPlan struct {
+	record   kpimport.Record
+	point    *entities.KnowledgePoint
+	action   string
+	prereqs  []string
+	hasError bool
+}
+
+// Import 校验并导入知识点；全部记录在同一事务中写入，存在任何错误时不写入数据，新建的知识点为草稿状态
+func (s *KnowledgeImportService) Import(ctx context.Context, records []kpimport.Record, parseErrors []kpimport.RowError, opts *KnowledgeImportOptions) (*KnowledgeImportReport, error) {
+	report := &KnowledgeImportReport{
+		DryRun: opts.DryRun,
+		Total:  len(records) + len(parseErrors),
+		Items:  []KnowledgeImportItem{},
+		Errors: append([]kpimport.RowError{}, parseErrors...),
+	}
+	fail := func(plan *importPlan, format string, args ...interface{}) {
+		plan.hasError = true
+		report.Errors = append(report.Errors, kpimport.RowError{
+			Source:  plan.record.Source,
+			Line:    plan.record.Line,
+			Key:     plan.record.ExternalKey,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// 校验字段并检查批次内重复
+	plans := make([]*importPlan, len(records))
+	batchKeys := make(map[string]*importPlan)
+	batchTitles := make(map[string]*importPlan)
+	var keys []string
+	for i, record := range records {
+		plan := &importPlan{record: record}
+		plans[i] = plan
+		s.validate(plan, fail)
+
+		if record.ExternalKey != "" {
+			if _, exists := batchKeys[record.ExternalKey]; exists {
+				fail(plan, "外部键在导入数据中重复: %s", record.ExternalKey)
+			} else {
+				batchKeys[record.ExternalKey] = plan
+				keys = append(keys, record.ExternalKey)
+			}
+		}
+		if _, exists := batchTitles[record.Title]; exists {
+			fail(plan, "标题在导入数据中重复: %s", record.Title)
+		} else if record.Title != "" {
+			batchTitles[record.Title] = plan
+		}
+	}
+
+	// 匹配已存在的知识点
+	existing, err := s.knowledgeRepo.GetByExternalKeys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	existingByKey := make(map[string]*entities.KnowledgePoint, len(existing))
+	for _, point := range existing {
+		existingByKey[*point.ExternalKey] = point
+	}
+	for _, plan := range plans {
+		if point, ok := existingByKey[plan.record.ExternalKey]; ok && plan.record.ExternalKey != "" {
+			if !opts.Upsert {
+				fail(plan, "外部键已存在: %s（可使用upsert模式更新）", plan.record.ExternalKey)
+				continue
+			}
+			plan.point = point
+			plan.action = importActionUpdate
+		} else {
+			plan.point = &entities.KnowledgePoint{ID: uuid.New()}
+			plan.action = importActionCreate
+		}
+	}
+
+	// 解析前置知识点引用：优先匹配导入数据，其次匹配已有知识点
+	if err := s.resolvePrerequisites(ctx, plans, batchKeys, batchTitles, fail); err != nil {
+		return nil, err
+	}
+
+	// 检测新建知识点与已有知识点的疑似重复
+	var creating []*entities.KnowledgePoint
+	for _, plan := range plans {
+		if !plan.hasError && plan.action == importActionCreate {
+			creating = append(creating, &entities.KnowledgePoint{ID: plan.point.ID, Title: plan.record.Title, Content: plan.record.Content})
+		}
+	}
+	duplicates, err := s.knowledgePointService.DetectDuplicates(ctx, creating, opts.Actor)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		if plan.hasError {
+			continue
+		}
+		report.Items = append(report.Items, KnowledgeImportItem{
+			Source:      plan.record.Source,
+			Line:        plan.record.Line,
+			ExternalKey: plan.record.ExternalKey,
+			Title:       plan.record.Title,
+			Action:      plan.action,
+			ID:          plan.point.ID.String(),
+			Duplicates:  duplicates[plan.point.ID],
+		})
+	}
+	if opts.DryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	// 单条记录写入失败时继续收集其余错误，最后整体回滚
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, plan := range plans {
+			if err := s.apply(ctx, plan, opts.Actor); err != nil {
+				message := err.Error()
+				if errors.Is(err, ErrKnowledgePointForbidden) {
+					message = "无权更新该知识点"
+				}
+				fail(plan, "%s", message)
+				continue
+			}
+			if plan.action == importActionCreate {
+				report.Created++
+			} else {
+				report.Updated++
+			}
+		}
+		if len(report.Errors) > 0 {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, fmt.Errorf("导入知识点失败: %w", err)
+	}
+	if len(report.Errors) > 0 {
+		report.Created, report.Updated = 0, 0
+	}
+
+	logger.Info("知识点批量导入完成",
+		logger.Int("total", report.Total),
+		logger.Int("created", report.Created),
+		logger.Int("updated", report.Updated),
+		logger.Int("errors", len(report.Errors)),
+	)
+	return report, nil
+}
+
+// validate 校验记录字段
+func (s *KnowledgeImportService) validate(plan *importPlan, fail func(*importPlan, string, ...interface{})) {
+	record := plan.record
+	if record.Title == "" {
+		fail(plan, "标题不能为空")
+	} else if utf8.RuneCountInString(record.Title) > 255 {
+		fail(plan, "标题长度不能超过255个字符")
+	}
+	if record.Category == "" {
+		fail(plan, "分类不能为空")
+	} else if utf8.RuneCountInString(record.Category) > 100 {
+		fail(plan, "分类长度不能超过100个字符")
+	}
+	if record.Difficulty != "beginner" && record.Difficulty != "intermediate" && record.Difficulty != "advanced" {
+		fail(plan, "无效的难度值: %s", record.Difficulty)
+	}
+	if record.Content == "" {
+		fail(plan, "内容不能为空")
+	} else if err := markdown.Validate(record.Content); err != nil {
+		fail(plan, "%s", err.Error())
+	}
+	if utf8.RuneCountInString(record.ExternalKey) > 255 {
+		fail(plan, "外部键长度不能超过255个字符")
+	}
+	for _, resource := range importResources(record.Resources) {
+		if err := resource.Validate(); err != nil {
+			fail(plan, "%s", err.Error())
+		}
+	}
+}
+
+// resolvePrerequisites 将前置知识点的标题或外部键解析为知识点ID
+func (s *KnowledgeImportService) resolvePrerequisites(
+	ctx context.Context,
+	plans []*importPlan,
+	batchKeys, batchTitles map[string]*importPlan,
+	fail func(*importPlan, string, ...interface{}),
+) error {
+	// 收集需要在数据库中查找的引用
+	var lookups []string
+	seen := make(map[string]bool)
+	for _, plan := range plans {
+		for _, ref := range plan.record.Prerequisites {
+			if batchKeys[ref] == nil && batchTitles[ref] == nil && !seen[ref] {
+				seen[ref] = true
+				lookups = append(lookups, ref)
+			}
+		}
+	}
+
+	byKey, err := s.knowledgeRepo.GetByExternalKeys(ctx, lookups)
+	if err != nil {
+		return err
+	}
+	byTitle, err := s.knowledgeRepo.GetByTitles(ctx, lookups)
+	if err != nil {
+		return err
+	}
+	dbKeys := make(map[string]uuid.UUID, len(byKey))
+	for _, point := range byKey {
+		dbKeys[*point.ExternalKey] = point.ID
+	}
+	dbTitles := make(map[string][]uuid.UUID, len(byTitle))
+	for _, point := range byTitle {
+		dbTitles[point.Title] = append(dbTitles[point.Title], point.ID)
+	}
+
+	for _, plan := range plans {
+		if plan.point == nil {
+			continue
+		}
+		for _, ref := range plan.record.Prerequisites {
+			target := batchKeys[ref]
+			if target == nil {
+				target = batchTitles[ref]
+			}
+
+			var id uuid.UUID
+			switch {
+			case target != nil:
+				if target == plan {
+					fail(plan, "知识点不能以自身为前置: %s", ref)
+					continue
+				}
+				if target.point == nil {
+					fail(plan, "前置知识点存在错误: %s", ref)
+					continue
+				}
+				id = target.point.ID
+			case dbKeys[ref] != uuid.Nil:
+				id = dbKeys[ref]
+			case len(dbTitles[ref]) == 1:
+				id = dbTitles[ref][0]
+			case len(dbTitles[ref]) > 1:
+				fail(plan, "前置知识点标题存在多个匹配，请使用外部键: %s", ref)
+				continue
+			default:
+				fail(plan, "未找到前置知识点: %s", ref)
+				continue
+			}
+			if id == plan.point.ID {
+				fail(plan, "知识点不能以自身为前置: %s", ref)
+				continue
+			}
+			plan.prereqs = append(plan.prereqs, id.String())
+		}
+	}
+	return nil
+}
+
+// apply 写入单条导入记录
+func (s *KnowledgeImportService) apply(ctx context.Context, plan *importPlan, actor *KnowledgePointActor) error {
+	record := plan.record
+	point := plan.point
+
+	point.Title = record.Title
+	point.Description = record.Description
+	point.Category = record.Category
+	point.Difficulty = record.Difficulty
+	point.Content = record.Content
+	point.SetTags(record.Tags)
+	point.SetAliases(record.Aliases)
+	point.SetResources(importResources(record.Resources))
+	point.Prerequisites = jsonList(plan.prereqs)
+	if record.ExternalKey != "" {
+		key := record.ExternalKey
+		point.ExternalKey = &key
+	}
+
+	if plan.action == importActionCreate {
+		return s.knowledgePointService.CreateKnowledgePoint(ctx, point, actor)
+	}
+	_, err := s.knowledgePointService.UpdateKnowledgePoint(ctx, point, actor)
+	return err
+}
+
+// importResources 将导入数据中的资源URL转换为链接类型的学习资源
+func importResources(urls []string) []entities.LearningResource {
+	resources := make([]entities.LearningResource, 0, len(urls))
+	for _, url := range urls {
+		resources = append(resources, entities.LearningResource{
+			Type:  string(entities.ResourceTypeLink),
+			Title: url,
+			URL:   url,
+		})
+	}
+	return resources
+}
+
+// jsonList 将字符串列表序列化为JSON数组
+func jsonList(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+	"sical-go-backend/pkg/mention"
+	"sical-go-backend/pkg/textsearch"
+)
+
+// linkRebuildBatchSize 重建关联时每批处理的知识点数
+const linkRebuildBatchSize = 200
+
+// KnowledgePointRelation 关联的知识点及提及信息
+type KnowledgePointRelation struct {
+	Point       *entities.KnowledgePoint
+	Mention     string
+	Occurrences int
+}
+
+// GetRelatedKnowledgePoints 获取知识点内容中提及的其他知识点，仅返回操作者可见的
+func (s *KnowledgePointService) GetRelatedKnowledgePoints(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) ([]*KnowledgePointRelation, error) {
+	if _, err := s.GetKnowledgePoint(ctx, id, actor); err != nil {
+		return nil, err
+	}
+	links, err := s.linkRepo.GetOutgoing(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleRelations(ctx, links, func(link *entities.KnowledgePointLink) uuid.UUID { return link.TargetID }, actor)
+}
+
+// GetBacklinks 获取内容中提及该知识点的其他知识点，仅返回操作者可见的
+func (s *KnowledgePointService) GetBacklinks(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) ([]*KnowledgePointRelation, error) {
+	if _, err := s.GetKnowledgePoint(ctx, id, actor); err != nil {
+		return nil, err
+	}
+	links, err := s.linkRepo.GetIncoming(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleRelations(ctx, links, func(link *entities.KnowledgePointLink) uuid.UUID { return link.SourceID }, actor)
+}
+
+// RebuildLinks 重新识别全部知识点内容中的提及，仅限管理员，返回处理的知识点数
+func (s *KnowledgePointService) RebuildLinks(ctx context.Context, actor *KnowledgePointActor) (int, error) {
+	if !actor.IsAdmin() {
+		return 0, ErrKnowledgePointForbidden
+	}
+	index, err := s.loadMentionIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for start := 0; start < len(index.ids); start += linkRebuildBatchSize {
+		end := min(start+linkRebuildBatchSize, len(index.ids))
+		points, err := s.knowledgeRepo.GetByIDs(ctx, index.ids[start:end])
+		if err != nil {
+			return count, err
+		}
+		for _, point := range points {
+			if err := s.indexOutgoingLinks(ctx, index.matcher, point); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+
+	logger.Info("知识点关联重建完成", logger.Int("count", count))
+	return count, nil
+}
+
+// refreshLinks 知识点写入后更新关联；关联为派生数据，失败时仅记录日志，可通过重建恢复
+func (s *KnowledgePointService) refreshLinks(ctx context.Context, point *entities.KnowledgePoint, previousTerms []string) {
+	if err := s.indexLinks(ctx, point, previousTerms); err != nil {
+		logger.Error("更新知识点关联失败",
+			logger.String("knowledge_point_id", point.ID.String()),
+			logger.String("error", err.Error()),
+		)
+	}
+}
+
+// RefreshMentions 术语表变更后，重新识别内容中包含相关名称的知识点；失败时仅记录日志
+func (s *KnowledgePointService) RefreshMentions(ctx context.Context, names []string) {
+	if err := s.reindexMentioning(ctx, names); err != nil {
+		logger.Error("更新知识点关联失败", logger.String("error", err.Error()))
+	}
+}
+
+// reindexMentioning 重新识别内容中包含任一名称的知识点
+func (s *KnowledgePointService) reindexMentioning(ctx context.Context, names []string) error {
+	index, err := s.loadMentionIndex(ctx)
+	if err != nil {
+		return err
+	}
+	candidates, err := s.knowledgeRepo.GetByContentMentions(ctx, names)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		if err := s.indexOutgoingLinks(ctx, index.matcher, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexLinks 重新识别知识点内容中的提及；新建知识点或标题、别名变更时，
+// 同时重新识别内容中可能提及新名称的知识点以及原有的反向链接来源
+func (s *KnowledgePointService) indexLinks(ctx context.Context, point *entities.KnowledgePoint, previousTerms []string) error {
+	index, err := s.loadMentionIndex(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.indexOutgoingLinks(ctx, index.matcher, point); err != nil {
+		return err
+	}
+	if previousTerms != nil && sameTerms(previousTerms, point.MentionTerms()) {
+		return nil
+	}
+
+	candidates, err := s.knowledgeRepo.GetByContentMentions(ctx, index.names[point.ID])
+	if err != nil {
+		return err
+	}
+	incoming, err := s.linkRepo.GetIncoming(ctx, point.ID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uuid.UUID]bool, len(candidates))
+	for _, candidate := range candidates {
+		seen[candidate.ID] = true
+	}
+	var sourceIDs []uuid.UUID
+	for _, link := range incoming {
+		if !seen[link.SourceID] {
+			seen[link.SourceID] = true
+			sourceIDs = append(sourceIDs, link.SourceID)
+		}
+	}
+	sources, err := s.knowledgeRepo.GetByIDs(ctx, sourceIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range append(candidates, sources...) {
+		if candidate.ID == point.ID {
+			continue
+		}
+		if err := s.indexOutgoingLinks(ctx, index.matcher, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexOutgoingLinks 识别单个知识点内容中的提及并替换其关联，忽略对自身的提及
+func (s *KnowledgePointService) indexOutgoingLinks(ctx context.Context, matcher *mention.Matcher, point *entities.KnowledgePoint) error {
+	var links []*entities.KnowledgePointLink
+	byTarget := make(map[uuid.UUID]*entities.KnowledgePointLink)
+	for _, match := range matcher.FindAll(markdown.PlainText(point.Content)) {
+		targetID, err := uuid.Parse(match.Target)
+		if err != nil || targetID == point.ID {
+			continue
+		}
+		if link := byTarget[targetID]; link != nil {
+			link.Occurrences++
+			continue
+		}
+		link := &entities.KnowledgePointLink{
+			ID:          uuid.New(),
+			SourceID:    point.ID,
+			TargetID:    targetID,
+			Mention:     match.Text,
+			Occurrences: 1,
+		}
+		byTarget[targetID] = link
+		links = append(links, link)
+	}
+	return s.linkRepo.ReplaceOutgoing(ctx, point.ID, links)
+}
+
+// visibleRelations 加载关联的知识点并过滤操作者不可见的
+func (s *KnowledgePointService) visibleRelations(
+	ctx context.Context,
+	links []*entities.KnowledgePointLink,
+	pick func(*entities.KnowledgePointLink) uuid.UUID,
+	actor *KnowledgePointActor,
+) ([]*KnowledgePointRelation, error) {
+	ids := make([]uuid.UUID, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, pick(link))
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		byID[point.ID] = point
+	}
+
+	relations := []*KnowledgePointRelation{}
+	for _, link := range links {
+		point := byID[pick(link)]
+		if point == nil || !s.CanView(point, actor) {
+			continue
+		}
+		relations = append(relations, &KnowledgePointRelation{
+			Point:       point,
+			Mention:     link.Mention,
+			Occurrences: link.Occurrences,
+		})
+	}
+	return relations, nil
+}
+
+// mentionIndex 提及识别索引
+type mentionIndex struct {
+	ids     []uuid.UUID            // 全部知识点ID
+	matcher *mention.Matcher       // 名称匹配器
+	names   map[uuid.UUID][]string // 每个知识点可被识别的全部名称
+}
+
+// loadMentionIndex 根据知识点标题、别名以及术语表创建提及识别索引：
+// 术语表条目的任一名称与知识点标题或别名相同时，该条目的其余同义词与缩写同样指向该知识点；
+// 名称重复时先创建的知识点优先，知识点自身的标题与别名优先于术语表
+func (s *KnowledgePointService) loadMentionIndex(ctx context.Context) (*mentionIndex, error) {
+	points, err := s.knowledgeRepo.GetMentionIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	glossary, err := s.glossaryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &mentionIndex{
+		ids:   make([]uuid.UUID, 0, len(points)),
+		names: make(map[uuid.UUID][]string, len(points)),
+	}
+	var terms []mention.Term
+	owners := make(map[string]uuid.UUID)
+	for _, point := range points {
+		index.ids = append(index.ids, point.ID)
+		for _, name := range point.MentionTerms() {
+			terms = append(terms, mention.Term{Text: name, Target: point.ID.String()})
+			index.names[point.ID] = append(index.names[point.ID], name)
+			if key := textsearch.NormalizeTerm(name); key != "" {
+				if _, exists := owners[key]; !exists {
+					owners[key] = point.ID
+				}
+			}
+		}
+	}
+	for _, entry := range glossary {
+		owner, found := uuid.Nil, false
+		for _, key := range entry.NormalizedNames() {
+			if owner, found = owners[key]; found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, name := range entry.Names() {
+			terms = append(terms, mention.Term{Text: name, Target: owner.String()})
+			index.names[owner] = append(index.names[owner], name)
+		}
+	}
+	index.matcher = mention.NewMatcher(terms)
+	return index, nil
+}
+
+// sameTerms 比较两组名称是否一致（不区分大小写与顺序）
+func sameTerms(a, b []string) bool {
+	normalize := func(terms []string) string {
+		lowered := make([]string, len(terms))
+		for i, term := range terms {
+			lowered[i] = strings.ToLower(strings.TrimSpace(term))
+		}
+		sort.Strings(lowered)
+		return strings.Join(lowered, "\x00")
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,606 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/locale"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+	"sical-go-backend/pkg/textdiff"
+)
+
+// diffContextLines 内容差异的上下文行数
+const diffContextLines = 3
+
+// ErrKnowledgePointForbidden 无权操作知识点
+var ErrKnowledgePointForbidden = errors.New("无权操作该知识点")
+
+// KnowledgePointService 知识点服务
+type KnowledgePointService struct {
+	knowledgeRepo   repositories.KnowledgePointRepository
+	revisionRepo    repositories.KnowledgePointRevisionRepository
+	reviewRepo      repositories.KnowledgePointReviewRepository
+	attachmentRepo  repositories.AttachmentRepository
+	linkRepo        repositories.KnowledgePointLinkRepository
+	glossaryRepo    repositories.GlossaryRepository
+	fingerprintRepo repositories.KnowledgePointFingerprintRepository
+	txManager       repositories.TransactionManager
+}
+
+// NewKnowledgePointService 创建知识点服务
+func NewKnowledgePointService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	revisionRepo repositories.KnowledgePointRevisionRepository,
+	reviewRepo repositories.KnowledgePointReviewRepository,
+	attachmentRepo repositories.AttachmentRepository,
+	linkRepo repositories.KnowledgePointLinkRepository,
+	glossaryRepo repositories.GlossaryRepository,
+	fingerprintRepo repositories.KnowledgePointFingerprintRepository,
+	txManager repositories.TransactionManager,
+) *KnowledgePointService {
+	return &KnowledgePointService{
+		knowledgeRepo:   knowledgeRepo,
+		revisionRepo:    revisionRepo,
+		reviewRepo:      reviewRepo,
+		attachmentRepo:  attachmentRepo,
+		linkRepo:        linkRepo,
+		glossaryRepo:    glossaryRepo,
+		fingerprintRepo: fingerprintRepo,
+		txManager:       txManager,
+	}
+}
+
+// KnowledgePointActor 知识点操作者；匿名访问时为nil
+type KnowledgePointActor struct {
+	UserID uint
+	Role   string
+}
+
+// IsAdmin 检查操作者是否为管理员
+func (a *KnowledgePointActor) IsAdmin() bool {
+	return a != nil && (a.Role == string(entities.RoleAdmin) || a.Role == "super_admin")
+}
+
+// IsModerator 检查操作者是否具备审核权限（审核员或管理员）
+func (a *KnowledgePointActor) IsModerator() bool {
+	return a != nil && (a.Role == string(entities.RoleModerator) || a.IsAdmin())
+}
+
+// SystemActor 后台定时任务使用的系统操作者，具备管理员权限
+func SystemActor() *KnowledgePointActor {
+	return &KnowledgePointActor{Role: string(entities.RoleAdmin)}
+}
+
+// userID 获取操作者ID，匿名时返回nil
+func (a *KnowledgePointActor) userID() *uint {
+	if a == nil {
+		return nil
+	}
+	id := a.UserID
+	return &id
+}
+
+// FieldChange 字段变更
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// RevisionComparison 两个修订版本的比较结果
+type RevisionComparison struct {
+	From          int           `json:"from"`
+	To            int           `json:"to"`
+	ChangedFields []FieldChange `json:"changed_fields"`
+	ContentDiff   string        `json:"content_diff"`
+}
+
+// CreateKnowledgePoint 创建草稿状态的知识点并记录初始版本
+func (s *KnowledgePointService) CreateKnowledgePoint(ctx context.Context, point *entities.KnowledgePoint, actor *KnowledgePointActor) error {
+	if err := markdown.Validate(point.Content); err != nil {
+		return err
+	}
+	point.Status = string(entities.KnowledgePointStatusDraft)
+	point.AuthorID = actor.userID()
+	point.ApprovedBy = nil
+	point.PublishedAt = nil
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.knowledgeRepo.Create(ctx, point); err != nil {
+			return err
+		}
+		if err := trackKnowledgePointAttachments(ctx, s.attachmentRepo, point); err != nil {
+			return err
+		}
+		_, err := s.recordRevision(ctx, point, actor.userID(), nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.refreshLinks(ctx, point, nil)
+	s.refreshFingerprint(ctx, point)
+
+	logger.Info("知识点创建成功", logger.String("knowledge_point_id", point.ID.String()))
+	return nil
+}
+
+// GetKnowledgePoint 获取知识点；未发布的知识点仅作者与审核人员可见
+func (s *KnowledgePointService) GetKnowledgePoint(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.KnowledgePoint, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanView(point, actor) {
+		return nil, repositories.NotFound("知识点不存在")
+	}
+	return point, nil
+}
+
+// CanView 检查操作者是否可查看知识点
+func (s *KnowledgePointService) CanView(point *entities.KnowledgePoint, actor *KnowledgePointActor) bool {
+	if point.IsPublished() {
+		return true
+	}
+	return actor != nil && (actor.IsModerator() || point.IsAuthoredBy(actor.UserID))
+}
+
+// CanEdit 检查操作者是否可编辑知识点：作者可编辑草稿，审核人员可编辑任意状态
+func (s *KnowledgePointService) CanEdit(point *entities.KnowledgePoint, actor *KnowledgePointActor) bool {
+	if actor == nil {
+		return false
+	}
+	if actor.IsModerator() {
+		return true
+	}
+	return point.IsAuthoredBy(actor.UserID) && point.Status == string(entities.KnowledgePointStatusDraft)
+}
+
+// UpdateKnowledgePoint 更新知识点并记录修订版本；无实际变更时不产生新版本
+func (s *KnowledgePointService) UpdateKnowledgePoint(ctx context.Context, point *entities.KnowledgePoint, actor *KnowledgePointActor) (*entities.KnowledgePointRevision, error) {
+	if actor == nil {
+		return nil, ErrKnowledgePointForbidden
+	}
+	if err := markdown.Validate(point.Content); err != nil {
+		return nil, err
+	}
+
+	// 内容更新与修订版本在同一事务中写入，锁定知识点以串行化并发编辑
+	var previous *entities.KnowledgePoint
+	var revision *entities.KnowledgePointRevision
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if previous, err = s.knowledgeRepo.GetByIDForUpdate(ctx, point.ID); err != nil {
+			return err
+		}
+		// 按锁定后的状态与作者检查权限；状态与审核字段只能通过状态流转修改
+		if !s.CanEdit(previous, actor) {
+			return ErrKnowledgePointForbidden
+		}
+		point.Status = previous.Status
+		point.AuthorID = previous.AuthorID
+		point.ApprovedBy = previous.ApprovedBy
+		point.PublishedAt = previous.PublishedAt
+		// 审核中的内容被修改后，原有的审核通过不再适用
+		if point.Status == string(entities.KnowledgePointStatusInReview) {
+			point.ApprovedBy = nil
+		}
+		if err := s.ensureBaseline(ctx, point.ID); err != nil {
+			return err
+		}
+		if err := s.knowledgeRepo.Update(ctx, point); err != nil {
+			return err
+		}
+		if err := trackKnowledgePointAttachments(ctx, s.attachmentRepo, point); err != nil {
+			return err
+		}
+		revision, err = s.recordRevision(ctx, point, actor.userID(), nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.refreshLinks(ctx, point, previous.MentionTerms())
+	s.refreshFingerprint(ctx, point)
+	return revision, nil
+}
+
+// DeleteKnowledgePoint 删除知识点：管理员可删除任意知识点，作者可删除自己的草稿
+func (s *KnowledgePointService) DeleteKnowledgePoint(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	point, err := s.knowledgeRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if actor == nil || !(actor.IsAdmin() || (point.IsAuthoredBy(actor.UserID) && point.Status == string(entities.KnowledgePointStatusDraft))) {
+		return ErrKnowledgePointForbidden
+	}
+	if err := s.knowledgeRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := s.linkRepo.DeleteByKnowledgePoint(ctx, id); err != nil {
+		logger.Error("删除知识点关联失败", logger.String("knowledge_point_id", id.String()), logger.String("error", err.Error()))
+	}
+	if err := s.fingerprintRepo.Delete(ctx, id); err != nil {
+		logger.Error("删除知识点指纹失败", logger.String("knowledge_point_id", id.String()), logger.String("error", err.Error()))
+	}
+	return nil
+}
+
+// KnowledgePointSearchResult 知识点检索结果
+type KnowledgePointSearchResult struct {
+	Hits     []*repositories.KnowledgePointSearchHit
+	Total    int64
+	Synonyms map[string][]string // 按术语表扩展的查询词及其同义词
+	Locale   string              // 检索所用的语言
+}
+
+// SearchKnowledgePoints 全文检索已发布的知识点；查询整体或其中的单词命中术语表时，同时检索其同义词与缩写
+func (s *KnowledgePointService) SearchKnowledgePoints(ctx context.Context, query string, offset, limit int) (*KnowledgePointSearchResult, error) {
+	synonyms, err := lookupSynonyms(ctx, s.glossaryRepo, append([]string{query}, strings.Fields(query)...))
+	if err != nil {
+		return nil, err
+	}
+	hits, total, err := s.knowledgeRepo.Search(ctx, query, synonyms, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &KnowledgePointSearchResult{Hits: hits, Total: total, Synonyms: synonyms, Locale: locale.DefaultLocale}, nil
+}
+
+// GetMyKnowledgePoints 获取用户创建的知识点（含草稿）
+func (s *KnowledgePointService) GetMyKnowledgePoints(ctx context.Context, userID uint) ([]*entities.KnowledgePoint, error) {
+	return s.knowledgeRepo.GetByAuthor(ctx, userID)
+}
+
+// GetReviewQueue 获取待审核的知识点，仅限审核人员
+func (s *KnowledgePointService) GetReviewQueue(ctx context.Context, actor *KnowledgePointActor) ([]*entities.KnowledgePoint, error) {
+	if !actor.IsModerator() {
+		return nil, ErrKnowledgePointForbidden
+	}
+	return s.knowledgeRepo.GetByStatus(ctx, string(entities.KnowledgePointStatusInReview))
+}
+
+// Transition 执行知识点状态流转：
+// 作者提交审核（submit），审核人员通过（approve）或退回修改（request_changes），
+// 管理员发布已通过审核的知识点（publish）、归档（archive），审核人员可将归档内容重新打开为草稿（reopen）
+func (s *KnowledgePointService) Transition(ctx context.Context, id uuid.UUID, action entities.ReviewAction, comment string, actor *KnowledgePointActor) (*entities.KnowledgePoint, error) {
+	if actor == nil {
+		return nil, ErrKnowledgePointForbidden
+	}
+
+	// 状态变更与审核记录在同一事务中写入，锁定知识点以串行化并发的状态流转
+	var point *entities.KnowledgePoint
+	var from, to entities.KnowledgePointStatus
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if point, err = s.knowledgeRepo.GetByIDForUpdate(ctx, id); err != nil {
+			return err
+		}
+		if from, to, err = s.applyTransition(point, action, comment, actor); err != nil {
+			return err
+		}
+		if err := s.knowledgeRepo.Update(ctx, point); err != nil {
+			return err
+		}
+		_, err = s.createReview(ctx, point, action, comment, from, to, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("知识点状态流转",
+		logger.String("knowledge_point_id", point.ID.String()),
+		logger.String("action", string(action)),
+		logger.String("from", string(from)),
+		logger.String("to", string(to)),
+	)
+	return point, nil
+}
+
+// applyTransition 检查操作权限与当前状态，并将状态流转应用到知识点上，返回流转前后的状态
+func (s *KnowledgePointService) applyTransition(point *entities.KnowledgePoint, action entities.ReviewAction, comment string, actor *KnowledgePointActor) (from, to entities.KnowledgePointStatus, err error) {
+	from = entities.KnowledgePointStatus(point.Status)
+	switch action {
+	case entities.ReviewActionSubmit:
+		if !point.IsAuthoredBy(actor.UserID) && !actor.IsModerator() {
+			return from, to, ErrKnowledgePointForbidden
+		}
+		if from != entities.KnowledgePointStatusDraft {
+			return from, to, fmt.Errorf("只有草稿可以提交审核")
+		}
+		to = entities.KnowledgePointStatusInReview
+		point.ApprovedBy = nil
+	case entities.ReviewActionApprove:
+		if !actor.IsModerator() {
+			return from, to, ErrKnowledgePointForbidden
+		}
+		if from != entities.KnowledgePointStatusInReview {
+			return from, to, fmt.Errorf("只有审核中的知识点可以审核通过")
+		}
+		to = from
+		point.ApprovedBy = actor.userID()
+	case entities.ReviewActionRequestChanges:
+		if !actor.IsModerator() {
+			return from, to, ErrKnowledgePointForbidden
+		}
+		if from != entities.KnowledgePointStatusInReview {
+			return from, to, fmt.Errorf("只有审核中的知识点可以退回修改")
+		}
+		if comment == "" {
+			return from, to, fmt.Errorf("退回修改需要填写审核意见")
+		}
+		to = entities.KnowledgePointStatusDraft
+		point.ApprovedBy = nil
+	case entities.ReviewActionPublish:
+		if !actor.IsAdmin() {
+			return from, to, ErrKnowledgePointForbidden
+		}
+		if from != entities.KnowledgePointStatusInReview || point.ApprovedBy == nil {
+			return from, to, fmt.Errorf("只有审核通过的知识点可以发布")
+		}
+		to = entities.KnowledgePointStatusPublished
+		now := time.Now()
+		point.PublishedAt = &now
+	case entities.ReviewActionArchive:
+		if !actor.IsAdmin() {
+			return from, to, ErrKnowledgePointForbidden
+		}
+		if from != entities.KnowledgePointStatusPublished {
+			return from, to, fmt.Errorf("只有已发布的知识点可以归档")
+		}
+		to = entities.KnowledgePointStatusArchived
+	case entities.ReviewActionReopen:
+		if !actor.IsModerator() {
+			return from, to, ErrKnowledgePointForbidden
+		}
+		if from != entities.KnowledgePointStatusArchived {
+			return from, to, fmt.Errorf("只有已归档的知识点可以重新打开")
+		}
+		to = entities.KnowledgePointStatusDraft
+		point.ApprovedBy = nil
+	default:
+		return from, to, fmt.Errorf("不支持的审核操作: %s", action)
+	}
+
+	point.Status = string(to)
+	return from, to, nil
+}
+
+// AddReviewComment 添加审核意见，不改变知识点状态
+func (s *KnowledgePointService) AddReviewComment(ctx context.Context, id uuid.UUID, comment string, actor *KnowledgePointActor) (*entities.KnowledgePointReview, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil || !(actor.IsModerator() || point.IsAuthoredBy(actor.UserID)) {
+		return nil, ErrKnowledgePointForbidden
+	}
+
+	status := entities.KnowledgePointStatus(point.Status)
+	return s.createReview(ctx, point, entities.ReviewActionComment, comment, status, status, actor)
+}
+
+// GetReviews 获取知识点的审核记录
+func (s *KnowledgePointService) GetReviews(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) ([]*entities.KnowledgePointReview, error) {
+	if _, err := s.GetKnowledgePoint(ctx, id, actor); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.GetByKnowledgePointID(ctx, id)
+}
+
+// createReview 记录审核操作，审核记录关联当前修订版本
+func (s *KnowledgePointService) createReview(ctx context.Context, point *entities.KnowledgePoint, action entities.ReviewAction, comment string, from, to entities.KnowledgePointStatus, actor *KnowledgePointActor) (*entities.KnowledgePointReview, error) {
+	latest, err := s.revisionRepo.GetLatest(ctx, point.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &entities.KnowledgePointReview{
+		ID:               uuid.New(),
+		KnowledgePointID: point.ID,
+		ReviewerID:       actor.UserID,
+		Action:           string(action),
+		Comment:          comment,
+		FromStatus:       string(from),
+		ToStatus:         string(to),
+	}
+	if latest != nil {
+		review.Version = latest.Version
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetRevisions 获取知识点的修订历史
+func (s *KnowledgePointService) GetRevisions(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) ([]*entities.KnowledgePointRevision, error) {
+	if _, err := s.GetKnowledgePoint(ctx, knowledgePointID, actor); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.GetByKnowledgePointID(ctx, knowledgePointID)
+}
+
+// GetRevision 获取指定修订版本
+func (s *KnowledgePointService) GetRevision(ctx context.Context, knowledgePointID uuid.UUID, version int, actor *KnowledgePointActor) (*entities.KnowledgePointRevision, error) {
+	if _, err := s.GetKnowledgePoint(ctx, knowledgePointID, actor); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.GetByVersion(ctx, knowledgePointID, version)
+}
+
+// CompareRevisions 比较两个修订版本
+func (s *KnowledgePointService) CompareRevisions(ctx context.Context, knowledgePointID uuid.UUID, from, to int, actor *KnowledgePointActor) (*RevisionComparison, error) {
+	if _, err := s.GetKnowledgePoint(ctx, knowledgePointID, actor); err != nil {
+		return nil, err
+	}
+	fromRevision, err := s.revisionRepo.GetByVersion(ctx, knowledgePointID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.revisionRepo.GetByVersion(ctx, knowledgePointID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &RevisionComparison{
+		From:          from,
+		To:            to,
+		ChangedFields: []FieldChange{},
+		ContentDiff:   textdiff.Unified(fromRevision.Content, toRevision.Content, fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to), diffContextLines),
+	}
+	before, after := revisionFields(fromRevision), revisionFields(toRevision)
+	for _, field := range revisionFieldNames {
+		if before[field] == after[field] {
+			continue
+		}
+		change := FieldChange{Field: field}
+		// 内容字段通过差异文本展示
+		if field != "content" {
+			change.Before = before[field]
+			change.After = after[field]
+		}
+		comparison.ChangedFields = append(comparison.ChangedFields, change)
+	}
+	return comparison, nil
+}
+
+// RestoreRevision 将知识点恢复到指定修订版本，恢复操作本身产生一个新版本
+func (s *KnowledgePointService) RestoreRevision(ctx context.Context, knowledgePointID uuid.UUID, version int, actor *KnowledgePointActor) (*entities.KnowledgePoint, *entities.KnowledgePointRevision, error) {
+	revision, err := s.revisionRepo.GetByVersion(ctx, knowledgePointID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var point *entities.KnowledgePoint
+	var restored *entities.KnowledgePointRevision
+	var previousTerms []string
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if point, err = s.knowledgeRepo.GetByIDForUpdate(ctx, knowledgePointID); err != nil {
+			return err
+		}
+		if !s.CanEdit(point, actor) {
+			return ErrKnowledgePointForbidden
+		}
+		if err := s.ensureBaseline(ctx, knowledgePointID); err != nil {
+			return err
+		}
+
+		previousTerms = point.MentionTerms()
+		revision.ApplyTo(point)
+		if err := markdown.Validate(point.Content); err != nil {
+			return err
+		}
+		if err := s.knowledgeRepo.Update(ctx, point); err != nil {
+			return err
+		}
+		restored, err = s.recordRevision(ctx, point, actor.userID(), &version)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.refreshLinks(ctx, point, previousTerms)
+	s.refreshFingerprint(ctx, point)
+
+	return point, restored, nil
+}
+
+// ensureBaseline 为尚无修订记录的知识点补记当前内容作为基线版本
+func (s *KnowledgePointService) ensureBaseline(ctx context.Context, knowledgePointID uuid.UUID) error {
+	latest, err := s.revisionRepo.GetLatest(ctx, knowledgePointID)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		return nil
+	}
+	current, err := s.knowledgeRepo.GetByID(ctx, knowledgePointID)
+	if err != nil {
+		return err
+	}
+	_, err = s.recordRevision(ctx, current, nil, nil)
+	return err
+}
+
+// recordRevision 记录知识点当前内容为新修订版本
+func (s *KnowledgePointService) recordRevision(ctx context.Context, point *entities.KnowledgePoint, authorID *uint, restoredFrom *int) (*entities.KnowledgePointRevision, error) {
+	latest, err := s.revisionRepo.GetLatest(ctx, point.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := entities.NewKnowledgePointRevision(point)
+	revision.AuthorID = authorID
+	revision.RestoredFrom = restoredFrom
+
+	changed := revisionFieldNames
+	if latest != nil {
+		changed = changedFields(latest, revision)
+		if len(changed) == 0 {
+			return latest, nil
+		}
+		revision.ContentDiff = textdiff.Unified(latest.Content, revision.Content,
+			fmt.Sprintf("v%d", latest.Version), fmt.Sprintf("v%d", latest.Version+1), diffContextLines)
+	}
+	data, _ := json.Marshal(changed)
+	revision.ChangedFields = string(data)
+
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// revisionFieldNames 纳入版本管理的字段
+var revisionFieldNames = []string{"title", "description", "category", "difficulty", "content", "resources", "prerequisites", "tags", "aliases"}
+
+// revisionFields 获取修订版本的字段值
+func revisionFields(revision *entities.KnowledgePointRevision) map[string]string {
+	return map[string]string{
+		"title":         revision.Title,
+		"description":   revision.Description,
+		"category":      revision.Category,
+		"difficulty":    revision.Difficulty,
+		"content":       revision.Content,
+		"resources":     normalizeJSON(revision.Resources),
+		"prerequisites": normalizeJSON(revision.Prerequisites),
+		"tags":          normalizeJSON(revision.Tags),
+		"aliases":       normalizeJSON(revision.Aliases),
+	}
+}
+
+// normalizeJSON 规范化JSON文本，避免数据库jsonb格式化造成的差异
+func normalizeJSON(value string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return value
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return value
+	}
+	return string(data)
+}
+
+// changedFields 比较两个修订版本的变更字段
+func changedFields(before, after *entities.KnowledgePointRevision) []string {
+	beforeFields, afterFields := revisionFields(before), revisionFields(after)
+	changed := []string{}
+	for _, field := range revisionFieldNames {
+		if beforeFields[field] != afterFields[field] {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sical-go-backend/internal/domain/entities"
+)
+
+var (
+	testAuthor    = &KnowledgePointActor{UserID: 1, Role: string(entities.RoleUser)}
+	testOtherUser = &KnowledgePointActor{UserID: 2, Role: string(entities.RoleUser)}
+	testModerator = &KnowledgePointActor{UserID: 3, Role: string(entities.RoleModerator)}
+	testAdmin     = &KnowledgePointActor{UserID: 4, Role: string(entities.RoleAdmin)}
+)
+
+func TestKnowledgePointServiceUpdateKnowledgePoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     entities.KnowledgePointStatus
+		approvedBy *uint
+		actor      *KnowledgePointActor
+		// edit 模拟调用方在加锁前读取并修改的副本
+		edit         func(point *entities.KnowledgePoint)
+		wantErr      error
+		wantStatus   entities.KnowledgePointStatus
+		wantApproved bool
+	}{
+		{
+			name:       "作者编辑自己的草稿",
+			stored:     entities.KnowledgePointStatusDraft,
+			actor:      testAuthor,
+			wantStatus: entities.KnowledgePointStatusDraft,
+		},
+		{
+			name:   "调用方读取后已提交审核，作者不能再编辑",
+			stored: entities.KnowledgePointStatusInReview,
+			actor:  testAuthor,
+			edit: func(point *entities.KnowledgePoint) {
+				point.Status = string(entities.KnowledgePointStatusDraft)
+			},
+			wantErr: ErrKnowledgePointForbidden,
+		},
+		{
+			name:   "调用方读取后作者已变更",
+			stored: entities.KnowledgePointStatusDraft,
+			actor:  testOtherUser,
+			edit: func(point *entities.KnowledgePoint) {
+				point.AuthorID = uintPtr(testOtherUser.UserID)
+			},
+			wantErr: ErrKnowledgePointForbidden,
+		},
+		{
+			name:   "编辑不能修改状态",
+			stored: entities.KnowledgePointStatusDraft,
+			actor:  testAuthor,
+			edit: func(point *entities.KnowledgePoint) {
+				point.Status = string(entities.KnowledgePointStatusPublished)
+			},
+			wantStatus: entities.KnowledgePointStatusDraft,
+		},
+		{
+			name:       "审核人员编辑审核中的内容后审核通过失效",
+			stored:     entities.KnowledgePointStatusInReview,
+			approvedBy: uintPtr(testModerator.UserID),
+			actor:      testModerator,
+			wantStatus: entities.KnowledgePointStatusInReview,
+		},
+		{
+			name:         "审核人员编辑已发布的内容保留审核人",
+			stored:       entities.KnowledgePointStatusPublished,
+			approvedBy:   uintPtr(testModerator.UserID),
+			actor:        testModerator,
+			wantStatus:   entities.KnowledgePointStatusPublished,
+			wantApproved: true,
+		},
+		{
+			name:    "匿名用户",
+			stored:  entities.KnowledgePointStatusDraft,
+			wantErr: ErrKnowledgePointForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			point := testKnowledgePoint("二叉树", tt.stored, testAuthor.UserID)
+			point.ApprovedBy = tt.approvedBy
+			f := newKnowledgeFixture(point)
+
+			edited := *point
+			edited.Content = "更新后的内容"
+			if tt.edit != nil {
+				tt.edit(&edited)
+			}
+			_, err := f.service.UpdateKnowledgePoint(context.Background(), &edited, tt.actor)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UpdateKnowledgePoint() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(f.db.writes) != 0 {
+					t.Fatalf("UpdateKnowledgePoint() wrote %v, want no writes", f.db.writes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateKnowledgePoint() error = %v", err)
+			}
+
+			stored := f.points.points[point.ID]
+			if stored.Content != "更新后的内容" || stored.Status != string(tt.wantStatus) {
+				t.Fatalf("stored = {content: %q, status: %q}, want {content: %q, status: %q}", stored.Content, stored.Status, "更新后的内容", tt.wantStatus)
+			}
+			if (stored.ApprovedBy != nil) != tt.wantApproved {
+				t.Fatalf("stored ApprovedBy = %v, want approved %v", stored.ApprovedBy, tt.wantApproved)
+			}
+			if len(f.db.outside) != 0 {
+				t.Fatalf("writes outside transaction: %v", f.db.outside)
+			}
+		})
+	}
+}
+
+func TestKnowledgePointServiceTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     entities.KnowledgePointStatus
+		approvedBy *uint
+		action     entities.ReviewAction
+		comment    string
+		actor      *KnowledgePointActor
+		reviewErr  error
+		wantErr    bool
+		wantStatus entities.KnowledgePointStatus
+	}{
+		{name: "作者提交审核", stored: entities.KnowledgePointStatusDraft, action: entities.ReviewActionSubmit, actor: testAuthor, wantStatus: entities.KnowledgePointStatusInReview},
+		{name: "其他用户不能提交审核", stored: entities.KnowledgePointStatusDraft, action: entities.ReviewActionSubmit, actor: testOtherUser, wantErr: true},
+		{name: "普通用户不能审核通过", stored: entities.KnowledgePointStatusInReview, action: entities.ReviewActionApprove, actor: testAuthor, wantErr: true},
+		{name: "审核人员退回修改", stored: entities.KnowledgePointStatusInReview, action: entities.ReviewActionRequestChanges, comment: "补充示例", actor: testModerator, wantStatus: entities.KnowledgePointStatusDraft},
+		{name: "退回修改需要意见", stored: entities.KnowledgePointStatusInReview, action: entities.ReviewActionRequestChanges, actor: testModerator, wantErr: true},
+		{name: "未审核通过不能发布", stored: entities.KnowledgePointStatusInReview, action: entities.ReviewActionPublish, actor: testAdmin, wantErr: true},
+		{name: "管理员发布审核通过的知识点", stored: entities.KnowledgePointStatusInReview, approvedBy: uintPtr(testModerator.UserID), action: entities.ReviewActionPublish, actor: testAdmin, wantStatus: entities.KnowledgePointStatusPublished},
+		{name: "审核记录写入失败时状态回滚", stored: entities.KnowledgePointStatusDraft, action: entities.ReviewActionSubmit, actor: testAuthor, reviewErr: errors.New("写入失败"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			point := testKnowledgePoint("二叉树", tt.stored, testAuthor.UserID)
+			point.ApprovedBy = tt.approvedBy
+			f := newKnowledgeFixture(point)
+			f.reviews.createErr = tt.reviewErr
+
+			_, err := f.service.Transition(context.Background(), point.ID, tt.action, tt.comment, tt.actor)
+			stored := f.points.points[point.ID]
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Transition() error = nil, want error")
+				}
+				if len(f.db.writes) != 0 || stored.Status != string(tt.stored) {
+					t.Fatalf("after failed Transition() writes = %v, status = %q, want no writes and status %q", f.db.writes, stored.Status, tt.stored)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transition() error = %v", err)
+			}
+			if stored.Status != string(tt.wantStatus) {
+				t.Fatalf("status = %q, want %q", stored.Status, tt.wantStatus)
+			}
+			if len(f.reviews.reviews) != 1 || f.reviews.reviews[0].ToStatus != string(tt.wantStatus) {
+				t.Fatalf("reviews = %+v, want one review to %q", f.reviews.reviews, tt.wantStatus)
+			}
+			if len(f.db.outside) != 0 {
+				t.Fatalf("writes outside transaction: %v", f.db.outside)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// 推荐参数
+const (
+	DefaultRecommendationLimit = 10
+	MaxRecommendationLimit     = 50
+	masteryInterval            = 21 // 全部闪卡复习间隔均不少于该天数时视为已掌握
+	transitionWindow           = 5  // 每个知识点仅与其后学习的若干个知识点统计转移
+	minTransitionLearners      = 2  // 学习者少于该人数的转移不参与推荐
+)
+
+// 推荐理由类型
+const (
+	RecommendationReasonCollaborative = "collaborative" // 学习过相同知识点的学习者随后学习了该知识点
+	RecommendationReasonPopular       = "popular"       // 暂无学习记录时推荐学习人数最多的知识点
+)
+
+// ErrRecommendationForbidden 无权重新计算推荐模型
+var ErrRecommendationForbidden = errors.New("无权重新计算推荐模型")
+
+// RecommendationReason 推荐理由
+type RecommendationReason struct {
+	Type        string     `json:"type"`
+	Message     string     `json:"message"`
+	SourceID    *uuid.UUID `json:"source_id,omitempty"`    // 贡献最大的已学知识点
+	SourceTitle string     `json:"source_title,omitempty"` // 贡献最大的已学知识点标题
+	Learners    int        `json:"learners"`
+	Confidence  float64    `json:"confidence,omitempty"`
+}
+
+// KnowledgeRecommendation 知识点推荐结果
+type KnowledgeRecommendation struct {
+	Point  *entities.KnowledgePoint
+	Score  float64
+	Reason RecommendationReason
+}
+
+// KnowledgeRecommendationService 基于协同过滤的知识点推荐服务
+type KnowledgeRecommendationService struct {
+	recommendationRepo repositories.KnowledgeRecommendationRepository
+	knowledgeRepo      repositories.KnowledgePointRepository
+}
+
+// NewKnowledgeRecommendationService 创建知识点推荐服务
+func NewKnowledgeRecommendationService(
+	recommendationRepo repositories.KnowledgeRecommendationRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+) *KnowledgeRecommendationService {
+	return &KnowledgeRecommendationService{
+		recommendationRepo: recommendationRepo,
+		knowledgeRepo:      knowledgeRepo,
+	}
+}
+
+// isMastered 检查学习状态是否已掌握
+func isMastered(state *repositories.LearnerKnowledgeState) bool {
+	return state.MinInterval >= masteryInterval
+}
+
+// outcomeWeight 学习表现权重：平均难度系数越高（复习评分越好）、遗忘越少、对知识点的评分越高，权重越高，取值约0.5至1.2；
+// 仅完成学习路径步骤或评分而未复习闪卡时按默认难度系数计算
+func outcomeWeight(state *repositories.LearnerKnowledgeState) float64 {
+	ease := state.AvgEase
+	if ease == 0 {
+		ease = defaultEaseFactor
+	}
+	weight := ease / defaultEaseFactor
+	weight /= 1 + 0.1*float64(state.Lapses)
+	if state.Rating > 0 {
+		weight = (weight + float64(state.Rating)/4) / 2
+	}
+	return math.Max(0.5, math.Min(1.2, weight))
+}
+
+// RecomputeTransitions 根据全部学习者的闪卡复习、学习路径步骤完成与评分记录重新计算知识点转移统计，仅限管理员，返回转移数
+func (s *KnowledgeRecommendationService) RecomputeTransitions(ctx context.Context, actor *KnowledgePointActor) (int, error) {
+	if !actor.IsAdmin() {
+		return 0, ErrRecommendationForbidden
+	}
+	states, err := s.recommendationRepo.GetLearnerStates(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	type pair struct{ source, target uuid.UUID }
+	learners := make(map[uuid.UUID]int)
+	counts := make(map[pair]int)
+	weights := make(map[pair]float64)
+
+	// 按学习者分组，状态已按首次学习时间排序
+	for start := 0; start < len(states); {
+		end := start
+		for end < len(states) && states[end].UserID == states[start].UserID {
+			end++
+		}
+		sequence := states[start:end]
+		for i, source := range sequence {
+			learners[source.KnowledgePointID]++
+			for j := i + 1; j < len(sequence) && j <= i+transitionWindow; j++ {
+				key := pair{source: source.KnowledgePointID, target: sequence[j].KnowledgePointID}
+				counts[key]++
+				weights[key] += outcomeWeight(sequence[j])
+			}
+		}
+		start = end
+	}
+
+	now := time.Now()
+	transitions := make([]*entities.KnowledgePointTransition, 0, len(counts))
+	for key, count := range counts {
+		if count < minTransitionLearners {
+			continue
+		}
+		total := float64(learners[key.source])
+		transitions = append(transitions, &entities.KnowledgePointTransition{
+			SourceID:   key.source,
+			TargetID:   key.target,
+			Learners:   count,
+			Confidence: float64(count) / total,
+			Score:      weights[key] / total,
+			ComputedAt: now,
+		})
+	}
+	if err := s.recommendationRepo.ReplaceTransitions(ctx, transitions); err != nil {
+		return 0, err
+	}
+
+	logger.Info("知识点推荐模型计算完成",
+		logger.Int("learner_states", len(states)),
+		logger.Int("transitions", len(transitions)))
+	return len(transitions), nil
+}
+
+// Recommend 为用户推荐下一步学习的已发布知识点，按推荐分数降序并附推荐理由；
+// 排除已掌握的知识点，暂无学习记录或协同过滤无结果时推荐热门知识点
+func (s *KnowledgeRecommendationService) Recommend(ctx context.Context, userID uint, limit int) ([]*KnowledgeRecommendation, error) {
+	states, err := s.recommendationRepo.GetLearnerStates(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+	studied := make(map[uuid.UUID]*repositories.LearnerKnowledgeState, len(states))
+	sourceIDs := make([]uuid.UUID, 0, len(states))
+	for _, state := range states {
+		studied[state.KnowledgePointID] = state
+		sourceIDs = append(sourceIDs, state.KnowledgePointID)
+	}
+
+	transitions, err := s.recommendationRepo.GetTransitionsFrom(ctx, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 累加各已学知识点的转移强度，并记录贡献最大的来源作为推荐理由
+	scores := make(map[uuid.UUID]float64)
+	best := make(map[uuid.UUID]*entities.KnowledgePointTransition)
+	for _, transition := range transitions {
+		state, inProgress := studied[transition.TargetID]
+		switch {
+		case inProgress && isMastered(state):
+			continue
+		case inProgress:
+			// 已在学习中的知识点降低权重，优先推荐新的知识点
+			scores[transition.TargetID] += transition.Score * 0.5
+		default:
+			scores[transition.TargetID] += transition.Score
+		}
+		if current := best[transition.TargetID]; current == nil || transition.Score > current.Score {
+			best[transition.TargetID] = transition
+		}
+	}
+
+	if len(scores) == 0 {
+		return s.recommendPopular(ctx, studied, limit)
+	}
+
+	targetIDs := make([]uuid.UUID, 0, len(scores))
+	for id := range scores {
+		targetIDs = append(targetIDs, id)
+	}
+	points, err := s.loadPublished(ctx, append(targetIDs, sourceIDs...))
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]*KnowledgeRecommendation, 0, len(scores))
+	for id, score := range scores {
+		point, ok := points[id]
+		if !ok || !point.IsPublished() {
+			continue
+		}
+		transition := best[id]
+		sourceID := transition.SourceID
+		reason := RecommendationReason{
+			Type:       RecommendationReasonCollaborative,
+			SourceID:   &sourceID,
+			Learners:   transition.Learners,
+			Confidence: transition.Confidence,
+		}
+		if source, ok := points[sourceID]; ok {
+			reason.SourceTitle = source.Title
+		}
+		reason.Message = fmt.Sprintf("学习过「%s」的学习者中有%d人（%.0f%%）随后学习了该知识点",
+			reason.SourceTitle, transition.Learners, transition.Confidence*100)
+		recommendations = append(recommendations, &KnowledgeRecommendation{Point: point, Score: score, Reason: reason})
+	}
+	sortRecommendations(recommendations)
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// recommendPopular 推荐学习人数最多且用户尚未学习的知识点
+func (s *KnowledgeRecommendationService) recommendPopular(ctx context.Context, studied map[uuid.UUID]*repositories.LearnerKnowledgeState, limit int) ([]*KnowledgeRecommendation, error) {
+	popular, err := s.recommendationRepo.GetPopular(ctx, limit+len(studied))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(popular))
+	for _, item := range popular {
+		ids = append(ids, item.KnowledgePointID)
+	}
+	points, err := s.loadPublished(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]*KnowledgeRecommendation, 0, limit)
+	for _, item := range popular {
+		point, ok := points[item.KnowledgePointID]
+		if _, seen := studied[item.KnowledgePointID]; seen || !ok {
+			continue
+		}
+		recommendations = append(recommendations, &KnowledgeRecommendation{
+			Point: point,
+			Score: float64(item.Learners),
+			Reason: RecommendationReason{
+				Type:     RecommendationReasonPopular,
+				Message:  fmt.Sprintf("热门知识点：%d位学习者学习过", item.Learners),
+				Learners: item.Learners,
+			},
+		})
+		if len(recommendations) >= limit {
+			break
+		}
+	}
+	return recommendations, nil
+}
+
+// loadPublished 批量加载已发布的知识点
+func (s *KnowledgeRecommendationService) loadPublished(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*entities.KnowledgePoint, error) {
+	points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		if point.IsPublished() {
+			byID[point.ID] = point
+		}
+	}
+	return byID, nil
+}
+
+// sortRecommendations 按推荐分数降序排列，分数相同时按标题排序以保证结果稳定
+func sortRecommendations(recommendations []*KnowledgeRecommendation) {
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Point.Title < recommendations[j].Point.Title
+	})
+}
+
+// GetLastComputedAt 获取推荐模型的最近计算时间
+func (s *KnowledgeRecommendationService) GetLastComputedAt(ctx context.Context) (*time.Time, error) {
+	return s.recommendationRepo.GetLastComputedAt(ctx)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// ErrLearningResourceNotFound 学习资源不存在
+var ErrLearningResourceNotFound = errors.New("学习资源不存在")
+
+// GetResources 获取知识点的学习资源
+func (s *KnowledgePointService) GetResources(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) ([]entities.LearningResource, error) {
+	point, err := s.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+	return point.ResourceList(), nil
+}
+
+// AddResource 为知识点添加学习资源，并记录修订版本
+func (s *KnowledgePointService) AddResource(ctx context.Context, knowledgePointID uuid.UUID, resource entities.LearningResource, actor *KnowledgePointActor) (*entities.LearningResource, error) {
+	if err := resource.Validate(); err != nil {
+		return nil, err
+	}
+	point, err := s.editableKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	resource.ID = uuid.New()
+	point.SetResources(append(point.ResourceList(), resource))
+	if _, err := s.UpdateKnowledgePoint(ctx, point, actor); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateResource 更新知识点的学习资源，并记录修订版本
+func (s *KnowledgePointService) UpdateResource(ctx context.Context, knowledgePointID, resourceID uuid.UUID, resource entities.LearningResource, actor *KnowledgePointActor) (*entities.LearningResource, error) {
+	if err := resource.Validate(); err != nil {
+		return nil, err
+	}
+	point, err := s.editableKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := point.ResourceList()
+	index := findResource(resources, resourceID)
+	if index < 0 {
+		return nil, ErrLearningResourceNotFound
+	}
+	resource.ID = resourceID
+	resources[index] = resource
+	point.SetResources(resources)
+	if _, err := s.UpdateKnowledgePoint(ctx, point, actor); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// DeleteResource 删除知识点的学习资源，并记录修订版本
+func (s *KnowledgePointService) DeleteResource(ctx context.Context, knowledgePointID, resourceID uuid.UUID, actor *KnowledgePointActor) error {
+	point, err := s.editableKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return err
+	}
+
+	resources := point.ResourceList()
+	index := findResource(resources, resourceID)
+	if index < 0 {
+		return ErrLearningResourceNotFound
+	}
+	point.SetResources(append(resources[:index], resources[index+1:]...))
+	_, err = s.UpdateKnowledgePoint(ctx, point, actor)
+	return err
+}
+
+// editableKnowledgePoint 获取操作者可编辑的知识点
+func (s *KnowledgePointService) editableKnowledgePoint(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) (*entities.KnowledgePoint, error) {
+	point, err := s.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanEdit(point, actor) {
+		return nil, ErrKnowledgePointForbidden
+	}
+	return point, nil
+}
+
+// findResource 查找学习资源的位置，不存在时返回-1
+func findResource(resources []entities.LearningResource, resourceID uuid.UUID) int {
+	for i, resource := range resources {
+		if resource.ID == resourceID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/vector"
+)
+
+// 相似度检索参数
+const (
+	DefaultSimilarLimit = 10
+	MaxSimilarLimit     = 50
+	minSimilarity       = 0.05 // 低于该相似度的结果不返回
+	focusAreaThreshold  = 0.1  // 知识点与关注领域的最低相似度
+)
+
+// SimilarKnowledgePoint 相似知识点
+type SimilarKnowledgePoint struct {
+	Point *entities.KnowledgePoint
+	Score float64
+}
+
+// KnowledgeSimilarityService 知识点语义相似度服务：在进程内维护已发布知识点的TF-IDF向量索引，
+// 知识点数量或最近更新时间变化时自动重建
+type KnowledgeSimilarityService struct {
+	knowledgeRepo         repositories.KnowledgePointRepository
+	knowledgePointService *KnowledgePointService
+
+	mu     sync.Mutex
+	index  *vector.Index
+	count  int64     // 构建索引时的已发布知识点数
+	latest time.Time // 构建索引时已发布知识点的最近更新时间
+}
+
+// NewKnowledgeSimilarityService 创建知识点语义相似度服务
+func NewKnowledgeSimilarityService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	knowledgePointService *KnowledgePointService,
+) *KnowledgeSimilarityService {
+	return &KnowledgeSimilarityService{
+		knowledgeRepo:         knowledgeRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// pointDocument 知识点的向量化文本：标题、别名与标签加权，其次为分类、描述与内容
+func pointDocument(point *entities.KnowledgePoint) string {
+	return weightedText(point, true)
+}
+
+// pointSummary 知识点的摘要文本（不含内容），用于与简短的关注领域比较
+func pointSummary(point *entities.KnowledgePoint) string {
+	return weightedText(point, false)
+}
+
+// weightedText 以重复次数提高标题、别名与标签的权重
+func weightedText(point *entities.KnowledgePoint, withContent bool) string {
+	names := strings.Join(append(point.AliasList(), point.TagList()...), " ")
+	parts := []string{point.Title, point.Title, point.Title, names, names, point.Category, point.Description}
+	if withContent {
+		parts = append(parts, point.Content)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// getIndex 获取向量索引，已发布知识点有变化时重建
+func (s *KnowledgeSimilarityService) getIndex(ctx context.Context) (*vector.Index, error) {
+	count, latest, err := s.knowledgeRepo.GetPublishedVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index != nil && s.count == count && s.latest.Equal(latest) {
+		return s.index, nil
+	}
+
+	points, err := s.knowledgeRepo.GetByStatus(ctx, string(entities.KnowledgePointStatusPublished))
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]string, len(points))
+	for _, point := range points {
+		docs[point.ID.String()] = pointDocument(point)
+	}
+	s.index = vector.Build(docs)
+	s.count = count
+	s.latest = latest
+
+	logger.Info("知识点向量索引重建完成", logger.Int("count", s.index.Len()))
+	return s.index, nil
+}
+
+// GetSimilar 获取与知识点内容最相似的已发布知识点（更多类似内容）
+func (s *KnowledgeSimilarityService) GetSimilar(ctx context.Context, id uuid.UUID, limit int, actor *KnowledgePointActor) ([]*SimilarKnowledgePoint, error) {
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query, ok := index.Vector(id.String())
+	if !ok {
+		// 未发布的知识点不在索引中，按当前语料即时向量化
+		query = index.Vectorize(pointDocument(point))
+	}
+	return s.resolve(ctx, index.Nearest(query, limit, minSimilarity, id.String()))
+}
+
+// GetSimilarToText 获取与任意文本最相似的已发布知识点
+func (s *KnowledgeSimilarityService) GetSimilarToText(ctx context.Context, text string, limit int) ([]*SimilarKnowledgePoint, error) {
+	index, err := s.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, index.Nearest(index.Vectorize(text), limit, minSimilarity))
+}
+
+// resolve 按相似度顺序加载知识点
+func (s *KnowledgeSimilarityService) resolve(ctx context.Context, matches []vector.Match) ([]*SimilarKnowledgePoint, error) {
+	ids := make([]uuid.UUID, 0, len(matches))
+	for _, match := range matches {
+		if id, err := uuid.Parse(match.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		byID[point.ID.String()] = point
+	}
+
+	results := make([]*SimilarKnowledgePoint, 0, len(matches))
+	for _, match := range matches {
+		// 索引重建前被撤回发布或删除的知识点不返回
+		if point, ok := byID[match.ID]; ok && point.IsPublished() {
+			results = append(results, &SimilarKnowledgePoint{Point: point, Score: match.Score})
+		}
+	}
+	return results, nil
+}
+
+// FocusAreaScores 计算各知识点与关注领域的语义相似度（取与各领域相似度的最大值）
+func (s *KnowledgeSimilarityService) FocusAreaScores(ctx context.Context, points []*entities.KnowledgePoint, focusAreas []string) (map[uuid.UUID]float64, error) {
+	scores := make(map[uuid.UUID]float64, len(points))
+	if len(focusAreas) == 0 || len(points) == 0 {
+		return scores, nil
+	}
+	index, err := s.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	areas := make([]vector.Vector, 0, len(focusAreas))
+	for _, area := range focusAreas {
+		areas = append(areas, index.Vectorize(area))
+	}
+	for _, point := range points {
+		summary := index.Vectorize(pointSummary(point))
+		for _, area := range areas {
+			if score := vector.Cosine(summary, area); score > scores[point.ID] {
+				scores[point.ID] = score
+			}
+		}
+	}
+	return scores, nil
+}
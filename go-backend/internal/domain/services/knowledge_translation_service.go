@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/locale"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// ErrInvalidLocale 语言标签无效或为原文语言
+var ErrInvalidLocale = errors.New("无效的语言标签")
+
+// KnowledgeTranslationService 知识点多语言服务
+type KnowledgeTranslationService struct {
+	translationRepo       repositories.KnowledgePointTranslationRepository
+	revisionRepo          repositories.KnowledgePointRevisionRepository
+	profileRepo           repositories.UserProfileRepository
+	glossaryRepo          repositories.GlossaryRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewKnowledgeTranslationService 创建知识点多语言服务
+func NewKnowledgeTranslationService(
+	translationRepo repositories.KnowledgePointTranslationRepository,
+	revisionRepo repositories.KnowledgePointRevisionRepository,
+	profileRepo repositories.UserProfileRepository,
+	glossaryRepo repositories.GlossaryRepository,
+	knowledgePointService *KnowledgePointService,
+) *KnowledgeTranslationService {
+	return &KnowledgeTranslationService{
+		translationRepo:       translationRepo,
+		revisionRepo:          revisionRepo,
+		profileRepo:           profileRepo,
+		glossaryRepo:          glossaryRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// TranslationStatusItem 知识点某一语言的翻译状态
+type TranslationStatusItem struct {
+	Locale        string    `json:"locale"`
+	Status        string    `json:"status"`
+	SourceVersion int       `json:"source_version"`
+	Outdated      bool      `json:"outdated"` // 原文在翻译后已有新的修订
+	TranslatorID  *uint     `json:"translator_id"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LocalizedInfo 知识点按语言协商后的结果
+type LocalizedInfo struct {
+	Locale           string   // 实际返回内容的语言
+	AvailableLocales []string // 可用语言（原文语言及已发布译文的语言）
+}
+
+// normalizeLocale 规范化译文语言标签；原文语言不可作为译文语言
+func normalizeLocale(tag string) (string, error) {
+	if !locale.IsValid(tag) {
+		return "", ErrInvalidLocale
+	}
+	tag = locale.Canonicalize(tag)
+	if tag == locale.DefaultLocale {
+		return "", fmt.Errorf("%w: %s为原文语言，请直接编辑知识点", ErrInvalidLocale, tag)
+	}
+	return tag, nil
+}
+
+// SaveTranslation 创建或更新知识点译文，记录其所依据的原文修订版本；需具备知识点的编辑权限
+func (s *KnowledgeTranslationService) SaveTranslation(ctx context.Context, knowledgePointID uuid.UUID, translation *entities.KnowledgePointTranslation, actor *KnowledgePointActor) (*entities.KnowledgePointTranslation, error) {
+	tag, err := normalizeLocale(translation.Locale)
+	if err != nil {
+		return nil, err
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !s.knowledgePointService.CanEdit(point, actor) {
+		return nil, ErrKnowledgePointForbidden
+	}
+	if err := markdown.Validate(translation.Content); err != nil {
+		return nil, err
+	}
+	if translation.Status == "" {
+		translation.Status = string(entities.TranslationStatusDraft)
+	}
+	if translation.Status != string(entities.TranslationStatusDraft) && translation.Status != string(entities.TranslationStatusPublished) {
+		return nil, fmt.Errorf("无效的译文状态: %s", translation.Status)
+	}
+
+	existing, err := s.translationRepo.Get(ctx, knowledgePointID, tag)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		translation.ID = existing.ID
+		translation.CreatedAt = existing.CreatedAt
+	}
+	latest, err := s.revisionRepo.GetLatest(ctx, knowledgePointID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		translation.SourceVersion = latest.Version
+	}
+	translation.KnowledgePointID = knowledgePointID
+	translation.Locale = tag
+	translation.TranslatorID = actor.userID()
+	if err := s.translationRepo.Save(ctx, translation); err != nil {
+		return nil, err
+	}
+
+	logger.Info("知识点译文保存成功",
+		logger.String("knowledge_point_id", knowledgePointID.String()),
+		logger.String("locale", tag))
+	return translation, nil
+}
+
+// GetTranslation 获取知识点指定语言的译文；草稿译文仅具备编辑权限者可见
+func (s *KnowledgeTranslationService) GetTranslation(ctx context.Context, knowledgePointID uuid.UUID, tag string, actor *KnowledgePointActor) (*entities.KnowledgePointTranslation, error) {
+	tag, err := normalizeLocale(tag)
+	if err != nil {
+		return nil, err
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+	translation, err := s.translationRepo.Get(ctx, knowledgePointID, tag)
+	if err != nil {
+		return nil, err
+	}
+	if translation == nil || (!translation.IsPublished() && !s.knowledgePointService.CanEdit(point, actor)) {
+		return nil, fmt.Errorf("知识点译文不存在")
+	}
+	return translation, nil
+}
+
+// ListTranslations 获取知识点各语言的翻译状态；草稿译文仅具备编辑权限者可见
+func (s *KnowledgeTranslationService) ListTranslations(ctx context.Context, knowledgePointID uuid.UUID, actor *KnowledgePointActor) ([]*TranslationStatusItem, error) {
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := s.translationRepo.GetByKnowledgePoint(ctx, knowledgePointID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.revisionRepo.GetLatest(ctx, knowledgePointID)
+	if err != nil {
+		return nil, err
+	}
+
+	canEdit := s.knowledgePointService.CanEdit(point, actor)
+	items := make([]*TranslationStatusItem, 0, len(translations))
+	for _, translation := range translations {
+		if !translation.IsPublished() && !canEdit {
+			continue
+		}
+		items = append(items, &TranslationStatusItem{
+			Locale:        translation.Locale,
+			Status:        translation.Status,
+			SourceVersion: translation.SourceVersion,
+			Outdated:      latest != nil && translation.SourceVersion < latest.Version,
+			TranslatorID:  translation.TranslatorID,
+			UpdatedAt:     translation.UpdatedAt,
+		})
+	}
+	return items, nil
+}
+
+// DeleteTranslation 删除知识点指定语言的译文；需具备知识点的编辑权限
+func (s *KnowledgeTranslationService) DeleteTranslation(ctx context.Context, knowledgePointID uuid.UUID, tag string, actor *KnowledgePointActor) error {
+	tag, err := normalizeLocale(tag)
+	if err != nil {
+		return err
+	}
+	point, err := s.knowledgePointService.GetKnowledgePoint(ctx, knowledgePointID, actor)
+	if err != nil {
+		return err
+	}
+	if !s.knowledgePointService.CanEdit(point, actor) {
+		return ErrKnowledgePointForbidden
+	}
+	return s.translationRepo.Delete(ctx, knowledgePointID, tag)
+}
+
+// PreferredLocales 获取请求的语言偏好，依次为：显式指定的lang参数、用户资料中的语言、Accept-Language请求头
+func (s *KnowledgeTranslationService) PreferredLocales(ctx context.Context, actor *KnowledgePointActor, lang, acceptLanguage string) []string {
+	var preferred []string
+	if lang != "" && locale.IsValid(lang) {
+		preferred = append(preferred, locale.Canonicalize(lang))
+	}
+	if actor != nil {
+		if profile, err := s.profileRepo.GetByUserID(ctx, actor.UserID); err == nil && locale.IsValid(profile.Language) {
+			preferred = append(preferred, locale.Canonicalize(profile.Language))
+		}
+	}
+	return append(preferred, locale.ParseAcceptLanguage(acceptLanguage)...)
+}
+
+// Localize 按语言偏好将已发布的译文覆盖到知识点上，无匹配译文时保留原文；
+// 返回各知识点实际使用的语言与可用语言
+func (s *KnowledgeTranslationService) Localize(ctx context.Context, points []*entities.KnowledgePoint, preferred []string) (map[uuid.UUID]*LocalizedInfo, error) {
+	infos := make(map[uuid.UUID]*LocalizedInfo, len(points))
+	ids := make([]uuid.UUID, 0, len(points))
+	for _, point := range points {
+		infos[point.ID] = &LocalizedInfo{Locale: locale.DefaultLocale, AvailableLocales: []string{locale.DefaultLocale}}
+		ids = append(ids, point.ID)
+	}
+	if len(ids) == 0 {
+		return infos, nil
+	}
+
+	translations, err := s.translationRepo.GetPublishedByKnowledgePoints(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byPoint := make(map[uuid.UUID]map[string]*entities.KnowledgePointTranslation)
+	for _, translation := range translations {
+		if byPoint[translation.KnowledgePointID] == nil {
+			byPoint[translation.KnowledgePointID] = make(map[string]*entities.KnowledgePointTranslation)
+		}
+		byPoint[translation.KnowledgePointID][translation.Locale] = translation
+		info := infos[translation.KnowledgePointID]
+		info.AvailableLocales = append(info.AvailableLocales, translation.Locale)
+	}
+
+	for _, point := range points {
+		info := infos[point.ID]
+		matched, ok := locale.Match(preferred, info.AvailableLocales)
+		if !ok || matched == locale.DefaultLocale {
+			continue
+		}
+		byPoint[point.ID][matched].ApplyTo(point)
+		info.Locale = matched
+	}
+	return infos, nil
+}
+
+// LocalizePoint 按语言偏好本地化单个知识点
+func (s *KnowledgeTranslationService) LocalizePoint(ctx context.Context, point *entities.KnowledgePoint, preferred []string) (*LocalizedInfo, error) {
+	infos, err := s.Localize(ctx, []*entities.KnowledgePoint{point}, preferred)
+	if err != nil {
+		return nil, err
+	}
+	return infos[point.ID], nil
+}
+
+// SearchKnowledgePoints 按语言偏好检索知识点：优先在匹配语言的译文索引中检索，
+// 偏好原文语言、无匹配语言或译文无命中时回退到原文索引，命中结果仍按语言偏好本地化
+func (s *KnowledgeTranslationService) SearchKnowledgePoints(ctx context.Context, query string, preferred []string, offset, limit int) (*KnowledgePointSearchResult, error) {
+	locales, err := s.translationRepo.GetLocales(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matched, ok := locale.Match(preferred, append([]string{locale.DefaultLocale}, locales...))
+	if ok && matched != locale.DefaultLocale {
+		synonyms, err := lookupSynonyms(ctx, s.glossaryRepo, append([]string{query}, strings.Fields(query)...))
+		if err != nil {
+			return nil, err
+		}
+		hits, total, err := s.translationRepo.Search(ctx, matched, query, synonyms, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			return &KnowledgePointSearchResult{Hits: hits, Total: total, Synonyms: synonyms, Locale: matched}, nil
+		}
+	}
+	result, err := s.knowledgePointService.SearchKnowledgePoints(ctx, query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]*entities.KnowledgePoint, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		points = append(points, hit.Point)
+	}
+	if _, err := s.Localize(ctx, points, preferred); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -13,11 +14,21 @@ import (
 	"sical-go-backend/pkg/logger"
 )
 
+// 共享学习路径错误
+var (
+	// ErrSharedPathStatus 公开学习路径由多名学习者共享，学习进度按报名分别记录，不能修改路径本身的状态
+	ErrSharedPathStatus = errors.New("公开学习路径的学习进度按报名记录维护，请通过报名进度接口更新")
+	// ErrSharedPathDelete 公开或仍有报名记录的学习路径不能删除，避免报名记录失去对应的路径
+	ErrSharedPathDelete = errors.New("公开或仍有报名记录的学习路径不能删除，请先由管理员取消公开并待报名全部取消")
+)
+
 // LearningPathService 学习路径服务
 type LearningPathService struct {
-	pathRepo      repositories.LearningPathRepository
-	goalRepo      repositories.LearningGoalRepository
-	knowledgeRepo repositories.KnowledgePointRepository
+	pathRepo          repositories.LearningPathRepository
+	goalRepo          repositories.LearningGoalRepository
+	knowledgeRepo     repositories.KnowledgePointRepository
+	enrollmentRepo    repositories.EnrollmentRepository
+	similarityService *KnowledgeSimilarityService
 }
 
 // NewLearningPathService 创建学习路径服务
@@ -25,11 +36,15 @@ func NewLearningPathService(
 	pathRepo repositories.LearningPathRepository,
 	goalRepo repositories.LearningGoalRepository,
 	knowledgeRepo repositories.KnowledgePointRepository,
+	enrollmentRepo repositories.EnrollmentRepository,
+	similarityService *KnowledgeSimilarityService,
 ) *LearningPathService {
 	return &LearningPathService{
-		pathRepo:      pathRepo,
-		goalRepo:      goalRepo,
-		knowledgeRepo: knowledgeRepo,
+		pathRepo:          pathRepo,
+		goalRepo:          goalRepo,
+		knowledgeRepo:     knowledgeRepo,
+		enrollmentRepo:    enrollmentRepo,
+		similarityService: similarityService,
 	}
 }
 
@@ -152,11 +167,33 @@ func (s *LearningPathService) UpdateLearningPathStatus(ctx context.Context, id u
 		return fmt.Errorf("无效的状态值: %s", status)
 	}
 
+	path, err := s.pathRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if path.IsPublic {
+		return ErrSharedPathStatus
+	}
+
 	return s.pathRepo.UpdateStatus(ctx, id, status)
 }
 
-// DeleteLearningPath 删除学习路径
+// DeleteLearningPath 删除学习路径；公开或仍有报名记录的路径不能删除
 func (s *LearningPathService) DeleteLearningPath(ctx context.Context, id uuid.UUID) error {
+	path, err := s.pathRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if path.IsPublic {
+		return ErrSharedPathDelete
+	}
+	stats, err := s.enrollmentRepo.GetPathStats(ctx, []uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+	if stat := stats[id]; stat != nil && stat.Enrolled > 0 {
+		return ErrSharedPathDelete
+	}
 	return s.pathRepo.Delete(ctx, id)
 }
 
@@ -190,10 +227,19 @@ func (s *LearningPathService) getRelevantKnowledgePoints(ctx context.Context, ca
 		}
 	}
 
-	// 根据关注领域进一步筛选
-	var relevantPoints []*entities.KnowledgePoint
+	// 根据关注领域进一步筛选（字面匹配或语义相似）
+	candidates := make([]*entities.KnowledgePoint, 0, len(pointMap))
 	for _, point := range pointMap {
-		if s.isRelevantToFocusAreas(point, focusAreas) {
+		candidates = append(candidates, point)
+	}
+	scores, err := s.similarityService.FocusAreaScores(ctx, candidates, focusAreas)
+	if err != nil {
+		return nil, err
+	}
+
+	var relevantPoints []*entities.KnowledgePoint
+	for _, point := range candidates {
+		if s.isRelevantToFocusAreas(point, focusAreas) || scores[point.ID] >= focusAreaThreshold {
 			relevantPoints = append(relevantPoints, point)
 		}
 	}
@@ -234,7 +280,7 @@ func (s *LearningPathService) generatePathSteps(points []*entities.KnowledgePoin
 	totalTime := 0
 
 	for i, point := range points {
-		// 估算学习时间（基于难度）
+		// 估算学习时间（基于学习资源时长或难度）
 		estimatedTime := s.estimateStudyTime(point)
 		
 		// 检查时间限制
@@ -316,9 +362,18 @@ func (s *LearningPathService) calculateDependencyScore(point *entities.Knowledge
 	return score
 }
 
-// estimateStudyTime 估算学习时间
+// estimateStudyTime 估算学习时间(小时)
 func (s *LearningPathService) estimateStudyTime(point *entities.KnowledgePoint) int {
-	// 基于难度的基础时间估算
+	return estimatePointHours(point)
+}
+
+// estimatePointHours 估算知识点的学习时间(小时)，优先使用学习资源的预计时长，向上取整
+func estimatePointHours(point *entities.KnowledgePoint) int {
+	if minutes := point.ResourceMinutes(); minutes > 0 {
+		return (minutes + 59) / 60
+	}
+
+	// 资源未标注时长时，基于难度的基础时间估算
 	baseTime := map[string]int{
 		"beginner":     2, // 2小时
 		"intermediate": 4, // 4小时
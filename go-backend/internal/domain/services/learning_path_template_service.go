@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// 模板限制
+const (
+	maxTemplateTitleLength  = 255 // 标题的最大长度（字符）
+	maxTemplateSteps        = 50  // 模板的最大步骤数
+	maxTemplateStepPoints   = 50  // 每个步骤的最大知识点数
+	maxTemplateStepDuration = 500 // 每个步骤的最大预估学习时间(小时)
+)
+
+// 学习路径模板错误
+var (
+	ErrInvalidLearningPathTemplate   = errors.New("学习路径模板无效")
+	ErrLearningPathTemplateForbidden = errors.New("仅管理员可以管理学习路径模板")
+)
+
+// LearningPathTemplateInput 创建或更新模板的参数
+type LearningPathTemplateInput struct {
+	Title       string
+	Description string
+	Category    string
+	Difficulty  string
+	Status      entities.LearningPathTemplateStatus
+	Steps       []LearningPathTemplateStepInput
+}
+
+// LearningPathTemplateStepInput 模板步骤参数
+type LearningPathTemplateStepInput struct {
+	Title             string
+	Description       string
+	EstimatedDuration int // 预估学习时间(小时)，为0时按知识点估算
+	KnowledgePointIDs []uuid.UUID
+}
+
+// TemplateInstance 模板实例化结果
+type TemplateInstance struct {
+	Paths        []*entities.LearningPath
+	Skipped      []uuid.UUID // 因已掌握而跳过的知识点
+	Unavailable  []uuid.UUID // 已删除或未发布而未能加入的知识点
+	SkippedSteps int         // 全部知识点均被跳过而省略的步骤数
+}
+
+// LearningPathTemplateService 学习路径模板服务；模板由管理员编排，已发布的模板可供任意学习者实例化
+type LearningPathTemplateService struct {
+	templateRepo       repositories.LearningPathTemplateRepository
+	goalRepo           repositories.LearningGoalRepository
+	pathRepo           repositories.LearningPathRepository
+	knowledgeRepo      repositories.KnowledgePointRepository
+	recommendationRepo repositories.KnowledgeRecommendationRepository
+}
+
+// NewLearningPathTemplateService 创建学习路径模板服务
+func NewLearningPathTemplateService(
+	templateRepo repositories.LearningPathTemplateRepository,
+	goalRepo repositories.LearningGoalRepository,
+	pathRepo repositories.LearningPathRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	recommendationRepo repositories.KnowledgeRecommendationRepository,
+) *LearningPathTemplateService {
+	return &LearningPathTemplateService{
+		templateRepo:       templateRepo,
+		goalRepo:           goalRepo,
+		pathRepo:           pathRepo,
+		knowledgeRepo:      knowledgeRepo,
+		recommendationRepo: recommendationRepo,
+	}
+}
+
+// ListTemplates 获取模板目录；非管理员仅能看到已发布的模板
+func (s *LearningPathTemplateService) ListTemplates(ctx context.Context, filter *repositories.LearningPathTemplateFilter, actor *KnowledgePointActor) ([]*entities.LearningPathTemplate, int64, error) {
+	if !actor.IsAdmin() {
+		filter.Status = string(entities.LearningPathTemplateStatusPublished)
+	}
+	return s.templateRepo.List(ctx, filter)
+}
+
+// GetTemplate 获取模板；未发布的模板仅管理员可见
+func (s *LearningPathTemplateService) GetTemplate(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) (*entities.LearningPathTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsPublished() && !actor.IsAdmin() {
+		return nil, fmt.Errorf("学习路径模板不存在")
+	}
+	return template, nil
+}
+
+// CreateTemplate 创建模板
+func (s *LearningPathTemplateService) CreateTemplate(ctx context.Context, input *LearningPathTemplateInput, actor *KnowledgePointActor) (*entities.LearningPathTemplate, error) {
+	if !actor.IsAdmin() {
+		return nil, ErrLearningPathTemplateForbidden
+	}
+	template := &entities.LearningPathTemplate{AuthorID: actor.UserID}
+	if err := s.applyTemplateInput(ctx, template, input); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	logger.Info("学习路径模板创建成功",
+		logger.String("template_id", template.ID.String()),
+		logger.Int("steps", len(template.Steps)))
+	return template, nil
+}
+
+// UpdateTemplate 更新模板并替换全部步骤，已实例化的学习路径不受影响
+func (s *LearningPathTemplateService) UpdateTemplate(ctx context.Context, id uuid.UUID, input *LearningPathTemplateInput, actor *KnowledgePointActor) (*entities.LearningPathTemplate, error) {
+	if !actor.IsAdmin() {
+		return nil, ErrLearningPathTemplateForbidden
+	}
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTemplateInput(ctx, template, input); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// DeleteTemplate 删除模板
+func (s *LearningPathTemplateService) DeleteTemplate(ctx context.Context, id uuid.UUID, actor *KnowledgePointActor) error {
+	if !actor.IsAdmin() {
+		return ErrLearningPathTemplateForbidden
+	}
+	if _, err := s.templateRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Info("学习路径模板删除成功", logger.String("template_id", id.String()))
+	return nil
+}
+
+// Instantiate 为学习目标实例化模板：每个步骤创建一条学习路径，排在目标已有路径之后。
+// skipMastered为true时跳过操作者已掌握的知识点，知识点全部跳过的步骤不再创建
+func (s *LearningPathTemplateService) Instantiate(ctx context.Context, templateID, goalID uuid.UUID, skipMastered bool, actor *KnowledgePointActor) (*TemplateInstance, error) {
+	if actor == nil {
+		return nil, ErrLearningPathTemplateForbidden
+	}
+	template, err := s.GetTemplate(ctx, templateID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsPublished() {
+		return nil, fmt.Errorf("%w: 仅可使用已发布的模板", ErrInvalidLearningPathTemplate)
+	}
+	if _, err := s.goalRepo.GetByID(ctx, goalID); err != nil {
+		return nil, err
+	}
+
+	// 已发布的知识点
+	var allIDs []uuid.UUID
+	for i := range template.Steps {
+		allIDs = append(allIDs, template.Steps[i].KnowledgePointIDs()...)
+	}
+	points, err := s.knowledgeRepo.GetByIDs(ctx, allIDs)
+	if err != nil {
+		return nil, err
+	}
+	published := make(map[uuid.UUID]bool, len(points))
+	for _, point := range points {
+		if point.IsPublished() {
+			published[point.ID] = true
+		}
+	}
+
+	mastered := make(map[uuid.UUID]bool)
+	if skipMastered {
+		userID := actor.UserID
+		states, err := s.recommendationRepo.GetLearnerStates(ctx, &userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range states {
+			if isMastered(state) {
+				mastered[state.KnowledgePointID] = true
+			}
+		}
+	}
+
+	// 新路径排在目标已有路径之后
+	existing, err := s.pathRepo.GetByGoalID(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+	order := 0
+	for _, path := range existing {
+		order = max(order, path.Order)
+	}
+
+	instance := &TemplateInstance{}
+	var pointIDs [][]uuid.UUID
+	for i := range template.Steps {
+		step := &template.Steps[i]
+		stepIDs := step.KnowledgePointIDs()
+		kept := make([]uuid.UUID, 0, len(stepIDs))
+		for _, id := range stepIDs {
+			switch {
+			case !published[id]:
+				instance.Unavailable = append(instance.Unavailable, id)
+			case mastered[id]:
+				instance.Skipped = append(instance.Skipped, id)
+			default:
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == 0 {
+			instance.SkippedSteps++
+			continue
+		}
+
+		order++
+		instance.Paths = append(instance.Paths, &entities.LearningPath{
+			GoalID:            goalID,
+			TemplateID:        &template.ID,
+			Title:             step.Title,
+			Description:       step.Description,
+			Order:             order,
+			EstimatedDuration: scaleDuration(step.EstimatedDuration, len(kept), len(stepIDs)),
+			Status:            "pending",
+		})
+		pointIDs = append(pointIDs, kept)
+	}
+	if len(instance.Paths) == 0 {
+		return nil, fmt.Errorf("%w: 模板中的知识点均已掌握或不可用", ErrInvalidLearningPathTemplate)
+	}
+
+	if err := s.templateRepo.Instantiate(ctx, template.ID, instance.Paths, pointIDs); err != nil {
+		return nil, err
+	}
+
+	logger.Info("学习路径模板实例化成功",
+		logger.String("template_id", template.ID.String()),
+		logger.String("goal_id", goalID.String()),
+		logger.Int("paths", len(instance.Paths)),
+		logger.Int("skipped", len(instance.Skipped)))
+	return instance, nil
+}
+
+// applyTemplateInput 校验并设置模板字段与步骤；步骤中的知识点须已发布且在整个模板中不重复
+func (s *LearningPathTemplateService) applyTemplateInput(ctx context.Context, template *entities.LearningPathTemplate, input *LearningPathTemplateInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return fmt.Errorf("%w: 标题不能为空", ErrInvalidLearningPathTemplate)
+	}
+	if utf8.RuneCountInString(title) > maxTemplateTitleLength {
+		return fmt.Errorf("%w: 标题不能超过%d个字符", ErrInvalidLearningPathTemplate, maxTemplateTitleLength)
+	}
+	status := input.Status
+	if status == "" {
+		status = entities.LearningPathTemplateStatusDraft
+	}
+	if status != entities.LearningPathTemplateStatusDraft && status != entities.LearningPathTemplateStatusPublished {
+		return fmt.Errorf("%w: 不支持的状态 %s", ErrInvalidLearningPathTemplate, status)
+	}
+	if len(input.Steps) == 0 {
+		return fmt.Errorf("%w: 至少需要一个步骤", ErrInvalidLearningPathTemplate)
+	}
+	if len(input.Steps) > maxTemplateSteps {
+		return fmt.Errorf("%w: 步骤不能超过%d个", ErrInvalidLearningPathTemplate, maxTemplateSteps)
+	}
+
+	var allIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for i, step := range input.Steps {
+		if strings.TrimSpace(step.Title) == "" {
+			return fmt.Errorf("%w: 第%d步标题不能为空", ErrInvalidLearningPathTemplate, i+1)
+		}
+		if len(step.KnowledgePointIDs) == 0 {
+			return fmt.Errorf("%w: 第%d步至少需要一个知识点", ErrInvalidLearningPathTemplate, i+1)
+		}
+		if len(step.KnowledgePointIDs) > maxTemplateStepPoints {
+			return fmt.Errorf("%w: 第%d步知识点不能超过%d个", ErrInvalidLearningPathTemplate, i+1, maxTemplateStepPoints)
+		}
+		if step.EstimatedDuration < 0 || step.EstimatedDuration > maxTemplateStepDuration {
+			return fmt.Errorf("%w: 第%d步预估学习时间须在0至%d小时之间", ErrInvalidLearningPathTemplate, i+1, maxTemplateStepDuration)
+		}
+		for _, id := range step.KnowledgePointIDs {
+			if seen[id] {
+				return fmt.Errorf("%w: 知识点 %s 在模板中重复出现", ErrInvalidLearningPathTemplate, id)
+			}
+			seen[id] = true
+			allIDs = append(allIDs, id)
+		}
+	}
+
+	points, err := s.knowledgeRepo.GetByIDs(ctx, allIDs)
+	if err != nil {
+		return err
+	}
+	pointMap := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		pointMap[point.ID] = point
+	}
+	for _, id := range allIDs {
+		point, ok := pointMap[id]
+		if !ok {
+			return fmt.Errorf("%w: 知识点 %s 不存在", ErrInvalidLearningPathTemplate, id)
+		}
+		if !point.IsPublished() {
+			return fmt.Errorf("%w: 知识点《%s》尚未发布", ErrInvalidLearningPathTemplate, point.Title)
+		}
+	}
+
+	steps := make([]entities.LearningPathTemplateStep, 0, len(input.Steps))
+	for i, stepInput := range input.Steps {
+		step := entities.LearningPathTemplateStep{
+			Position:          i,
+			Title:             strings.TrimSpace(stepInput.Title),
+			Description:       strings.TrimSpace(stepInput.Description),
+			EstimatedDuration: stepInput.EstimatedDuration,
+		}
+		if step.EstimatedDuration == 0 {
+			for _, id := range stepInput.KnowledgePointIDs {
+				step.EstimatedDuration += estimatePointHours(pointMap[id])
+			}
+		}
+		step.SetKnowledgePointIDs(stepInput.KnowledgePointIDs)
+		steps = append(steps, step)
+	}
+
+	template.Title = title
+	template.Description = strings.TrimSpace(input.Description)
+	template.Category = strings.TrimSpace(input.Category)
+	template.Difficulty = input.Difficulty
+	template.Status = string(status)
+	template.Steps = steps
+	return nil
+}
+
+// scaleDuration 按保留的知识点比例缩减步骤的预估学习时间，向上取整且至少1小时
+func scaleDuration(duration, kept, total int) int {
+	if total == 0 || kept == total {
+		return duration
+	}
+	return max(1, (duration*kept+total-1)/total)
+}
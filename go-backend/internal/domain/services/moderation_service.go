@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// 举报限制
+const (
+	maxReportDetailsLength = 1000 // 举报说明的最大长度（字符）
+	maxExcerptLength       = 300  // 审核队列中内容摘要的最大长度（字符）
+)
+
+// 审核错误
+var (
+	ErrInvalidReport       = errors.New("举报无效")
+	ErrModerationForbidden = errors.New("无权执行审核操作")
+)
+
+// validReportReasons 支持的举报原因
+var validReportReasons = map[entities.ReportReason]bool{
+	entities.ReportReasonSpam:          true,
+	entities.ReportReasonAbuse:         true,
+	entities.ReportReasonInappropriate: true,
+	entities.ReportReasonInaccurate:    true,
+	entities.ReportReasonOther:         true,
+}
+
+// ReportedContent 被举报内容的快照，供审核人员判断
+type ReportedContent struct {
+	Excerpt          string     `json:"excerpt"`
+	AuthorID         *uint      `json:"author_id"`
+	AuthorName       string     `json:"author_name,omitempty"`
+	AuthorStatus     string     `json:"author_status,omitempty"`
+	KnowledgePointID *uuid.UUID `json:"knowledge_point_id,omitempty"` // 评论所属的知识点
+	Hidden           bool       `json:"hidden"`                       // 已删除、已隐藏或未公开
+	Missing          bool       `json:"missing"`                      // 对象已不存在
+}
+
+// ModerationQueueItem 审核队列项：被举报对象、其待处理举报与内容快照
+type ModerationQueueItem struct {
+	*repositories.ReportedTarget
+	Reasons map[string]int     `json:"reasons"`
+	Reports []*entities.Report `json:"reports"`
+	Content *ReportedContent   `json:"content"`
+}
+
+// ModerationDecision 审核决定；HideContent为true时警告或停用作者的同时隐藏被举报内容
+type ModerationDecision struct {
+	Action      entities.ModerationActionType
+	Note        string
+	HideContent bool
+}
+
+// ModerationService 内容举报与审核服务
+type ModerationService struct {
+	moderationRepo        repositories.ModerationRepository
+	commentRepo           repositories.CommentRepository
+	ratingRepo            repositories.RatingRepository
+	notificationRepo      repositories.NotificationRepository
+	userRepo              repositories.UserRepository
+	txManager             repositories.TransactionManager
+	knowledgePointService *KnowledgePointService
+}
+
+// NewModerationService 创建内容举报与审核服务
+func NewModerationService(
+	moderationRepo repositories.ModerationRepository,
+	commentRepo repositories.CommentRepository,
+	ratingRepo repositories.RatingRepository,
+	notificationRepo repositories.NotificationRepository,
+	userRepo repositories.UserRepository,
+	knowledgePointService *KnowledgePointService,
+	txManager repositories.TransactionManager,
+) *ModerationService {
+	return &ModerationService{
+		moderationRepo:        moderationRepo,
+		commentRepo:           commentRepo,
+		ratingRepo:            ratingRepo,
+		notificationRepo:      notificationRepo,
+		userRepo:              userRepo,
+		txManager:             txManager,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// Report 举报评论、评价或知识点；同一用户对同一对象只能有一条待处理的举报，且不能举报自己的内容
+func (s *ModerationService) Report(ctx context.Context, targetType entities.ReportTargetType, targetID uuid.UUID, reason entities.ReportReason, details string, actor *KnowledgePointActor) (*entities.Report, error) {
+	if actor == nil {
+		return nil, ErrModerationForbidden
+	}
+	if !validReportReasons[reason] {
+		return nil, fmt.Errorf("%w: 不支持的举报原因 %s", ErrInvalidReport, reason)
+	}
+	details = strings.TrimSpace(details)
+	if utf8.RuneCountInString(details) > maxReportDetailsLength {
+		return nil, fmt.Errorf("%w: 举报说明不能超过%d个字符", ErrInvalidReport, maxReportDetailsLength)
+	}
+
+	content, err := s.describeTarget(ctx, targetType, targetID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if content.Missing {
+		return nil, fmt.Errorf("%w: 举报的内容不存在", ErrInvalidReport)
+	}
+	if err := s.ensureReportable(ctx, targetType, content, actor); err != nil {
+		return nil, err
+	}
+	if content.AuthorID != nil && *content.AuthorID == actor.UserID {
+		return nil, fmt.Errorf("%w: 不能举报自己的内容", ErrInvalidReport)
+	}
+
+	reported, err := s.moderationRepo.HasOpenReport(ctx, string(targetType), targetID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if reported {
+		return nil, fmt.Errorf("%w: 已举报该内容，请等待审核人员处理", ErrInvalidReport)
+	}
+
+	report := &entities.Report{
+		TargetType: string(targetType),
+		TargetID:   targetID,
+		ReporterID: actor.UserID,
+		Reason:     string(reason),
+		Details:    details,
+		Status:     string(entities.ReportStatusOpen),
+	}
+	if err := s.moderationRepo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	logger.Info("内容举报成功",
+		logger.String("report_id", report.ID.String()),
+		logger.String("target_type", report.TargetType),
+		logger.String("target_id", targetID.String()),
+		logger.String("reason", report.Reason))
+	return report, nil
+}
+
+// ListMyReports 分页获取当前用户提交的举报及其处理状态
+func (s *ModerationService) ListMyReports(ctx context.Context, offset, limit int, actor *KnowledgePointActor) ([]*entities.Report, int64, error) {
+	if actor == nil {
+		return nil, 0, ErrModerationForbidden
+	}
+	return s.moderationRepo.ListReportsByReporter(ctx, actor.UserID, offset, limit)
+}
+
+// ListQueue 审核人员分页获取审核队列，每项附带待处理举报、举报原因统计与内容快照
+func (s *ModerationService) ListQueue(ctx context.Context, targetType string, offset, limit int, actor *KnowledgePointActor) ([]*ModerationQueueItem, int64, error) {
+	if !actor.IsModerator() {
+		return nil, 0, ErrModerationForbidden
+	}
+	targets, total, err := s.moderationRepo.ListReportedTargets(ctx, targetType, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 按对象类型批量获取待处理举报
+	idsByType := make(map[string][]uuid.UUID)
+	for _, target := range targets {
+		idsByType[target.TargetType] = append(idsByType[target.TargetType], target.TargetID)
+	}
+	reportsByTarget := make(map[string][]*entities.Report)
+	for reportType, ids := range idsByType {
+		reports, err := s.moderationRepo.GetOpenReports(ctx, reportType, ids)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, report := range reports {
+			key := report.TargetType + ":" + report.TargetID.String()
+			reportsByTarget[key] = append(reportsByTarget[key], report)
+		}
+	}
+
+	items := make([]*ModerationQueueItem, 0, len(targets))
+	for _, target := range targets {
+		content, err := s.describeTarget(ctx, entities.ReportTargetType(target.TargetType), target.TargetID, actor)
+		if err != nil {
+			return nil, 0, err
+		}
+		item := &ModerationQueueItem{
+			ReportedTarget: target,
+			Reasons:        make(map[string]int),
+			Reports:        reportsByTarget[target.TargetType+":"+target.TargetID.String()],
+			Content:        content,
+		}
+		for _, report := range item.Reports {
+			item.Reasons[report.Reason]++
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// TakeAction 审核人员处理举报：决定作用于举报对象，并一次性处理该对象的全部待处理举报；
+// 每次决定都记录审核操作。隐藏知识点即归档，仅管理员可执行。
+// 权限在写入前全部检查，隐藏内容、停用作者与审核记录在同一事务中写入
+func (s *ModerationService) TakeAction(ctx context.Context, reportID uuid.UUID, decision *ModerationDecision, actor *KnowledgePointActor) (*entities.ModerationAction, error) {
+	if !actor.IsModerator() {
+		return nil, ErrModerationForbidden
+	}
+	report, err := s.moderationRepo.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if !report.IsOpen() {
+		return nil, fmt.Errorf("%w: 举报已处理", ErrInvalidReport)
+	}
+	targetType := entities.ReportTargetType(report.TargetType)
+	content, err := s.describeTarget(ctx, targetType, report.TargetID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(decision.Note)
+	status := entities.ReportStatusResolved
+	hide := decision.HideContent
+	switch decision.Action {
+	case entities.ModerationActionDismiss:
+		status = entities.ReportStatusDismissed
+		hide = false
+	case entities.ModerationActionHideContent:
+		hide = true
+	case entities.ModerationActionWarnUser, entities.ModerationActionSuspendUser:
+		if content.AuthorID == nil {
+			return nil, fmt.Errorf("%w: 被举报内容没有可处理的作者", ErrInvalidReport)
+		}
+	default:
+		return nil, fmt.Errorf("%w: 不支持的审核操作 %s", ErrInvalidReport, decision.Action)
+	}
+
+	hideTarget := hide && !content.Missing && !content.Hidden
+	if hideTarget && targetType == entities.ReportTargetKnowledgePoint && !actor.IsAdmin() {
+		return nil, fmt.Errorf("%w: 下架知识点需要管理员权限", ErrModerationForbidden)
+	}
+	if decision.Action == entities.ModerationActionSuspendUser {
+		if err := s.ensureSuspendable(ctx, *content.AuthorID, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	action := &entities.ModerationAction{
+		ModeratorID:   actor.UserID,
+		Action:        string(decision.Action),
+		TargetType:    report.TargetType,
+		TargetID:      report.TargetID,
+		SubjectUserID: content.AuthorID,
+		Note:          note,
+		ContentHidden: hide,
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if hideTarget {
+			if err := s.hideContent(ctx, targetType, report.TargetID, note, actor); err != nil {
+				return err
+			}
+		}
+		if decision.Action == entities.ModerationActionSuspendUser {
+			if err := s.userRepo.UpdateStatus(ctx, *content.AuthorID, string(entities.StatusSuspended)); err != nil {
+				return fmt.Errorf("停用账号失败: %w", err)
+			}
+		}
+		return s.moderationRepo.ResolveReports(ctx, action, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 警告通知在审核记录提交后发送，事务回滚时不会误发
+	if decision.Action == entities.ModerationActionWarnUser {
+		s.warnAuthor(ctx, *content.AuthorID, report, note, actor)
+	}
+
+	logger.Info("审核操作完成",
+		logger.String("action", action.Action),
+		logger.String("target_type", action.TargetType),
+		logger.String("target_id", action.TargetID.String()),
+		logger.Int("reports_resolved", action.ReportsResolved),
+		logger.Uint("moderator_id", actor.UserID))
+	return action, nil
+}
+
+// ListActions 审核人员按条件分页获取审核记录
+func (s *ModerationService) ListActions(ctx context.Context, filter *repositories.ModerationActionFilter, actor *KnowledgePointActor) ([]*entities.ModerationAction, int64, error) {
+	if !actor.IsModerator() {
+		return nil, 0, ErrModerationForbidden
+	}
+	return s.moderationRepo.ListActions(ctx, filter)
+}
+
+// describeTarget 获取被举报对象的内容快照；对象不存在或操作者无权查看知识点时返回Missing为true的快照，其他查询错误直接返回
+func (s *ModerationService) describeTarget(ctx context.Context, targetType entities.ReportTargetType, targetID uuid.UUID, actor *KnowledgePointActor) (*ReportedContent, error) {
+	content := &ReportedContent{}
+	switch targetType {
+	case entities.ReportTargetComment:
+		comment, err := s.commentRepo.GetByID(ctx, targetID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			content.Missing = true
+			return content, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		authorID := comment.AuthorID
+		content.AuthorID = &authorID
+		content.KnowledgePointID = &comment.KnowledgePointID
+		content.Excerpt = comment.Body
+		content.Hidden = comment.IsDeleted()
+	case entities.ReportTargetRating:
+		rating, err := s.ratingRepo.GetByID(ctx, targetID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			content.Missing = true
+			return content, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		authorID := rating.UserID
+		content.AuthorID = &authorID
+		content.Excerpt = rating.Review
+		content.Hidden = rating.Hidden
+	case entities.ReportTargetKnowledgePoint:
+		point, err := s.knowledgePointService.GetKnowledgePoint(ctx, targetID, actor)
+		if errors.Is(err, repositories.ErrNotFound) {
+			content.Missing = true
+			return content, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		content.AuthorID = point.AuthorID
+		content.Excerpt = point.Title
+		if point.Description != "" {
+			content.Excerpt += "\n" + point.Description
+		}
+		content.Hidden = !point.IsPublished()
+	default:
+		return nil, fmt.Errorf("%w: 不支持的举报对象类型 %s", ErrInvalidReport, targetType)
+	}
+
+	content.Excerpt = truncateRunes(content.Excerpt, maxExcerptLength)
+	if content.AuthorID != nil {
+		if author, err := s.userRepo.GetByID(ctx, *content.AuthorID); err == nil && author != nil {
+			content.AuthorName = author.Username
+			content.AuthorStatus = author.Status
+		}
+	}
+	return content, nil
+}
+
+// ensureReportable 检查被举报的评论或评价仍公开可见
+func (s *ModerationService) ensureReportable(ctx context.Context, targetType entities.ReportTargetType, content *ReportedContent, actor *KnowledgePointActor) error {
+	switch targetType {
+	case entities.ReportTargetComment:
+		if content.Hidden {
+			return fmt.Errorf("%w: 评论已删除", ErrInvalidReport)
+		}
+		_, err := s.knowledgePointService.GetKnowledgePoint(ctx, *content.KnowledgePointID, actor)
+		return err
+	case entities.ReportTargetRating:
+		if content.Hidden {
+			return fmt.Errorf("%w: 评价已隐藏", ErrInvalidReport)
+		}
+	}
+	return nil
+}
+
+// hideContent 隐藏被举报内容：删除评论、隐藏评价或归档知识点
+func (s *ModerationService) hideContent(ctx context.Context, targetType entities.ReportTargetType, targetID uuid.UUID, note string, actor *KnowledgePointActor) error {
+	now := time.Now()
+	switch targetType {
+	case entities.ReportTargetComment:
+		comment, err := s.commentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		comment.Body = ""
+		comment.DeletedAt = &now
+		comment.DeletedBy = actor.userID()
+		return s.commentRepo.SoftDelete(ctx, comment)
+	case entities.ReportTargetRating:
+		rating, err := s.ratingRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		rating.Hidden = true
+		rating.HiddenBy = actor.userID()
+		rating.HiddenReason = truncateRunes(note, 500)
+		rating.HiddenAt = &now
+		return s.ratingRepo.SetHidden(ctx, rating)
+	default:
+		if note == "" {
+			note = "因举报被审核人员下架"
+		}
+		_, err := s.knowledgePointService.Transition(ctx, targetID, entities.ReviewActionArchive, note, actor)
+		return err
+	}
+}
+
+// warnAuthor 向内容作者发送警告通知；通知失败只记录日志
+func (s *ModerationService) warnAuthor(ctx context.Context, authorID uint, report *entities.Report, note string, actor *KnowledgePointActor) {
+	message := "你发布的内容被举报并经审核确认违反社区规范，请注意言行"
+	if note != "" {
+		message += "：" + note
+	}
+	notification := &entities.Notification{
+		UserID:  authorID,
+		Type:    string(entities.NotificationTypeWarning),
+		ActorID: actor.userID(),
+		Message: truncateRunes(message, 500),
+	}
+	if report.TargetType == string(entities.ReportTargetComment) {
+		notification.CommentID = &report.TargetID
+	} else if report.TargetType == string(entities.ReportTargetKnowledgePoint) {
+		notification.KnowledgePointID = &report.TargetID
+	}
+	if err := s.notificationRepo.CreateBatch(ctx, []*entities.Notification{notification}); err != nil {
+		logger.Error("发送警告通知失败", logger.Uint("user_id", authorID), logger.Err(err))
+	}
+}
+
+// ensureSuspendable 检查操作者可停用内容作者的账号：审核人员不能停用自己，停用审核人员或管理员需要管理员权限。
+// 账号停用后认证中间件按账号状态拒绝其后续请求
+func (s *ModerationService) ensureSuspendable(ctx context.Context, authorID uint, actor *KnowledgePointActor) error {
+	if authorID == actor.UserID {
+		return fmt.Errorf("%w: 不能停用自己的账号", ErrInvalidReport)
+	}
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return fmt.Errorf("获取用户失败: %w", err)
+	}
+	subject := &KnowledgePointActor{UserID: author.ID, Role: author.Role}
+	if subject.IsModerator() && !actor.IsAdmin() {
+		return ErrModerationForbidden
+	}
+	return nil
+}
+
+// truncateRunes 按字符截断文本
+func truncateRunes(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "…"
+}
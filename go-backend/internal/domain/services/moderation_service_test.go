@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+func TestModerationServiceTakeAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     entities.ReportTargetType
+		authorRole entities.UserRole
+		decision   ModerationDecision
+		actor      *KnowledgePointActor
+		resolveErr error
+		wantErr    error
+		wantHidden bool
+		wantStatus entities.UserStatus
+		wantWarned bool
+	}{
+		{name: "隐藏评论", target: entities.ReportTargetComment, decision: ModerationDecision{Action: entities.ModerationActionHideContent}, actor: testModerator, wantHidden: true, wantStatus: entities.StatusActive},
+		{name: "停用作者并隐藏评论", target: entities.ReportTargetComment, decision: ModerationDecision{Action: entities.ModerationActionSuspendUser, HideContent: true}, actor: testModerator, wantHidden: true, wantStatus: entities.StatusSuspended},
+		{name: "警告作者", target: entities.ReportTargetComment, decision: ModerationDecision{Action: entities.ModerationActionWarnUser}, actor: testModerator, wantStatus: entities.StatusActive, wantWarned: true},
+		{name: "管理员下架知识点", target: entities.ReportTargetKnowledgePoint, decision: ModerationDecision{Action: entities.ModerationActionHideContent}, actor: testAdmin, wantHidden: true, wantStatus: entities.StatusActive},
+		{name: "审核人员不能下架知识点", target: entities.ReportTargetKnowledgePoint, decision: ModerationDecision{Action: entities.ModerationActionHideContent}, actor: testModerator, wantErr: ErrModerationForbidden},
+		{name: "停用审核人员需要管理员", target: entities.ReportTargetComment, authorRole: entities.RoleModerator, decision: ModerationDecision{Action: entities.ModerationActionSuspendUser, HideContent: true}, actor: testModerator, wantErr: ErrModerationForbidden},
+		{name: "普通用户不能处理举报", target: entities.ReportTargetComment, decision: ModerationDecision{Action: entities.ModerationActionHideContent}, actor: testOtherUser, wantErr: ErrModerationForbidden},
+		{name: "记录审核操作失败时停用与隐藏一并回滚", target: entities.ReportTargetComment, decision: ModerationDecision{Action: entities.ModerationActionSuspendUser, HideContent: true}, actor: testModerator, resolveErr: errors.New("写入失败"), wantErr: errors.New("写入失败")},
+		{name: "记录审核操作失败时下架回滚且不发送警告", target: entities.ReportTargetKnowledgePoint, decision: ModerationDecision{Action: entities.ModerationActionWarnUser, HideContent: true}, actor: testAdmin, resolveErr: errors.New("写入失败"), wantErr: errors.New("写入失败")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			point := testKnowledgePoint("心肌梗死", entities.KnowledgePointStatusPublished, testAuthor.UserID)
+			f := newKnowledgeFixture(point)
+
+			authorRole := tt.authorRole
+			if authorRole == "" {
+				authorRole = entities.RoleUser
+			}
+			users := &fakeUserRepo{db: f.db, users: map[uint]*entities.User{
+				testAuthor.UserID: {ID: testAuthor.UserID, Username: "author", Role: string(authorRole), Status: string(entities.StatusActive)},
+			}}
+			comment := &entities.Comment{ID: uuid.New(), KnowledgePointID: point.ID, AuthorID: testAuthor.UserID, Body: "广告"}
+			comments := &fakeCommentRepo{db: f.db, comments: map[uuid.UUID]*entities.Comment{comment.ID: comment}}
+
+			targetID := comment.ID
+			if tt.target == entities.ReportTargetKnowledgePoint {
+				targetID = point.ID
+			}
+			report := &entities.Report{ID: uuid.New(), TargetType: string(tt.target), TargetID: targetID, ReporterID: testOtherUser.UserID, Status: string(entities.ReportStatusOpen)}
+			moderation := &fakeModerationRepo{db: f.db, reports: map[uuid.UUID]*entities.Report{report.ID: report}, resolveErr: tt.resolveErr}
+			notifications := &fakeNotificationRepo{}
+
+			service := NewModerationService(moderation, comments, nil, notifications, users, f.service, f.tx)
+			action, err := service.TakeAction(ctx, report.ID, &tt.decision, tt.actor)
+
+			hidden := comments.comments[comment.ID].IsDeleted()
+			if tt.target == entities.ReportTargetKnowledgePoint {
+				hidden = !f.points.points[point.ID].IsPublished()
+			}
+			if tt.wantErr != nil {
+				if err == nil || (errors.Is(tt.wantErr, ErrModerationForbidden) && !errors.Is(err, ErrModerationForbidden)) {
+					t.Fatalf("TakeAction() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(f.db.writes) != 0 || hidden || users.users[testAuthor.UserID].Status != string(entities.StatusActive) || len(notifications.notifications) != 0 {
+					t.Fatalf("after failed TakeAction() writes = %v, hidden = %v, author status = %q, notifications = %d",
+						f.db.writes, hidden, users.users[testAuthor.UserID].Status, len(notifications.notifications))
+				}
+				if !report.IsOpen() {
+					t.Fatalf("report status = %q, want open", report.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TakeAction() error = %v", err)
+			}
+			if hidden != tt.wantHidden || action.ContentHidden != tt.wantHidden {
+				t.Fatalf("hidden = %v, action.ContentHidden = %v, want %v", hidden, action.ContentHidden, tt.wantHidden)
+			}
+			if status := users.users[testAuthor.UserID].Status; status != string(tt.wantStatus) {
+				t.Fatalf("author status = %q, want %q", status, tt.wantStatus)
+			}
+			if warned := len(notifications.notifications) == 1; warned != tt.wantWarned {
+				t.Fatalf("notifications = %d, want warned %v", len(notifications.notifications), tt.wantWarned)
+			}
+			if report.IsOpen() || action.ReportsResolved != 1 || len(moderation.actions) != 1 {
+				t.Fatalf("report status = %q, reports resolved = %d, actions = %d", report.Status, action.ReportsResolved, len(moderation.actions))
+			}
+			if len(f.db.outside) != 0 {
+				t.Fatalf("writes outside transaction: %v", f.db.outside)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// NotificationService 站内通知服务
+type NotificationService struct {
+	notificationRepo repositories.NotificationRepository
+}
+
+// NewNotificationService 创建站内通知服务
+func NewNotificationService(notificationRepo repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// ListNotifications 分页获取用户的通知，返回通知、总数与未读数
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]*entities.Notification, int64, int64, error) {
+	notifications, total, err := s.notificationRepo.ListByUser(ctx, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return notifications, total, unread, nil
+}
+
+// CountUnread 获取用户的未读通知数
+func (s *NotificationService) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkRead 将用户的指定通知标记为已读，ids为空时标记全部
+func (s *NotificationService) MarkRead(ctx context.Context, userID uint, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return s.notificationRepo.MarkAllRead(ctx, userID)
+	}
+	return s.notificationRepo.MarkRead(ctx, userID, ids)
+}
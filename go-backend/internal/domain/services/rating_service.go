@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxReviewLength 文字评价的最大长度（字符）
+const maxReviewLength = 2000
+
+// 评分错误
+var (
+	ErrInvalidRating   = errors.New("评分无效")
+	ErrRatingForbidden = errors.New("无权管理该评价")
+)
+
+// RatingService 评分与评价服务
+type RatingService struct {
+	ratingRepo            repositories.RatingRepository
+	pathRepo              repositories.LearningPathRepository
+	knowledgePointService *KnowledgePointService
+}
+
+// NewRatingService 创建评分与评价服务
+func NewRatingService(
+	ratingRepo repositories.RatingRepository,
+	pathRepo repositories.LearningPathRepository,
+	knowledgePointService *KnowledgePointService,
+) *RatingService {
+	return &RatingService{
+		ratingRepo:            ratingRepo,
+		pathRepo:              pathRepo,
+		knowledgePointService: knowledgePointService,
+	}
+}
+
+// ensureTarget 检查评分对象存在且对操作者可见；forRating为true时要求知识点已发布
+func (s *RatingService) ensureTarget(ctx context.Context, targetType entities.RatingTargetType, targetID uuid.UUID, actor *KnowledgePointActor, forRating bool) error {
+	switch targetType {
+	case entities.RatingTargetKnowledgePoint:
+		point, err := s.knowledgePointService.GetKnowledgePoint(ctx, targetID, actor)
+		if err != nil {
+			return err
+		}
+		if forRating && !point.IsPublished() {
+			return fmt.Errorf("%w: 仅可评价已发布的知识点", ErrInvalidRating)
+		}
+		return nil
+	case entities.RatingTargetLearningPath:
+		_, err := s.pathRepo.GetByID(ctx, targetID)
+		return err
+	default:
+		return fmt.Errorf("%w: 不支持的评分对象类型 %s", ErrInvalidRating, targetType)
+	}
+}
+
+// Rate 创建或更新当前用户对对象的评分与文字评价；已被隐藏的评价修改后仍保持隐藏
+func (s *RatingService) Rate(ctx context.Context, targetType entities.RatingTargetType, targetID uuid.UUID, score int, review string, actor *KnowledgePointActor) (*entities.Rating, error) {
+	if actor == nil {
+		return nil, ErrRatingForbidden
+	}
+	if score < entities.MinRatingScore || score > entities.MaxRatingScore {
+		return nil, fmt.Errorf("%w: 评分应为%d到%d之间的整数", ErrInvalidRating, entities.MinRatingScore, entities.MaxRatingScore)
+	}
+	review = strings.TrimSpace(review)
+	if utf8.RuneCountInString(review) > maxReviewLength {
+		return nil, fmt.Errorf("%w: 文字评价不能超过%d个字符", ErrInvalidRating, maxReviewLength)
+	}
+	if err := s.ensureTarget(ctx, targetType, targetID, actor, true); err != nil {
+		return nil, err
+	}
+
+	rating, err := s.ratingRepo.GetByUser(ctx, string(targetType), targetID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if rating == nil {
+		rating = &entities.Rating{
+			TargetType: string(targetType),
+			TargetID:   targetID,
+			UserID:     actor.UserID,
+		}
+	}
+	rating.Score = score
+	rating.Review = review
+	if err := s.ratingRepo.Save(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	logger.Info("评分保存成功",
+		logger.String("target_type", rating.TargetType),
+		logger.String("target_id", targetID.String()),
+		logger.Int("score", score))
+	return rating, nil
+}
+
+// GetMyRating 获取当前用户对对象的评分，未评分时返回nil
+func (s *RatingService) GetMyRating(ctx context.Context, targetType entities.RatingTargetType, targetID uuid.UUID, actor *KnowledgePointActor) (*entities.Rating, error) {
+	if actor == nil {
+		return nil, ErrRatingForbidden
+	}
+	if err := s.ensureTarget(ctx, targetType, targetID, actor, false); err != nil {
+		return nil, err
+	}
+	return s.ratingRepo.GetByUser(ctx, string(targetType), targetID, actor.UserID)
+}
+
+// DeleteMyRating 删除当前用户对对象的评分
+func (s *RatingService) DeleteMyRating(ctx context.Context, targetType entities.RatingTargetType, targetID uuid.UUID, actor *KnowledgePointActor) error {
+	if actor == nil {
+		return ErrRatingForbidden
+	}
+	rating, err := s.ratingRepo.GetByUser(ctx, string(targetType), targetID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if rating == nil {
+		return fmt.Errorf("评分不存在")
+	}
+	return s.ratingRepo.Delete(ctx, rating)
+}
+
+// ListReviews 获取对象的文字评价与评分汇总；隐藏的评价仅审核人员可通过includeHidden查看
+func (s *RatingService) ListReviews(ctx context.Context, targetType entities.RatingTargetType, targetID uuid.UUID, includeHidden bool, offset, limit int, actor *KnowledgePointActor) ([]*entities.Rating, int64, *repositories.RatingSummary, error) {
+	if err := s.ensureTarget(ctx, targetType, targetID, actor, false); err != nil {
+		return nil, 0, nil, err
+	}
+
+	filter := &repositories.RatingFilter{
+		TargetType: string(targetType),
+		TargetID:   &targetID,
+		WithReview: true,
+		Offset:     offset,
+		Limit:      limit,
+	}
+	if !includeHidden || !actor.IsModerator() {
+		visible := false
+		filter.Hidden = &visible
+	}
+	reviews, total, err := s.ratingRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	summary, err := s.ratingRepo.GetSummary(ctx, string(targetType), targetID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return reviews, total, summary, nil
+}
+
+// ListForModeration 审核人员按条件获取全部对象的文字评价
+func (s *RatingService) ListForModeration(ctx context.Context, filter *repositories.RatingFilter, actor *KnowledgePointActor) ([]*entities.Rating, int64, error) {
+	if !actor.IsModerator() {
+		return nil, 0, ErrRatingForbidden
+	}
+	filter.WithReview = true
+	return s.ratingRepo.List(ctx, filter)
+}
+
+// SetReviewHidden 审核人员隐藏或恢复评价；隐藏的评价不公开展示，也不计入平均评分
+func (s *RatingService) SetReviewHidden(ctx context.Context, id uuid.UUID, hidden bool, reason string, actor *KnowledgePointActor) (*entities.Rating, error) {
+	if !actor.IsModerator() {
+		return nil, ErrRatingForbidden
+	}
+	rating, err := s.ratingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rating.Hidden = hidden
+	if hidden {
+		now := time.Now()
+		rating.HiddenBy = actor.userID()
+		rating.HiddenReason = strings.TrimSpace(reason)
+		rating.HiddenAt = &now
+	} else {
+		rating.HiddenBy = nil
+		rating.HiddenReason = ""
+		rating.HiddenAt = nil
+	}
+	if err := s.ratingRepo.SetHidden(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	logger.Info("评价状态已更新",
+		logger.String("rating_id", id.String()),
+		logger.Bool("hidden", hidden),
+		logger.Uint("moderator_id", actor.UserID))
+	return rating, nil
+}
@@ -277,6 +277,11 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*T
 		return nil, apperrors.ErrUnauthorized.WithCause(err)
 	}
 
+	// 检查用户状态，停用的账号不能续期
+	if user.Status != string(entities.StatusActive) {
+		return nil, apperrors.ErrForbidden.WithDetail("reason", "Account is not active")
+	}
+
 	// 生成新的令牌对
 	tokenPair, err := s.jwtManager.GenerateTokenPair(user.ID, user.Username, user.Email, user.Role)
 	if err != nil {
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// annotationRepositoryImpl 笔记与高亮仓储实现
+type annotationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAnnotationRepository 创建笔记与高亮仓储
+func NewAnnotationRepository(db *gorm.DB) repositories.AnnotationRepository {
+	return &annotationRepositoryImpl{db: db}
+}
+
+// Create 创建批注
+func (r *annotationRepositoryImpl) Create(ctx context.Context, annotation *entities.Annotation) error {
+	if err := dbWithContext(ctx, r.db).Create(annotation).Error; err != nil {
+		return fmt.Errorf("创建笔记失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取批注
+func (r *annotationRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Annotation, error) {
+	var annotation entities.Annotation
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&annotation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("笔记不存在")
+		}
+		return nil, fmt.Errorf("获取笔记失败: %w", err)
+	}
+	return &annotation, nil
+}
+
+// Update 更新批注的笔记正文与颜色
+func (r *annotationRepositoryImpl) Update(ctx context.Context, annotation *entities.Annotation) error {
+	if err := dbWithContext(ctx, r.db).Model(annotation).Select("body", "color").Updates(annotation).Error; err != nil {
+		return fmt.Errorf("更新笔记失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除批注
+func (r *annotationRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.Annotation{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除笔记失败: %w", err)
+	}
+	return nil
+}
+
+// List 按条件获取用户的批注
+func (r *annotationRepositoryImpl) List(ctx context.Context, filter *repositories.AnnotationFilter) ([]*entities.Annotation, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.Annotation{}).Where("user_id = ?", filter.UserID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.KnowledgePointID != nil {
+		query = query.Where("knowledge_point_id = ?", *filter.KnowledgePointID)
+	}
+	if keyword := strings.TrimSpace(filter.Query); keyword != "" {
+		pattern := "%" + escapeLikePattern(keyword) + "%"
+		query = query.Where("(body ILIKE ? OR exact ILIKE ?)", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取笔记失败: %w", err)
+	}
+	listQuery := query.Session(&gorm.Session{}).Order("updated_at DESC")
+	if filter.Limit > 0 {
+		listQuery = listQuery.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	var annotations []*entities.Annotation
+	if err := listQuery.Find(&annotations).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取笔记失败: %w", err)
+	}
+	return annotations, total, nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// attachmentRepositoryImpl 附件仓储实现
+type attachmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository 创建附件仓储实例
+func NewAttachmentRepository(db *gorm.DB) repositories.AttachmentRepository {
+	return &attachmentRepositoryImpl{db: db}
+}
+
+// Create 创建附件记录
+func (r *attachmentRepositoryImpl) Create(ctx context.Context, attachment *entities.Attachment) error {
+	if err := dbWithContext(ctx, r.db).Create(attachment).Error; err != nil {
+		return fmt.Errorf("创建附件失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取附件
+func (r *attachmentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Attachment, error) {
+	var attachment entities.Attachment
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&attachment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("附件不存在")
+		}
+		return nil, fmt.Errorf("获取附件失败: %w", err)
+	}
+	return &attachment, nil
+}
+
+// GetByIDs 根据ID批量获取附件
+func (r *attachmentRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Attachment, error) {
+	var attachments []*entities.Attachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("id IN ?", ids).Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("获取附件失败: %w", err)
+	}
+	return attachments, nil
+}
+
+// Delete 删除附件记录及其引用
+func (r *attachmentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attachment_id = ?", id).Delete(&entities.AttachmentReference{}).Error; err != nil {
+			return fmt.Errorf("删除附件引用失败: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&entities.Attachment{}).Error; err != nil {
+			return fmt.Errorf("删除附件失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// AddReferences 添加引用
+func (r *attachmentRepositoryImpl) AddReferences(ctx context.Context, refType entities.AttachmentRefType, refID string, attachmentIDs []uuid.UUID) error {
+	return r.addReferences(dbWithContext(ctx, r.db), refType, refID, attachmentIDs)
+}
+
+// ReplaceReferences 将引用方的引用替换为给定附件
+func (r *attachmentRepositoryImpl) ReplaceReferences(ctx context.Context, refType entities.AttachmentRefType, refID string, attachmentIDs []uuid.UUID) error {
+	return dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("ref_type = ? AND ref_id = ?", string(refType), refID)
+		if len(attachmentIDs) > 0 {
+			query = query.Where("attachment_id NOT IN ?", attachmentIDs)
+		}
+		if err := query.Delete(&entities.AttachmentReference{}).Error; err != nil {
+			return fmt.Errorf("删除附件引用失败: %w", err)
+		}
+		return r.addReferences(tx, refType, refID, attachmentIDs)
+	})
+}
+
+// addReferences 插入引用记录，仅保留存在的附件
+func (r *attachmentRepositoryImpl) addReferences(db *gorm.DB, refType entities.AttachmentRefType, refID string, attachmentIDs []uuid.UUID) error {
+	if len(attachmentIDs) == 0 {
+		return nil
+	}
+	var existing []uuid.UUID
+	if err := db.Model(&entities.Attachment{}).Where("id IN ?", attachmentIDs).Pluck("id", &existing).Error; err != nil {
+		return fmt.Errorf("获取附件失败: %w", err)
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	refs := make([]entities.AttachmentReference, 0, len(existing))
+	for _, id := range existing {
+		refs = append(refs, entities.AttachmentReference{
+			ID:           uuid.New(),
+			AttachmentID: id,
+			RefType:      string(refType),
+			RefID:        refID,
+		})
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&refs).Error; err != nil {
+		return fmt.Errorf("添加附件引用失败: %w", err)
+	}
+	return nil
+}
+
+// GetOrphans 获取在指定时间前创建且无任何引用的附件
+func (r *attachmentRepositoryImpl) GetOrphans(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.Attachment, error) {
+	var attachments []*entities.Attachment
+	err := dbWithContext(ctx, r.db).
+		Where("created_at < ?", createdBefore).
+		Where("NOT EXISTS (SELECT 1 FROM attachment_references ar WHERE ar.attachment_id = attachments.id)").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&attachments).Error
+	if err != nil {
+		return nil, fmt.Errorf("获取未引用附件失败: %w", err)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// collectionRepositoryImpl 收藏夹仓储实现
+type collectionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCollectionRepository 创建收藏夹仓储
+func NewCollectionRepository(db *gorm.DB) repositories.CollectionRepository {
+	return &collectionRepositoryImpl{db: db}
+}
+
+// Create 创建收藏夹
+func (r *collectionRepositoryImpl) Create(ctx context.Context, collection *entities.Collection) error {
+	if err := dbWithContext(ctx, r.db).Create(collection).Error; err != nil {
+		return fmt.Errorf("创建收藏夹失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取收藏夹
+func (r *collectionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Collection, error) {
+	var collection entities.Collection
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("收藏夹不存在")
+		}
+		return nil, fmt.Errorf("获取收藏夹失败: %w", err)
+	}
+	return &collection, nil
+}
+
+// GetByShareToken 根据分享令牌获取收藏夹
+func (r *collectionRepositoryImpl) GetByShareToken(ctx context.Context, token string) (*entities.Collection, error) {
+	var collection entities.Collection
+	if err := dbWithContext(ctx, r.db).Where("share_token = ?", token).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("收藏夹不存在")
+		}
+		return nil, fmt.Errorf("获取收藏夹失败: %w", err)
+	}
+	return &collection, nil
+}
+
+// ListByUser 获取用户的全部收藏夹，最近更新的在前
+func (r *collectionRepositoryImpl) ListByUser(ctx context.Context, userID uint) ([]*entities.Collection, error) {
+	var collections []*entities.Collection
+	if err := dbWithContext(ctx, r.db).Where("user_id = ?", userID).Order("updated_at DESC").Find(&collections).Error; err != nil {
+		return nil, fmt.Errorf("获取收藏夹失败: %w", err)
+	}
+	return collections, nil
+}
+
+// CountItems 批量统计收藏夹的条目数
+func (r *collectionRepositoryImpl) CountItems(ctx context.Context, collectionIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(collectionIDs))
+	if len(collectionIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		CollectionID uuid.UUID
+		Count        int64
+	}
+	if err := dbWithContext(ctx, r.db).Model(&entities.CollectionItem{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", collectionIDs).
+		Group("collection_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计收藏夹条目失败: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+// Update 更新收藏夹名称与描述
+func (r *collectionRepositoryImpl) Update(ctx context.Context, collection *entities.Collection) error {
+	if err := dbWithContext(ctx, r.db).Model(collection).Select("name", "description").Updates(collection).Error; err != nil {
+		return fmt.Errorf("更新收藏夹失败: %w", err)
+	}
+	return nil
+}
+
+// SetShareToken 设置或清除分享令牌
+func (r *collectionRepositoryImpl) SetShareToken(ctx context.Context, id uuid.UUID, token *string) error {
+	if err := dbWithContext(ctx, r.db).Model(&entities.Collection{}).Where("id = ?", id).Update("share_token", token).Error; err != nil {
+		return fmt.Errorf("更新收藏夹分享状态失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除收藏夹及其条目
+func (r *collectionRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entities.CollectionItem{}, "collection_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Collection{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("删除收藏夹失败: %w", err)
+	}
+	return nil
+}
+
+// GetItems 按排列顺序获取收藏夹条目
+func (r *collectionRepositoryImpl) GetItems(ctx context.Context, collectionID uuid.UUID) ([]*entities.CollectionItem, error) {
+	var items []*entities.CollectionItem
+	if err := dbWithContext(ctx, r.db).Where("collection_id = ?", collectionID).
+		Order("position ASC, created_at ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("获取收藏夹条目失败: %w", err)
+	}
+	return items, nil
+}
+
+// GetItem 获取收藏夹中的条目
+func (r *collectionRepositoryImpl) GetItem(ctx context.Context, collectionID, itemID uuid.UUID) (*entities.CollectionItem, error) {
+	var item entities.CollectionItem
+	if err := dbWithContext(ctx, r.db).Where("id = ? AND collection_id = ?", itemID, collectionID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("收藏条目不存在")
+		}
+		return nil, fmt.Errorf("获取收藏条目失败: %w", err)
+	}
+	return &item, nil
+}
+
+// AddItem 将条目追加到收藏夹末尾；锁定收藏夹行以保证并发追加时顺序号不重复
+func (r *collectionRepositoryImpl) AddItem(ctx context.Context, item *entities.CollectionItem) (*entities.CollectionItem, error) {
+	result := item
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var collection entities.Collection
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", item.CollectionID).First(&collection).Error; err != nil {
+			return err
+		}
+
+		var existing entities.CollectionItem
+		err := tx.Where("collection_id = ? AND target_type = ? AND target_id = ?", item.CollectionID, item.TargetType, item.TargetID).
+			First(&existing).Error
+		if err == nil {
+			result = &existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var position int
+		if err := tx.Model(&entities.CollectionItem{}).
+			Select("COALESCE(MAX(position), -1) + 1").
+			Where("collection_id = ?", item.CollectionID).
+			Scan(&position).Error; err != nil {
+			return err
+		}
+		item.Position = position
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return tx.Model(&collection).Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("添加收藏失败: %w", err)
+	}
+	return result, nil
+}
+
+// RemoveItem 从收藏夹移除条目
+func (r *collectionRepositoryImpl) RemoveItem(ctx context.Context, collectionID, itemID uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.CollectionItem{}, "id = ? AND collection_id = ?", itemID, collectionID).Error; err != nil {
+		return fmt.Errorf("移除收藏失败: %w", err)
+	}
+	return nil
+}
+
+// ReorderItems 按给定的条目ID顺序重排收藏夹
+func (r *collectionRepositoryImpl) ReorderItems(ctx context.Context, collectionID uuid.UUID, itemIDs []uuid.UUID) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for position, itemID := range itemIDs {
+			if err := tx.Model(&entities.CollectionItem{}).
+				Where("id = ? AND collection_id = ?", itemID, collectionID).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entities.Collection{}).Where("id = ?", collectionID).Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+	if err != nil {
+		return fmt.Errorf("调整收藏顺序失败: %w", err)
+	}
+	return nil
+}
+
+// ListContaining 获取用户收藏了指定对象的收藏夹ID
+func (r *collectionRepositoryImpl) ListContaining(ctx context.Context, userID uint, targetType string, targetID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := dbWithContext(ctx, r.db).Model(&entities.CollectionItem{}).
+		Joins("JOIN collections ON collections.id = collection_items.collection_id").
+		Where("collections.user_id = ? AND collection_items.target_type = ? AND collection_items.target_id = ?", userID, targetType, targetID).
+		Pluck("collection_items.collection_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("获取收藏状态失败: %w", err)
+	}
+	return ids, nil
+}
+
+// Clone 在同一事务中创建收藏夹并按原顺序复制条目
+func (r *collectionRepositoryImpl) Clone(ctx context.Context, collection *entities.Collection, items []*entities.CollectionItem) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(collection).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		copies := make([]*entities.CollectionItem, 0, len(items))
+		for position, item := range items {
+			copies = append(copies, &entities.CollectionItem{
+				CollectionID: collection.ID,
+				TargetType:   item.TargetType,
+				TargetID:     item.TargetID,
+				Position:     position,
+			})
+		}
+		return tx.CreateInBatches(copies, 100).Error
+	})
+	if err != nil {
+		return fmt.Errorf("复制收藏夹失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// commentRepositoryImpl 知识点评论仓储实现
+type commentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository 创建知识点评论仓储
+func NewCommentRepository(db *gorm.DB) repositories.CommentRepository {
+	return &commentRepositoryImpl{db: db}
+}
+
+// Create 创建评论；为回复时增加父评论的回复数
+func (r *commentRepositoryImpl) Create(ctx context.Context, comment *entities.Comment) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID == nil {
+			return nil
+		}
+		return tx.Model(&entities.Comment{}).
+			Where("id = ?", *comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	})
+	if err != nil {
+		return fmt.Errorf("创建评论失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取评论
+func (r *commentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
+	var comment entities.Comment
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.NotFound("评论不存在")
+		}
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+	return &comment, nil
+}
+
+// Update 更新评论正文
+func (r *commentRepositoryImpl) Update(ctx context.Context, comment *entities.Comment) error {
+	if err := dbWithContext(ctx, r.db).Model(&entities.Comment{}).Where("id = ?", comment.ID).Updates(map[string]interface{}{
+		"body":      comment.Body,
+		"edited_at": comment.EditedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("更新评论失败: %w", err)
+	}
+	return nil
+}
+
+// SoftDelete 软删除评论
+func (r *commentRepositoryImpl) SoftDelete(ctx context.Context, comment *entities.Comment) error {
+	if err := dbWithContext(ctx, r.db).Model(&entities.Comment{}).Where("id = ?", comment.ID).Updates(map[string]interface{}{
+		"body":       "",
+		"deleted_at": comment.DeletedAt,
+		"deleted_by": comment.DeletedBy,
+	}).Error; err != nil {
+		return fmt.Errorf("删除评论失败: %w", err)
+	}
+	return nil
+}
+
+// ListThreads 分页获取知识点的顶层评论
+func (r *commentRepositoryImpl) ListThreads(ctx context.Context, knowledgePointID uuid.UUID, offset, limit int) ([]*entities.Comment, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.Comment{}).
+		Where("knowledge_point_id = ? AND parent_id IS NULL", knowledgePointID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取评论失败: %w", err)
+	}
+	var comments []*entities.Comment
+	if err := query.Session(&gorm.Session{}).
+		Order("created_at DESC, id").
+		Offset(offset).Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取评论失败: %w", err)
+	}
+	return comments, total, nil
+}
+
+// GetByThreads 获取讨论串中的全部回复
+func (r *commentRepositoryImpl) GetByThreads(ctx context.Context, threadIDs []uuid.UUID) ([]*entities.Comment, error) {
+	var comments []*entities.Comment
+	if len(threadIDs) == 0 {
+		return comments, nil
+	}
+	if err := dbWithContext(ctx, r.db).
+		Where("thread_id IN ?", threadIDs).
+		Order("created_at, id").
+		Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("获取评论回复失败: %w", err)
+	}
+	return comments, nil
+}
+
+// ToggleLike 切换用户对评论的点赞并同步点赞数
+func (r *commentRepositoryImpl) ToggleLike(ctx context.Context, commentID uuid.UUID, userID uint) (bool, int, error) {
+	var liked bool
+	var count int
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		like := &entities.CommentLike{CommentID: commentID, UserID: userID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(like)
+		if result.Error != nil {
+			return result.Error
+		}
+		delta := 1
+		liked = true
+		if result.RowsAffected == 0 {
+			// 已点赞则取消
+			if err := tx.Delete(&entities.CommentLike{}, "comment_id = ? AND user_id = ?", commentID, userID).Error; err != nil {
+				return err
+			}
+			delta = -1
+			liked = false
+		}
+		if err := tx.Model(&entities.Comment{}).
+			Where("id = ?", commentID).
+			UpdateColumn("likes_count", gorm.Expr("GREATEST(likes_count + ?, 0)", delta)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.Comment{}).Select("likes_count").Where("id = ?", commentID).Scan(&count).Error
+	})
+	if err != nil {
+		return false, 0, fmt.Errorf("更新评论点赞失败: %w", err)
+	}
+	return liked, count, nil
+}
+
+// GetLikedIDs 获取指定评论中用户已点赞的评论ID
+func (r *commentRepositoryImpl) GetLikedIDs(ctx context.Context, userID uint, commentIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	liked := make(map[uuid.UUID]bool)
+	if len(commentIDs) == 0 {
+		return liked, nil
+	}
+	var ids []uuid.UUID
+	if err := dbWithContext(ctx, r.db).Model(&entities.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ?", userID, commentIDs).
+		Pluck("comment_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("获取评论点赞失败: %w", err)
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
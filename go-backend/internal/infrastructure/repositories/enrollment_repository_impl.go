@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// enrollmentRepositoryImpl 学习路径报名仓储实现
+type enrollmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewEnrollmentRepository 创建学习路径报名仓储
+func NewEnrollmentRepository(db *gorm.DB) repositories.EnrollmentRepository {
+	return &enrollmentRepositoryImpl{db: db}
+}
+
+// Create 在同一事务中创建报名记录及其步骤
+func (r *enrollmentRepositoryImpl) Create(ctx context.Context, enrollment *entities.PathEnrollment, steps []*entities.PathEnrollmentStep) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(enrollment).Error; err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+		for _, step := range steps {
+			step.EnrollmentID = enrollment.ID
+		}
+		return tx.CreateInBatches(steps, 100).Error
+	})
+	if err != nil {
+		return fmt.Errorf("报名学习路径失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取用户对学习路径的报名记录
+func (r *enrollmentRepositoryImpl) Get(ctx context.Context, userID uint, pathID uuid.UUID) (*entities.PathEnrollment, error) {
+	var enrollment entities.PathEnrollment
+	if err := dbWithContext(ctx, r.db).Where("user_id = ? AND learning_path_id = ?", userID, pathID).First(&enrollment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.NotFound("尚未报名该学习路径")
+		}
+		return nil, fmt.Errorf("获取报名记录失败: %w", err)
+	}
+	return &enrollment, nil
+}
+
+// ListByUser 获取用户的报名记录，最近学习的在前
+func (r *enrollmentRepositoryImpl) ListByUser(ctx context.Context, userID uint, status string) ([]*entities.PathEnrollment, error) {
+	query := dbWithContext(ctx, r.db).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var enrollments []*entities.PathEnrollment
+	if err := query.Order("COALESCE(last_activity_at, created_at) DESC").Find(&enrollments).Error; err != nil {
+		return nil, fmt.Errorf("获取报名记录失败: %w", err)
+	}
+	return enrollments, nil
+}
+
+// Delete 删除报名记录及其步骤进度
+func (r *enrollmentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entities.PathEnrollmentStep{}, "enrollment_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.PathEnrollment{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("取消报名失败: %w", err)
+	}
+	return nil
+}
+
+// GetSteps 按顺序获取报名记录的步骤进度
+func (r *enrollmentRepositoryImpl) GetSteps(ctx context.Context, enrollmentID uuid.UUID) ([]*entities.PathEnrollmentStep, error) {
+	var steps []*entities.PathEnrollmentStep
+	if err := dbWithContext(ctx, r.db).Where("enrollment_id = ?", enrollmentID).Order("position ASC").Find(&steps).Error; err != nil {
+		return nil, fmt.Errorf("获取学习进度失败: %w", err)
+	}
+	return steps, nil
+}
+
+// AddSteps 追加路径中新增的步骤
+func (r *enrollmentRepositoryImpl) AddSteps(ctx context.Context, enrollmentID uuid.UUID, steps []*entities.PathEnrollmentStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, step := range steps {
+			step.EnrollmentID = enrollmentID
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(steps, 100)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&entities.PathEnrollment{}).
+			Where("id = ? AND status = ?", enrollmentID, string(entities.EnrollmentStatusCompleted)).
+			Updates(map[string]interface{}{
+				"status":       string(entities.EnrollmentStatusActive),
+				"completed_at": nil,
+			}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("同步学习步骤失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateStep 保存步骤进度并更新报名记录；学习时长以增量累计，避免并发上报相互覆盖
+func (r *enrollmentRepositoryImpl) UpdateStep(ctx context.Context, step *entities.PathEnrollmentStep, addSeconds int) (*entities.PathEnrollment, error) {
+	var enrollment entities.PathEnrollment
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&entities.PathEnrollmentStep{}).
+			Where("id = ?", step.ID).
+			Updates(map[string]interface{}{
+				"status":       step.Status,
+				"started_at":   step.StartedAt,
+				"completed_at": step.CompletedAt,
+				"time_spent":   gorm.Expr("time_spent + ?", addSeconds),
+				"updated_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&entities.PathEnrollmentStep{}).
+			Where("enrollment_id = ? AND status <> ?", step.EnrollmentID, string(entities.EnrollmentStepCompleted)).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{
+			"started_at":       gorm.Expr("COALESCE(started_at, ?)", now),
+			"last_activity_at": now,
+			"time_spent":       gorm.Expr("time_spent + ?", addSeconds),
+			"status":           string(entities.EnrollmentStatusActive),
+			"completed_at":     nil,
+		}
+		if remaining == 0 {
+			updates["status"] = string(entities.EnrollmentStatusCompleted)
+			updates["completed_at"] = gorm.Expr("COALESCE(completed_at, ?)", now)
+		}
+		if err := tx.Model(&entities.PathEnrollment{}).Where("id = ?", step.EnrollmentID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", step.EnrollmentID).First(&enrollment).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("更新学习进度失败: %w", err)
+	}
+	return &enrollment, nil
+}
+
+// CountProgress 批量统计报名记录的步骤总数与已完成数
+func (r *enrollmentRepositoryImpl) CountProgress(ctx context.Context, enrollmentIDs []uuid.UUID) (map[uuid.UUID]*repositories.EnrollmentProgressCount, error) {
+	counts := make(map[uuid.UUID]*repositories.EnrollmentProgressCount, len(enrollmentIDs))
+	if len(enrollmentIDs) == 0 {
+		return counts, nil
+	}
+	var rows []*repositories.EnrollmentProgressCount
+	if err := dbWithContext(ctx, r.db).Model(&entities.PathEnrollmentStep{}).
+		Select("enrollment_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE status = ?) AS completed", string(entities.EnrollmentStepCompleted)).
+		Where("enrollment_id IN ?", enrollmentIDs).
+		Group("enrollment_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计学习进度失败: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.EnrollmentID] = row
+	}
+	return counts, nil
+}
+
+// GetPathStats 批量统计学习路径的报名人数与完成人数
+func (r *enrollmentRepositoryImpl) GetPathStats(ctx context.Context, pathIDs []uuid.UUID) (map[uuid.UUID]*repositories.PathEnrollmentStats, error) {
+	stats := make(map[uuid.UUID]*repositories.PathEnrollmentStats, len(pathIDs))
+	if len(pathIDs) == 0 {
+		return stats, nil
+	}
+	var rows []*repositories.PathEnrollmentStats
+	if err := dbWithContext(ctx, r.db).Model(&entities.PathEnrollment{}).
+		Select("learning_path_id, COUNT(*) AS enrolled, COUNT(*) FILTER (WHERE status = ?) AS completed", string(entities.EnrollmentStatusCompleted)).
+		Where("learning_path_id IN ?", pathIDs).
+		Group("learning_path_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计报名人数失败: %w", err)
+	}
+	for _, row := range rows {
+		stats[row.LearningPathID] = row
+	}
+	return stats, nil
+}
@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// flashcardRepositoryImpl 闪卡仓储实现
+type flashcardRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFlashcardRepository 创建闪卡仓储实例
+func NewFlashcardRepository(db *gorm.DB) repositories.FlashcardRepository {
+	return &flashcardRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建闪卡
+func (r *flashcardRepositoryImpl) Create(ctx context.Context, card *entities.Flashcard) error {
+	if err := dbWithContext(ctx, r.db).Create(card).Error; err != nil {
+		return fmt.Errorf("创建闪卡失败: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch 批量创建闪卡
+func (r *flashcardRepositoryImpl) CreateBatch(ctx context.Context, cards []*entities.Flashcard) error {
+	if len(cards) == 0 {
+		return nil
+	}
+	if err := dbWithContext(ctx, r.db).CreateInBatches(cards, 100).Error; err != nil {
+		return fmt.Errorf("批量创建闪卡失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取闪卡
+func (r *flashcardRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Flashcard, error) {
+	var card entities.Flashcard
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&card).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NotFound("闪卡不存在")
+		}
+		return nil, fmt.Errorf("获取闪卡失败: %w", err)
+	}
+	return &card, nil
+}
+
+// GetByKnowledgePointID 获取知识点下的闪卡
+func (r *flashcardRepositoryImpl) GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.Flashcard, error) {
+	var cards []*entities.Flashcard
+	if err := dbWithContext(ctx, r.db).Where("knowledge_point_id = ?", knowledgePointID).Order("created_at ASC, cloze_index ASC").Find(&cards).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点闪卡失败: %w", err)
+	}
+	return cards, nil
+}
+
+// GetByKnowledgePointIDs 批量获取多个知识点下的闪卡
+func (r *flashcardRepositoryImpl) GetByKnowledgePointIDs(ctx context.Context, knowledgePointIDs []uuid.UUID) ([]*entities.Flashcard, error) {
+	var cards []*entities.Flashcard
+	if len(knowledgePointIDs) == 0 {
+		return cards, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("knowledge_point_id IN ?", knowledgePointIDs).Order("knowledge_point_id ASC, created_at ASC, cloze_index ASC").Find(&cards).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点闪卡失败: %w", err)
+	}
+	return cards, nil
+}
+
+// GetByIDs 根据ID批量获取闪卡
+func (r *flashcardRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Flashcard, error) {
+	var cards []*entities.Flashcard
+	if len(ids) == 0 {
+		return cards, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("id IN ?", ids).Find(&cards).Error; err != nil {
+		return nil, fmt.Errorf("根据ID获取闪卡失败: %w", err)
+	}
+	return cards, nil
+}
+
+// Update 更新闪卡
+func (r *flashcardRepositoryImpl) Update(ctx context.Context, card *entities.Flashcard) error {
+	if err := dbWithContext(ctx, r.db).Save(card).Error; err != nil {
+		return fmt.Errorf("更新闪卡失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除闪卡
+func (r *flashcardRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.Flashcard{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除闪卡失败: %w", err)
+	}
+	return nil
+}
+
+// flashcardReviewRepositoryImpl 闪卡复习调度仓储实现
+type flashcardReviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFlashcardReviewRepository 创建闪卡复习调度仓储实例
+func NewFlashcardReviewRepository(db *gorm.DB) repositories.FlashcardReviewRepository {
+	return &flashcardReviewRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetByUserAndFlashcard 获取用户对某张闪卡的调度状态
+func (r *flashcardReviewRepositoryImpl) GetByUserAndFlashcard(ctx context.Context, userID uint, flashcardID uuid.UUID) (*entities.FlashcardReview, error) {
+	var review entities.FlashcardReview
+	if err := dbWithContext(ctx, r.db).Where("user_id = ? AND flashcard_id = ?", userID, flashcardID).First(&review).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取闪卡调度状态失败: %w", err)
+	}
+	return &review, nil
+}
+
+// GetFlashcardIDsByUser 获取用户已加入复习计划的闪卡ID（限定在给定闪卡范围内）
+func (r *flashcardReviewRepositoryImpl) GetFlashcardIDsByUser(ctx context.Context, userID uint, flashcardIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	result := make(map[uuid.UUID]bool, len(flashcardIDs))
+	if len(flashcardIDs) == 0 {
+		return result, nil
+	}
+	var ids []uuid.UUID
+	if err := dbWithContext(ctx, r.db).Model(&entities.FlashcardReview{}).
+		Where("user_id = ? AND flashcard_id IN ?", userID, flashcardIDs).
+		Pluck("flashcard_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("获取闪卡调度状态失败: %w", err)
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// GetByUserID 获取用户的全部调度状态
+func (r *flashcardReviewRepositoryImpl) GetByUserID(ctx context.Context, userID uint) ([]*entities.FlashcardReview, error) {
+	var reviews []*entities.FlashcardReview
+	if err := dbWithContext(ctx, r.db).Preload("Flashcard").
+		Joins("JOIN flashcards ON flashcards.id = flashcard_reviews.flashcard_id AND flashcards.deleted_at IS NULL").
+		Where("flashcard_reviews.user_id = ?", userID).
+		Order("flashcard_reviews.due_at ASC").
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("获取用户闪卡调度状态失败: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetDue 获取用户在指定时间前到期的闪卡
+func (r *flashcardReviewRepositoryImpl) GetDue(ctx context.Context, userID uint, before time.Time, limit int) ([]*entities.FlashcardReview, error) {
+	var reviews []*entities.FlashcardReview
+	if err := dbWithContext(ctx, r.db).Preload("Flashcard").
+		Joins("JOIN flashcards ON flashcards.id = flashcard_reviews.flashcard_id AND flashcards.deleted_at IS NULL").
+		Where("flashcard_reviews.user_id = ? AND flashcard_reviews.due_at <= ?", userID, before).
+		Order("flashcard_reviews.due_at ASC").
+		Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("获取到期闪卡失败: %w", err)
+	}
+	return reviews, nil
+}
+
+// CreateBatch 批量创建调度状态（已存在的记录将被忽略）
+func (r *flashcardReviewRepositoryImpl) CreateBatch(ctx context.Context, reviews []*entities.FlashcardReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	if err := dbWithContext(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(reviews, 100).Error; err != nil {
+		return fmt.Errorf("批量创建闪卡调度状态失败: %w", err)
+	}
+	return nil
+}
+
+// Save 保存调度状态
+func (r *flashcardReviewRepositoryImpl) Save(ctx context.Context, review *entities.FlashcardReview) error {
+	if err := dbWithContext(ctx, r.db).Omit("Flashcard").Save(review).Error; err != nil {
+		return fmt.Errorf("保存闪卡调度状态失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFlashcardID 删除闪卡的全部调度状态
+func (r *flashcardReviewRepositoryImpl) DeleteByFlashcardID(ctx context.Context, flashcardID uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.FlashcardReview{}, "flashcard_id = ?", flashcardID).Error; err != nil {
+		return fmt.Errorf("删除闪卡调度状态失败: %w", err)
+	}
+	return nil
+}
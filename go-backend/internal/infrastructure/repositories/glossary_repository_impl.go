@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// glossaryRepositoryImpl 术语表仓储实现
+type glossaryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGlossaryRepository 创建术语表仓储实例
+func NewGlossaryRepository(db *gorm.DB) repositories.GlossaryRepository {
+	return &glossaryRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建术语
+func (r *glossaryRepositoryImpl) Create(ctx context.Context, term *entities.GlossaryTerm) error {
+	term.RefreshVariants()
+	if err := dbWithContext(ctx, r.db).Create(term).Error; err != nil {
+		return fmt.Errorf("创建术语失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取术语
+func (r *glossaryRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.GlossaryTerm, error) {
+	var term entities.GlossaryTerm
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&term).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("术语不存在")
+		}
+		return nil, fmt.Errorf("获取术语失败: %w", err)
+	}
+	return &term, nil
+}
+
+// Update 更新术语
+func (r *glossaryRepositoryImpl) Update(ctx context.Context, term *entities.GlossaryTerm) error {
+	term.RefreshVariants()
+	if err := dbWithContext(ctx, r.db).Save(term).Error; err != nil {
+		return fmt.Errorf("更新术语失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除术语
+func (r *glossaryRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.GlossaryTerm{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除术语失败: %w", err)
+	}
+	return nil
+}
+
+// List 按筛选条件分页获取术语
+func (r *glossaryRepositoryImpl) List(ctx context.Context, filter *repositories.GlossaryFilter) ([]*entities.GlossaryTerm, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.GlossaryTerm{})
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Query != "" {
+		pattern := "%" + escapeLikePattern(filter.Query) + "%"
+		query = query.Where(
+			"term ILIKE ? OR definition ILIKE ? OR EXISTS (SELECT 1 FROM jsonb_array_elements_text(variants) AS v WHERE v ILIKE ?)",
+			pattern, pattern, pattern,
+		)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取术语列表失败: %w", err)
+	}
+
+	var terms []*entities.GlossaryTerm
+	if err := query.Order("term ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&terms).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取术语列表失败: %w", err)
+	}
+	return terms, total, nil
+}
+
+// GetAll 获取全部术语
+func (r *glossaryRepositoryImpl) GetAll(ctx context.Context) ([]*entities.GlossaryTerm, error) {
+	var terms []*entities.GlossaryTerm
+	if err := dbWithContext(ctx, r.db).Order("created_at ASC").Find(&terms).Error; err != nil {
+		return nil, fmt.Errorf("获取术语表失败: %w", err)
+	}
+	return terms, nil
+}
+
+// FindByNames 获取任一名称与给定规范化名称相同的术语
+func (r *glossaryRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]*entities.GlossaryTerm, error) {
+	var terms []*entities.GlossaryTerm
+	if len(names) == 0 {
+		return terms, nil
+	}
+	if err := dbWithContext(ctx, r.db).
+		Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(variants) AS v WHERE v IN ?)", names).
+		Order("created_at ASC").
+		Find(&terms).Error; err != nil {
+		return nil, fmt.Errorf("根据名称获取术语失败: %w", err)
+	}
+	return terms, nil
+}
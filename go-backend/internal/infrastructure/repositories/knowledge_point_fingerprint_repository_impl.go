@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgePointFingerprintRepositoryImpl 知识点查重指纹仓储实现
+type knowledgePointFingerprintRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgePointFingerprintRepository 创建知识点查重指纹仓储
+func NewKnowledgePointFingerprintRepository(db *gorm.DB) repositories.KnowledgePointFingerprintRepository {
+	return &knowledgePointFingerprintRepositoryImpl{db: db}
+}
+
+// Save 创建或更新指纹
+func (r *knowledgePointFingerprintRepositoryImpl) Save(ctx context.Context, fingerprint *entities.KnowledgePointFingerprint) error {
+	if err := dbWithContext(ctx, r.db).Save(fingerprint).Error; err != nil {
+		return fmt.Errorf("保存知识点指纹失败: %w", err)
+	}
+	return nil
+}
+
+// GetAll 获取全部指纹
+func (r *knowledgePointFingerprintRepositoryImpl) GetAll(ctx context.Context) ([]*entities.KnowledgePointFingerprint, error) {
+	var fingerprints []*entities.KnowledgePointFingerprint
+	if err := dbWithContext(ctx, r.db).Find(&fingerprints).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点指纹失败: %w", err)
+	}
+	return fingerprints, nil
+}
+
+// Delete 删除知识点的指纹
+func (r *knowledgePointFingerprintRepositoryImpl) Delete(ctx context.Context, knowledgePointID uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ?", knowledgePointID).
+		Delete(&entities.KnowledgePointFingerprint{}).Error; err != nil {
+		return fmt.Errorf("删除知识点指纹失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgePointLinkRepositoryImpl 知识点关联仓储实现
+type knowledgePointLinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgePointLinkRepository 创建知识点关联仓储实例
+func NewKnowledgePointLinkRepository(db *gorm.DB) repositories.KnowledgePointLinkRepository {
+	return &knowledgePointLinkRepositoryImpl{
+		db: db,
+	}
+}
+
+// ReplaceOutgoing 替换来源知识点的全部关联
+func (r *knowledgePointLinkRepositoryImpl) ReplaceOutgoing(ctx context.Context, sourceID uuid.UUID, links []*entities.KnowledgePointLink) error {
+	return dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source_id = ?", sourceID).Delete(&entities.KnowledgePointLink{}).Error; err != nil {
+			return fmt.Errorf("删除知识点关联失败: %w", err)
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		if err := tx.Create(links).Error; err != nil {
+			return fmt.Errorf("创建知识点关联失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetOutgoing 获取知识点提及的其他知识点
+func (r *knowledgePointLinkRepositoryImpl) GetOutgoing(ctx context.Context, sourceID uuid.UUID) ([]*entities.KnowledgePointLink, error) {
+	var links []*entities.KnowledgePointLink
+	if err := dbWithContext(ctx, r.db).
+		Where("source_id = ?", sourceID).
+		Order("occurrences DESC, created_at ASC").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点关联失败: %w", err)
+	}
+	return links, nil
+}
+
+// GetIncoming 获取提及该知识点的反向链接
+func (r *knowledgePointLinkRepositoryImpl) GetIncoming(ctx context.Context, targetID uuid.UUID) ([]*entities.KnowledgePointLink, error) {
+	var links []*entities.KnowledgePointLink
+	if err := dbWithContext(ctx, r.db).
+		Where("target_id = ?", targetID).
+		Order("occurrences DESC, created_at ASC").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点反向链接失败: %w", err)
+	}
+	return links, nil
+}
+
+// GetAll 获取全部关联
+func (r *knowledgePointLinkRepositoryImpl) GetAll(ctx context.Context) ([]*entities.KnowledgePointLink, error) {
+	var links []*entities.KnowledgePointLink
+	if err := dbWithContext(ctx, r.db).Order("created_at ASC").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点关联失败: %w", err)
+	}
+	return links, nil
+}
+
+// DeleteByKnowledgePoint 删除知识点作为来源或目标的全部关联
+func (r *knowledgePointLinkRepositoryImpl) DeleteByKnowledgePoint(ctx context.Context, id uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).
+		Where("source_id = ? OR target_id = ?", id, id).
+		Delete(&entities.KnowledgePointLink{}).Error; err != nil {
+		return fmt.Errorf("删除知识点关联失败: %w", err)
+	}
+	return nil
+}
+
+// MergeInto 将被合并知识点的关联迁移到保留的知识点
+func (r *knowledgePointLinkRepositoryImpl) MergeInto(ctx context.Context, survivorID, duplicateID uuid.UUID) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 反向链接：其他知识点对被合并知识点的提及
+		if err := tx.Exec(`UPDATE knowledge_point_links SET target_id = ?
+			WHERE target_id = ? AND source_id <> ? AND source_id NOT IN (
+				SELECT source_id FROM knowledge_point_links WHERE target_id = ?)`,
+			survivorID, duplicateID, survivorID, survivorID).Error; err != nil {
+			return err
+		}
+		// 被合并知识点对其他知识点的提及
+		if err := tx.Exec(`UPDATE knowledge_point_links SET source_id = ?
+			WHERE source_id = ? AND target_id <> ? AND target_id NOT IN (
+				SELECT target_id FROM knowledge_point_links WHERE source_id = ?)`,
+			survivorID, duplicateID, survivorID, survivorID).Error; err != nil {
+			return err
+		}
+		// 与保留的知识点重复的关联，以及两者之间的相互提及
+		return tx.Where("source_id = ? OR target_id = ?", duplicateID, duplicateID).
+			Delete(&entities.KnowledgePointLink{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("合并知识点关联失败: %w", err)
+	}
+	return nil
+}
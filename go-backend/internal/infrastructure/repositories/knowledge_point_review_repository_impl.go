@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgePointReviewRepositoryImpl 知识点审核记录仓储实现
+type knowledgePointReviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgePointReviewRepository 创建知识点审核记录仓储实例
+func NewKnowledgePointReviewRepository(db *gorm.DB) repositories.KnowledgePointReviewRepository {
+	return &knowledgePointReviewRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建审核记录
+func (r *knowledgePointReviewRepositoryImpl) Create(ctx context.Context, review *entities.KnowledgePointReview) error {
+	if err := dbWithContext(ctx, r.db).Omit("KnowledgePoint").Create(review).Error; err != nil {
+		return fmt.Errorf("创建知识点审核记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetByKnowledgePointID 获取知识点的审核记录
+func (r *knowledgePointReviewRepositoryImpl) GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.KnowledgePointReview, error) {
+	var reviews []*entities.KnowledgePointReview
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ?", knowledgePointID).
+		Order("created_at ASC").
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点审核记录失败: %w", err)
+	}
+	return reviews, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgePointRevisionRepositoryImpl 知识点修订版本仓储实现
+type knowledgePointRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgePointRevisionRepository 创建知识点修订版本仓储实例
+func NewKnowledgePointRevisionRepository(db *gorm.DB) repositories.KnowledgePointRevisionRepository {
+	return &knowledgePointRevisionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建修订版本；锁定知识点行后再分配版本号，避免并发编辑产生相同的版本号
+func (r *knowledgePointRevisionRepositoryImpl) Create(ctx context.Context, revision *entities.KnowledgePointRevision) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM knowledge_points WHERE id = ? FOR UPDATE", revision.KnowledgePointID).Error; err != nil {
+			return err
+		}
+		var latest int
+		if err := tx.Model(&entities.KnowledgePointRevision{}).
+			Where("knowledge_point_id = ?", revision.KnowledgePointID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		revision.Version = latest + 1
+		return tx.Omit("KnowledgePoint").Create(revision).Error
+	})
+	if err != nil {
+		return fmt.Errorf("创建知识点修订版本失败: %w", err)
+	}
+	return nil
+}
+
+// GetLatest 获取知识点的最新修订版本
+func (r *knowledgePointRevisionRepositoryImpl) GetLatest(ctx context.Context, knowledgePointID uuid.UUID) (*entities.KnowledgePointRevision, error) {
+	var revision entities.KnowledgePointRevision
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ?", knowledgePointID).
+		Order("version DESC").
+		First(&revision).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取知识点最新修订版本失败: %w", err)
+	}
+	return &revision, nil
+}
+
+// GetByVersion 根据版本号获取修订版本
+func (r *knowledgePointRevisionRepositoryImpl) GetByVersion(ctx context.Context, knowledgePointID uuid.UUID, version int) (*entities.KnowledgePointRevision, error) {
+	var revision entities.KnowledgePointRevision
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ? AND version = ?", knowledgePointID, version).
+		First(&revision).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("修订版本不存在")
+		}
+		return nil, fmt.Errorf("获取知识点修订版本失败: %w", err)
+	}
+	return &revision, nil
+}
+
+// GetByKnowledgePointID 获取知识点的全部修订版本
+func (r *knowledgePointRevisionRepositoryImpl) GetByKnowledgePointID(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.KnowledgePointRevision, error) {
+	var revisions []*entities.KnowledgePointRevision
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ?", knowledgePointID).
+		Order("version DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点修订历史失败: %w", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/textsearch"
+)
+
+// knowledgePointTranslationRepositoryImpl 知识点译文仓储实现
+type knowledgePointTranslationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgePointTranslationRepository 创建知识点译文仓储
+func NewKnowledgePointTranslationRepository(db *gorm.DB) repositories.KnowledgePointTranslationRepository {
+	return &knowledgePointTranslationRepositoryImpl{db: db}
+}
+
+// Save 创建或更新译文，并更新其全文检索向量
+func (r *knowledgePointTranslationRepositoryImpl) Save(ctx context.Context, translation *entities.KnowledgePointTranslation) error {
+	return dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(translation).Error; err != nil {
+			return fmt.Errorf("保存知识点译文失败: %w", err)
+		}
+		if err := tx.Exec(
+			"UPDATE knowledge_point_translations SET search_vector = "+searchVectorSQL+" WHERE id = ?",
+			textsearch.ToDocument(translation.Title),
+			textsearch.ToDocument(translation.Description),
+			textsearch.ToDocument(translation.Content),
+			translation.ID,
+		).Error; err != nil {
+			return fmt.Errorf("更新知识点译文检索索引失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get 获取知识点指定语言的译文，不存在时返回nil
+func (r *knowledgePointTranslationRepositoryImpl) Get(ctx context.Context, knowledgePointID uuid.UUID, locale string) (*entities.KnowledgePointTranslation, error) {
+	var translation entities.KnowledgePointTranslation
+	err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ? AND locale = ?", knowledgePointID, locale).
+		First(&translation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取知识点译文失败: %w", err)
+	}
+	return &translation, nil
+}
+
+// GetByKnowledgePoint 获取知识点的全部译文
+func (r *knowledgePointTranslationRepositoryImpl) GetByKnowledgePoint(ctx context.Context, knowledgePointID uuid.UUID) ([]*entities.KnowledgePointTranslation, error) {
+	var translations []*entities.KnowledgePointTranslation
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ?", knowledgePointID).
+		Order("locale ASC").
+		Find(&translations).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点译文失败: %w", err)
+	}
+	return translations, nil
+}
+
+// GetPublishedByKnowledgePoints 批量获取知识点已发布的译文
+func (r *knowledgePointTranslationRepositoryImpl) GetPublishedByKnowledgePoints(ctx context.Context, knowledgePointIDs []uuid.UUID) ([]*entities.KnowledgePointTranslation, error) {
+	var translations []*entities.KnowledgePointTranslation
+	if len(knowledgePointIDs) == 0 {
+		return translations, nil
+	}
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id IN ? AND status = ?", knowledgePointIDs, entities.TranslationStatusPublished).
+		Find(&translations).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点译文失败: %w", err)
+	}
+	return translations, nil
+}
+
+// GetLocales 获取已有已发布译文的全部语言
+func (r *knowledgePointTranslationRepositoryImpl) GetLocales(ctx context.Context) ([]string, error) {
+	var locales []string
+	if err := dbWithContext(ctx, r.db).Model(&entities.KnowledgePointTranslation{}).
+		Where("status = ?", entities.TranslationStatusPublished).
+		Distinct().Order("locale ASC").
+		Pluck("locale", &locales).Error; err != nil {
+		return nil, fmt.Errorf("获取译文语言失败: %w", err)
+	}
+	return locales, nil
+}
+
+// Delete 删除知识点指定语言的译文
+func (r *knowledgePointTranslationRepositoryImpl) Delete(ctx context.Context, knowledgePointID uuid.UUID, locale string) error {
+	result := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id = ? AND locale = ?", knowledgePointID, locale).
+		Delete(&entities.KnowledgePointTranslation{})
+	if result.Error != nil {
+		return fmt.Errorf("删除知识点译文失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("知识点译文不存在")
+	}
+	return nil
+}
+
+// Search 在指定语言已发布的译文中全文检索已发布的知识点
+func (r *knowledgePointTranslationRepositoryImpl) Search(ctx context.Context, locale, query string, synonyms map[string][]string, offset, limit int) ([]*repositories.KnowledgePointSearchHit, int64, error) {
+	tsQuery := textsearch.ExpandTSQuery(query, synonyms)
+	if tsQuery == "" {
+		return []*repositories.KnowledgePointSearchHit{}, 0, nil
+	}
+
+	base := dbWithContext(ctx, r.db).Table("knowledge_point_translations AS t").
+		Joins("JOIN knowledge_points AS kp ON kp.id = t.knowledge_point_id").
+		Where("kp.deleted_at IS NULL AND kp.status = ?", entities.KnowledgePointStatusPublished).
+		Where("t.locale = ? AND t.status = ?", locale, entities.TranslationStatusPublished).
+		Where("t.search_vector @@ to_tsquery('simple', ?)", tsQuery)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点译文失败: %w", err)
+	}
+	if total == 0 {
+		return []*repositories.KnowledgePointSearchHit{}, 0, nil
+	}
+
+	var ranked []struct {
+		KnowledgePointID uuid.UUID
+		Rank             float64
+	}
+	if err := base.Session(&gorm.Session{}).
+		Select("t.knowledge_point_id, ts_rank_cd(t.search_vector, to_tsquery('simple', ?), 32) AS rank", tsQuery).
+		Order("rank DESC, t.updated_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&ranked).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点译文失败: %w", err)
+	}
+	if len(ranked) == 0 {
+		return []*repositories.KnowledgePointSearchHit{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(ranked))
+	for i, item := range ranked {
+		ids[i] = item.KnowledgePointID
+	}
+	var points []*entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).Where("id IN ?", ids).Find(&points).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点译文失败: %w", err)
+	}
+	var translations []*entities.KnowledgePointTranslation
+	if err := dbWithContext(ctx, r.db).
+		Where("knowledge_point_id IN ? AND locale = ?", ids, locale).
+		Find(&translations).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点译文失败: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		byID[point.ID] = point
+	}
+	for _, translation := range translations {
+		if point, ok := byID[translation.KnowledgePointID]; ok {
+			translation.ApplyTo(point)
+		}
+	}
+
+	// 按相关度顺序组装结果
+	hits := make([]*repositories.KnowledgePointSearchHit, 0, len(ranked))
+	for _, item := range ranked {
+		if point, ok := byID[item.KnowledgePointID]; ok {
+			hits = append(hits, &repositories.KnowledgePointSearchHit{Point: point, Rank: item.Rank})
+		}
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgeRecommendationRepositoryImpl 知识点推荐仓储实现
+type knowledgeRecommendationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeRecommendationRepository 创建知识点推荐仓储
+func NewKnowledgeRecommendationRepository(db *gorm.DB) repositories.KnowledgeRecommendationRepository {
+	return &knowledgeRecommendationRepositoryImpl{db: db}
+}
+
+// learnerEventsSQL 学习者在知识点上的学习事件：闪卡复习、学习路径步骤完成与未隐藏的评分
+const learnerEventsSQL = `SELECT fr.user_id, f.knowledge_point_id, fr.created_at AS studied_at,
+		fr."interval" AS review_interval, fr.ease_factor, fr.lapses
+	FROM flashcard_reviews AS fr
+	JOIN flashcards AS f ON f.id = fr.flashcard_id AND f.deleted_at IS NULL
+	WHERE fr.repetitions > 0 OR fr.lapses > 0
+	UNION ALL
+	SELECT e.user_id, s.knowledge_point_id, s.completed_at, NULL, NULL, 0
+	FROM path_enrollment_steps AS s
+	JOIN path_enrollments AS e ON e.id = s.enrollment_id
+	WHERE s.status = ? AND s.completed_at IS NOT NULL
+	UNION ALL
+	SELECT user_id, target_id, created_at, NULL, NULL, 0
+	FROM ratings
+	WHERE target_type = ? AND hidden = false`
+
+// GetLearnerStates 获取学习者的知识点学习状态
+func (r *knowledgeRecommendationRepositoryImpl) GetLearnerStates(ctx context.Context, userID *uint) ([]*repositories.LearnerKnowledgeState, error) {
+	db := dbWithContext(ctx, r.db)
+	events := db.Raw(learnerEventsSQL, entities.EnrollmentStepCompleted, entities.RatingTargetKnowledgePoint)
+	query := db.Table("(?) AS ev", events).
+		Select(`ev.user_id, ev.knowledge_point_id,
+			MIN(ev.studied_at) AS first_studied_at,
+			COALESCE(MIN(ev.review_interval), 0) AS min_interval,
+			COALESCE(AVG(ev.ease_factor), 0) AS avg_ease,
+			SUM(ev.lapses) AS lapses,
+			COALESCE(MAX(r.score), 0) AS rating`).
+		Joins("LEFT JOIN ratings AS r ON r.target_type = ? AND r.target_id = ev.knowledge_point_id AND r.user_id = ev.user_id AND r.hidden = false",
+			entities.RatingTargetKnowledgePoint).
+		Group("ev.user_id, ev.knowledge_point_id").
+		Order("ev.user_id ASC, first_studied_at ASC")
+	if userID != nil {
+		query = query.Where("ev.user_id = ?", *userID)
+	}
+
+	var states []*repositories.LearnerKnowledgeState
+	if err := query.Scan(&states).Error; err != nil {
+		return nil, fmt.Errorf("获取学习状态失败: %w", err)
+	}
+	return states, nil
+}
+
+// ReplaceTransitions 以新的计算结果替换全部转移统计
+func (r *knowledgeRecommendationRepositoryImpl) ReplaceTransitions(ctx context.Context, transitions []*entities.KnowledgePointTransition) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entities.KnowledgePointTransition{}).Error; err != nil {
+			return err
+		}
+		if len(transitions) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(transitions, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("保存知识点转移统计失败: %w", err)
+	}
+	return nil
+}
+
+// GetTransitionsFrom 获取从任一源知识点出发的转移统计
+func (r *knowledgeRecommendationRepositoryImpl) GetTransitionsFrom(ctx context.Context, sourceIDs []uuid.UUID) ([]*entities.KnowledgePointTransition, error) {
+	var transitions []*entities.KnowledgePointTransition
+	if len(sourceIDs) == 0 {
+		return transitions, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("source_id IN ?", sourceIDs).Find(&transitions).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点转移统计失败: %w", err)
+	}
+	return transitions, nil
+}
+
+// GetPopular 获取学习人数最多的已发布知识点
+func (r *knowledgeRecommendationRepositoryImpl) GetPopular(ctx context.Context, limit int) ([]*repositories.KnowledgePointPopularity, error) {
+	var popular []*repositories.KnowledgePointPopularity
+	if err := dbWithContext(ctx, r.db).Table("flashcard_reviews AS fr").
+		Select("f.knowledge_point_id, COUNT(DISTINCT fr.user_id) AS learners").
+		Joins("JOIN flashcards AS f ON f.id = fr.flashcard_id AND f.deleted_at IS NULL").
+		Joins("JOIN knowledge_points AS kp ON kp.id = f.knowledge_point_id AND kp.deleted_at IS NULL").
+		Where("kp.status = ?", entities.KnowledgePointStatusPublished).
+		Group("f.knowledge_point_id").
+		Order("learners DESC").
+		Limit(limit).
+		Scan(&popular).Error; err != nil {
+		return nil, fmt.Errorf("获取热门知识点失败: %w", err)
+	}
+	return popular, nil
+}
+
+// GetLastComputedAt 获取转移统计的最近计算时间
+func (r *knowledgeRecommendationRepositoryImpl) GetLastComputedAt(ctx context.Context) (*time.Time, error) {
+	var computedAt *time.Time
+	if err := dbWithContext(ctx, r.db).Model(&entities.KnowledgePointTransition{}).
+		Select("MAX(computed_at)").
+		Scan(&computedAt).Error; err != nil {
+		return nil, fmt.Errorf("获取推荐计算时间失败: %w", err)
+	}
+	return computedAt, nil
+}
@@ -2,12 +2,18 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"sical-go-backend/internal/domain/entities"
 	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/textsearch"
 )
 
 // learningPathRepositoryImpl 学习路径仓储实现
@@ -24,7 +30,7 @@ func NewLearningPathRepository(db *gorm.DB) repositories.LearningPathRepository
 
 // Create 创建学习路径
 func (r *learningPathRepositoryImpl) Create(ctx context.Context, path *entities.LearningPath) error {
-	if err := r.db.WithContext(ctx).Create(path).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Create(path).Error; err != nil {
 		return fmt.Errorf("创建学习路径失败: %w", err)
 	}
 	return nil
@@ -33,9 +39,9 @@ func (r *learningPathRepositoryImpl) Create(ctx context.Context, path *entities.
 // GetByID 根据ID获取学习路径
 func (r *learningPathRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.LearningPath, error) {
 	var path entities.LearningPath
-	if err := r.db.WithContext(ctx).Preload("LearningGoal").Preload("KnowledgePoints").Where("id = ?", id).First(&path).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Preload("LearningGoal").Preload("KnowledgePoints").Where("id = ?", id).First(&path).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("学习路径不存在")
+			return nil, repositories.NotFound("学习路径不存在")
 		}
 		return nil, fmt.Errorf("获取学习路径失败: %w", err)
 	}
@@ -45,7 +51,19 @@ func (r *learningPathRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID)
 // GetByGoalID 根据目标ID获取学习路径
 func (r *learningPathRepositoryImpl) GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.LearningPath, error) {
 	var paths []*entities.LearningPath
-	if err := r.db.WithContext(ctx).Preload("KnowledgePoints").Where("goal_id = ?", goalID).Order("order ASC").Find(&paths).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Preload("KnowledgePoints").Where("goal_id = ?", goalID).Order("order ASC").Find(&paths).Error; err != nil {
+		return nil, fmt.Errorf("获取学习路径失败: %w", err)
+	}
+	return paths, nil
+}
+
+// GetByIDs 根据ID批量获取学习路径
+func (r *learningPathRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.LearningPath, error) {
+	var paths []*entities.LearningPath
+	if len(ids) == 0 {
+		return paths, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("id IN ?", ids).Find(&paths).Error; err != nil {
 		return nil, fmt.Errorf("获取学习路径失败: %w", err)
 	}
 	return paths, nil
@@ -53,7 +71,7 @@ func (r *learningPathRepositoryImpl) GetByGoalID(ctx context.Context, goalID uui
 
 // Update 更新学习路径
 func (r *learningPathRepositoryImpl) Update(ctx context.Context, path *entities.LearningPath) error {
-	if err := r.db.WithContext(ctx).Save(path).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Save(path).Error; err != nil {
 		return fmt.Errorf("更新学习路径失败: %w", err)
 	}
 	return nil
@@ -61,7 +79,7 @@ func (r *learningPathRepositoryImpl) Update(ctx context.Context, path *entities.
 
 // Delete 删除学习路径
 func (r *learningPathRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Delete(&entities.LearningPath{}, "id = ?", id).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.LearningPath{}, "id = ?", id).Error; err != nil {
 		return fmt.Errorf("删除学习路径失败: %w", err)
 	}
 	return nil
@@ -69,12 +87,42 @@ func (r *learningPathRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) e
 
 // UpdateStatus 更新学习路径状态
 func (r *learningPathRepositoryImpl) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
-	if err := r.db.WithContext(ctx).Model(&entities.LearningPath{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Model(&entities.LearningPath{}).Where("id = ?", id).Update("status", status).Error; err != nil {
 		return fmt.Errorf("更新学习路径状态失败: %w", err)
 	}
 	return nil
 }
 
+// ListPublic 获取公开的学习路径，评分高的在前
+func (r *learningPathRepositoryImpl) ListPublic(ctx context.Context, query string, offset, limit int) ([]*entities.LearningPath, int64, error) {
+	db := dbWithContext(ctx, r.db).Model(&entities.LearningPath{}).Where("is_public = ?", true)
+	if keyword := strings.TrimSpace(query); keyword != "" {
+		pattern := "%" + escapeLikePattern(keyword) + "%"
+		db = db.Where("(title ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取公开学习路径失败: %w", err)
+	}
+	var paths []*entities.LearningPath
+	if err := db.Session(&gorm.Session{}).
+		Order("average_rating DESC, ratings_count DESC, created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&paths).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取公开学习路径失败: %w", err)
+	}
+	return paths, total, nil
+}
+
+// SetPublic 设置学习路径是否公开
+func (r *learningPathRepositoryImpl) SetPublic(ctx context.Context, id uuid.UUID, public bool) error {
+	if err := dbWithContext(ctx, r.db).Model(&entities.LearningPath{}).Where("id = ?", id).Update("is_public", public).Error; err != nil {
+		return fmt.Errorf("更新学习路径公开状态失败: %w", err)
+	}
+	return nil
+}
+
 // knowledgePointRepositoryImpl 知识点仓储实现
 type knowledgePointRepositoryImpl struct {
 	db *gorm.DB
@@ -89,18 +137,30 @@ func NewKnowledgePointRepository(db *gorm.DB) repositories.KnowledgePointReposit
 
 // Create 创建知识点
 func (r *knowledgePointRepositoryImpl) Create(ctx context.Context, point *entities.KnowledgePoint) error {
-	if err := r.db.WithContext(ctx).Create(point).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Create(point).Error; err != nil {
 		return fmt.Errorf("创建知识点失败: %w", err)
 	}
-	return nil
+	return r.updateSearchVector(ctx, point)
 }
 
 // GetByID 根据ID获取知识点
 func (r *knowledgePointRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error) {
 	var point entities.KnowledgePoint
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&point).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&point).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("知识点不存在")
+			return nil, repositories.NotFound("知识点不存在")
+		}
+		return nil, fmt.Errorf("获取知识点失败: %w", err)
+	}
+	return &point, nil
+}
+
+// GetByIDForUpdate 获取知识点并锁定该行直至当前事务结束
+func (r *knowledgePointRepositoryImpl) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error) {
+	var point entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&point).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NotFound("知识点不存在")
 		}
 		return nil, fmt.Errorf("获取知识点失败: %w", err)
 	}
@@ -110,33 +170,116 @@ func (r *knowledgePointRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID
 // GetByCategory 根据类别获取知识点
 func (r *knowledgePointRepositoryImpl) GetByCategory(ctx context.Context, category string) ([]*entities.KnowledgePoint, error) {
 	var points []*entities.KnowledgePoint
-	if err := r.db.WithContext(ctx).Where("category = ?", category).Find(&points).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("category = ? AND status = ?", category, entities.KnowledgePointStatusPublished).Find(&points).Error; err != nil {
 		return nil, fmt.Errorf("根据类别获取知识点失败: %w", err)
 	}
 	return points, nil
 }
 
-// Search 搜索知识点
-func (r *knowledgePointRepositoryImpl) Search(ctx context.Context, keyword string) ([]*entities.KnowledgePoint, error) {
+// searchVectorSQL 标题、描述、内容分别以A、B、C权重构建检索向量
+const searchVectorSQL = `setweight(to_tsvector('simple', ?), 'A') || setweight(to_tsvector('simple', ?), 'B') || setweight(to_tsvector('simple', ?), 'C')`
+
+// updateSearchVector 更新知识点的全文检索向量
+func (r *knowledgePointRepositoryImpl) updateSearchVector(ctx context.Context, point *entities.KnowledgePoint) error {
+	if err := dbWithContext(ctx, r.db).Exec(
+		"UPDATE knowledge_points SET search_vector = "+searchVectorSQL+" WHERE id = ?",
+		textsearch.ToDocument(point.Title),
+		textsearch.ToDocument(point.Description),
+		textsearch.ToDocument(point.Content),
+		point.ID,
+	).Error; err != nil {
+		return fmt.Errorf("更新知识点检索索引失败: %w", err)
+	}
+	return nil
+}
+
+// Search 全文检索知识点
+func (r *knowledgePointRepositoryImpl) Search(ctx context.Context, query string, synonyms map[string][]string, offset, limit int) ([]*repositories.KnowledgePointSearchHit, int64, error) {
+	tsQuery := textsearch.ExpandTSQuery(query, synonyms)
+	if tsQuery == "" {
+		return []*repositories.KnowledgePointSearchHit{}, 0, nil
+	}
+
+	base := dbWithContext(ctx, r.db).Model(&entities.KnowledgePoint{}).
+		Where("status = ?", entities.KnowledgePointStatusPublished).
+		Where("search_vector @@ to_tsquery('simple', ?)", tsQuery)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点失败: %w", err)
+	}
+	if total == 0 {
+		return []*repositories.KnowledgePointSearchHit{}, 0, nil
+	}
+
+	var ranked []struct {
+		ID   uuid.UUID
+		Rank float64
+	}
+	if err := base.Session(&gorm.Session{}).
+		Select("id, ts_rank_cd(search_vector, to_tsquery('simple', ?), 32) AS rank", tsQuery).
+		Order("rank DESC, updated_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&ranked).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点失败: %w", err)
+	}
+	if len(ranked) == 0 {
+		return []*repositories.KnowledgePointSearchHit{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(ranked))
+	for i, item := range ranked {
+		ids[i] = item.ID
+	}
 	var points []*entities.KnowledgePoint
-	searchPattern := "%" + keyword + "%"
-	if err := r.db.WithContext(ctx).Where("title ILIKE ? OR description ILIKE ?", searchPattern, searchPattern).Find(&points).Error; err != nil {
-		return nil, fmt.Errorf("搜索知识点失败: %w", err)
+	if err := dbWithContext(ctx, r.db).Where("id IN ?", ids).Find(&points).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索知识点失败: %w", err)
 	}
-	return points, nil
+	byID := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+	for _, point := range points {
+		byID[point.ID] = point
+	}
+
+	// 按相关度顺序组装结果
+	hits := make([]*repositories.KnowledgePointSearchHit, 0, len(ranked))
+	for _, item := range ranked {
+		if point, ok := byID[item.ID]; ok {
+			hits = append(hits, &repositories.KnowledgePointSearchHit{Point: point, Rank: item.Rank})
+		}
+	}
+	return hits, total, nil
+}
+
+// RebuildSearchIndex 重建全部知识点的全文检索向量
+func (r *knowledgePointRepositoryImpl) RebuildSearchIndex(ctx context.Context) (int64, error) {
+	var count int64
+	var points []*entities.KnowledgePoint
+	err := dbWithContext(ctx, r.db).FindInBatches(&points, 200, func(tx *gorm.DB, batch int) error {
+		for _, point := range points {
+			if err := r.updateSearchVector(ctx, point); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return count, fmt.Errorf("重建知识点检索索引失败: %w", err)
+	}
+	return count, nil
 }
 
 // Update 更新知识点
 func (r *knowledgePointRepositoryImpl) Update(ctx context.Context, point *entities.KnowledgePoint) error {
-	if err := r.db.WithContext(ctx).Save(point).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Save(point).Error; err != nil {
 		return fmt.Errorf("更新知识点失败: %w", err)
 	}
-	return nil
+	return r.updateSearchVector(ctx, point)
 }
 
 // Delete 删除知识点
 func (r *knowledgePointRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Delete(&entities.KnowledgePoint{}, "id = ?", id).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.KnowledgePoint{}, "id = ?", id).Error; err != nil {
 		return fmt.Errorf("删除知识点失败: %w", err)
 	}
 	return nil
@@ -145,8 +288,408 @@ func (r *knowledgePointRepositoryImpl) Delete(ctx context.Context, id uuid.UUID)
 // GetByDifficulty 根据难度获取知识点
 func (r *knowledgePointRepositoryImpl) GetByDifficulty(ctx context.Context, difficulty string) ([]*entities.KnowledgePoint, error) {
 	var points []*entities.KnowledgePoint
-	if err := r.db.WithContext(ctx).Where("difficulty = ?", difficulty).Find(&points).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("difficulty = ? AND status = ?", difficulty, entities.KnowledgePointStatusPublished).Find(&points).Error; err != nil {
 		return nil, fmt.Errorf("根据难度获取知识点失败: %w", err)
 	}
 	return points, nil
+}
+
+// GetByAuthor 获取用户创建的全部知识点
+func (r *knowledgePointRepositoryImpl) GetByAuthor(ctx context.Context, authorID uint) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).Where("author_id = ?", authorID).Order("updated_at DESC").Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("获取用户知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetByExternalKeys 根据外部键批量获取知识点
+func (r *knowledgePointRepositoryImpl) GetByExternalKeys(ctx context.Context, keys []string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(keys) == 0 {
+		return points, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("external_key IN ?", keys).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("根据外部键获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetByIDs 根据ID批量获取知识点
+func (r *knowledgePointRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(ids) == 0 {
+		return points, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("id IN ?", ids).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("根据ID获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetByTitles 根据标题批量获取知识点
+func (r *knowledgePointRepositoryImpl) GetByTitles(ctx context.Context, titles []string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(titles) == 0 {
+		return points, nil
+	}
+	if err := dbWithContext(ctx, r.db).Where("title IN ?", titles).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("根据标题获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetMentionIndex 获取全部知识点的ID、标题与别名
+func (r *knowledgePointRepositoryImpl) GetMentionIndex(ctx context.Context) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).Select("id", "title", "aliases").Order("created_at ASC").Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点名称索引失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetByPrerequisite 获取前置知识点中包含指定知识点的知识点
+func (r *knowledgePointRepositoryImpl) GetByPrerequisite(ctx context.Context, prerequisiteID uuid.UUID) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).
+		Where("prerequisites @> ?", fmt.Sprintf(`[%q]`, prerequisiteID.String())).
+		Order("created_at ASC").
+		Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("根据前置知识点获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// MergeInto 将被合并知识点的关联迁移到保留的知识点并删除被合并的知识点
+func (r *knowledgePointRepositoryImpl) MergeInto(ctx context.Context, survivorID, duplicateID uuid.UUID) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 闪卡
+		if err := tx.Model(&entities.Flashcard{}).
+			Where("knowledge_point_id = ?", duplicateID).
+			Update("knowledge_point_id", survivorID).Error; err != nil {
+			return err
+		}
+
+		// 评论
+		if err := tx.Model(&entities.Comment{}).
+			Where("knowledge_point_id = ?", duplicateID).
+			Update("knowledge_point_id", survivorID).Error; err != nil {
+			return err
+		}
+
+		// 评分：已同时评价两者的用户仅保留对保留知识点的评分，迁移后重新计算平均评分
+		if err := tx.Exec(`UPDATE ratings SET target_id = ?
+			WHERE target_type = ? AND target_id = ? AND user_id NOT IN (
+				SELECT user_id FROM ratings WHERE target_type = ? AND target_id = ?)`,
+			survivorID, string(entities.RatingTargetKnowledgePoint), duplicateID,
+			string(entities.RatingTargetKnowledgePoint), survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.Rating{}, "target_type = ? AND target_id = ?",
+			string(entities.RatingTargetKnowledgePoint), duplicateID).Error; err != nil {
+			return err
+		}
+		if err := refreshRatingAggregate(tx, string(entities.RatingTargetKnowledgePoint), survivorID); err != nil {
+			return err
+		}
+
+		// 译文：保留的知识点已有同语言译文时丢弃被合并知识点的译文
+		if err := tx.Exec(`UPDATE knowledge_point_translations SET knowledge_point_id = ?
+			WHERE knowledge_point_id = ? AND locale NOT IN (
+				SELECT locale FROM knowledge_point_translations WHERE knowledge_point_id = ?)`,
+			survivorID, duplicateID, survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.KnowledgePointTranslation{}, "knowledge_point_id = ?", duplicateID).Error; err != nil {
+			return err
+		}
+
+		// 学习者的笔记与高亮（引文在保留的知识点内容中重新定位）
+		if err := tx.Model(&entities.Annotation{}).
+			Where("knowledge_point_id = ?", duplicateID).
+			Update("knowledge_point_id", survivorID).Error; err != nil {
+			return err
+		}
+
+		// 收藏条目：已同时收藏两者的收藏夹仅保留原条目
+		if err := tx.Exec(`UPDATE collection_items SET target_id = ?
+			WHERE target_type = ? AND target_id = ? AND collection_id NOT IN (
+				SELECT collection_id FROM collection_items WHERE target_type = ? AND target_id = ?)`,
+			survivorID, string(entities.CollectionItemKnowledgePoint), duplicateID,
+			string(entities.CollectionItemKnowledgePoint), survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.CollectionItem{}, "target_type = ? AND target_id = ?",
+			string(entities.CollectionItemKnowledgePoint), duplicateID).Error; err != nil {
+			return err
+		}
+
+		// 报名学习进度：已同时包含两者的报名仅保留原步骤
+		if err := tx.Exec(`UPDATE path_enrollment_steps SET knowledge_point_id = ?
+			WHERE knowledge_point_id = ? AND enrollment_id NOT IN (
+				SELECT enrollment_id FROM path_enrollment_steps WHERE knowledge_point_id = ?)`,
+			survivorID, duplicateID, survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.PathEnrollmentStep{}, "knowledge_point_id = ?", duplicateID).Error; err != nil {
+			return err
+		}
+
+		// 学习路径关联：已同时包含两者的路径仅保留原关联
+		if err := tx.Exec(`INSERT INTO path_knowledge_points (learning_path_id, knowledge_point_id)
+			SELECT learning_path_id, ? FROM path_knowledge_points WHERE knowledge_point_id = ?
+			ON CONFLICT DO NOTHING`, survivorID, duplicateID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM path_knowledge_points WHERE knowledge_point_id = ?", duplicateID).Error; err != nil {
+			return err
+		}
+
+		// 学习路径模板步骤中的知识点引用
+		var steps []*entities.LearningPathTemplateStep
+		if err := tx.Select("id", "knowledge_points").
+			Where("knowledge_points @> ?", fmt.Sprintf(`[%q]`, duplicateID.String())).
+			Find(&steps).Error; err != nil {
+			return err
+		}
+		for _, step := range steps {
+			ids := step.KnowledgePointIDs()
+			for i, id := range ids {
+				if id == duplicateID {
+					ids[i] = survivorID
+				}
+			}
+			step.SetKnowledgePointIDs(ids)
+			if err := tx.Model(&entities.LearningPathTemplateStep{}).
+				Where("id = ?", step.ID).
+				Update("knowledge_points", step.KnowledgePoints).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&entities.KnowledgePoint{}, "id = ?", duplicateID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("合并知识点失败: %w", err)
+	}
+	return nil
+}
+
+// GetGraphNodes 获取全部知识点的图谱字段
+func (r *knowledgePointRepositoryImpl) GetGraphNodes(ctx context.Context) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).
+		Select("id", "title", "category", "difficulty", "status", "author_id", "prerequisites").
+		Order("created_at ASC").
+		Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("获取知识图谱失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetByContentMentions 获取内容中包含任一名称的知识点
+func (r *knowledgePointRepositoryImpl) GetByContentMentions(ctx context.Context, terms []string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(terms) == 0 {
+		return points, nil
+	}
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		conditions[i] = "content ILIKE ?"
+		args[i] = "%" + escapeLikePattern(term) + "%"
+	}
+	if err := dbWithContext(ctx, r.db).Where(strings.Join(conditions, " OR "), args...).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("根据内容提及获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// escapeLikePattern 转义LIKE模式中的通配符
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// GetByStatus 根据状态获取知识点
+func (r *knowledgePointRepositoryImpl) GetByStatus(ctx context.Context, status string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if err := dbWithContext(ctx, r.db).Where("status = ?", status).Order("updated_at ASC").Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("根据状态获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// GetPublishedVersion 获取已发布知识点的数量与最近更新时间
+func (r *knowledgePointRepositoryImpl) GetPublishedVersion(ctx context.Context) (int64, time.Time, error) {
+	var version struct {
+		Count  int64
+		Latest *time.Time
+	}
+	if err := dbWithContext(ctx, r.db).Model(&entities.KnowledgePoint{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS latest").
+		Where("status = ?", entities.KnowledgePointStatusPublished).
+		Scan(&version).Error; err != nil {
+		return 0, time.Time{}, fmt.Errorf("获取知识点版本失败: %w", err)
+	}
+	if version.Latest == nil {
+		return version.Count, time.Time{}, nil
+	}
+	return version.Count, *version.Latest, nil
+}
+
+// List 按筛选条件分页获取知识点目录
+func (r *knowledgePointRepositoryImpl) List(ctx context.Context, filter *repositories.KnowledgePointFilter) (*repositories.KnowledgePointPage, error) {
+	sortBy := filter.SortBy
+	switch sortBy {
+	case "":
+		sortBy = repositories.KnowledgePointSortCreatedAt
+	case repositories.KnowledgePointSortCreatedAt, repositories.KnowledgePointSortUpdatedAt, repositories.KnowledgePointSortTitle, repositories.KnowledgePointSortRating:
+	default:
+		return nil, fmt.Errorf("不支持的排序字段: %s", sortBy)
+	}
+
+	base := r.applyFilter(dbWithContext(ctx, r.db).Model(&entities.KnowledgePoint{}), filter, false, false)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点目录失败: %w", err)
+	}
+
+	direction, comparator := "ASC", ">"
+	if filter.SortDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	query := base.Session(&gorm.Session{})
+	if filter.Cursor != nil {
+		value, err := cursorValue(sortBy, filter.Cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortBy, comparator), value, filter.Cursor.ID)
+	}
+
+	// 多取一条用于判断是否存在下一页
+	var points []*entities.KnowledgePoint
+	if err := query.
+		Order(fmt.Sprintf("%s %s, id %s", sortBy, direction, direction)).
+		Limit(filter.Limit + 1).
+		Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点目录失败: %w", err)
+	}
+
+	page := &repositories.KnowledgePointPage{Points: points, Total: total}
+	if len(points) > filter.Limit {
+		page.Points = points[:filter.Limit]
+		last := page.Points[len(page.Points)-1]
+		page.NextCursor = &repositories.KnowledgePointCursor{Value: sortValue(sortBy, last), ID: last.ID}
+	}
+	return page, nil
+}
+
+// GetFacets 获取分面统计
+func (r *knowledgePointRepositoryImpl) GetFacets(ctx context.Context, filter *repositories.KnowledgePointFilter) (*repositories.KnowledgePointFacets, error) {
+	type bucket struct {
+		Value string
+		Count int64
+	}
+
+	facets := &repositories.KnowledgePointFacets{
+		Categories:   make(map[string]int64),
+		Difficulties: make(map[string]int64),
+	}
+
+	var categories []bucket
+	if err := r.applyFilter(dbWithContext(ctx, r.db).Model(&entities.KnowledgePoint{}), filter, true, false).
+		Select("category AS value, COUNT(*) AS count").
+		Group("category").
+		Scan(&categories).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点分类统计失败: %w", err)
+	}
+	for _, b := range categories {
+		facets.Categories[b.Value] = b.Count
+	}
+
+	var difficulties []bucket
+	if err := r.applyFilter(dbWithContext(ctx, r.db).Model(&entities.KnowledgePoint{}), filter, false, true).
+		Select("difficulty AS value, COUNT(*) AS count").
+		Group("difficulty").
+		Scan(&difficulties).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点难度统计失败: %w", err)
+	}
+	for _, b := range difficulties {
+		facets.Difficulties[b.Value] = b.Count
+	}
+
+	return facets, nil
+}
+
+// applyFilter 应用目录筛选条件（仅已发布），分面统计时可跳过分类或难度维度
+func (r *knowledgePointRepositoryImpl) applyFilter(db *gorm.DB, filter *repositories.KnowledgePointFilter, skipCategory, skipDifficulty bool) *gorm.DB {
+	db = db.Where("status = ?", entities.KnowledgePointStatusPublished)
+	if len(filter.Categories) > 0 && !skipCategory {
+		db = db.Where("category IN ?", filter.Categories)
+	}
+	if len(filter.Difficulties) > 0 && !skipDifficulty {
+		db = db.Where("difficulty IN ?", filter.Difficulties)
+	}
+	if len(filter.Tags) > 0 {
+		conditions := make([]string, len(filter.Tags))
+		args := make([]interface{}, len(filter.Tags))
+		for i, tag := range filter.Tags {
+			conditions[i] = "tags @> ?::jsonb"
+			data, _ := json.Marshal([]string{tag})
+			args[i] = string(data)
+		}
+		db = db.Where(strings.Join(conditions, " OR "), args...)
+	}
+	if filter.HasPrerequisite != nil {
+		// 前置知识点为非空JSON数组
+		condition := "prerequisites @> '[]'::jsonb AND prerequisites <> '[]'::jsonb"
+		if *filter.HasPrerequisite {
+			db = db.Where(condition)
+		} else {
+			db = db.Where("NOT COALESCE(" + condition + ", false)")
+		}
+	}
+	if filter.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		db = db.Where("created_at < ?", *filter.CreatedTo)
+	}
+	return db
+}
+
+// cursorValue 将游标中的排序值转换为对应列的类型
+func cursorValue(sortBy, value string) (interface{}, error) {
+	switch sortBy {
+	case repositories.KnowledgePointSortTitle:
+		return value, nil
+	case repositories.KnowledgePointSortRating:
+		rating, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("分页游标无效")
+		}
+		return rating, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("分页游标无效")
+	}
+	return t, nil
+}
+
+// sortValue 获取知识点在排序列上的值
+func sortValue(sortBy string, point *entities.KnowledgePoint) string {
+	switch sortBy {
+	case repositories.KnowledgePointSortTitle:
+		return point.Title
+	case repositories.KnowledgePointSortRating:
+		return strconv.FormatFloat(point.AverageRating, 'f', -1, 64)
+	case repositories.KnowledgePointSortUpdatedAt:
+		return point.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return point.CreatedAt.Format(time.RFC3339Nano)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// learningPathTemplateRepositoryImpl 学习路径模板仓储实现
+type learningPathTemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewLearningPathTemplateRepository 创建学习路径模板仓储
+func NewLearningPathTemplateRepository(db *gorm.DB) repositories.LearningPathTemplateRepository {
+	return &learningPathTemplateRepositoryImpl{db: db}
+}
+
+// preloadSteps 按顺序预加载模板步骤
+func preloadSteps(db *gorm.DB) *gorm.DB {
+	return db.Preload("Steps", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("position ASC")
+	})
+}
+
+// Create 创建模板及其步骤
+func (r *learningPathTemplateRepositoryImpl) Create(ctx context.Context, template *entities.LearningPathTemplate) error {
+	if err := dbWithContext(ctx, r.db).Create(template).Error; err != nil {
+		return fmt.Errorf("创建学习路径模板失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取模板
+func (r *learningPathTemplateRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.LearningPathTemplate, error) {
+	var template entities.LearningPathTemplate
+	if err := preloadSteps(dbWithContext(ctx, r.db)).Where("id = ?", id).First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("学习路径模板不存在")
+		}
+		return nil, fmt.Errorf("获取学习路径模板失败: %w", err)
+	}
+	return &template, nil
+}
+
+// List 按条件获取模板，实例化次数多的在前
+func (r *learningPathTemplateRepositoryImpl) List(ctx context.Context, filter *repositories.LearningPathTemplateFilter) ([]*entities.LearningPathTemplate, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.LearningPathTemplate{})
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Difficulty != "" {
+		query = query.Where("difficulty = ?", filter.Difficulty)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if keyword := strings.TrimSpace(filter.Query); keyword != "" {
+		pattern := "%" + escapeLikePattern(keyword) + "%"
+		query = query.Where("(title ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取学习路径模板失败: %w", err)
+	}
+	var templates []*entities.LearningPathTemplate
+	if err := preloadSteps(query.Session(&gorm.Session{})).
+		Order("usage_count DESC, updated_at DESC").
+		Offset(filter.Offset).Limit(filter.Limit).
+		Find(&templates).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取学习路径模板失败: %w", err)
+	}
+	return templates, total, nil
+}
+
+// Update 在同一事务中更新模板并替换全部步骤
+func (r *learningPathTemplateRepositoryImpl) Update(ctx context.Context, template *entities.LearningPathTemplate) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(template).
+			Select("title", "description", "category", "difficulty", "status").
+			Updates(template).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.LearningPathTemplateStep{}, "template_id = ?", template.ID).Error; err != nil {
+			return err
+		}
+		if len(template.Steps) == 0 {
+			return nil
+		}
+		for i := range template.Steps {
+			template.Steps[i].ID = uuid.Nil
+			template.Steps[i].TemplateID = template.ID
+		}
+		return tx.Create(&template.Steps).Error
+	})
+	if err != nil {
+		return fmt.Errorf("更新学习路径模板失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除模板
+func (r *learningPathTemplateRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := dbWithContext(ctx, r.db).Delete(&entities.LearningPathTemplate{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除学习路径模板失败: %w", err)
+	}
+	return nil
+}
+
+// Instantiate 在同一事务中创建学习路径、关联知识点并累计模板的实例化次数
+func (r *learningPathTemplateRepositoryImpl) Instantiate(ctx context.Context, templateID uuid.UUID, paths []*entities.LearningPath, knowledgePointIDs [][]uuid.UUID) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for i, path := range paths {
+			if err := tx.Omit(clause.Associations).Create(path).Error; err != nil {
+				return err
+			}
+			for _, pointID := range knowledgePointIDs[i] {
+				if err := tx.Exec(`INSERT INTO path_knowledge_points (learning_path_id, knowledge_point_id)
+					VALUES (?, ?) ON CONFLICT DO NOTHING`, path.ID, pointID).Error; err != nil {
+					return err
+				}
+			}
+		}
+		// usage_count仅允许创建时写入，通过原生SQL累计
+		return tx.Exec("UPDATE learning_path_templates SET usage_count = usage_count + 1 WHERE id = ?", templateID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("实例化学习路径模板失败: %w", err)
+	}
+	return nil
+}
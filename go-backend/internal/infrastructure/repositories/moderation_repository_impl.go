@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// moderationRepositoryImpl 举报与审核记录仓储实现
+type moderationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewModerationRepository 创建举报与审核记录仓储
+func NewModerationRepository(db *gorm.DB) repositories.ModerationRepository {
+	return &moderationRepositoryImpl{db: db}
+}
+
+// CreateReport 创建举报
+func (r *moderationRepositoryImpl) CreateReport(ctx context.Context, report *entities.Report) error {
+	if err := dbWithContext(ctx, r.db).Create(report).Error; err != nil {
+		return fmt.Errorf("创建举报失败: %w", err)
+	}
+	return nil
+}
+
+// GetReport 根据ID获取举报
+func (r *moderationRepositoryImpl) GetReport(ctx context.Context, id uuid.UUID) (*entities.Report, error) {
+	var report entities.Report
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("举报不存在")
+		}
+		return nil, fmt.Errorf("获取举报失败: %w", err)
+	}
+	return &report, nil
+}
+
+// HasOpenReport 检查用户是否已举报对象且尚未处理
+func (r *moderationRepositoryImpl) HasOpenReport(ctx context.Context, targetType string, targetID uuid.UUID, reporterID uint) (bool, error) {
+	var count int64
+	if err := dbWithContext(ctx, r.db).Model(&entities.Report{}).
+		Where("target_type = ? AND target_id = ? AND reporter_id = ? AND status = ?",
+			targetType, targetID, reporterID, entities.ReportStatusOpen).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("检查举报失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListReportsByReporter 分页获取用户提交的举报
+func (r *moderationRepositoryImpl) ListReportsByReporter(ctx context.Context, reporterID uint, offset, limit int) ([]*entities.Report, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.Report{}).Where("reporter_id = ?", reporterID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取举报失败: %w", err)
+	}
+	var reports []*entities.Report
+	if err := query.Session(&gorm.Session{}).
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&reports).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取举报失败: %w", err)
+	}
+	return reports, total, nil
+}
+
+// ListReportedTargets 分页获取有待处理举报的对象
+func (r *moderationRepositoryImpl) ListReportedTargets(ctx context.Context, targetType string, offset, limit int) ([]*repositories.ReportedTarget, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.Report{}).Where("status = ?", entities.ReportStatusOpen)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	var total int64
+	if err := dbWithContext(ctx, r.db).
+		Table("(?) AS targets", query.Session(&gorm.Session{}).Select("target_type, target_id").Group("target_type, target_id")).
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取审核队列失败: %w", err)
+	}
+	var targets []*repositories.ReportedTarget
+	if err := query.Session(&gorm.Session{}).
+		Select("target_type, target_id, COUNT(*) AS report_count, MIN(created_at) AS first_reported_at, MAX(created_at) AS last_reported_at").
+		Group("target_type, target_id").
+		Order("report_count DESC, first_reported_at ASC").
+		Offset(offset).Limit(limit).
+		Scan(&targets).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取审核队列失败: %w", err)
+	}
+	return targets, total, nil
+}
+
+// GetOpenReports 获取对象的全部待处理举报
+func (r *moderationRepositoryImpl) GetOpenReports(ctx context.Context, targetType string, targetIDs []uuid.UUID) ([]*entities.Report, error) {
+	var reports []*entities.Report
+	if len(targetIDs) == 0 {
+		return reports, nil
+	}
+	if err := dbWithContext(ctx, r.db).
+		Where("target_type = ? AND target_id IN ? AND status = ?", targetType, targetIDs, entities.ReportStatusOpen).
+		Order("created_at ASC").
+		Find(&reports).Error; err != nil {
+		return nil, fmt.Errorf("获取举报失败: %w", err)
+	}
+	return reports, nil
+}
+
+// ResolveReports 处理对象的全部待处理举报并记录审核操作
+func (r *moderationRepositoryImpl) ResolveReports(ctx context.Context, action *entities.ModerationAction, status entities.ReportStatus) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&entities.Report{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", action.TargetType, action.TargetID, entities.ReportStatusOpen).
+			Updates(map[string]interface{}{
+				"status":      string(status),
+				"resolution":  action.Action,
+				"resolved_by": action.ModeratorID,
+				"resolved_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		action.ReportsResolved = int(result.RowsAffected)
+		return tx.Create(action).Error
+	})
+	if err != nil {
+		return fmt.Errorf("记录审核操作失败: %w", err)
+	}
+	return nil
+}
+
+// ListActions 按条件分页获取审核记录
+func (r *moderationRepositoryImpl) ListActions(ctx context.Context, filter *repositories.ModerationActionFilter) ([]*entities.ModerationAction, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.ModerationAction{})
+	if filter.ModeratorID != nil {
+		query = query.Where("moderator_id = ?", *filter.ModeratorID)
+	}
+	if filter.SubjectUserID != nil {
+		query = query.Where("subject_user_id = ?", *filter.SubjectUserID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取审核记录失败: %w", err)
+	}
+	var actions []*entities.ModerationAction
+	if err := query.Session(&gorm.Session{}).
+		Order("created_at DESC").
+		Offset(filter.Offset).Limit(filter.Limit).
+		Find(&actions).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取审核记录失败: %w", err)
+	}
+	return actions, total, nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// notificationRepositoryImpl 站内通知仓储实现
+type notificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建站内通知仓储
+func NewNotificationRepository(db *gorm.DB) repositories.NotificationRepository {
+	return &notificationRepositoryImpl{db: db}
+}
+
+// CreateBatch 批量创建通知
+func (r *notificationRepositoryImpl) CreateBatch(ctx context.Context, notifications []*entities.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	if err := dbWithContext(ctx, r.db).Create(&notifications).Error; err != nil {
+		return fmt.Errorf("创建通知失败: %w", err)
+	}
+	return nil
+}
+
+// ListByUser 分页获取用户的通知
+func (r *notificationRepositoryImpl) ListByUser(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]*entities.Notification, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取通知失败: %w", err)
+	}
+	var notifications []*entities.Notification
+	if err := query.Session(&gorm.Session{}).
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取通知失败: %w", err)
+	}
+	return notifications, total, nil
+}
+
+// CountUnread 获取用户的未读通知数
+func (r *notificationRepositoryImpl) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := dbWithContext(ctx, r.db).Model(&entities.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("获取未读通知数失败: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead 将用户的指定通知标记为已读
+func (r *notificationRepositoryImpl) MarkRead(ctx context.Context, userID uint, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := dbWithContext(ctx, r.db).Model(&entities.Notification{}).
+		Where("user_id = ? AND id IN ? AND read_at IS NULL", userID, ids).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("标记通知已读失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// MarkAllRead 将用户的全部通知标记为已读
+func (r *notificationRepositoryImpl) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	result := dbWithContext(ctx, r.db).Model(&entities.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("标记通知已读失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
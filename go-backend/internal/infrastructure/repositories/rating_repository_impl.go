@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// ratingTargetTables 评分对象类型对应的数据表
+var ratingTargetTables = map[string]string{
+	string(entities.RatingTargetKnowledgePoint): "knowledge_points",
+	string(entities.RatingTargetLearningPath):   "learning_paths",
+}
+
+// ratingRepositoryImpl 评分仓储实现
+type ratingRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRatingRepository 创建评分仓储
+func NewRatingRepository(db *gorm.DB) repositories.RatingRepository {
+	return &ratingRepositoryImpl{db: db}
+}
+
+// Save 创建或更新评分并更新对象的评分汇总
+func (r *ratingRepositoryImpl) Save(ctx context.Context, rating *entities.Rating) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(rating).Error; err != nil {
+			return err
+		}
+		return refreshRatingAggregate(tx, rating.TargetType, rating.TargetID)
+	})
+	if err != nil {
+		return fmt.Errorf("保存评分失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取评分
+func (r *ratingRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Rating, error) {
+	var rating entities.Rating
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).First(&rating).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.NotFound("评价不存在")
+		}
+		return nil, fmt.Errorf("获取评价失败: %w", err)
+	}
+	return &rating, nil
+}
+
+// GetByUser 获取用户对对象的评分，不存在时返回nil
+func (r *ratingRepositoryImpl) GetByUser(ctx context.Context, targetType string, targetID uuid.UUID, userID uint) (*entities.Rating, error) {
+	var rating entities.Rating
+	err := dbWithContext(ctx, r.db).
+		Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+		First(&rating).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取评分失败: %w", err)
+	}
+	return &rating, nil
+}
+
+// List 按条件分页获取评价
+func (r *ratingRepositoryImpl) List(ctx context.Context, filter *repositories.RatingFilter) ([]*entities.Rating, int64, error) {
+	query := dbWithContext(ctx, r.db).Model(&entities.Rating{})
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Hidden != nil {
+		query = query.Where("hidden = ?", *filter.Hidden)
+	}
+	if filter.WithReview {
+		query = query.Where("review <> ''")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取评价失败: %w", err)
+	}
+	var ratings []*entities.Rating
+	if err := query.Session(&gorm.Session{}).
+		Order("updated_at DESC").
+		Offset(filter.Offset).Limit(filter.Limit).
+		Find(&ratings).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取评价失败: %w", err)
+	}
+	return ratings, total, nil
+}
+
+// GetSummary 获取对象未隐藏评分的汇总
+func (r *ratingRepositoryImpl) GetSummary(ctx context.Context, targetType string, targetID uuid.UUID) (*repositories.RatingSummary, error) {
+	var buckets []struct {
+		Score int
+		Count int64
+	}
+	if err := dbWithContext(ctx, r.db).Model(&entities.Rating{}).
+		Select("score, COUNT(*) AS count").
+		Where("target_type = ? AND target_id = ? AND hidden = ?", targetType, targetID, false).
+		Group("score").
+		Scan(&buckets).Error; err != nil {
+		return nil, fmt.Errorf("获取评分汇总失败: %w", err)
+	}
+
+	summary := &repositories.RatingSummary{Distribution: make(map[int]int64, entities.MaxRatingScore)}
+	for score := entities.MinRatingScore; score <= entities.MaxRatingScore; score++ {
+		summary.Distribution[score] = 0
+	}
+	var sum int64
+	for _, bucket := range buckets {
+		summary.Distribution[bucket.Score] = bucket.Count
+		summary.Count += bucket.Count
+		sum += int64(bucket.Score) * bucket.Count
+	}
+	if summary.Count > 0 {
+		summary.Average = float64(sum) / float64(summary.Count)
+	}
+	return summary, nil
+}
+
+// Delete 删除评分并更新对象的评分汇总
+func (r *ratingRepositoryImpl) Delete(ctx context.Context, rating *entities.Rating) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entities.Rating{}, "id = ?", rating.ID).Error; err != nil {
+			return err
+		}
+		return refreshRatingAggregate(tx, rating.TargetType, rating.TargetID)
+	})
+	if err != nil {
+		return fmt.Errorf("删除评分失败: %w", err)
+	}
+	return nil
+}
+
+// SetHidden 隐藏或恢复评价并更新对象的评分汇总
+func (r *ratingRepositoryImpl) SetHidden(ctx context.Context, rating *entities.Rating) error {
+	err := dbWithContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Rating{}).Where("id = ?", rating.ID).Updates(map[string]interface{}{
+			"hidden":        rating.Hidden,
+			"hidden_by":     rating.HiddenBy,
+			"hidden_reason": rating.HiddenReason,
+			"hidden_at":     rating.HiddenAt,
+		}).Error; err != nil {
+			return err
+		}
+		return refreshRatingAggregate(tx, rating.TargetType, rating.TargetID)
+	})
+	if err != nil {
+		return fmt.Errorf("更新评价状态失败: %w", err)
+	}
+	return nil
+}
+
+// refreshRatingAggregate 根据未隐藏的评分重新计算对象的平均评分与评分人数
+func refreshRatingAggregate(tx *gorm.DB, targetType string, targetID uuid.UUID) error {
+	table, ok := ratingTargetTables[targetType]
+	if !ok {
+		return fmt.Errorf("不支持的评分对象类型: %s", targetType)
+	}
+	return tx.Exec(`UPDATE `+table+` SET
+		average_rating = COALESCE((SELECT ROUND(AVG(score), 2) FROM ratings WHERE target_type = ? AND target_id = ? AND hidden = false), 0),
+		ratings_count = (SELECT COUNT(*) FROM ratings WHERE target_type = ? AND target_id = ? AND hidden = false)
+		WHERE id = ?`,
+		targetType, targetID, targetType, targetID, targetID,
+	).Error
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// txContextKey 上下文中事务连接的键
+type txContextKey struct{}
+
+// transactionManagerImpl 基于gorm的事务管理器
+type transactionManagerImpl struct {
+	db *gorm.DB
+}
+
+// NewTransactionManager 创建事务管理器
+func NewTransactionManager(db *gorm.DB) repositories.TransactionManager {
+	return &transactionManagerImpl{db: db}
+}
+
+// WithinTransaction 在事务中执行fn；外层已有事务时以保存点嵌套
+func (m *transactionManagerImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return dbWithContext(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// dbWithContext 获取ctx中的事务连接，不在事务中时使用db
+func dbWithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -86,6 +86,26 @@ func (r *userRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*en
 	return users, total, err
 }
 
+// GetByIDs 根据ID批量获取用户
+func (r *userRepositoryImpl) GetByIDs(ctx context.Context, ids []uint) ([]*entities.User, error) {
+	var users []*entities.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// GetByUsernames 根据用户名批量获取用户
+func (r *userRepositoryImpl) GetByUsernames(ctx context.Context, usernames []string) ([]*entities.User, error) {
+	var users []*entities.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("username IN ?", usernames).Find(&users).Error
+	return users, err
+}
+
 // Search 搜索用户
 func (r *userRepositoryImpl) Search(ctx context.Context, keyword string, offset, limit int) ([]*entities.User, int64, error) {
 	var users []*entities.User
@@ -164,7 +184,7 @@ func (r *userRepositoryImpl) ExistsByID(ctx context.Context, id uint) (bool, err
 
 // UpdateStatus 更新用户状态
 func (r *userRepositoryImpl) UpdateStatus(ctx context.Context, id uint, status string) error {
-	return r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).Update("status", status).Error
+	return dbWithContext(ctx, r.db).Model(&entities.User{}).Where("id = ?", id).Update("status", status).Error
 }
 
 // UpdateRole 更新用户角色
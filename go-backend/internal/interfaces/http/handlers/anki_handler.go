@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxApkgUploadSize apkg上传大小上限
+const maxApkgUploadSize = 100 << 20
+
+// AnkiHandler Anki牌组处理器
+type AnkiHandler struct {
+	ankiService *services.AnkiService
+}
+
+// NewAnkiHandler 创建Anki牌组处理器
+func NewAnkiHandler(ankiService *services.AnkiService) *AnkiHandler {
+	return &AnkiHandler{
+		ankiService: ankiService,
+	}
+}
+
+// ExportApkg 导出当前用户的闪卡为.apkg；指定learning_path_id时导出该学习路径的全部闪卡
+func (h *AnkiHandler) ExportApkg(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	var buf bytes.Buffer
+	filename := fmt.Sprintf("sical-flashcards-%s.apkg", time.Now().Format("20060102"))
+
+	if pathIDStr := c.Query("learning_path_id"); pathIDStr != "" {
+		pathID, err := uuid.Parse(pathIDStr)
+		if err != nil {
+			logger.Error("路径ID格式无效", logger.String("path_id", pathIDStr))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "路径ID格式无效"})
+			return
+		}
+		if err := h.ankiService.ExportLearningPathFlashcards(c.Request.Context(), pathID, actor, &buf); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "学习路径不存在"})
+				return
+			}
+			logger.Error("导出学习路径闪卡失败", logger.String("error", err.Error()))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "导出闪卡失败"})
+			return
+		}
+		filename = fmt.Sprintf("sical-path-%s.apkg", pathID.String()[:8])
+	} else if err := h.ankiService.ExportUserFlashcards(c.Request.Context(), actor, &buf); err != nil {
+		logger.Error("导出用户闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出闪卡失败"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/octet-stream", buf.Bytes())
+}
+
+// ImportApkg 导入.apkg牌组到指定知识点类别
+func (h *AnkiHandler) ImportApkg(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	category := c.PostForm("category")
+	if category == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少分类参数"})
+		return
+	}
+
+	difficulty := c.DefaultPostForm("difficulty", "intermediate")
+	if difficulty != "beginner" && difficulty != "intermediate" && difficulty != "advanced" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的难度值"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少apkg文件"})
+		return
+	}
+	if fileHeader.Size > maxApkgUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "apkg文件过大"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("读取上传文件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	req := &services.AnkiImportRequest{
+		Actor:      actor,
+		Category:   category,
+		Difficulty: difficulty,
+	}
+	result, err := h.ankiService.ImportPackage(c.Request.Context(), req, file, fileHeader.Size)
+	if err != nil {
+		if errors.Is(err, services.ErrFlashcardForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("导入Anki牌组失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "导入Anki牌组失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
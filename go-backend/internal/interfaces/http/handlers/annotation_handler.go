@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// AnnotationHandler 学习笔记与高亮处理器
+type AnnotationHandler struct {
+	annotationService *services.AnnotationService
+}
+
+// NewAnnotationHandler 创建学习笔记与高亮处理器
+func NewAnnotationHandler(annotationService *services.AnnotationService) *AnnotationHandler {
+	return &AnnotationHandler{annotationService: annotationService}
+}
+
+// CreateAnnotationRequest 创建笔记或高亮请求；位置为知识点可见文本（连续空白折叠为一个空格）中的字符偏移，
+// 也可以只提供引文及其前后文
+type CreateAnnotationRequest struct {
+	Type   string `json:"type" binding:"required,oneof=note highlight"`
+	Body   string `json:"body"`
+	Color  string `json:"color"`
+	Start  *int   `json:"start"`
+	End    *int   `json:"end"`
+	Exact  string `json:"exact"`
+	Prefix string `json:"prefix"`
+	Suffix string `json:"suffix"`
+}
+
+// UpdateAnnotationRequest 修改笔记请求
+type UpdateAnnotationRequest struct {
+	Body  *string `json:"body,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+// AnnotationResponse 笔记响应
+type AnnotationResponse struct {
+	ID                  uuid.UUID `json:"id"`
+	KnowledgePointID    uuid.UUID `json:"knowledge_point_id"`
+	KnowledgePointTitle string    `json:"knowledge_point_title"`
+	Type                string    `json:"type"`
+	Body                string    `json:"body"`
+	BodyHTML            string    `json:"body_html,omitempty"`
+	Color               string    `json:"color,omitempty"`
+	Exact               string    `json:"exact,omitempty"`
+	Prefix              string    `json:"prefix,omitempty"`
+	Suffix              string    `json:"suffix,omitempty"`
+	Start               *int      `json:"start"` // 当前内容中的位置，无法定位时为null
+	End                 *int      `json:"end"`
+	Orphaned            bool      `json:"orphaned"` // 原文已修改，引文无法在当前内容中定位
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// ListKnowledgePointAnnotations 获取当前用户在知识点上的笔记与高亮
+func (h *AnnotationHandler) ListKnowledgePointAnnotations(c *gin.Context) {
+	knowledgePointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+	annotations, err := h.annotationService.ListForKnowledgePoint(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		respondAnnotationError(c, err, "获取笔记失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  toAnnotationResponses(annotations),
+		"count": len(annotations),
+	})
+}
+
+// CreateAnnotation 在知识点上创建笔记或高亮
+func (h *AnnotationHandler) CreateAnnotation(c *gin.Context) {
+	knowledgePointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+	var req CreateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+
+	annotation, err := h.annotationService.CreateAnnotation(c.Request.Context(), knowledgePointID, &services.AnnotationInput{
+		Type:   entities.AnnotationType(req.Type),
+		Body:   req.Body,
+		Color:  req.Color,
+		Start:  req.Start,
+		End:    req.End,
+		Exact:  req.Exact,
+		Prefix: req.Prefix,
+		Suffix: req.Suffix,
+	}, currentActor(c))
+	if err != nil {
+		respondAnnotationError(c, err, "创建笔记失败")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": toAnnotationResponse(annotation)})
+}
+
+// UpdateAnnotation 修改笔记正文或高亮颜色
+func (h *AnnotationHandler) UpdateAnnotation(c *gin.Context) {
+	annotationID, ok := parseAnnotationID(c)
+	if !ok {
+		return
+	}
+	var req UpdateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	annotation, err := h.annotationService.UpdateAnnotation(c.Request.Context(), annotationID, req.Body, req.Color, currentActor(c))
+	if err != nil {
+		respondAnnotationError(c, err, "修改笔记失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toAnnotationResponse(annotation)})
+}
+
+// DeleteAnnotation 删除笔记或高亮
+func (h *AnnotationHandler) DeleteAnnotation(c *gin.Context) {
+	annotationID, ok := parseAnnotationID(c)
+	if !ok {
+		return
+	}
+	if err := h.annotationService.DeleteAnnotation(c.Request.Context(), annotationID, currentActor(c)); err != nil {
+		respondAnnotationError(c, err, "删除笔记失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// ListMyAnnotations 获取当前用户在整个知识库中的笔记与高亮（type、knowledge_point_id、q筛选）
+func (h *AnnotationHandler) ListMyAnnotations(c *gin.Context) {
+	filter, ok := parseAnnotationFilter(c)
+	if !ok {
+		return
+	}
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	filter.Type = c.Query("type")
+	filter.Query = c.Query("q")
+	filter.Offset = offset
+	filter.Limit = limit
+
+	annotations, total, err := h.annotationService.ListMyAnnotations(c.Request.Context(), filter, currentActor(c))
+	if err != nil {
+		respondAnnotationError(c, err, "获取笔记失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  toAnnotationResponses(annotations),
+		"count": len(annotations),
+		"total": total,
+	})
+}
+
+// ExportAnnotations 将当前用户的笔记与高亮导出为Markdown文件（knowledge_point_id筛选）
+func (h *AnnotationHandler) ExportAnnotations(c *gin.Context) {
+	filter, ok := parseAnnotationFilter(c)
+	if !ok {
+		return
+	}
+	content, err := h.annotationService.ExportMarkdown(c.Request.Context(), filter.KnowledgePointID, currentActor(c))
+	if err != nil {
+		respondAnnotationError(c, err, "导出笔记失败")
+		return
+	}
+
+	filename := fmt.Sprintf("notes-%s.md", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(content))
+}
+
+// toAnnotationResponses 批量转换为笔记响应
+func toAnnotationResponses(annotations []*services.AnchoredAnnotation) []*AnnotationResponse {
+	responses := make([]*AnnotationResponse, 0, len(annotations))
+	for _, annotation := range annotations {
+		responses = append(responses, toAnnotationResponse(annotation))
+	}
+	return responses
+}
+
+// toAnnotationResponse 转换为笔记响应
+func toAnnotationResponse(anchored *services.AnchoredAnnotation) *AnnotationResponse {
+	annotation := anchored.Annotation
+	response := &AnnotationResponse{
+		ID:                  annotation.ID,
+		KnowledgePointID:    annotation.KnowledgePointID,
+		KnowledgePointTitle: anchored.KnowledgePoint.Title,
+		Type:                annotation.Type,
+		Body:                annotation.Body,
+		Color:               annotation.Color,
+		Exact:               annotation.Exact,
+		Prefix:              annotation.Prefix,
+		Suffix:              annotation.Suffix,
+		Start:               anchored.Start,
+		End:                 anchored.End,
+		Orphaned:            anchored.Orphaned,
+		CreatedAt:           annotation.CreatedAt,
+		UpdatedAt:           annotation.UpdatedAt,
+	}
+	if annotation.Body != "" {
+		html, err := markdown.Render(annotation.Body)
+		if err != nil {
+			logger.Error("渲染笔记失败", logger.String("annotation_id", annotation.ID.String()), logger.Err(err))
+		}
+		response.BodyHTML = html
+	}
+	return response
+}
+
+// parseAnnotationID 解析路径中的笔记ID
+func parseAnnotationID(c *gin.Context) (uuid.UUID, bool) {
+	annotationID, err := uuid.Parse(c.Param("annotation_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "笔记ID格式无效"})
+		return uuid.Nil, false
+	}
+	return annotationID, true
+}
+
+// parseAnnotationFilter 解析笔记的知识点筛选参数
+func parseAnnotationFilter(c *gin.Context) (*repositories.AnnotationFilter, bool) {
+	filter := &repositories.AnnotationFilter{}
+	if value := c.Query("knowledge_point_id"); value != "" {
+		knowledgePointID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "knowledge_point_id参数无效"})
+			return nil, false
+		}
+		filter.KnowledgePointID = &knowledgePointID
+	}
+	return filter, true
+}
+
+// respondAnnotationError 输出笔记操作的错误响应
+func respondAnnotationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAnnotation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, markdown.ErrUnsafeContent):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKnowledgePointForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/imaging"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/storage"
+)
+
+// AttachmentResponse 附件响应
+type AttachmentResponse struct {
+	ID           string    `json:"id"`
+	URI          string    `json:"uri"` // 在知识点内容中引用附件时使用
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AttachmentHandler 附件处理器
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+	store             storage.Storage
+	gcGracePeriod     time.Duration
+}
+
+// NewAttachmentHandler 创建附件处理器
+func NewAttachmentHandler(attachmentService *services.AttachmentService, store storage.Storage, gcGracePeriod time.Duration) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		store:             store,
+		gcGracePeriod:     gcGracePeriod,
+	}
+}
+
+// UploadAttachment 上传附件（图片或PDF）
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少上传文件"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("读取上传文件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), userID, fileHeader.Filename, file)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+	h.respondAttachment(c, http.StatusCreated, attachment)
+}
+
+// UploadAvatar 上传图片并设置为当前用户头像
+func (h *AttachmentHandler) UploadAvatar(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少上传文件"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("读取上传文件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.SetAvatar(c.Request.Context(), userID, fileHeader.Filename, file)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+	h.respondAttachment(c, http.StatusOK, attachment)
+}
+
+// GetAttachment 获取附件信息及限时下载地址
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "附件ID格式无效"})
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), attachmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "附件不存在"})
+		return
+	}
+	h.respondAttachment(c, http.StatusOK, attachment)
+}
+
+// RedirectContent 重定向到附件的限时签名地址，供渲染后的知识点内容引用；
+// 指定thumbnail=true时优先返回缩略图
+func (h *AttachmentHandler) RedirectContent(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "附件ID格式无效"})
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), attachmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "附件不存在"})
+		return
+	}
+	urls, err := h.attachmentService.SignedURLs(c.Request.Context(), attachment)
+	if err != nil {
+		logger.Error("生成下载地址失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成下载地址失败"})
+		return
+	}
+
+	target := urls.URL
+	if c.Query("thumbnail") == "true" && urls.ThumbnailURL != "" {
+		target = urls.ThumbnailURL
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target)
+}
+
+// ServeFile 通过签名地址下载本地存储中的文件
+func (h *AttachmentHandler) ServeFile(c *gin.Context) {
+	verifier, ok := h.store.(storage.URLVerifier)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "文件不存在"})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || !verifier.VerifySignedURL(key, expires, c.Query("signature")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "下载地址无效或已过期"})
+		return
+	}
+
+	reader, err := h.store.Open(c.Request.Context(), key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			logger.Error("读取文件失败", logger.String("error", err.Error()))
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "文件不存在"})
+		return
+	}
+	defer reader.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age="+strconv.FormatInt(max(0, expires-time.Now().Unix()), 10))
+	c.DataFromReader(http.StatusOK, -1, contentType, reader, nil)
+}
+
+// CollectGarbage 立即清理未引用的附件
+func (h *AttachmentHandler) CollectGarbage(c *gin.Context) {
+	removed, err := h.attachmentService.CollectGarbage(c.Request.Context(), h.gcGracePeriod, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrAttachmentForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("清理附件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "清理附件失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"removed": removed}})
+}
+
+// respondAttachment 返回附件响应
+func (h *AttachmentHandler) respondAttachment(c *gin.Context, status int, attachment *entities.Attachment) {
+	urls, err := h.attachmentService.SignedURLs(c.Request.Context(), attachment)
+	if err != nil {
+		logger.Error("生成下载地址失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成下载地址失败"})
+		return
+	}
+
+	c.JSON(status, gin.H{"data": AttachmentResponse{
+		ID:           attachment.ID.String(),
+		URI:          attachment.URI(),
+		Filename:     attachment.Filename,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+		URL:          urls.URL,
+		ThumbnailURL: urls.ThumbnailURL,
+		ExpiresAt:    urls.ExpiresAt,
+		CreatedAt:    attachment.CreatedAt,
+	}})
+}
+
+// respondUploadError 返回上传错误响应
+func (h *AttachmentHandler) respondUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAttachmentTooLarge), errors.Is(err, imaging.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnsupportedAttachmentType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	default:
+		logger.Error("上传附件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "上传附件失败"})
+	}
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// CollectionHandler 收藏夹处理器
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+// NewCollectionHandler 创建收藏夹处理器
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// CreateCollectionRequest 创建收藏夹请求
+type CreateCollectionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateCollectionRequest 修改收藏夹请求
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// ShareCollectionRequest 开启或关闭链接分享请求
+type ShareCollectionRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// AddCollectionItemRequest 加入收藏请求
+type AddCollectionItemRequest struct {
+	TargetType string    `json:"target_type" binding:"required,oneof=knowledge_point learning_path"`
+	TargetID   uuid.UUID `json:"target_id" binding:"required"`
+}
+
+// ReorderCollectionItemsRequest 调整收藏顺序请求，按新顺序列出全部条目ID
+type ReorderCollectionItemsRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" binding:"required"`
+}
+
+// CloneCollectionRequest 复制分享的收藏夹请求，名称为空时沿用原名称
+type CloneCollectionRequest struct {
+	Name string `json:"name"`
+}
+
+// CollectionResponse 收藏夹响应
+type CollectionResponse struct {
+	ID           uuid.UUID                 `json:"id"`
+	Name         string                    `json:"name"`
+	Description  string                    `json:"description"`
+	ItemsCount   int64                     `json:"items_count"`
+	Shared       bool                      `json:"shared"`
+	ShareToken   string                    `json:"share_token,omitempty"` // 仅所有者可见
+	ClonedFromID *uuid.UUID                `json:"cloned_from_id,omitempty"`
+	Items        []*CollectionItemResponse `json:"items,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+// CollectionItemResponse 收藏条目响应
+type CollectionItemResponse struct {
+	ID            uuid.UUID `json:"id"`
+	TargetType    string    `json:"target_type"`
+	TargetID      uuid.UUID `json:"target_id"`
+	Position      int       `json:"position"`
+	Available     bool      `json:"available"` // 对象已删除或不可见时为false
+	Title         string    `json:"title,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	AverageRating float64   `json:"average_rating,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ListMyCollections 获取当前用户的收藏夹
+func (h *CollectionHandler) ListMyCollections(c *gin.Context) {
+	summaries, err := h.collectionService.ListMyCollections(c.Request.Context(), currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "获取收藏夹失败")
+		return
+	}
+	responses := make([]*CollectionResponse, 0, len(summaries))
+	for _, summary := range summaries {
+		response := toCollectionResponse(summary.Collection, true)
+		response.ItemsCount = summary.ItemsCount
+		responses = append(responses, response)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// ListCollectionsContaining 获取当前用户收藏了指定对象的收藏夹ID（target_type、target_id）
+func (h *CollectionHandler) ListCollectionsContaining(c *gin.Context) {
+	targetID, err := uuid.Parse(c.Query("target_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_id参数无效"})
+		return
+	}
+	ids, err := h.collectionService.ListCollectionsContaining(c.Request.Context(),
+		entities.CollectionItemType(c.Query("target_type")), targetID, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "获取收藏状态失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       ids,
+		"bookmarked": len(ids) > 0,
+	})
+}
+
+// CreateCollection 创建收藏夹
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	collection, err := h.collectionService.CreateCollection(c.Request.Context(), req.Name, req.Description, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "创建收藏夹失败")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": toCollectionResponse(collection, true)})
+}
+
+// GetCollection 获取当前用户的收藏夹及条目
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	detail, err := h.collectionService.GetCollection(c.Request.Context(), collectionID, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "获取收藏夹失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCollectionDetailResponse(detail, true)})
+}
+
+// UpdateCollection 修改收藏夹名称或描述
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	var req UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+	collection, err := h.collectionService.UpdateCollection(c.Request.Context(), collectionID, req.Name, req.Description, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "修改收藏夹失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCollectionResponse(collection, true)})
+}
+
+// DeleteCollection 删除收藏夹
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	if err := h.collectionService.DeleteCollection(c.Request.Context(), collectionID, currentActor(c)); err != nil {
+		respondCollectionError(c, err, "删除收藏夹失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// ShareCollection 开启或关闭收藏夹的只读链接分享
+func (h *CollectionHandler) ShareCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	var req ShareCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+	collection, err := h.collectionService.SetSharing(c.Request.Context(), collectionID, req.Enabled, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "更新分享状态失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCollectionResponse(collection, true)})
+}
+
+// AddCollectionItem 将知识点或学习路径加入收藏夹
+func (h *CollectionHandler) AddCollectionItem(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	var req AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	item, err := h.collectionService.AddItem(c.Request.Context(), collectionID,
+		entities.CollectionItemType(req.TargetType), req.TargetID, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "添加收藏失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+// RemoveCollectionItem 从收藏夹移除条目
+func (h *CollectionHandler) RemoveCollectionItem(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "条目ID格式无效"})
+		return
+	}
+	if err := h.collectionService.RemoveItem(c.Request.Context(), collectionID, itemID, currentActor(c)); err != nil {
+		respondCollectionError(c, err, "移除收藏失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "移除成功"})
+}
+
+// ReorderCollectionItems 调整收藏夹内条目的顺序
+func (h *CollectionHandler) ReorderCollectionItems(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	var req ReorderCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	detail, err := h.collectionService.ReorderItems(c.Request.Context(), collectionID, req.ItemIDs, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "调整收藏顺序失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCollectionDetailResponse(detail, true)})
+}
+
+// GetSharedCollection 通过分享链接只读查看收藏夹
+func (h *CollectionHandler) GetSharedCollection(c *gin.Context) {
+	detail, err := h.collectionService.GetSharedCollection(c.Request.Context(), c.Param("token"), currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "获取收藏夹失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCollectionDetailResponse(detail, false)})
+}
+
+// CloneSharedCollection 将分享的收藏夹复制到当前用户名下
+func (h *CollectionHandler) CloneSharedCollection(c *gin.Context) {
+	var req CloneCollectionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+			return
+		}
+	}
+	detail, err := h.collectionService.CloneSharedCollection(c.Request.Context(), c.Param("token"), req.Name, currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "复制收藏夹失败")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": toCollectionDetailResponse(detail, true)})
+}
+
+// toCollectionResponse 转换为收藏夹响应，owner为true时包含分享令牌
+func toCollectionResponse(collection *entities.Collection, owner bool) *CollectionResponse {
+	response := &CollectionResponse{
+		ID:           collection.ID,
+		Name:         collection.Name,
+		Description:  collection.Description,
+		Shared:       collection.IsShared(),
+		ClonedFromID: collection.ClonedFromID,
+		CreatedAt:    collection.CreatedAt,
+		UpdatedAt:    collection.UpdatedAt,
+	}
+	if owner && collection.IsShared() {
+		response.ShareToken = *collection.ShareToken
+	}
+	return response
+}
+
+// toCollectionDetailResponse 转换为包含条目的收藏夹响应
+func toCollectionDetailResponse(detail *services.CollectionDetail, owner bool) *CollectionResponse {
+	response := toCollectionResponse(detail.Collection, owner)
+	response.ItemsCount = int64(len(detail.Entries))
+	response.Items = make([]*CollectionItemResponse, 0, len(detail.Entries))
+	for _, entry := range detail.Entries {
+		item := &CollectionItemResponse{
+			ID:         entry.Item.ID,
+			TargetType: entry.Item.TargetType,
+			TargetID:   entry.Item.TargetID,
+			Position:   entry.Item.Position,
+			Available:  entry.Available,
+			CreatedAt:  entry.Item.CreatedAt,
+		}
+		switch {
+		case entry.KnowledgePoint != nil:
+			item.Title = entry.KnowledgePoint.Title
+			item.Description = entry.KnowledgePoint.Description
+			item.AverageRating = entry.KnowledgePoint.AverageRating
+		case entry.LearningPath != nil:
+			item.Title = entry.LearningPath.Title
+			item.Description = entry.LearningPath.Description
+			item.AverageRating = entry.LearningPath.AverageRating
+		}
+		response.Items = append(response.Items, item)
+	}
+	return response
+}
+
+// parseCollectionID 解析路径中的收藏夹ID
+func parseCollectionID(c *gin.Context) (uuid.UUID, bool) {
+	collectionID, err := uuid.Parse(c.Param("collection_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "收藏夹ID格式无效"})
+		return uuid.Nil, false
+	}
+	return collectionID, true
+}
+
+// respondCollectionError 输出收藏夹操作的错误响应
+func respondCollectionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCollection):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCollectionForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// 评论分页参数
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 50
+)
+
+// CommentHandler 知识点评论处理器
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+// NewCommentHandler 创建知识点评论处理器
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// SaveCommentRequest 发表或修改评论请求
+type SaveCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// CommentResponse 评论响应；已删除的评论保留在讨论串中，但不返回正文与作者
+type CommentResponse struct {
+	ID               uuid.UUID          `json:"id"`
+	KnowledgePointID uuid.UUID          `json:"knowledge_point_id"`
+	ParentID         *uuid.UUID         `json:"parent_id"`
+	ThreadID         *uuid.UUID         `json:"thread_id"`
+	AuthorID         *uint              `json:"author_id"`
+	AuthorName       string             `json:"author_name,omitempty"`
+	Body             string             `json:"body"`
+	BodyHTML         string             `json:"body_html"`
+	LikesCount       int                `json:"likes_count"`
+	ReplyCount       int                `json:"reply_count"`
+	Liked            bool               `json:"liked"`
+	Edited           bool               `json:"edited"`
+	Deleted          bool               `json:"deleted"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	Replies          []*CommentResponse `json:"replies"`
+}
+
+// ListComments 分页获取知识点的讨论串
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	knowledgePointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+	offset, limit, ok := parseCommentPage(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.commentService.ListComments(c.Request.Context(), knowledgePointID, offset, limit, currentActor(c))
+	if err != nil {
+		respondCommentError(c, err, "获取评论失败")
+		return
+	}
+	responses := make([]*CommentResponse, 0, len(page.Threads))
+	for _, thread := range page.Threads {
+		responses = append(responses, toCommentResponse(thread, page))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+		"total": page.Total,
+	})
+}
+
+// GetComment 获取评论及其回复
+func (h *CommentHandler) GetComment(c *gin.Context) {
+	commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	page, err := h.commentService.GetComment(c.Request.Context(), commentID, currentActor(c))
+	if err != nil {
+		respondCommentError(c, err, "获取评论失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCommentResponse(page.Threads[0], page)})
+}
+
+// CreateComment 在知识点下发表评论
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	knowledgePointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+	req, ok := bindCommentRequest(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentService.AddComment(c.Request.Context(), knowledgePointID, req.Body, currentActor(c))
+	if err != nil {
+		respondCommentError(c, err, "发表评论失败")
+		return
+	}
+	h.respondComment(c, http.StatusCreated, comment.ID)
+}
+
+// ReplyComment 回复评论
+func (h *CommentHandler) ReplyComment(c *gin.Context) {
+	commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	req, ok := bindCommentRequest(c)
+	if !ok {
+		return
+	}
+
+	reply, err := h.commentService.Reply(c.Request.Context(), commentID, req.Body, currentActor(c))
+	if err != nil {
+		respondCommentError(c, err, "回复评论失败")
+		return
+	}
+	h.respondComment(c, http.StatusCreated, reply.ID)
+}
+
+// UpdateComment 修改评论
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	req, ok := bindCommentRequest(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.commentService.UpdateComment(c.Request.Context(), commentID, req.Body, currentActor(c)); err != nil {
+		respondCommentError(c, err, "修改评论失败")
+		return
+	}
+	h.respondComment(c, http.StatusOK, commentID)
+}
+
+// DeleteComment 删除评论
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	if err := h.commentService.DeleteComment(c.Request.Context(), commentID, currentActor(c)); err != nil {
+		respondCommentError(c, err, "删除评论失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// LikeComment 点赞或取消点赞评论
+func (h *CommentHandler) LikeComment(c *gin.Context) {
+	commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	liked, count, err := h.commentService.ToggleLike(c.Request.Context(), commentID, currentActor(c))
+	if err != nil {
+		respondCommentError(c, err, "点赞评论失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"liked": liked, "likes_count": count}})
+}
+
+// respondComment 重新获取评论并输出完整的评论响应
+func (h *CommentHandler) respondComment(c *gin.Context, status int, commentID uuid.UUID) {
+	page, err := h.commentService.GetComment(c.Request.Context(), commentID, currentActor(c))
+	if err != nil {
+		respondCommentError(c, err, "获取评论失败")
+		return
+	}
+	c.JSON(status, gin.H{"data": toCommentResponse(page.Threads[0], page)})
+}
+
+// toCommentResponse 转换为评论响应，递归转换回复
+func toCommentResponse(node *services.CommentNode, page *services.CommentPage) *CommentResponse {
+	comment := node.Comment
+	response := &CommentResponse{
+		ID:               comment.ID,
+		KnowledgePointID: comment.KnowledgePointID,
+		ParentID:         comment.ParentID,
+		ThreadID:         comment.ThreadID,
+		LikesCount:       comment.LikesCount,
+		ReplyCount:       comment.ReplyCount,
+		Liked:            page.Liked[comment.ID],
+		Edited:           comment.EditedAt != nil,
+		Deleted:          comment.IsDeleted(),
+		CreatedAt:        comment.CreatedAt,
+		UpdatedAt:        comment.UpdatedAt,
+		Replies:          make([]*CommentResponse, 0, len(node.Replies)),
+	}
+	if !comment.IsDeleted() {
+		authorID := comment.AuthorID
+		response.AuthorID = &authorID
+		if author, ok := page.Authors[comment.AuthorID]; ok {
+			response.AuthorName = author.Username
+		}
+		response.Body = comment.Body
+		html, err := markdown.Render(comment.Body)
+		if err != nil {
+			logger.Error("渲染评论失败", logger.String("comment_id", comment.ID.String()), logger.Err(err))
+		}
+		response.BodyHTML = html
+	}
+	for _, reply := range node.Replies {
+		response.Replies = append(response.Replies, toCommentResponse(reply, page))
+	}
+	return response
+}
+
+// bindCommentRequest 绑定评论请求
+func bindCommentRequest(c *gin.Context) (*SaveCommentRequest, bool) {
+	var req SaveCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请提供评论内容"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// parseCommentID 解析路径中的评论ID
+func parseCommentID(c *gin.Context) (uuid.UUID, bool) {
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评论ID格式无效"})
+		return uuid.Nil, false
+	}
+	return commentID, true
+}
+
+// parseCommentPage 解析评论分页参数
+func parseCommentPage(c *gin.Context) (int, int, bool) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset参数无效"})
+		return 0, 0, false
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultCommentLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit参数无效"})
+		return 0, 0, false
+	}
+	if limit > maxCommentLimit {
+		limit = maxCommentLimit
+	}
+	return offset, limit, true
+}
+
+// respondCommentError 输出评论操作的错误响应
+func respondCommentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidComment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, markdown.ErrUnsafeContent):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCommentForbidden), errors.Is(err, services.ErrKnowledgePointForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// EnrollmentHandler 公开学习路径报名处理器
+type EnrollmentHandler struct {
+	enrollmentService *services.EnrollmentService
+}
+
+// NewEnrollmentHandler 创建公开学习路径报名处理器
+func NewEnrollmentHandler(enrollmentService *services.EnrollmentService) *EnrollmentHandler {
+	return &EnrollmentHandler{enrollmentService: enrollmentService}
+}
+
+// SetPathVisibilityRequest 设置学习路径公开状态请求
+type SetPathVisibilityRequest struct {
+	IsPublic *bool `json:"is_public" binding:"required"`
+}
+
+// StudyTimeRequest 学习时长请求
+type StudyTimeRequest struct {
+	TimeSpent int `json:"time_spent" binding:"min=0"` // 本次学习时长(秒)
+}
+
+// PublicPathResponse 公开学习路径响应
+type PublicPathResponse struct {
+	PathResponse
+	EnrolledCount  int64 `json:"enrolled_count"`
+	CompletedCount int64 `json:"completed_count"`
+}
+
+// EnrollmentResponse 报名记录响应
+type EnrollmentResponse struct {
+	ID             uuid.UUID     `json:"id"`
+	LearningPathID uuid.UUID     `json:"learning_path_id"`
+	Path           *PathResponse `json:"path,omitempty"`
+	Status         string        `json:"status"`
+	EnrolledAt     time.Time     `json:"enrolled_at"`
+	StartedAt      *time.Time    `json:"started_at"`
+	CompletedAt    *time.Time    `json:"completed_at"`
+	LastActivityAt *time.Time    `json:"last_activity_at"`
+	TimeSpent      int           `json:"time_spent"` // 累计学习时长(秒)
+	TotalSteps     int64         `json:"total_steps"`
+	CompletedSteps int64         `json:"completed_steps"`
+	Progress       float64       `json:"progress"` // 完成百分比
+}
+
+// EnrollmentProgressResponse 报名进度响应
+type EnrollmentProgressResponse struct {
+	EnrollmentResponse
+	Steps []EnrollmentStepResponse `json:"steps"`
+}
+
+// EnrollmentStepResponse 步骤进度响应
+type EnrollmentStepResponse struct {
+	Position         int                     `json:"position"`
+	KnowledgePointID uuid.UUID               `json:"knowledge_point_id"`
+	KnowledgePoint   *KnowledgePointResponse `json:"knowledge_point,omitempty"`
+	Status           string                  `json:"status"`
+	StartedAt        *time.Time              `json:"started_at"`
+	CompletedAt      *time.Time              `json:"completed_at"`
+	TimeSpent        int                     `json:"time_spent"` // 学习时长(秒)
+}
+
+// ListPublicPaths 获取公开学习路径目录（q搜索）
+func (h *EnrollmentHandler) ListPublicPaths(c *gin.Context) {
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	paths, total, err := h.enrollmentService.ListPublicPaths(c.Request.Context(), c.Query("q"), offset, limit)
+	if err != nil {
+		respondEnrollmentError(c, err, "获取公开学习路径失败")
+		return
+	}
+	responses := make([]PublicPathResponse, 0, len(paths))
+	for _, path := range paths {
+		responses = append(responses, PublicPathResponse{
+			PathResponse:   toPathResponse(path.Path),
+			EnrolledCount:  path.Enrolled,
+			CompletedCount: path.Completed,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+		"total": total,
+	})
+}
+
+// SetPathVisibility 设置学习路径是否公开
+func (h *EnrollmentHandler) SetPathVisibility(c *gin.Context) {
+	pathID, ok := parsePathID(c)
+	if !ok {
+		return
+	}
+	var req SetPathVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	path, err := h.enrollmentService.SetPathPublic(c.Request.Context(), pathID, *req.IsPublic, currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "设置学习路径公开状态失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toPathResponse(path)})
+}
+
+// Enroll 报名公开学习路径
+func (h *EnrollmentHandler) Enroll(c *gin.Context) {
+	pathID, ok := parsePathID(c)
+	if !ok {
+		return
+	}
+	progress, err := h.enrollmentService.Enroll(c.Request.Context(), pathID, currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "报名学习路径失败")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": toEnrollmentProgressResponse(progress)})
+}
+
+// Unenroll 取消报名，学习进度一并删除
+func (h *EnrollmentHandler) Unenroll(c *gin.Context) {
+	pathID, ok := parsePathID(c)
+	if !ok {
+		return
+	}
+	if err := h.enrollmentService.Unenroll(c.Request.Context(), pathID, currentActor(c)); err != nil {
+		respondEnrollmentError(c, err, "取消报名失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已取消报名"})
+}
+
+// GetProgress 获取当前用户在学习路径上的进度
+func (h *EnrollmentHandler) GetProgress(c *gin.Context) {
+	pathID, ok := parsePathID(c)
+	if !ok {
+		return
+	}
+	progress, err := h.enrollmentService.GetProgress(c.Request.Context(), pathID, currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "获取学习进度失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEnrollmentProgressResponse(progress)})
+}
+
+// StartStep 开始学习步骤
+func (h *EnrollmentHandler) StartStep(c *gin.Context) {
+	pathID, knowledgePointID, ok := parseStepParams(c)
+	if !ok {
+		return
+	}
+	progress, err := h.enrollmentService.StartStep(c.Request.Context(), pathID, knowledgePointID, currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "更新学习进度失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEnrollmentProgressResponse(progress)})
+}
+
+// CompleteStep 完成学习步骤，可附带本次学习时长
+func (h *EnrollmentHandler) CompleteStep(c *gin.Context) {
+	pathID, knowledgePointID, ok := parseStepParams(c)
+	if !ok {
+		return
+	}
+	var req StudyTimeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+			return
+		}
+	}
+	progress, err := h.enrollmentService.CompleteStep(c.Request.Context(), pathID, knowledgePointID, req.TimeSpent, currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "更新学习进度失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEnrollmentProgressResponse(progress)})
+}
+
+// RecordStudyTime 记录步骤的学习时长
+func (h *EnrollmentHandler) RecordStudyTime(c *gin.Context) {
+	pathID, knowledgePointID, ok := parseStepParams(c)
+	if !ok {
+		return
+	}
+	var req StudyTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	progress, err := h.enrollmentService.RecordStudyTime(c.Request.Context(), pathID, knowledgePointID, req.TimeSpent, currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "记录学习时长失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEnrollmentProgressResponse(progress)})
+}
+
+// ListMyEnrollments 获取当前用户的报名记录（status筛选）
+func (h *EnrollmentHandler) ListMyEnrollments(c *gin.Context) {
+	summaries, err := h.enrollmentService.ListMyEnrollments(c.Request.Context(), c.Query("status"), currentActor(c))
+	if err != nil {
+		respondEnrollmentError(c, err, "获取报名记录失败")
+		return
+	}
+	responses := make([]EnrollmentResponse, 0, len(summaries))
+	for _, summary := range summaries {
+		response := toEnrollmentResponse(summary.Enrollment, summary.TotalSteps, summary.CompletedSteps)
+		if summary.Path != nil {
+			path := toPathResponse(summary.Path)
+			response.Path = &path
+		}
+		responses = append(responses, response)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// toEnrollmentResponse 转换为报名记录响应
+func toEnrollmentResponse(enrollment *entities.PathEnrollment, total, completed int64) EnrollmentResponse {
+	response := EnrollmentResponse{
+		ID:             enrollment.ID,
+		LearningPathID: enrollment.LearningPathID,
+		Status:         enrollment.Status,
+		EnrolledAt:     enrollment.CreatedAt,
+		StartedAt:      enrollment.StartedAt,
+		CompletedAt:    enrollment.CompletedAt,
+		LastActivityAt: enrollment.LastActivityAt,
+		TimeSpent:      enrollment.TimeSpent,
+		TotalSteps:     total,
+		CompletedSteps: completed,
+	}
+	if total > 0 {
+		response.Progress = float64(completed) * 100 / float64(total)
+	}
+	return response
+}
+
+// toEnrollmentProgressResponse 转换为报名进度响应
+func toEnrollmentProgressResponse(progress *services.EnrollmentProgress) EnrollmentProgressResponse {
+	response := EnrollmentProgressResponse{
+		EnrollmentResponse: toEnrollmentResponse(progress.Enrollment, int64(len(progress.Steps)), int64(progress.CompletedSteps)),
+		Steps:              make([]EnrollmentStepResponse, 0, len(progress.Steps)),
+	}
+	if progress.Path != nil {
+		path := toPathResponse(progress.Path)
+		path.KnowledgePoints = nil
+		response.Path = &path
+	}
+	for _, item := range progress.Steps {
+		step := EnrollmentStepResponse{
+			Position:         item.Step.Position,
+			KnowledgePointID: item.Step.KnowledgePointID,
+			Status:           item.Step.Status,
+			StartedAt:        item.Step.StartedAt,
+			CompletedAt:      item.Step.CompletedAt,
+			TimeSpent:        item.Step.TimeSpent,
+		}
+		if kp := item.KnowledgePoint; kp != nil {
+			step.KnowledgePoint = &KnowledgePointResponse{
+				ID:          kp.ID.String(),
+				Title:       kp.Title,
+				Description: kp.Description,
+				Category:    kp.Category,
+				Difficulty:  kp.Difficulty,
+			}
+		}
+		response.Steps = append(response.Steps, step)
+	}
+	return response
+}
+
+// parsePathID 解析路径中的学习路径ID
+func parsePathID(c *gin.Context) (uuid.UUID, bool) {
+	pathID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "路径ID格式无效"})
+		return uuid.Nil, false
+	}
+	return pathID, true
+}
+
+// parseStepParams 解析路径中的学习路径ID与知识点ID
+func parseStepParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	pathID, ok := parsePathID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	knowledgePointID, err := uuid.Parse(c.Param("knowledge_point_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return pathID, knowledgePointID, true
+}
+
+// respondEnrollmentError 输出报名操作的错误响应
+func respondEnrollmentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidEnrollment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEnrollmentForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyEnrolled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// FlashcardHandler 闪卡处理器
+type FlashcardHandler struct {
+	flashcardService *services.FlashcardService
+}
+
+// NewFlashcardHandler 创建闪卡处理器
+func NewFlashcardHandler(flashcardService *services.FlashcardService) *FlashcardHandler {
+	return &FlashcardHandler{
+		flashcardService: flashcardService,
+	}
+}
+
+// CreateFlashcardRequest 创建闪卡请求
+type CreateFlashcardRequest struct {
+	CardType string `json:"card_type" binding:"omitempty,oneof=basic cloze"`
+	Front    string `json:"front" binding:"required"`
+	Back     string `json:"back"`
+}
+
+// UpdateFlashcardRequest 更新闪卡请求
+type UpdateFlashcardRequest struct {
+	Front      *string `json:"front,omitempty"`
+	Back       *string `json:"back,omitempty"`
+	ClozeIndex *int    `json:"cloze_index,omitempty"`
+}
+
+// ReviewFlashcardRequest 复习闪卡请求
+type ReviewFlashcardRequest struct {
+	Rating int `json:"rating" binding:"required,min=1,max=4"` // 1=again 2=hard 3=good 4=easy
+}
+
+// CheckClozeAnswerRequest 挖空作答请求，answers按出现顺序对应各个挖空
+type CheckClozeAnswerRequest struct {
+	Answers []string `json:"answers" binding:"required"`
+}
+
+// FlashcardResponse 闪卡响应
+type FlashcardResponse struct {
+	ID               string    `json:"id"`
+	KnowledgePointID string    `json:"knowledge_point_id"`
+	CardType         string    `json:"card_type"`
+	Front            string    `json:"front"`
+	Back             string    `json:"back"`
+	ClozeIndex       int       `json:"cloze_index,omitempty"`
+	Question         string    `json:"question"`
+	Answer           string    `json:"answer"`
+	CreatedBy        uint      `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// FlashcardReviewResponse 闪卡调度状态响应
+type FlashcardReviewResponse struct {
+	FlashcardID    string             `json:"flashcard_id"`
+	EaseFactor     float64            `json:"ease_factor"`
+	Interval       int                `json:"interval"`
+	Repetitions    int                `json:"repetitions"`
+	Lapses         int                `json:"lapses"`
+	DueAt          time.Time          `json:"due_at"`
+	LastReviewedAt *time.Time         `json:"last_reviewed_at"`
+	Flashcard      *FlashcardResponse `json:"flashcard,omitempty"`
+}
+
+// CreateFlashcard 为知识点创建闪卡
+func (h *FlashcardHandler) CreateFlashcard(c *gin.Context) {
+	knowledgePointID, ok := h.parseUUIDParam(c, "id", "知识点ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req CreateFlashcardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	card := &entities.Flashcard{
+		KnowledgePointID: knowledgePointID,
+		CardType:         req.CardType,
+		Front:            req.Front,
+		Back:             req.Back,
+	}
+
+	cards, err := h.flashcardService.CreateFlashcards(c.Request.Context(), card, currentActor(c))
+	if err != nil {
+		if h.respondForbidden(c, err) {
+			return
+		}
+		logger.Error("创建闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "创建闪卡失败"})
+		return
+	}
+
+	logger.Info("闪卡创建成功",
+		logger.String("knowledge_point_id", knowledgePointID.String()),
+		logger.Int("cards_count", len(cards)))
+	c.JSON(http.StatusCreated, gin.H{
+		"data":  h.convertToFlashcardResponses(cards),
+		"count": len(cards),
+	})
+}
+
+// GenerateClozeFlashcards 根据知识点内容中的挖空标记批量生成闪卡
+func (h *FlashcardHandler) GenerateClozeFlashcards(c *gin.Context) {
+	knowledgePointID, ok := h.parseUUIDParam(c, "id", "知识点ID格式无效")
+	if !ok {
+		return
+	}
+
+	cards, err := h.flashcardService.GenerateClozeCards(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		if h.respondForbidden(c, err) {
+			return
+		}
+		logger.Error("生成挖空闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成挖空闪卡失败"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":  h.convertToFlashcardResponses(cards),
+		"count": len(cards),
+	})
+}
+
+// GetKnowledgePointFlashcards 获取知识点下的闪卡
+func (h *FlashcardHandler) GetKnowledgePointFlashcards(c *gin.Context) {
+	knowledgePointID, ok := h.parseUUIDParam(c, "id", "知识点ID格式无效")
+	if !ok {
+		return
+	}
+
+	cards, err := h.flashcardService.GetFlashcardsByKnowledgePoint(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		if h.respondNotFound(c, err, "知识点不存在") {
+			return
+		}
+		logger.Error("获取知识点闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取闪卡失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  h.convertToFlashcardResponses(cards),
+		"count": len(cards),
+	})
+}
+
+// StudyKnowledgePoint 将知识点下的闪卡加入当前用户的复习计划
+func (h *FlashcardHandler) StudyKnowledgePoint(c *gin.Context) {
+	knowledgePointID, ok := h.parseUUIDParam(c, "id", "知识点ID格式无效")
+	if !ok {
+		return
+	}
+
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	added, err := h.flashcardService.StudyKnowledgePoint(c.Request.Context(), knowledgePointID, actor)
+	if err != nil {
+		if h.respondNotFound(c, err, "知识点不存在") {
+			return
+		}
+		logger.Error("加入复习计划失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "加入复习计划失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"added": added}})
+}
+
+// GetFlashcard 获取单张闪卡
+func (h *FlashcardHandler) GetFlashcard(c *gin.Context) {
+	cardID, ok := h.parseUUIDParam(c, "id", "闪卡ID格式无效")
+	if !ok {
+		return
+	}
+
+	card, err := h.flashcardService.GetFlashcard(c.Request.Context(), cardID, currentActor(c))
+	if err != nil {
+		logger.Error("获取闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "闪卡不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToFlashcardResponse(card)})
+}
+
+// UpdateFlashcard 更新闪卡
+func (h *FlashcardHandler) UpdateFlashcard(c *gin.Context) {
+	cardID, ok := h.parseUUIDParam(c, "id", "闪卡ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req UpdateFlashcardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	card, err := h.flashcardService.GetFlashcard(c.Request.Context(), cardID, currentActor(c))
+	if err != nil {
+		logger.Error("获取闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "闪卡不存在"})
+		return
+	}
+
+	if req.Front != nil {
+		card.Front = *req.Front
+	}
+	if req.Back != nil {
+		card.Back = *req.Back
+	}
+	if req.ClozeIndex != nil && card.IsCloze() {
+		card.ClozeIndex = *req.ClozeIndex
+	}
+
+	if err := h.flashcardService.UpdateFlashcard(c.Request.Context(), card, currentActor(c)); err != nil {
+		if h.respondForbidden(c, err) {
+			return
+		}
+		logger.Error("更新闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "更新闪卡失败"})
+		return
+	}
+
+	logger.Info("闪卡更新成功", logger.String("flashcard_id", card.ID.String()))
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToFlashcardResponse(card)})
+}
+
+// DeleteFlashcard 删除闪卡
+func (h *FlashcardHandler) DeleteFlashcard(c *gin.Context) {
+	cardID, ok := h.parseUUIDParam(c, "id", "闪卡ID格式无效")
+	if !ok {
+		return
+	}
+
+	if err := h.flashcardService.DeleteFlashcard(c.Request.Context(), cardID, currentActor(c)); err != nil {
+		if h.respondForbidden(c, err) {
+			return
+		}
+		logger.Error("删除闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除闪卡失败"})
+		return
+	}
+
+	logger.Info("闪卡删除成功", logger.String("flashcard_id", cardID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetDueFlashcards 获取当前用户到期需要复习的闪卡
+func (h *FlashcardHandler) GetDueFlashcards(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 20
+	}
+
+	reviews, err := h.flashcardService.GetDueFlashcards(c.Request.Context(), actor.UserID, limit)
+	if err != nil {
+		logger.Error("获取到期闪卡失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取到期闪卡失败"})
+		return
+	}
+
+	var responses []FlashcardReviewResponse
+	for _, review := range reviews {
+		responses = append(responses, h.convertToFlashcardReviewResponse(review))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// ReviewFlashcard 提交闪卡复习结果
+func (h *FlashcardHandler) ReviewFlashcard(c *gin.Context) {
+	cardID, ok := h.parseUUIDParam(c, "id", "闪卡ID格式无效")
+	if !ok {
+		return
+	}
+
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	var req ReviewFlashcardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	review, err := h.flashcardService.ReviewFlashcard(c.Request.Context(), cardID, entities.ReviewRating(req.Rating), actor)
+	if err != nil {
+		if h.respondNotFound(c, err, "闪卡不存在") {
+			return
+		}
+		logger.Error("提交复习结果失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交复习结果失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToFlashcardReviewResponse(review)})
+}
+
+// CheckClozeAnswer 判定挖空卡片的作答，返回每个挖空的结果与建议评分
+func (h *FlashcardHandler) CheckClozeAnswer(c *gin.Context) {
+	cardID, ok := h.parseUUIDParam(c, "id", "闪卡ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req CheckClozeAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	result, err := h.flashcardService.CheckClozeAnswer(c.Request.Context(), cardID, req.Answers, currentActor(c))
+	if err != nil {
+		logger.Error("判定挖空作答失败", logger.String("error", err.Error()))
+		if errors.Is(err, services.ErrInvalidClozeAnswer) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "闪卡不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// respondForbidden 无权编辑时写入403响应并返回true
+func (h *FlashcardHandler) respondForbidden(c *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrFlashcardForbidden) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	return true
+}
+
+// respondNotFound 记录不存在或不可见时写入404响应并返回true
+func (h *FlashcardHandler) respondNotFound(c *gin.Context, err error, message string) bool {
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return false
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": message})
+	return true
+}
+
+// parseUUIDParam 解析路径中的UUID参数，失败时直接写入错误响应
+func (h *FlashcardHandler) parseUUIDParam(c *gin.Context, name, message string) (uuid.UUID, bool) {
+	idStr := c.Param(name)
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error(message, logger.String(name, idStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// convertToFlashcardResponse 转换为闪卡响应
+func (h *FlashcardHandler) convertToFlashcardResponse(card *entities.Flashcard) FlashcardResponse {
+	return FlashcardResponse{
+		ID:               card.ID.String(),
+		KnowledgePointID: card.KnowledgePointID.String(),
+		CardType:         card.CardType,
+		Front:            card.Front,
+		Back:             card.Back,
+		ClozeIndex:       card.ClozeIndex,
+		Question:         card.Question(),
+		Answer:           card.Answer(),
+		CreatedBy:        card.CreatedBy,
+		CreatedAt:        card.CreatedAt,
+		UpdatedAt:        card.UpdatedAt,
+	}
+}
+
+// convertToFlashcardResponses 批量转换为闪卡响应
+func (h *FlashcardHandler) convertToFlashcardResponses(cards []*entities.Flashcard) []FlashcardResponse {
+	responses := make([]FlashcardResponse, 0, len(cards))
+	for _, card := range cards {
+		responses = append(responses, h.convertToFlashcardResponse(card))
+	}
+	return responses
+}
+
+// convertToFlashcardReviewResponse 转换为闪卡调度状态响应
+func (h *FlashcardHandler) convertToFlashcardReviewResponse(review *entities.FlashcardReview) FlashcardReviewResponse {
+	response := FlashcardReviewResponse{
+		FlashcardID:    review.FlashcardID.String(),
+		EaseFactor:     review.EaseFactor,
+		Interval:       review.Interval,
+		Repetitions:    review.Repetitions,
+		Lapses:         review.Lapses,
+		DueAt:          review.DueAt,
+		LastReviewedAt: review.LastReviewedAt,
+	}
+	if review.Flashcard.ID != uuid.Nil {
+		card := h.convertToFlashcardResponse(&review.Flashcard)
+		response.Flashcard = &card
+	}
+	return response
+}
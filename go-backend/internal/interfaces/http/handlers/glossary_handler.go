@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// 术语表分页参数
+const (
+	defaultGlossaryLimit = 50
+	maxGlossaryLimit     = 200
+)
+
+// GlossaryHandler 术语表处理器
+type GlossaryHandler struct {
+	glossaryService *services.GlossaryService
+}
+
+// NewGlossaryHandler 创建术语表处理器
+func NewGlossaryHandler(glossaryService *services.GlossaryService) *GlossaryHandler {
+	return &GlossaryHandler{glossaryService: glossaryService}
+}
+
+// GlossaryTermRequest 术语请求
+type GlossaryTermRequest struct {
+	Term          string   `json:"term" binding:"required,min=1,max=255"`
+	Definition    string   `json:"definition"`
+	Synonyms      []string `json:"synonyms"`
+	Abbreviations []string `json:"abbreviations"`
+	Category      string   `json:"category" binding:"max=100"`
+}
+
+// GlossaryTermResponse 术语响应
+type GlossaryTermResponse struct {
+	ID            string    `json:"id"`
+	Term          string    `json:"term"`
+	Definition    string    `json:"definition"`
+	Synonyms      []string  `json:"synonyms"`
+	Abbreviations []string  `json:"abbreviations"`
+	Category      string    `json:"category"`
+	CreatedBy     *uint     `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ListTerms 分页获取术语，支持按名称、释义模糊查询与按分类筛选
+func (h *GlossaryHandler) ListTerms(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultGlossaryLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultGlossaryLimit
+	}
+	if limit > maxGlossaryLimit {
+		limit = maxGlossaryLimit
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	terms, total, err := h.glossaryService.ListTerms(c.Request.Context(), &repositories.GlossaryFilter{
+		Query:    c.Query("q"),
+		Category: c.Query("category"),
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		logger.Error("获取术语列表失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取术语列表失败"})
+		return
+	}
+
+	responses := make([]GlossaryTermResponse, 0, len(terms))
+	for _, term := range terms {
+		responses = append(responses, h.convertToGlossaryTermResponse(term))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetTerm 获取术语
+func (h *GlossaryHandler) GetTerm(c *gin.Context) {
+	termID, ok := h.parseTermID(c)
+	if !ok {
+		return
+	}
+
+	term, err := h.glossaryService.GetTerm(c.Request.Context(), termID)
+	if err != nil {
+		logger.Error("获取术语失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "术语不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToGlossaryTermResponse(term)})
+}
+
+// CreateTerm 创建术语
+func (h *GlossaryHandler) CreateTerm(c *gin.Context) {
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	term := &entities.GlossaryTerm{ID: uuid.New()}
+	req.applyTo(term)
+	if err := h.glossaryService.CreateTerm(c.Request.Context(), term, currentActor(c)); err != nil {
+		h.respondGlossaryError(c, "创建术语失败", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.convertToGlossaryTermResponse(term)})
+}
+
+// UpdateTerm 更新术语
+func (h *GlossaryHandler) UpdateTerm(c *gin.Context) {
+	termID, ok := h.parseTermID(c)
+	if !ok {
+		return
+	}
+
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	term, err := h.glossaryService.GetTerm(c.Request.Context(), termID)
+	if err != nil {
+		logger.Error("获取术语失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "术语不存在"})
+		return
+	}
+
+	previousNames := term.Names()
+	req.applyTo(term)
+	if err := h.glossaryService.UpdateTerm(c.Request.Context(), term, previousNames, currentActor(c)); err != nil {
+		h.respondGlossaryError(c, "更新术语失败", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToGlossaryTermResponse(term)})
+}
+
+// DeleteTerm 删除术语
+func (h *GlossaryHandler) DeleteTerm(c *gin.Context) {
+	termID, ok := h.parseTermID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.glossaryService.DeleteTerm(c.Request.Context(), termID, currentActor(c)); err != nil {
+		logger.Error("删除术语失败", logger.String("error", err.Error()))
+		if errors.Is(err, services.ErrGlossaryForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "术语不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// parseTermID 解析路径中的术语ID，失败时直接写入错误响应
+func (h *GlossaryHandler) parseTermID(c *gin.Context) (uuid.UUID, bool) {
+	termID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "术语ID格式无效"})
+		return uuid.Nil, false
+	}
+	return termID, true
+}
+
+// respondGlossaryError 返回术语写操作的错误响应
+func (h *GlossaryHandler) respondGlossaryError(c *gin.Context, message string, err error) {
+	logger.Error(message, logger.String("error", err.Error()))
+	switch {
+	case errors.Is(err, services.ErrGlossaryForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrGlossaryNameConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": message + ": " + err.Error()})
+}
+
+// applyTo 将请求内容写入术语
+func (r *GlossaryTermRequest) applyTo(term *entities.GlossaryTerm) {
+	term.Term = r.Term
+	term.Definition = r.Definition
+	term.Category = r.Category
+	term.SetSynonyms(r.Synonyms)
+	term.SetAbbreviations(r.Abbreviations)
+}
+
+// convertToGlossaryTermResponse 转换为术语响应
+func (h *GlossaryHandler) convertToGlossaryTermResponse(term *entities.GlossaryTerm) GlossaryTermResponse {
+	return GlossaryTermResponse{
+		ID:            term.ID.String(),
+		Term:          term.Term,
+		Definition:    term.Definition,
+		Synonyms:      term.SynonymList(),
+		Abbreviations: term.AbbreviationList(),
+		Category:      term.Category,
+		CreatedBy:     term.CreatedBy,
+		CreatedAt:     term.CreatedAt,
+		UpdatedAt:     term.UpdatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/kbbundle"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxBundleUploadSize 导出包上传大小上限
+const maxBundleUploadSize = 200 << 20
+
+// KnowledgeBundleHandler 知识库导出包处理器
+type KnowledgeBundleHandler struct {
+	bundleService *services.KnowledgeBundleService
+}
+
+// NewKnowledgeBundleHandler 创建知识库导出包处理器
+func NewKnowledgeBundleHandler(bundleService *services.KnowledgeBundleService) *KnowledgeBundleHandler {
+	return &KnowledgeBundleHandler{
+		bundleService: bundleService,
+	}
+}
+
+// ExportBundle 导出知识库；指定category时仅导出该类别
+func (h *KnowledgeBundleHandler) ExportBundle(c *gin.Context) {
+	category := c.Query("category")
+
+	var buf bytes.Buffer
+	if err := h.bundleService.Export(c.Request.Context(), category, &buf, currentActor(c)); err != nil {
+		if errors.Is(err, services.ErrKnowledgeBundleForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("导出知识库失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出知识库失败"})
+		return
+	}
+
+	filename := fmt.Sprintf("sical-knowledge-v%d-%s.zip", kbbundle.SchemaVersion, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// GetBundleSchema 获取导出包的JSON Schema
+func (h *KnowledgeBundleHandler) GetBundleSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", kbbundle.Schema)
+}
+
+// ImportBundle 导入知识库导出包，dry_run=true时仅校验并返回变更统计
+func (h *KnowledgeBundleHandler) ImportBundle(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run参数无效"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少导出包文件"})
+		return
+	}
+	if fileHeader.Size > maxBundleUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "导出包文件过大"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("读取上传文件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	bundle, err := kbbundle.Read(file, fileHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.bundleService.Import(c.Request.Context(), bundle, dryRun, actor)
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgeBundleForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("导入知识库导出包失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导入知识库导出包失败"})
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{"data": report})
+}
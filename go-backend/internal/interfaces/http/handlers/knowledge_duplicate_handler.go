@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// MergeKnowledgePointRequest 合并知识点请求
+type MergeKnowledgePointRequest struct {
+	DuplicateID string `json:"duplicate_id" binding:"required"` // 被合并并删除的知识点
+}
+
+// DuplicateClusterMember 重复簇中的知识点
+type DuplicateClusterMember struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Category    string  `json:"category"`
+	Status      string  `json:"status"`
+	AuthorID    *uint   `json:"author_id"`
+	ExternalKey *string `json:"external_key,omitempty"`
+}
+
+// DuplicateClusterResponse 重复簇响应
+type DuplicateClusterResponse struct {
+	Points []DuplicateClusterMember `json:"points"`
+	Pairs  []services.DuplicatePair `json:"pairs"`
+}
+
+// GetDuplicates 获取与知识点疑似重复的其他知识点
+func (h *KnowledgePointHandler) GetDuplicates(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	actor := currentActor(c)
+	point, err := h.knowledgePointService.GetKnowledgePoint(c.Request.Context(), knowledgePointID, actor)
+	if err != nil {
+		logger.Error("获取知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+
+	candidates, err := h.knowledgePointService.FindDuplicates(c.Request.Context(), point, actor)
+	if err != nil {
+		logger.Error("检测重复知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "检测重复知识点失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  candidates,
+		"count": len(candidates),
+	})
+}
+
+// GetDuplicateReport 获取全部疑似重复簇（threshold为内容相似度阈值，默认0.7）
+func (h *KnowledgePointHandler) GetDuplicateReport(c *gin.Context) {
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", strconv.FormatFloat(services.DefaultDuplicateThreshold, 'f', -1, 64)), 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "相似度阈值应在0到1之间"})
+		return
+	}
+
+	clusters, err := h.knowledgePointService.GetDuplicateClusters(c.Request.Context(), threshold, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("获取重复知识点报告失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取重复知识点报告失败"})
+		return
+	}
+
+	responses := make([]DuplicateClusterResponse, 0, len(clusters))
+	for _, cluster := range clusters {
+		response := DuplicateClusterResponse{
+			Points: make([]DuplicateClusterMember, 0, len(cluster.Points)),
+			Pairs:  cluster.Pairs,
+		}
+		for _, point := range cluster.Points {
+			response.Points = append(response.Points, DuplicateClusterMember{
+				ID:          point.ID.String(),
+				Title:       point.Title,
+				Category:    point.Category,
+				Status:      point.Status,
+				AuthorID:    point.AuthorID,
+				ExternalKey: point.ExternalKey,
+			})
+		}
+		responses = append(responses, response)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// MergeKnowledgePoint 将重复的知识点合并到当前知识点
+func (h *KnowledgePointHandler) MergeKnowledgePoint(c *gin.Context) {
+	survivorID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	var req MergeKnowledgePointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+	duplicateID, err := uuid.Parse(req.DuplicateID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+
+	survivor, err := h.knowledgePointService.MergeKnowledgePoints(c.Request.Context(), survivorID, duplicateID, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("合并知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "合并知识点失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToKnowledgePointDetailResponse(survivor)})
+}
+
+// RebuildFingerprints 重建全部知识点的查重指纹
+func (h *KnowledgePointHandler) RebuildFingerprints(c *gin.Context) {
+	count, err := h.knowledgePointService.RebuildFingerprints(c.Request.Context(), currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("重建知识点指纹失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "重建知识点指纹失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"indexed": count}})
+}
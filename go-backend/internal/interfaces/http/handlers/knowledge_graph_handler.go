@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/graph"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgeGraphHandler 知识图谱处理器
+type KnowledgeGraphHandler struct {
+	graphService *services.KnowledgeGraphService
+}
+
+// NewKnowledgeGraphHandler 创建知识图谱处理器
+func NewKnowledgeGraphHandler(graphService *services.KnowledgeGraphService) *KnowledgeGraphHandler {
+	return &KnowledgeGraphHandler{graphService: graphService}
+}
+
+// GetAncestors 获取知识点的前置知识点图谱（depth为遍历深度，related=true时包含内容关联边）
+func (h *KnowledgeGraphHandler) GetAncestors(c *gin.Context) {
+	h.traverse(c, h.graphService.GetAncestors, "ancestors")
+}
+
+// GetDescendants 获取以知识点为前置的后续知识点图谱
+func (h *KnowledgeGraphHandler) GetDescendants(c *gin.Context) {
+	h.traverse(c, h.graphService.GetDescendants, "descendants")
+}
+
+// GetPrerequisiteChain 获取两个知识点之间的最短前置关系链（from、to）
+func (h *KnowledgeGraphHandler) GetPrerequisiteChain(c *gin.Context) {
+	from, errFrom := uuid.Parse(c.Query("from"))
+	to, errTo := uuid.Parse(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+
+	result, err := h.graphService.GetPrerequisiteChain(c.Request.Context(), from, to, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrNoPrerequisiteChain) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("获取前置关系链失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+	h.respondGraph(c, result, "prerequisite_chain")
+}
+
+// GetCategoryGraph 获取分类下的知识点图谱
+func (h *KnowledgeGraphHandler) GetCategoryGraph(c *gin.Context) {
+	category := c.Query("category")
+	if category == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少分类参数"})
+		return
+	}
+
+	result, err := h.graphService.GetCategoryGraph(c.Request.Context(), category, c.Query("related") == "true", currentActor(c))
+	if err != nil {
+		logger.Error("获取分类图谱失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取分类图谱失败"})
+		return
+	}
+	h.respondGraph(c, result, category)
+}
+
+// traverse 处理前置、后续知识点的遍历请求
+func (h *KnowledgeGraphHandler) traverse(
+	c *gin.Context,
+	query func(ctx context.Context, id uuid.UUID, depth int, includeRelated bool, actor *services.KnowledgePointActor) (*graph.Graph, error),
+	name string,
+) {
+	knowledgePointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(services.DefaultGraphDepth)))
+	if err != nil || depth <= 0 || depth > services.MaxGraphDepth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "遍历深度应为1到" + strconv.Itoa(services.MaxGraphDepth)})
+		return
+	}
+
+	result, err := query(c.Request.Context(), knowledgePointID, depth, c.Query("related") == "true", currentActor(c))
+	if err != nil {
+		logger.Error("获取知识图谱失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+	h.respondGraph(c, result, name)
+}
+
+// respondGraph 按format参数输出图谱：json（默认）、graphml或dot
+func (h *KnowledgeGraphHandler) respondGraph(c *gin.Context, result *graph.Graph, name string) {
+	var buf bytes.Buffer
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, gin.H{
+			"data":       result,
+			"node_count": len(result.Nodes),
+			"edge_count": len(result.Edges),
+		})
+	case "graphml":
+		if err := result.WriteGraphML(&buf); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "导出图谱失败"})
+			return
+		}
+		c.Data(http.StatusOK, "application/graphml+xml; charset=utf-8", buf.Bytes())
+	case "dot":
+		if err := result.WriteDOT(&buf, name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "导出图谱失败"})
+			return
+		}
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", buf.Bytes())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的图谱格式，可选 json、graphml、dot"})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"archive/zip"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/kpimport"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxImportUploadSize 导入文件大小上限
+const maxImportUploadSize = 50 << 20
+
+// KnowledgeImportHandler 知识点批量导入处理器
+type KnowledgeImportHandler struct {
+	importService *services.KnowledgeImportService
+}
+
+// NewKnowledgeImportHandler 创建知识点批量导入处理器
+func NewKnowledgeImportHandler(importService *services.KnowledgeImportService) *KnowledgeImportHandler {
+	return &KnowledgeImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportKnowledgePoints 批量导入知识点：
+// format为csv、jsonl或markdown（单个.md文件或包含.md文件的.zip压缩包），
+// dry_run=true时仅校验，upsert=true时按外部键更新已有知识点
+func (h *KnowledgeImportHandler) ImportKnowledgePoints(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run参数无效"})
+		return
+	}
+	upsert, err := strconv.ParseBool(c.DefaultPostForm("upsert", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "upsert参数无效"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少导入文件"})
+		return
+	}
+	if fileHeader.Size > maxImportUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "导入文件过大"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("读取上传文件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	format := c.PostForm("format")
+	if format == "" {
+		format = formatFromFilename(fileHeader.Filename)
+	}
+
+	var records []kpimport.Record
+	var parseErrors []kpimport.RowError
+	switch format {
+	case kpimport.FormatCSV:
+		records, parseErrors = kpimport.ParseCSV(file, fileHeader.Filename)
+	case kpimport.FormatJSONL:
+		records, parseErrors = kpimport.ParseJSONL(file, fileHeader.Filename)
+	case kpimport.FormatMarkdown:
+		if strings.EqualFold(filepath.Ext(fileHeader.Filename), ".zip") {
+			archive, err := zip.NewReader(file, fileHeader.Size)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的zip文件"})
+				return
+			}
+			records, parseErrors = kpimport.ParseMarkdownFS(archive)
+		} else {
+			data, err := io.ReadAll(file)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+				return
+			}
+			record, err := kpimport.ParseMarkdown(data, fileHeader.Filename)
+			if err != nil {
+				parseErrors = append(parseErrors, kpimport.AsRowError(err, fileHeader.Filename))
+			} else {
+				records = append(records, record)
+			}
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的导入格式"})
+		return
+	}
+
+	report, err := h.importService.Import(c.Request.Context(), records, parseErrors, &services.KnowledgeImportOptions{
+		DryRun: dryRun,
+		Upsert: upsert,
+		Actor:  actor,
+	})
+	if err != nil {
+		logger.Error("批量导入知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "批量导入知识点失败"})
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{"data": report})
+}
+
+// formatFromFilename 根据文件扩展名推断导入格式
+func formatFromFilename(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return kpimport.FormatCSV
+	case ".jsonl", ".ndjson":
+		return kpimport.FormatJSONL
+	case ".md", ".zip":
+		return kpimport.FormatMarkdown
+	}
+	return ""
+}
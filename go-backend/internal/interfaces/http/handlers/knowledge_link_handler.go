@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgePointLinkResponse 关联知识点响应
+type KnowledgePointLinkResponse struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Category    string `json:"category"`
+	Difficulty  string `json:"difficulty"`
+	Status      string `json:"status"`
+	Mention     string `json:"mention"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// GetRelatedKnowledgePoints 获取知识点内容中提及的其他知识点
+func (h *KnowledgePointHandler) GetRelatedKnowledgePoints(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	relations, err := h.knowledgePointService.GetRelatedKnowledgePoints(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		logger.Error("获取关联知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+	h.respondRelations(c, relations)
+}
+
+// GetBacklinks 获取内容中提及该知识点的其他知识点
+func (h *KnowledgePointHandler) GetBacklinks(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	relations, err := h.knowledgePointService.GetBacklinks(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		logger.Error("获取反向链接失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+	h.respondRelations(c, relations)
+}
+
+// RebuildLinks 重建全部知识点的提及关联
+func (h *KnowledgePointHandler) RebuildLinks(c *gin.Context) {
+	count, err := h.knowledgePointService.RebuildLinks(c.Request.Context(), currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("重建知识点关联失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "重建知识点关联失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"indexed": count}})
+}
+
+// respondRelations 返回关联知识点列表
+func (h *KnowledgePointHandler) respondRelations(c *gin.Context, relations []*services.KnowledgePointRelation) {
+	responses := make([]KnowledgePointLinkResponse, 0, len(relations))
+	for _, relation := range relations {
+		responses = append(responses, KnowledgePointLinkResponse{
+			ID:          relation.Point.ID.String(),
+			Title:       relation.Point.Title,
+			Category:    relation.Point.Category,
+			Difficulty:  relation.Point.Difficulty,
+			Status:      relation.Point.Status,
+			Mention:     relation.Mention,
+			Occurrences: relation.Occurrences,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
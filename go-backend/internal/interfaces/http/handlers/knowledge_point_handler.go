@@ -1,63 +1,118 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"sical-go-backend/internal/domain/entities"
 	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
 	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+	"sical-go-backend/pkg/textsearch"
 )
 
 // KnowledgePointHandler 知识点处理器
 type KnowledgePointHandler struct {
-	knowledgePointRepo repositories.KnowledgePointRepository
+	knowledgePointRepo    repositories.KnowledgePointRepository
+	knowledgePointService *services.KnowledgePointService
+	translationService    *services.KnowledgeTranslationService
 }
 
 // NewKnowledgePointHandler 创建知识点处理器
-func NewKnowledgePointHandler(knowledgePointRepo repositories.KnowledgePointRepository) *KnowledgePointHandler {
+func NewKnowledgePointHandler(knowledgePointRepo repositories.KnowledgePointRepository, knowledgePointService *services.KnowledgePointService, translationService *services.KnowledgeTranslationService) *KnowledgePointHandler {
 	return &KnowledgePointHandler{
-		knowledgePointRepo: knowledgePointRepo,
+		knowledgePointRepo:    knowledgePointRepo,
+		knowledgePointService: knowledgePointService,
+		translationService:    translationService,
 	}
 }
 
 // CreateKnowledgePointRequest 创建知识点请求
 type CreateKnowledgePointRequest struct {
-	Title         string `json:"title" binding:"required,min=1,max=255"`
-	Description   string `json:"description"`
-	Content       string `json:"content" binding:"required"`
-	Category      string `json:"category" binding:"required"`
-	Difficulty    string `json:"difficulty" binding:"required,oneof=beginner intermediate advanced"`
-	Resources     string `json:"resources"`
-	Prerequisites string `json:"prerequisites"`
+	Title         string                    `json:"title" binding:"required,min=1,max=255"`
+	Description   string                    `json:"description"`
+	Content       string                    `json:"content" binding:"required"`
+	Category      string                    `json:"category" binding:"required"`
+	Difficulty    string                    `json:"difficulty" binding:"required,oneof=beginner intermediate advanced"`
+	Resources     []LearningResourceRequest `json:"resources" binding:"dive"`
+	Prerequisites string                    `json:"prerequisites"`
+	Tags          []string                  `json:"tags"`
+	Aliases       []string                  `json:"aliases"`
 }
 
 // UpdateKnowledgePointRequest 更新知识点请求
 type UpdateKnowledgePointRequest struct {
-	Title         *string `json:"title,omitempty"`
-	Description   *string `json:"description,omitempty"`
-	Content       *string `json:"content,omitempty"`
-	Category      *string `json:"category,omitempty"`
-	Difficulty    *string `json:"difficulty,omitempty"`
-	Resources     *string `json:"resources,omitempty"`
-	Prerequisites *string `json:"prerequisites,omitempty"`
+	Title         *string                    `json:"title,omitempty"`
+	Description   *string                    `json:"description,omitempty"`
+	Content       *string                    `json:"content,omitempty"`
+	Category      *string                    `json:"category,omitempty"`
+	Difficulty    *string                    `json:"difficulty,omitempty"`
+	Resources     *[]LearningResourceRequest `json:"resources,omitempty"`
+	Prerequisites *string                    `json:"prerequisites,omitempty"`
+	Tags          *[]string                  `json:"tags,omitempty"`
+	Aliases       *[]string                  `json:"aliases,omitempty"`
 }
 
 // KnowledgePointDetailResponse 知识点详细响应
 type KnowledgePointDetailResponse struct {
-	ID            string    `json:"id"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	Content       string    `json:"content"`
-	Category      string    `json:"category"`
-	Difficulty    string    `json:"difficulty"`
-	Resources     string    `json:"resources"`
-	Prerequisites string    `json:"prerequisites"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID               string                      `json:"id"`
+	Title            string                      `json:"title"`
+	Description      string                      `json:"description"`
+	Content          string                      `json:"content"`
+	ContentHTML      string                      `json:"content_html"`
+	Category         string                      `json:"category"`
+	Difficulty       string                      `json:"difficulty"`
+	Resources        []entities.LearningResource `json:"resources"`
+	Prerequisites    string                      `json:"prerequisites"`
+	Tags             []string                    `json:"tags"`
+	Aliases          []string                    `json:"aliases"`
+	Status           string                      `json:"status"`
+	AuthorID         *uint                       `json:"author_id"`
+	ApprovedBy       *uint                       `json:"approved_by"`
+	PublishedAt      *time.Time                  `json:"published_at"`
+	CreatedAt        time.Time                   `json:"created_at"`
+	UpdatedAt        time.Time                   `json:"updated_at"`
+	AverageRating    float64                     `json:"average_rating"`
+	RatingsCount     int                         `json:"ratings_count"`
+	Locale           string                      `json:"locale,omitempty"`            // 标题、描述与内容所用的语言
+	AvailableLocales []string                    `json:"available_locales,omitempty"` // 可用语言
+}
+
+// KnowledgePointSearchResponse 知识点检索结果响应
+type KnowledgePointSearchResponse struct {
+	KnowledgePointDetailResponse
+	Rank             float64 `json:"rank"`
+	TitleHighlight   string  `json:"title_highlight"`
+	SnippetHighlight string  `json:"snippet_highlight"`
+}
+
+// 检索分页与摘要参数
+const (
+	maxSearchLimit    = 100
+	searchSnippetSize = 120
+)
+
+// 目录分页参数
+const (
+	defaultCatalogLimit = 20
+	maxCatalogLimit     = 100
+)
+
+// catalogSortFields 目录支持的排序字段
+var catalogSortFields = map[string]bool{
+	repositories.KnowledgePointSortCreatedAt: true,
+	repositories.KnowledgePointSortUpdatedAt: true,
+	repositories.KnowledgePointSortTitle:     true,
+	repositories.KnowledgePointSortRating:    true,
 }
 
 // CreateKnowledgePoint 创建知识点
@@ -77,22 +132,41 @@ func (h *KnowledgePointHandler) CreateKnowledgePoint(c *gin.Context) {
 		Content:       req.Content,
 		Category:      req.Category,
 		Difficulty:    req.Difficulty,
-		Resources:     req.Resources,
 		Prerequisites: req.Prerequisites,
 	}
+	knowledgePoint.SetTags(req.Tags)
+	knowledgePoint.SetAliases(req.Aliases)
 
-	// 保存到数据库
-	if err := h.knowledgePointRepo.Create(c.Request.Context(), knowledgePoint); err != nil {
+	// 校验学习资源
+	resources, err := toLearningResources(req.Resources)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	knowledgePoint.SetResources(resources)
+
+	// 保存为草稿并记录初始版本
+	if err := h.knowledgePointService.CreateKnowledgePoint(c.Request.Context(), knowledgePoint, currentActor(c)); err != nil {
+		if errors.Is(err, markdown.ErrUnsafeContent) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("创建知识点失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建知识点失败"})
 		return
 	}
 
+	// 疑似重复仅作提示，不阻止创建
+	duplicates, err := h.knowledgePointService.FindDuplicates(c.Request.Context(), knowledgePoint, currentActor(c))
+	if err != nil {
+		logger.Error("检测重复知识点失败", logger.String("error", err.Error()))
+		duplicates = []*services.DuplicateCandidate{}
+	}
+
 	// 转换响应
 	response := h.convertToKnowledgePointDetailResponse(knowledgePoint)
 
-	logger.Info("知识点创建成功", logger.String("knowledge_point_id", knowledgePoint.ID.String()))
-	c.JSON(http.StatusCreated, gin.H{"data": response})
+	c.JSON(http.StatusCreated, gin.H{"data": response, "duplicates": duplicates})
 }
 
 // GetKnowledgePoint 获取单个知识点
@@ -105,19 +179,75 @@ func (h *KnowledgePointHandler) GetKnowledgePoint(c *gin.Context) {
 		return
 	}
 
-	knowledgePoint, err := h.knowledgePointRepo.GetByID(c.Request.Context(), knowledgePointID)
+	knowledgePoint, err := h.knowledgePointService.GetKnowledgePoint(c.Request.Context(), knowledgePointID, currentActor(c))
 	if err != nil {
 		logger.Error("获取知识点失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
 		return
 	}
 
+	infos := h.localize(c, []*entities.KnowledgePoint{knowledgePoint})
+
 	// 转换响应
 	response := h.convertToKnowledgePointDetailResponse(knowledgePoint)
+	applyLocalizedInfo(&response, infos[knowledgePoint.ID])
+	if response.Locale != "" {
+		c.Header("Content-Language", response.Locale)
+	}
+	c.Header("Vary", "Accept-Language")
 
 	c.JSON(http.StatusOK, gin.H{"data": response})
 }
 
+// ListKnowledgePoints 知识点目录：组合筛选、排序、游标分页并返回分面统计
+func (h *KnowledgePointHandler) ListKnowledgePoints(c *gin.Context) {
+	filter, err := parseKnowledgePointFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.knowledgePointRepo.List(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("获取知识点目录失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取知识点失败"})
+		return
+	}
+
+	facets, err := h.knowledgePointRepo.GetFacets(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("获取知识点分面统计失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取知识点失败"})
+		return
+	}
+
+	infos := h.localize(c, page.Points)
+
+	// 转换响应
+	responses := make([]KnowledgePointDetailResponse, 0, len(page.Points))
+	for _, kp := range page.Points {
+		response := h.convertToKnowledgePointDetailResponse(kp)
+		applyLocalizedInfo(&response, infos[kp.ID])
+		responses = append(responses, response)
+	}
+	c.Header("Vary", "Accept-Language")
+
+	var nextCursor string
+	if page.NextCursor != nil {
+		data, _ := json.Marshal(page.NextCursor)
+		nextCursor = base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        responses,
+		"count":       len(responses),
+		"total":       page.Total,
+		"limit":       filter.Limit,
+		"next_cursor": nextCursor,
+		"facets":      facets,
+	})
+}
+
 // GetKnowledgePointsByCategory 按分类获取知识点
 func (h *KnowledgePointHandler) GetKnowledgePointsByCategory(c *gin.Context) {
 	category := c.Query("category")
@@ -200,24 +330,38 @@ func (h *KnowledgePointHandler) SearchKnowledgePoints(c *gin.Context) {
 		offset = 0
 	}
 
-	knowledgePoints, err := h.knowledgePointRepo.Search(c.Request.Context(), query)
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	preferred := h.translationService.PreferredLocales(c.Request.Context(), currentActor(c), c.Query("lang"), c.GetHeader("Accept-Language"))
+	result, err := h.translationService.SearchKnowledgePoints(c.Request.Context(), query, preferred, offset, limit)
 	if err != nil {
 		logger.Error("搜索知识点失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "搜索知识点失败"})
 		return
 	}
 
+	// 同义词一并高亮
+	highlightQuery := query
+	for _, alternatives := range result.Synonyms {
+		highlightQuery += " " + strings.Join(alternatives, " ")
+	}
+
 	// 转换响应
-	var responses []KnowledgePointDetailResponse
-	for _, kp := range knowledgePoints {
-		responses = append(responses, h.convertToKnowledgePointDetailResponse(kp))
+	responses := make([]KnowledgePointSearchResponse, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		responses = append(responses, h.convertToKnowledgePointSearchResponse(hit, highlightQuery))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":   responses,
-		"count":  len(responses),
-		"limit":  limit,
-		"offset": offset,
+		"data":           responses,
+		"count":          len(responses),
+		"total":          result.Total,
+		"limit":          limit,
+		"offset":         offset,
+		"expanded_terms": result.Synonyms,
+		"locale":         result.Locale,
 	})
 }
 
@@ -268,14 +412,34 @@ func (h *KnowledgePointHandler) UpdateKnowledgePoint(c *gin.Context) {
 		knowledgePoint.Difficulty = *req.Difficulty
 	}
 	if req.Resources != nil {
-		knowledgePoint.Resources = *req.Resources
+		resources, err := toLearningResources(*req.Resources)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		knowledgePoint.SetResources(resources)
 	}
 	if req.Prerequisites != nil {
 		knowledgePoint.Prerequisites = *req.Prerequisites
 	}
+	if req.Tags != nil {
+		knowledgePoint.SetTags(*req.Tags)
+	}
+	if req.Aliases != nil {
+		knowledgePoint.SetAliases(*req.Aliases)
+	}
 
-	// 保存更新
-	if err := h.knowledgePointRepo.Update(c.Request.Context(), knowledgePoint); err != nil {
+	// 保存更新并记录修订版本
+	revision, err := h.knowledgePointService.UpdateKnowledgePoint(c.Request.Context(), knowledgePoint, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, markdown.ErrUnsafeContent) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("更新知识点失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新知识点失败"})
 		return
@@ -284,8 +448,11 @@ func (h *KnowledgePointHandler) UpdateKnowledgePoint(c *gin.Context) {
 	// 转换响应
 	response := h.convertToKnowledgePointDetailResponse(knowledgePoint)
 
-	logger.Info("知识点更新成功", logger.String("knowledge_point_id", knowledgePoint.ID.String()))
-	c.JSON(http.StatusOK, gin.H{"data": response})
+	logger.Info("知识点更新成功",
+		logger.String("knowledge_point_id", knowledgePoint.ID.String()),
+		logger.Int("version", revision.Version),
+	)
+	c.JSON(http.StatusOK, gin.H{"data": response, "version": revision.Version})
 }
 
 // DeleteKnowledgePoint 删除知识点
@@ -298,7 +465,11 @@ func (h *KnowledgePointHandler) DeleteKnowledgePoint(c *gin.Context) {
 		return
 	}
 
-	if err := h.knowledgePointRepo.Delete(c.Request.Context(), knowledgePointID); err != nil {
+	if err := h.knowledgePointService.DeleteKnowledgePoint(c.Request.Context(), knowledgePointID, currentActor(c)); err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("删除知识点失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除知识点失败"})
 		return
@@ -308,18 +479,155 @@ func (h *KnowledgePointHandler) DeleteKnowledgePoint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
 
-// convertToKnowledgePointDetailResponse 转换为知识点详细响应
+// convertToKnowledgePointDetailResponse 转换为知识点详细响应，内容渲染为净化后的HTML
 func (h *KnowledgePointHandler) convertToKnowledgePointDetailResponse(kp *entities.KnowledgePoint) KnowledgePointDetailResponse {
+	contentHTML, err := markdown.Render(kp.Content)
+	if err != nil {
+		logger.Error("渲染知识点内容失败",
+			logger.String("knowledge_point_id", kp.ID.String()),
+			logger.String("error", err.Error()),
+		)
+	}
 	return KnowledgePointDetailResponse{
 		ID:            kp.ID.String(),
 		Title:         kp.Title,
 		Description:   kp.Description,
 		Content:       kp.Content,
+		ContentHTML:   contentHTML,
 		Category:      kp.Category,
 		Difficulty:    kp.Difficulty,
-		Resources:     kp.Resources,
+		Resources:     kp.ResourceList(),
 		Prerequisites: kp.Prerequisites,
+		Tags:          kp.TagList(),
+		Aliases:       kp.AliasList(),
+		Status:        kp.Status,
+		AuthorID:      kp.AuthorID,
+		ApprovedBy:    kp.ApprovedBy,
+		PublishedAt:   kp.PublishedAt,
 		CreatedAt:     kp.CreatedAt,
 		UpdatedAt:     kp.UpdatedAt,
+		AverageRating: kp.AverageRating,
+		RatingsCount:  kp.RatingsCount,
+	}
+}
+
+// convertToKnowledgePointSearchResponse 转换为检索结果响应，摘要优先取自描述，其次取自内容
+func (h *KnowledgePointHandler) convertToKnowledgePointSearchResponse(hit *repositories.KnowledgePointSearchHit, query string) KnowledgePointSearchResponse {
+	snippetSource := hit.Point.Description
+	if !containsAnyTerm(snippetSource, query) && containsAnyTerm(hit.Point.Content, query) {
+		snippetSource = hit.Point.Content
 	}
-}
\ No newline at end of file
+	return KnowledgePointSearchResponse{
+		KnowledgePointDetailResponse: h.convertToKnowledgePointDetailResponse(hit.Point),
+		Rank:                         hit.Rank,
+		TitleHighlight:               textsearch.Highlight(hit.Point.Title, query, 0),
+		SnippetHighlight:             textsearch.Highlight(snippetSource, query, searchSnippetSize),
+	}
+}
+
+// containsAnyTerm 检查文本是否包含任一查询词
+func containsAnyTerm(text, query string) bool {
+	lower := strings.ToLower(text)
+	for _, term := range textsearch.Terms(query) {
+		if strings.Contains(lower, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseKnowledgePointFilter 解析目录查询参数；多值参数支持重复传参或逗号分隔
+func parseKnowledgePointFilter(c *gin.Context) (*repositories.KnowledgePointFilter, error) {
+	filter := &repositories.KnowledgePointFilter{
+		Categories:   queryList(c, "category"),
+		Difficulties: queryList(c, "difficulty"),
+		Tags:         queryList(c, "tag"),
+		Limit:        defaultCatalogLimit,
+	}
+
+	for _, difficulty := range filter.Difficulties {
+		if difficulty != "beginner" && difficulty != "intermediate" && difficulty != "advanced" {
+			return nil, fmt.Errorf("无效的难度值")
+		}
+	}
+
+	if value := c.Query("has_prerequisite"); value != "" {
+		hasPrerequisite, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("has_prerequisite参数无效")
+		}
+		filter.HasPrerequisite = &hasPrerequisite
+	}
+
+	if value := c.Query("created_from"); value != "" {
+		t, err := parseQueryTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("created_from参数无效")
+		}
+		filter.CreatedFrom = &t
+	}
+	if value := c.Query("created_to"); value != "" {
+		t, err := parseQueryTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("created_to参数无效")
+		}
+		filter.CreatedTo = &t
+	}
+
+	// 排序字段前缀"-"表示降序，默认按创建时间降序
+	sort := c.DefaultQuery("sort", "-"+repositories.KnowledgePointSortCreatedAt)
+	if strings.HasPrefix(sort, "-") {
+		filter.SortDesc = true
+		sort = sort[1:]
+	}
+	if !catalogSortFields[sort] {
+		return nil, fmt.Errorf("不支持的排序字段")
+	}
+	filter.SortBy = sort
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("limit参数无效")
+		}
+		if limit > maxCatalogLimit {
+			limit = maxCatalogLimit
+		}
+		filter.Limit = limit
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("分页游标无效")
+		}
+		var cursor repositories.KnowledgePointCursor
+		if err := json.Unmarshal(data, &cursor); err != nil {
+			return nil, fmt.Errorf("分页游标无效")
+		}
+		filter.Cursor = &cursor
+	}
+
+	return filter, nil
+}
+
+// queryList 获取多值查询参数
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseQueryTime 解析RFC3339时间或日期（YYYY-MM-DD）
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
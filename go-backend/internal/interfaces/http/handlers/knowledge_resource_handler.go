@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// LearningResourceRequest 学习资源请求
+type LearningResourceRequest struct {
+	ID              string `json:"id,omitempty"` // 整体替换资源列表时用于保留已有资源ID
+	Type            string `json:"type" binding:"required,oneof=video article textbook_chapter pdf link"`
+	Title           string `json:"title" binding:"required,min=1,max=255"`
+	URL             string `json:"url"`
+	Locator         string `json:"locator"`
+	DurationMinutes int    `json:"duration_minutes" binding:"min=0"`
+	Language        string `json:"language"`
+	License         string `json:"license"`
+}
+
+// ListResources 获取知识点的学习资源
+func (h *KnowledgePointHandler) ListResources(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	resources, err := h.knowledgePointService.GetResources(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		logger.Error("获取学习资源失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+
+	totalMinutes := 0
+	for _, resource := range resources {
+		totalMinutes += resource.DurationMinutes
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":          resources,
+		"count":         len(resources),
+		"total_minutes": totalMinutes,
+	})
+}
+
+// AddResource 为知识点添加学习资源
+func (h *KnowledgePointHandler) AddResource(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	var req LearningResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	resource, err := h.knowledgePointService.AddResource(c.Request.Context(), knowledgePointID, req.toEntity(), currentActor(c))
+	if err != nil {
+		h.respondResourceError(c, "添加学习资源失败", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": resource})
+}
+
+// UpdateResource 更新知识点的学习资源
+func (h *KnowledgePointHandler) UpdateResource(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	resourceID, err := uuid.Parse(c.Param("resource_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "资源ID格式无效"})
+		return
+	}
+
+	var req LearningResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	resource, err := h.knowledgePointService.UpdateResource(c.Request.Context(), knowledgePointID, resourceID, req.toEntity(), currentActor(c))
+	if err != nil {
+		h.respondResourceError(c, "更新学习资源失败", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resource})
+}
+
+// DeleteResource 删除知识点的学习资源
+func (h *KnowledgePointHandler) DeleteResource(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	resourceID, err := uuid.Parse(c.Param("resource_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "资源ID格式无效"})
+		return
+	}
+
+	if err := h.knowledgePointService.DeleteResource(c.Request.Context(), knowledgePointID, resourceID, currentActor(c)); err != nil {
+		h.respondResourceError(c, "删除学习资源失败", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// respondResourceError 返回学习资源操作的错误响应
+func (h *KnowledgePointHandler) respondResourceError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrKnowledgePointForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLearningResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// toEntity 转换为学习资源实体
+func (r *LearningResourceRequest) toEntity() entities.LearningResource {
+	resource := entities.LearningResource{
+		Type:            r.Type,
+		Title:           r.Title,
+		URL:             r.URL,
+		Locator:         r.Locator,
+		DurationMinutes: r.DurationMinutes,
+		Language:        r.Language,
+		License:         r.License,
+	}
+	if id, err := uuid.Parse(r.ID); err == nil {
+		resource.ID = id
+	}
+	return resource
+}
+
+// toLearningResources 转换并校验学习资源列表
+func toLearningResources(requests []LearningResourceRequest) ([]entities.LearningResource, error) {
+	resources := make([]entities.LearningResource, 0, len(requests))
+	for _, req := range requests {
+		resource := req.toEntity()
+		if err := resource.Validate(); err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
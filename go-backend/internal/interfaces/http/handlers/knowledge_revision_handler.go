@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgeRevisionResponse 修订版本摘要响应
+type KnowledgeRevisionResponse struct {
+	ID            string    `json:"id"`
+	Version       int       `json:"version"`
+	AuthorID      *uint     `json:"author_id"`
+	Title         string    `json:"title"`
+	ChangedFields []string  `json:"changed_fields"`
+	RestoredFrom  *int      `json:"restored_from,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// KnowledgeRevisionDetailResponse 修订版本详细响应
+type KnowledgeRevisionDetailResponse struct {
+	KnowledgeRevisionResponse
+	Description   string   `json:"description"`
+	Category      string   `json:"category"`
+	Difficulty    string   `json:"difficulty"`
+	Content       string   `json:"content"`
+	Resources     string   `json:"resources"`
+	Prerequisites string   `json:"prerequisites"`
+	Tags          []string `json:"tags"`
+	Aliases       []string `json:"aliases"`
+	ContentDiff   string   `json:"content_diff"`
+}
+
+// ListRevisions 获取知识点修订历史
+func (h *KnowledgePointHandler) ListRevisions(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.knowledgePointService.GetRevisions(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		logger.Error("获取知识点修订历史失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+
+	responses := make([]KnowledgeRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, h.convertToKnowledgeRevisionResponse(revision))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// GetRevision 获取指定修订版本
+func (h *KnowledgePointHandler) GetRevision(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.knowledgePointService.GetRevision(c.Request.Context(), knowledgePointID, version, currentActor(c))
+	if err != nil {
+		logger.Error("获取知识点修订版本失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "修订版本不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToKnowledgeRevisionDetailResponse(revision)})
+}
+
+// CompareRevisions 比较两个修订版本
+func (h *KnowledgePointHandler) CompareRevisions(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版本号参数无效"})
+		return
+	}
+
+	comparison, err := h.knowledgePointService.CompareRevisions(c.Request.Context(), knowledgePointID, from, to, currentActor(c))
+	if err != nil {
+		logger.Error("比较知识点修订版本失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "修订版本不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comparison})
+}
+
+// RestoreRevision 将知识点恢复到指定修订版本
+func (h *KnowledgePointHandler) RestoreRevision(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+
+	point, revision, err := h.knowledgePointService.RestoreRevision(c.Request.Context(), knowledgePointID, version, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("恢复知识点修订版本失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "恢复修订版本失败: " + err.Error()})
+		return
+	}
+
+	logger.Info("知识点已恢复",
+		logger.String("knowledge_point_id", knowledgePointID.String()),
+		logger.Int("restored_from", version),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"data":    h.convertToKnowledgePointDetailResponse(point),
+		"version": revision.Version,
+	})
+}
+
+// convertToKnowledgeRevisionResponse 转换为修订版本摘要响应
+func (h *KnowledgePointHandler) convertToKnowledgeRevisionResponse(revision *entities.KnowledgePointRevision) KnowledgeRevisionResponse {
+	return KnowledgeRevisionResponse{
+		ID:            revision.ID.String(),
+		Version:       revision.Version,
+		AuthorID:      revision.AuthorID,
+		Title:         revision.Title,
+		ChangedFields: revision.ChangedFieldList(),
+		RestoredFrom:  revision.RestoredFrom,
+		CreatedAt:     revision.CreatedAt,
+	}
+}
+
+// convertToKnowledgeRevisionDetailResponse 转换为修订版本详细响应
+func (h *KnowledgePointHandler) convertToKnowledgeRevisionDetailResponse(revision *entities.KnowledgePointRevision) KnowledgeRevisionDetailResponse {
+	snapshot := &entities.KnowledgePoint{Tags: revision.Tags, Aliases: revision.Aliases}
+	return KnowledgeRevisionDetailResponse{
+		KnowledgeRevisionResponse: h.convertToKnowledgeRevisionResponse(revision),
+		Description:               revision.Description,
+		Category:                  revision.Category,
+		Difficulty:                revision.Difficulty,
+		Content:                   revision.Content,
+		Resources:                 revision.Resources,
+		Prerequisites:             revision.Prerequisites,
+		Tags:                      snapshot.TagList(),
+		Aliases:                   snapshot.AliasList(),
+		ContentDiff:               revision.ContentDiff,
+	}
+}
+
+// parseKnowledgePointID 解析路径中的知识点ID
+func (h *KnowledgePointHandler) parseKnowledgePointID(c *gin.Context) (uuid.UUID, bool) {
+	knowledgePointIDStr := c.Param("id")
+	knowledgePointID, err := uuid.Parse(knowledgePointIDStr)
+	if err != nil {
+		logger.Error("知识点ID格式无效", logger.String("knowledge_point_id", knowledgePointIDStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return uuid.Nil, false
+	}
+	return knowledgePointID, true
+}
+
+// parseVersionParam 解析路径中的版本号参数
+func parseVersionParam(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版本号格式无效"})
+		return 0, false
+	}
+	return version, true
+}
+
+// currentActor 获取当前操作者，未登录时返回nil
+func currentActor(c *gin.Context) *services.KnowledgePointActor {
+	userID, _, role, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		return nil
+	}
+	return &services.KnowledgePointActor{UserID: userID, Role: role}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgeSimilarityHandler 知识点相似度处理器
+type KnowledgeSimilarityHandler struct {
+	similarityService *services.KnowledgeSimilarityService
+}
+
+// NewKnowledgeSimilarityHandler 创建知识点相似度处理器
+func NewKnowledgeSimilarityHandler(similarityService *services.KnowledgeSimilarityService) *KnowledgeSimilarityHandler {
+	return &KnowledgeSimilarityHandler{similarityService: similarityService}
+}
+
+// SimilarKnowledgePointResponse 相似知识点响应
+type SimilarKnowledgePointResponse struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Difficulty  string  `json:"difficulty"`
+	Score       float64 `json:"score"`
+}
+
+// GetSimilar 获取与知识点内容相似的知识点（limit默认10）
+func (h *KnowledgeSimilarityHandler) GetSimilar(c *gin.Context) {
+	knowledgePointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return
+	}
+	limit, ok := parseSimilarLimit(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.similarityService.GetSimilar(c.Request.Context(), knowledgePointID, limit, currentActor(c))
+	if err != nil {
+		logger.Error("获取相似知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+	respondSimilar(c, results)
+}
+
+// GetSimilarToText 获取与文本相似的知识点（text为查询文本）
+func (h *KnowledgeSimilarityHandler) GetSimilarToText(c *gin.Context) {
+	text := c.Query("text")
+	if text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少查询文本"})
+		return
+	}
+	limit, ok := parseSimilarLimit(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.similarityService.GetSimilarToText(c.Request.Context(), text, limit)
+	if err != nil {
+		logger.Error("获取相似知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取相似知识点失败"})
+		return
+	}
+	respondSimilar(c, results)
+}
+
+// parseSimilarLimit 解析结果数量参数
+func parseSimilarLimit(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultSimilarLimit)))
+	if err != nil || limit <= 0 || limit > services.MaxSimilarLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "结果数量应为1到" + strconv.Itoa(services.MaxSimilarLimit)})
+		return 0, false
+	}
+	return limit, true
+}
+
+// respondSimilar 输出相似知识点列表
+func respondSimilar(c *gin.Context, results []*services.SimilarKnowledgePoint) {
+	responses := make([]SimilarKnowledgePointResponse, 0, len(results))
+	for _, result := range results {
+		responses = append(responses, SimilarKnowledgePointResponse{
+			ID:          result.Point.ID.String(),
+			Title:       result.Point.Title,
+			Description: result.Point.Description,
+			Category:    result.Point.Category,
+			Difficulty:  result.Point.Difficulty,
+			Score:       result.Score,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
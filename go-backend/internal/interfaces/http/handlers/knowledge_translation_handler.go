@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/markdown"
+)
+
+// SaveTranslationRequest 保存知识点译文请求
+type SaveTranslationRequest struct {
+	Title       string `json:"title" binding:"required,min=1,max=255"`
+	Description string `json:"description"`
+	Content     string `json:"content" binding:"required"`
+	Status      string `json:"status" binding:"omitempty,oneof=draft published"`
+}
+
+// ListTranslations 获取知识点各语言的翻译状态
+func (h *KnowledgePointHandler) ListTranslations(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.translationService.ListTranslations(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		logger.Error("获取知识点译文失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"count": len(items),
+	})
+}
+
+// GetTranslation 获取知识点指定语言的译文
+func (h *KnowledgePointHandler) GetTranslation(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	translation, err := h.translationService.GetTranslation(c.Request.Context(), knowledgePointID, c.Param("locale"), currentActor(c))
+	if err != nil {
+		h.respondTranslationError(c, err, "获取知识点译文失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": translation})
+}
+
+// SaveTranslation 创建或更新知识点指定语言的译文
+func (h *KnowledgePointHandler) SaveTranslation(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	var req SaveTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	translation, err := h.translationService.SaveTranslation(c.Request.Context(), knowledgePointID, &entities.KnowledgePointTranslation{
+		Locale:      c.Param("locale"),
+		Title:       req.Title,
+		Description: req.Description,
+		Content:     req.Content,
+		Status:      req.Status,
+	}, currentActor(c))
+	if err != nil {
+		h.respondTranslationError(c, err, "保存知识点译文失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": translation})
+}
+
+// DeleteTranslation 删除知识点指定语言的译文
+func (h *KnowledgePointHandler) DeleteTranslation(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteTranslation(c.Request.Context(), knowledgePointID, c.Param("locale"), currentActor(c)); err != nil {
+		h.respondTranslationError(c, err, "删除知识点译文失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// respondTranslationError 输出译文操作的错误响应
+func (h *KnowledgePointHandler) respondTranslationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLocale), errors.Is(err, markdown.ErrUnsafeContent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKnowledgePointForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// localize 按请求的语言偏好（lang参数、用户资料、Accept-Language）本地化知识点；失败时保留原文并记录日志
+func (h *KnowledgePointHandler) localize(c *gin.Context, points []*entities.KnowledgePoint) map[uuid.UUID]*services.LocalizedInfo {
+	preferred := h.translationService.PreferredLocales(c.Request.Context(), currentActor(c), c.Query("lang"), c.GetHeader("Accept-Language"))
+	infos, err := h.translationService.Localize(c.Request.Context(), points, preferred)
+	if err != nil {
+		logger.Error("本地化知识点失败", logger.String("error", err.Error()))
+		return nil
+	}
+	return infos
+}
+
+// applyLocalizedInfo 将语言协商结果写入知识点响应
+func applyLocalizedInfo(response *KnowledgePointDetailResponse, info *services.LocalizedInfo) {
+	if info == nil {
+		return
+	}
+	response.Locale = info.Locale
+	response.AvailableLocales = info.AvailableLocales
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// TransitionKnowledgePointRequest 知识点状态流转请求
+type TransitionKnowledgePointRequest struct {
+	Action  string `json:"action" binding:"required,oneof=submit approve request_changes publish archive reopen"`
+	Comment string `json:"comment" binding:"max=5000"`
+}
+
+// AddReviewCommentRequest 添加审核意见请求
+type AddReviewCommentRequest struct {
+	Comment string `json:"comment" binding:"required,min=1,max=5000"`
+}
+
+// KnowledgePointReviewResponse 审核记录响应
+type KnowledgePointReviewResponse struct {
+	ID         string    `json:"id"`
+	ReviewerID uint      `json:"reviewer_id"`
+	Action     string    `json:"action"`
+	Comment    string    `json:"comment"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TransitionKnowledgePoint 执行知识点状态流转（提交、审核、发布、归档等）
+func (h *KnowledgePointHandler) TransitionKnowledgePoint(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	var req TransitionKnowledgePointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	point, err := h.knowledgePointService.Transition(c.Request.Context(), knowledgePointID, entities.ReviewAction(req.Action), req.Comment, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("知识点状态流转失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToKnowledgePointDetailResponse(point)})
+}
+
+// ListReviews 获取知识点审核记录
+func (h *KnowledgePointHandler) ListReviews(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.knowledgePointService.GetReviews(c.Request.Context(), knowledgePointID, currentActor(c))
+	if err != nil {
+		logger.Error("获取知识点审核记录失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+
+	responses := make([]KnowledgePointReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		responses = append(responses, h.convertToKnowledgePointReviewResponse(review))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// AddReviewComment 添加审核意见
+func (h *KnowledgePointHandler) AddReviewComment(c *gin.Context) {
+	knowledgePointID, ok := h.parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	var req AddReviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	review, err := h.knowledgePointService.AddReviewComment(c.Request.Context(), knowledgePointID, req.Comment, currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("添加审核意见失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "添加审核意见失败"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.convertToKnowledgePointReviewResponse(review)})
+}
+
+// GetMyKnowledgePoints 获取当前用户创建的知识点（含草稿与审核中）
+func (h *KnowledgePointHandler) GetMyKnowledgePoints(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	points, err := h.knowledgePointService.GetMyKnowledgePoints(c.Request.Context(), actor.UserID)
+	if err != nil {
+		logger.Error("获取用户知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取知识点失败"})
+		return
+	}
+
+	h.respondKnowledgePoints(c, points)
+}
+
+// GetReviewQueue 获取待审核的知识点
+func (h *KnowledgePointHandler) GetReviewQueue(c *gin.Context) {
+	points, err := h.knowledgePointService.GetReviewQueue(c.Request.Context(), currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrKnowledgePointForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("获取待审核知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取知识点失败"})
+		return
+	}
+
+	h.respondKnowledgePoints(c, points)
+}
+
+// respondKnowledgePoints 返回知识点列表响应
+func (h *KnowledgePointHandler) respondKnowledgePoints(c *gin.Context, points []*entities.KnowledgePoint) {
+	responses := make([]KnowledgePointDetailResponse, 0, len(points))
+	for _, kp := range points {
+		responses = append(responses, h.convertToKnowledgePointDetailResponse(kp))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// convertToKnowledgePointReviewResponse 转换为审核记录响应
+func (h *KnowledgePointHandler) convertToKnowledgePointReviewResponse(review *entities.KnowledgePointReview) KnowledgePointReviewResponse {
+	return KnowledgePointReviewResponse{
+		ID:         review.ID.String(),
+		ReviewerID: review.ReviewerID,
+		Action:     review.Action,
+		Comment:    review.Comment,
+		FromStatus: review.FromStatus,
+		ToStatus:   review.ToStatus,
+		Version:    review.Version,
+		CreatedAt:  review.CreatedAt,
+	}
+}
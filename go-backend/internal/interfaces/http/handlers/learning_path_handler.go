@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -58,13 +59,17 @@ type UpdateStatusRequest struct {
 type PathResponse struct {
 	ID                string                   `json:"id"`
 	GoalID            string                   `json:"goal_id"`
+	TemplateID        *uuid.UUID               `json:"template_id,omitempty"` // 来源模板
 	Title             string                   `json:"title"`
 	Description       string                   `json:"description"`
 	Order             int                      `json:"order"`
 	EstimatedDuration int                      `json:"estimated_duration"`
 	Status            string                   `json:"status"`
+	IsPublic          bool                     `json:"is_public"` // 公开路径可供多人报名
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
+	AverageRating     float64                  `json:"average_rating"`
+	RatingsCount      int                      `json:"ratings_count"`
 	KnowledgePoints   []KnowledgePointResponse `json:"knowledge_points,omitempty"`
 }
 
@@ -266,6 +271,10 @@ func (h *LearningPathHandler) UpdateLearningPathStatus(c *gin.Context) {
 	}
 
 	if err := h.pathService.UpdateLearningPathStatus(c.Request.Context(), pathID, req.Status); err != nil {
+		if errors.Is(err, services.ErrSharedPathStatus) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("更新学习路径状态失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新学习路径状态失败"})
 		return
@@ -288,6 +297,10 @@ func (h *LearningPathHandler) DeleteLearningPath(c *gin.Context) {
 	}
 
 	if err := h.pathService.DeleteLearningPath(c.Request.Context(), pathID); err != nil {
+		if errors.Is(err, services.ErrSharedPathDelete) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("删除学习路径失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除学习路径失败"})
 		return
@@ -299,16 +312,25 @@ func (h *LearningPathHandler) DeleteLearningPath(c *gin.Context) {
 
 // convertToPathResponse 转换为路径响应
 func (h *LearningPathHandler) convertToPathResponse(path *entities.LearningPath) PathResponse {
+	return toPathResponse(path)
+}
+
+// toPathResponse 转换为路径响应
+func toPathResponse(path *entities.LearningPath) PathResponse {
 	response := PathResponse{
 		ID:                path.ID.String(),
 		GoalID:            path.GoalID.String(),
+		TemplateID:        path.TemplateID,
 		Title:             path.Title,
 		Description:       path.Description,
 		Order:             path.Order,
 		EstimatedDuration: path.EstimatedDuration,
 		Status:            path.Status,
+		IsPublic:          path.IsPublic,
 		CreatedAt:         path.CreatedAt,
 		UpdatedAt:         path.UpdatedAt,
+		AverageRating:     path.AverageRating,
+		RatingsCount:      path.RatingsCount,
 	}
 
 	// 转换知识点
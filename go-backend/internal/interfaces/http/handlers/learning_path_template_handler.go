@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// LearningPathTemplateHandler 学习路径模板处理器
+type LearningPathTemplateHandler struct {
+	templateService *services.LearningPathTemplateService
+}
+
+// NewLearningPathTemplateHandler 创建学习路径模板处理器
+func NewLearningPathTemplateHandler(templateService *services.LearningPathTemplateService) *LearningPathTemplateHandler {
+	return &LearningPathTemplateHandler{templateService: templateService}
+}
+
+// SaveTemplateRequest 创建或更新模板请求，更新时替换全部步骤
+type SaveTemplateRequest struct {
+	Title       string                    `json:"title" binding:"required,min=1,max=255"`
+	Description string                    `json:"description"`
+	Category    string                    `json:"category" binding:"required"`
+	Difficulty  string                    `json:"difficulty" binding:"required,oneof=beginner intermediate advanced"`
+	Status      string                    `json:"status" binding:"omitempty,oneof=draft published"`
+	Steps       []SaveTemplateStepRequest `json:"steps" binding:"required,min=1,dive"`
+}
+
+// SaveTemplateStepRequest 模板步骤请求
+type SaveTemplateStepRequest struct {
+	Title             string      `json:"title" binding:"required,min=1,max=255"`
+	Description       string      `json:"description"`
+	EstimatedDuration int         `json:"estimated_duration" binding:"min=0"` // 为0时按知识点估算
+	KnowledgePointIDs []uuid.UUID `json:"knowledge_point_ids" binding:"required,min=1"`
+}
+
+// InstantiateTemplateRequest 实例化模板请求
+type InstantiateTemplateRequest struct {
+	GoalID       uuid.UUID `json:"goal_id" binding:"required"`
+	SkipMastered bool      `json:"skip_mastered"` // 跳过已掌握的知识点
+}
+
+// TemplateResponse 模板响应
+type TemplateResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Category      string                 `json:"category"`
+	Difficulty    string                 `json:"difficulty"`
+	Status        string                 `json:"status"`
+	AuthorID      uint                   `json:"author_id"`
+	UsageCount    int                    `json:"usage_count"`
+	StepsCount    int                    `json:"steps_count"`
+	TotalDuration int                    `json:"total_duration"` // 各步骤预估学习时间之和(小时)
+	Steps         []TemplateStepResponse `json:"steps,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// TemplateStepResponse 模板步骤响应
+type TemplateStepResponse struct {
+	ID                uuid.UUID   `json:"id"`
+	Position          int         `json:"position"`
+	Title             string      `json:"title"`
+	Description       string      `json:"description"`
+	EstimatedDuration int         `json:"estimated_duration"`
+	KnowledgePointIDs []uuid.UUID `json:"knowledge_point_ids"`
+}
+
+// TemplateInstanceResponse 模板实例化响应
+type TemplateInstanceResponse struct {
+	Paths                      []PathResponse `json:"paths"`
+	SkippedKnowledgePointIDs   []uuid.UUID    `json:"skipped_knowledge_point_ids"`
+	UnavailableKnowledgePoints []uuid.UUID    `json:"unavailable_knowledge_point_ids"`
+	SkippedSteps               int            `json:"skipped_steps"`
+}
+
+// ListTemplates 获取模板目录（category、difficulty、q、status筛选）
+func (h *LearningPathTemplateHandler) ListTemplates(c *gin.Context) {
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	filter := &repositories.LearningPathTemplateFilter{
+		Category:   c.Query("category"),
+		Difficulty: c.Query("difficulty"),
+		Status:     c.Query("status"),
+		Query:      c.Query("q"),
+		Offset:     offset,
+		Limit:      limit,
+	}
+	templates, total, err := h.templateService.ListTemplates(c.Request.Context(), filter, currentActor(c))
+	if err != nil {
+		respondTemplateError(c, err, "获取学习路径模板失败")
+		return
+	}
+	responses := make([]TemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		responses = append(responses, toTemplateResponse(template, false))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+		"total": total,
+	})
+}
+
+// GetTemplate 获取模板及其步骤
+func (h *LearningPathTemplateHandler) GetTemplate(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	template, err := h.templateService.GetTemplate(c.Request.Context(), templateID, currentActor(c))
+	if err != nil {
+		respondTemplateError(c, err, "获取学习路径模板失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toTemplateResponse(template, true)})
+}
+
+// CreateTemplate 创建模板
+func (h *LearningPathTemplateHandler) CreateTemplate(c *gin.Context) {
+	var req SaveTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	template, err := h.templateService.CreateTemplate(c.Request.Context(), req.toInput(), currentActor(c))
+	if err != nil {
+		respondTemplateError(c, err, "创建学习路径模板失败")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": toTemplateResponse(template, true)})
+}
+
+// UpdateTemplate 更新模板
+func (h *LearningPathTemplateHandler) UpdateTemplate(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	var req SaveTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	template, err := h.templateService.UpdateTemplate(c.Request.Context(), templateID, req.toInput(), currentActor(c))
+	if err != nil {
+		respondTemplateError(c, err, "更新学习路径模板失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toTemplateResponse(template, true)})
+}
+
+// DeleteTemplate 删除模板
+func (h *LearningPathTemplateHandler) DeleteTemplate(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), templateID, currentActor(c)); err != nil {
+		respondTemplateError(c, err, "删除学习路径模板失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// InstantiateTemplate 为学习目标实例化模板
+func (h *LearningPathTemplateHandler) InstantiateTemplate(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	var req InstantiateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	instance, err := h.templateService.Instantiate(c.Request.Context(), templateID, req.GoalID, req.SkipMastered, currentActor(c))
+	if err != nil {
+		respondTemplateError(c, err, "实例化学习路径模板失败")
+		return
+	}
+
+	response := TemplateInstanceResponse{
+		Paths:                      make([]PathResponse, 0, len(instance.Paths)),
+		SkippedKnowledgePointIDs:   instance.Skipped,
+		UnavailableKnowledgePoints: instance.Unavailable,
+		SkippedSteps:               instance.SkippedSteps,
+	}
+	for _, path := range instance.Paths {
+		response.Paths = append(response.Paths, toPathResponse(path))
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": response})
+}
+
+// toInput 转换为服务层参数
+func (r *SaveTemplateRequest) toInput() *services.LearningPathTemplateInput {
+	input := &services.LearningPathTemplateInput{
+		Title:       r.Title,
+		Description: r.Description,
+		Category:    r.Category,
+		Difficulty:  r.Difficulty,
+		Status:      entities.LearningPathTemplateStatus(r.Status),
+		Steps:       make([]services.LearningPathTemplateStepInput, 0, len(r.Steps)),
+	}
+	for _, step := range r.Steps {
+		input.Steps = append(input.Steps, services.LearningPathTemplateStepInput{
+			Title:             step.Title,
+			Description:       step.Description,
+			EstimatedDuration: step.EstimatedDuration,
+			KnowledgePointIDs: step.KnowledgePointIDs,
+		})
+	}
+	return input
+}
+
+// toTemplateResponse 转换为模板响应，withSteps为true时包含步骤详情
+func toTemplateResponse(template *entities.LearningPathTemplate, withSteps bool) TemplateResponse {
+	response := TemplateResponse{
+		ID:          template.ID,
+		Title:       template.Title,
+		Description: template.Description,
+		Category:    template.Category,
+		Difficulty:  template.Difficulty,
+		Status:      template.Status,
+		AuthorID:    template.AuthorID,
+		UsageCount:  template.UsageCount,
+		StepsCount:  len(template.Steps),
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+	for i := range template.Steps {
+		step := &template.Steps[i]
+		response.TotalDuration += step.EstimatedDuration
+		if withSteps {
+			response.Steps = append(response.Steps, TemplateStepResponse{
+				ID:                step.ID,
+				Position:          step.Position,
+				Title:             step.Title,
+				Description:       step.Description,
+				EstimatedDuration: step.EstimatedDuration,
+				KnowledgePointIDs: step.KnowledgePointIDs(),
+			})
+		}
+	}
+	return response
+}
+
+// parseTemplateID 解析路径中的模板ID
+func parseTemplateID(c *gin.Context) (uuid.UUID, bool) {
+	templateID, err := uuid.Parse(c.Param("template_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "模板ID格式无效"})
+		return uuid.Nil, false
+	}
+	return templateID, true
+}
+
+// respondTemplateError 输出学习路径模板操作的错误响应
+func respondTemplateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLearningPathTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLearningPathTemplateForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
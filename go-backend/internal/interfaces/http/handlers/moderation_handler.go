@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// ModerationHandler 内容举报与审核处理器
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+}
+
+// NewModerationHandler 创建内容举报与审核处理器
+func NewModerationHandler(moderationService *services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// CreateReportRequest 举报请求
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=comment rating knowledge_point"`
+	TargetID   string `json:"target_id" binding:"required,uuid"`
+	Reason     string `json:"reason" binding:"required,oneof=spam abuse inappropriate inaccurate other"`
+	Details    string `json:"details"`
+}
+
+// ModerationActionRequest 审核操作请求
+type ModerationActionRequest struct {
+	Action      string `json:"action" binding:"required,oneof=dismiss hide_content warn_user suspend_user"`
+	Note        string `json:"note" binding:"max=1000"`
+	HideContent bool   `json:"hide_content"` // 警告或停用作者时是否同时隐藏内容
+}
+
+// CreateReport 举报评论、评价或知识点
+func (h *ModerationHandler) CreateReport(c *gin.Context) {
+	var req CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+	targetID, _ := uuid.Parse(req.TargetID)
+
+	report, err := h.moderationService.Report(
+		c.Request.Context(),
+		entities.ReportTargetType(req.TargetType),
+		targetID,
+		entities.ReportReason(req.Reason),
+		req.Details,
+		currentActor(c),
+	)
+	if err != nil {
+		respondModerationError(c, err, "举报失败")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": report})
+}
+
+// ListMyReports 获取当前用户提交的举报
+func (h *ModerationHandler) ListMyReports(c *gin.Context) {
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	reports, total, err := h.moderationService.ListMyReports(c.Request.Context(), offset, limit, currentActor(c))
+	if err != nil {
+		respondModerationError(c, err, "获取举报失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  reports,
+		"count": len(reports),
+		"total": total,
+	})
+}
+
+// GetQueue 获取审核队列（target_type筛选），按举报数与最早举报时间排序
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	items, total, err := h.moderationService.ListQueue(c.Request.Context(), c.Query("target_type"), offset, limit, currentActor(c))
+	if err != nil {
+		respondModerationError(c, err, "获取审核队列失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"count": len(items),
+		"total": total,
+	})
+}
+
+// TakeAction 处理举报：驳回、隐藏内容、警告或停用作者
+func (h *ModerationHandler) TakeAction(c *gin.Context) {
+	reportID, err := uuid.Parse(c.Param("report_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "举报ID格式无效"})
+		return
+	}
+	var req ModerationActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效: " + err.Error()})
+		return
+	}
+
+	action, err := h.moderationService.TakeAction(c.Request.Context(), reportID, &services.ModerationDecision{
+		Action:      entities.ModerationActionType(req.Action),
+		Note:        req.Note,
+		HideContent: req.HideContent,
+	}, currentActor(c))
+	if err != nil {
+		respondModerationError(c, err, "处理举报失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": action})
+}
+
+// ListActions 获取审核记录（moderator_id、user_id、target_type、target_id筛选）
+func (h *ModerationHandler) ListActions(c *gin.Context) {
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	filter := &repositories.ModerationActionFilter{
+		TargetType: c.Query("target_type"),
+		Offset:     offset,
+		Limit:      limit,
+	}
+	if value := c.Query("moderator_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "moderator_id参数无效"})
+			return
+		}
+		moderatorID := uint(id)
+		filter.ModeratorID = &moderatorID
+	}
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id参数无效"})
+			return
+		}
+		userID := uint(id)
+		filter.SubjectUserID = &userID
+	}
+	if value := c.Query("target_id"); value != "" {
+		targetID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target_id参数无效"})
+			return
+		}
+		filter.TargetID = &targetID
+	}
+
+	actions, total, err := h.moderationService.ListActions(c.Request.Context(), filter, currentActor(c))
+	if err != nil {
+		respondModerationError(c, err, "获取审核记录失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  actions,
+		"count": len(actions),
+		"total": total,
+	})
+}
+
+// respondModerationError 输出举报与审核操作的错误响应
+func respondModerationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidReport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrModerationForbidden), errors.Is(err, services.ErrKnowledgePointForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// NotificationHandler 站内通知处理器
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler 创建站内通知处理器
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// MarkNotificationsReadRequest 标记通知已读请求，ids为空时标记全部
+type MarkNotificationsReadRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// ListNotifications 分页获取当前用户的通知（unread=true仅未读）
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+	offset, limit, ok := parseCommentPage(c)
+	if !ok {
+		return
+	}
+
+	notifications, total, unread, err := h.notificationService.ListNotifications(c.Request.Context(), actor.UserID, c.Query("unread") == "true", offset, limit)
+	if err != nil {
+		logger.Error("获取通知失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取通知失败"})
+		return
+	}
+	if notifications == nil {
+		notifications = []*entities.Notification{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   notifications,
+		"count":  len(notifications),
+		"total":  total,
+		"unread": unread,
+	})
+}
+
+// GetUnreadCount 获取当前用户的未读通知数
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+	unread, err := h.notificationService.CountUnread(c.Request.Context(), actor.UserID)
+	if err != nil {
+		logger.Error("获取未读通知数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取未读通知数失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"unread": unread}})
+}
+
+// MarkRead 将当前用户的通知标记为已读
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	actor := currentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+	var req MarkNotificationsReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+			return
+		}
+	}
+
+	updated, err := h.notificationService.MarkRead(c.Request.Context(), actor.UserID, req.IDs)
+	if err != nil {
+		logger.Error("标记通知已读失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "标记通知已读失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"updated": updated}})
+}
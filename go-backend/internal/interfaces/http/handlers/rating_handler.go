@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// 评价分页参数
+const (
+	defaultReviewLimit = 20
+	maxReviewLimit     = 100
+)
+
+// RatingHandler 评分与评价处理器
+type RatingHandler struct {
+	ratingService *services.RatingService
+}
+
+// NewRatingHandler 创建评分与评价处理器
+func NewRatingHandler(ratingService *services.RatingService) *RatingHandler {
+	return &RatingHandler{ratingService: ratingService}
+}
+
+// SaveRatingRequest 评分请求
+type SaveRatingRequest struct {
+	Score  int    `json:"score" binding:"required,min=1,max=5"`
+	Review string `json:"review"`
+}
+
+// SetReviewHiddenRequest 隐藏评价请求
+type SetReviewHiddenRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// SaveKnowledgePointRating 评价知识点
+func (h *RatingHandler) SaveKnowledgePointRating(c *gin.Context) {
+	h.saveRating(c, entities.RatingTargetKnowledgePoint)
+}
+
+// GetMyKnowledgePointRating 获取当前用户对知识点的评分
+func (h *RatingHandler) GetMyKnowledgePointRating(c *gin.Context) {
+	h.getMyRating(c, entities.RatingTargetKnowledgePoint)
+}
+
+// DeleteKnowledgePointRating 删除当前用户对知识点的评分
+func (h *RatingHandler) DeleteKnowledgePointRating(c *gin.Context) {
+	h.deleteRating(c, entities.RatingTargetKnowledgePoint)
+}
+
+// ListKnowledgePointReviews 获取知识点的文字评价与评分汇总
+func (h *RatingHandler) ListKnowledgePointReviews(c *gin.Context) {
+	h.listReviews(c, entities.RatingTargetKnowledgePoint)
+}
+
+// SaveLearningPathRating 评价学习路径
+func (h *RatingHandler) SaveLearningPathRating(c *gin.Context) {
+	h.saveRating(c, entities.RatingTargetLearningPath)
+}
+
+// GetMyLearningPathRating 获取当前用户对学习路径的评分
+func (h *RatingHandler) GetMyLearningPathRating(c *gin.Context) {
+	h.getMyRating(c, entities.RatingTargetLearningPath)
+}
+
+// DeleteLearningPathRating 删除当前用户对学习路径的评分
+func (h *RatingHandler) DeleteLearningPathRating(c *gin.Context) {
+	h.deleteRating(c, entities.RatingTargetLearningPath)
+}
+
+// ListLearningPathReviews 获取学习路径的文字评价与评分汇总
+func (h *RatingHandler) ListLearningPathReviews(c *gin.Context) {
+	h.listReviews(c, entities.RatingTargetLearningPath)
+}
+
+// saveRating 创建或更新评分
+func (h *RatingHandler) saveRating(c *gin.Context, targetType entities.RatingTargetType) {
+	targetID, ok := parseRatingTargetID(c)
+	if !ok {
+		return
+	}
+	var req SaveRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请提供1-5之间的评分"})
+		return
+	}
+
+	rating, err := h.ratingService.Rate(c.Request.Context(), targetType, targetID, req.Score, req.Review, currentActor(c))
+	if err != nil {
+		respondRatingError(c, err, "保存评分失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rating})
+}
+
+// getMyRating 获取当前用户的评分
+func (h *RatingHandler) getMyRating(c *gin.Context, targetType entities.RatingTargetType) {
+	targetID, ok := parseRatingTargetID(c)
+	if !ok {
+		return
+	}
+	rating, err := h.ratingService.GetMyRating(c.Request.Context(), targetType, targetID, currentActor(c))
+	if err != nil {
+		respondRatingError(c, err, "获取评分失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rating})
+}
+
+// deleteRating 删除当前用户的评分
+func (h *RatingHandler) deleteRating(c *gin.Context, targetType entities.RatingTargetType) {
+	targetID, ok := parseRatingTargetID(c)
+	if !ok {
+		return
+	}
+	if err := h.ratingService.DeleteMyRating(c.Request.Context(), targetType, targetID, currentActor(c)); err != nil {
+		respondRatingError(c, err, "删除评分失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// listReviews 获取文字评价与评分汇总（审核人员可通过include_hidden=true查看已隐藏的评价）
+func (h *RatingHandler) listReviews(c *gin.Context, targetType entities.RatingTargetType) {
+	targetID, ok := parseRatingTargetID(c)
+	if !ok {
+		return
+	}
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+
+	reviews, total, summary, err := h.ratingService.ListReviews(c.Request.Context(), targetType, targetID, c.Query("include_hidden") == "true", offset, limit, currentActor(c))
+	if err != nil {
+		respondRatingError(c, err, "获取评价失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":    reviews,
+		"count":   len(reviews),
+		"total":   total,
+		"summary": summary,
+	})
+}
+
+// ListReviewsForModeration 审核人员获取全部对象的文字评价（target_type、hidden筛选）
+func (h *RatingHandler) ListReviewsForModeration(c *gin.Context) {
+	offset, limit, ok := parseReviewPage(c)
+	if !ok {
+		return
+	}
+	filter := &repositories.RatingFilter{
+		TargetType: c.Query("target_type"),
+		Offset:     offset,
+		Limit:      limit,
+	}
+	if value := c.Query("hidden"); value != "" {
+		hidden, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hidden参数无效"})
+			return
+		}
+		filter.Hidden = &hidden
+	}
+
+	reviews, total, err := h.ratingService.ListForModeration(c.Request.Context(), filter, currentActor(c))
+	if err != nil {
+		respondRatingError(c, err, "获取评价失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  reviews,
+		"count": len(reviews),
+		"total": total,
+	})
+}
+
+// HideReview 隐藏评价
+func (h *RatingHandler) HideReview(c *gin.Context) {
+	var req SetReviewHiddenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+	h.setHidden(c, true, req.Reason)
+}
+
+// UnhideReview 恢复已隐藏的评价
+func (h *RatingHandler) UnhideReview(c *gin.Context) {
+	h.setHidden(c, false, "")
+}
+
+// setHidden 隐藏或恢复评价
+func (h *RatingHandler) setHidden(c *gin.Context, hidden bool, reason string) {
+	reviewID, err := uuid.Parse(c.Param("review_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评价ID格式无效"})
+		return
+	}
+	rating, err := h.ratingService.SetReviewHidden(c.Request.Context(), reviewID, hidden, reason, currentActor(c))
+	if err != nil {
+		respondRatingError(c, err, "更新评价状态失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rating})
+}
+
+// parseRatingTargetID 解析路径中的评分对象ID
+func parseRatingTargetID(c *gin.Context) (uuid.UUID, bool) {
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID格式无效"})
+		return uuid.Nil, false
+	}
+	return targetID, true
+}
+
+// parseReviewPage 解析评价分页参数
+func parseReviewPage(c *gin.Context) (int, int, bool) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset参数无效"})
+		return 0, 0, false
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultReviewLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit参数无效"})
+		return 0, 0, false
+	}
+	if limit > maxReviewLimit {
+		limit = maxReviewLimit
+	}
+	return offset, limit, true
+}
+
+// respondRatingError 输出评分操作的错误响应
+func respondRatingError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRating):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRatingForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// RecommendationHandler 学习推荐处理器
+type RecommendationHandler struct {
+	recommendationService *services.KnowledgeRecommendationService
+}
+
+// NewRecommendationHandler 创建学习推荐处理器
+func NewRecommendationHandler(recommendationService *services.KnowledgeRecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{recommendationService: recommendationService}
+}
+
+// KnowledgeRecommendationResponse 知识点推荐响应
+type KnowledgeRecommendationResponse struct {
+	ID          string                        `json:"id"`
+	Title       string                        `json:"title"`
+	Description string                        `json:"description"`
+	Category    string                        `json:"category"`
+	Difficulty  string                        `json:"difficulty"`
+	Score       float64                       `json:"score"`
+	Reason      services.RecommendationReason `json:"reason"`
+}
+
+// GetKnowledgePointRecommendations 获取当前用户下一步学习的知识点推荐（limit默认10）
+func (h *RecommendationHandler) GetKnowledgePointRecommendations(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultRecommendationLimit)))
+	if err != nil || limit <= 0 || limit > services.MaxRecommendationLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "结果数量应为1到" + strconv.Itoa(services.MaxRecommendationLimit)})
+		return
+	}
+
+	recommendations, err := h.recommendationService.Recommend(c.Request.Context(), userID, limit)
+	if err != nil {
+		logger.Error("获取知识点推荐失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取知识点推荐失败"})
+		return
+	}
+	computedAt, err := h.recommendationService.GetLastComputedAt(c.Request.Context())
+	if err != nil {
+		logger.Error("获取推荐计算时间失败", logger.String("error", err.Error()))
+	}
+
+	responses := make([]KnowledgeRecommendationResponse, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		responses = append(responses, KnowledgeRecommendationResponse{
+			ID:          recommendation.Point.ID.String(),
+			Title:       recommendation.Point.Title,
+			Description: recommendation.Point.Description,
+			Category:    recommendation.Point.Category,
+			Difficulty:  recommendation.Point.Difficulty,
+			Score:       recommendation.Score,
+			Reason:      recommendation.Reason,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        responses,
+		"count":       len(responses),
+		"computed_at": computedAt,
+	})
+}
+
+// RecomputeRecommendations 立即重新计算推荐模型
+func (h *RecommendationHandler) RecomputeRecommendations(c *gin.Context) {
+	count, err := h.recommendationService.RecomputeTransitions(c.Request.Context(), currentActor(c))
+	if err != nil {
+		if errors.Is(err, services.ErrRecommendationForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("计算推荐模型失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "计算推荐模型失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"transitions": count}})
+}
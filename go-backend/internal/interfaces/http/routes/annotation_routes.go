@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupAnnotationRoutes 设置学习笔记与高亮路由；笔记为私人内容，全部路由需登录
+func SetupAnnotationRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	// 初始化服务层
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	knowledgePointService := services.NewKnowledgePointService(
+		knowledgePointRepo,
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		repositories.NewTransactionManager(db),
+	)
+	annotationService := services.NewAnnotationService(
+		repositories.NewAnnotationRepository(db),
+		knowledgePointRepo,
+		knowledgePointService,
+	)
+
+	// 初始化处理器
+	annotationHandler := handlers.NewAnnotationHandler(annotationService)
+
+	// 知识点上的笔记
+	pointAnnotations := router.Group("/knowledge-points/:id/annotations")
+	{
+		pointAnnotations.GET("", annotationHandler.ListKnowledgePointAnnotations) // 我在该知识点上的笔记与高亮（按位置排序）
+		pointAnnotations.POST("", annotationHandler.CreateAnnotation)             // 创建笔记或高亮
+	}
+
+	// 我的笔记
+	annotations := router.Group("/annotations")
+	{
+		annotations.GET("", annotationHandler.ListMyAnnotations)                  // 全部笔记（type、knowledge_point_id、q）
+		annotations.GET("/export", annotationHandler.ExportAnnotations)           // 导出为Markdown（knowledge_point_id）
+		annotations.PUT("/:annotation_id", annotationHandler.UpdateAnnotation)    // 修改笔记正文或颜色
+		annotations.DELETE("/:annotation_id", annotationHandler.DeleteAnnotation) // 删除笔记
+	}
+}
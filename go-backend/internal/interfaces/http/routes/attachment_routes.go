@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+	"sical-go-backend/pkg/storage"
+)
+
+// SetupAttachmentRoutes 设置附件路由；附件内容通过限时签名地址下载
+func SetupAttachmentRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware, store storage.Storage, urlExpiry, gcGracePeriod time.Duration) {
+	// 初始化仓储层
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	profileRepo := repositories.NewUserProfileRepository(db)
+
+	// 初始化服务层
+	attachmentService := services.NewAttachmentService(attachmentRepo, profileRepo, store, urlExpiry)
+
+	// 初始化处理器
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, store, gcGracePeriod)
+
+	// 附件路由组
+	attachments := router.Group("/attachments")
+	{
+		attachments.POST("", authMiddleware.RequireAuth(), attachmentHandler.UploadAttachment)    // 上传附件
+		attachments.POST("/avatar", authMiddleware.RequireAuth(), attachmentHandler.UploadAvatar) // 上传头像
+		attachments.POST("/gc", authMiddleware.RequireAdmin(), attachmentHandler.CollectGarbage)  // 清理未引用附件
+		attachments.GET("/:id", attachmentHandler.GetAttachment)                                  // 获取附件信息与下载地址
+		attachments.GET("/:id/content", attachmentHandler.RedirectContent)                        // 重定向到附件内容
+	}
+
+	// 本地存储文件下载（签名校验）
+	router.GET("/files/*key", attachmentHandler.ServeFile)
+}
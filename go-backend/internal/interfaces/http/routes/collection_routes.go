@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupCollectionRoutes 设置收藏夹路由；管理收藏夹与复制需登录，分享链接可匿名只读访问
+func SetupCollectionRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	knowledgePointService := services.NewKnowledgePointService(
+		knowledgePointRepo,
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		repositories.NewTransactionManager(db),
+	)
+	collectionService := services.NewCollectionService(
+		repositories.NewCollectionRepository(db),
+		knowledgePointRepo,
+		repositories.NewLearningPathRepository(db),
+		knowledgePointService,
+	)
+
+	// 初始化处理器
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+
+	requireAuth := authMiddleware.RequireAuth()
+
+	// 我的收藏夹
+	collections := router.Group("/collections", requireAuth)
+	{
+		collections.GET("", collectionHandler.ListMyCollections)                                     // 我的收藏夹
+		collections.POST("", collectionHandler.CreateCollection)                                     // 创建收藏夹
+		collections.GET("/containing", collectionHandler.ListCollectionsContaining)                  // 收藏了指定对象的收藏夹（target_type、target_id）
+		collections.GET("/:collection_id", collectionHandler.GetCollection)                          // 收藏夹详情与条目
+		collections.PUT("/:collection_id", collectionHandler.UpdateCollection)                       // 修改名称或描述
+		collections.DELETE("/:collection_id", collectionHandler.DeleteCollection)                    // 删除收藏夹
+		collections.PUT("/:collection_id/share", collectionHandler.ShareCollection)                  // 开启或关闭链接分享
+		collections.POST("/:collection_id/items", collectionHandler.AddCollectionItem)               // 收藏知识点或学习路径
+		collections.PUT("/:collection_id/items/order", collectionHandler.ReorderCollectionItems)     // 调整条目顺序
+		collections.DELETE("/:collection_id/items/:item_id", collectionHandler.RemoveCollectionItem) // 移除条目
+	}
+
+	// 分享的收藏夹
+	shared := router.Group("/shared-collections")
+	{
+		shared.GET("/:token", collectionHandler.GetSharedCollection)                       // 只读查看
+		shared.POST("/:token/clone", requireAuth, collectionHandler.CloneSharedCollection) // 复制到我的收藏夹
+	}
+}
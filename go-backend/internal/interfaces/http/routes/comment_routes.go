@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupCommentRoutes 设置知识点评论路由；评论可匿名浏览，发表、回复、点赞需登录，修改与删除仅限作者或审核人员
+func SetupCommentRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	knowledgePointService := services.NewKnowledgePointService(
+		repositories.NewKnowledgePointRepository(db),
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		repositories.NewTransactionManager(db),
+	)
+	commentService := services.NewCommentService(
+		repositories.NewCommentRepository(db),
+		repositories.NewNotificationRepository(db),
+		repositories.NewUserRepository(db),
+		knowledgePointService,
+	)
+
+	// 初始化处理器
+	commentHandler := handlers.NewCommentHandler(commentService)
+
+	requireAuth := authMiddleware.RequireAuth()
+
+	// 知识点讨论串
+	pointComments := router.Group("/knowledge-points/:id/comments")
+	{
+		pointComments.GET("", commentHandler.ListComments)                // 讨论串（分页，含回复树）
+		pointComments.POST("", requireAuth, commentHandler.CreateComment) // 发表评论
+	}
+
+	// 单条评论
+	comments := router.Group("/comments/:comment_id")
+	{
+		comments.GET("", commentHandler.GetComment)                         // 评论及其回复
+		comments.PUT("", requireAuth, commentHandler.UpdateComment)         // 修改评论（作者或审核人员）
+		comments.DELETE("", requireAuth, commentHandler.DeleteComment)      // 删除评论（保留讨论串结构）
+		comments.POST("/replies", requireAuth, commentHandler.ReplyComment) // 回复评论
+		comments.POST("/like", requireAuth, commentHandler.LikeComment)     // 点赞或取消点赞
+	}
+}
+
+// SetupNotificationRoutes 设置站内通知路由
+func SetupNotificationRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	// 初始化服务层
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+
+	// 初始化处理器
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+	// 通知路由组
+	notifications := router.Group("/notifications")
+	{
+		notifications.GET("", notificationHandler.ListNotifications)           // 我的通知（unread=true仅未读）
+		notifications.GET("/unread-count", notificationHandler.GetUnreadCount) // 未读通知数
+		notifications.POST("/read", notificationHandler.MarkRead)              // 标记已读（ids为空时全部）
+	}
+}
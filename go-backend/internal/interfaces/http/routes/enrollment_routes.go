@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupEnrollmentRoutes 设置公开学习路径报名路由；目录可匿名浏览，报名与进度需登录，公开状态仅限管理员设置
+func SetupEnrollmentRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	enrollmentService := services.NewEnrollmentService(
+		repositories.NewEnrollmentRepository(db),
+		repositories.NewLearningPathRepository(db),
+		repositories.NewKnowledgePointRepository(db),
+	)
+
+	// 初始化处理器
+	enrollmentHandler := handlers.NewEnrollmentHandler(enrollmentService)
+
+	requireAuth := authMiddleware.RequireAuth()
+	requireAdmin := authMiddleware.RequireRole(string(entities.RoleAdmin), "super_admin")
+
+	router.GET("/public-learning-paths", enrollmentHandler.ListPublicPaths)      // 公开学习路径目录及报名人数（q）
+	router.GET("/enrollments", requireAuth, enrollmentHandler.ListMyEnrollments) // 我的报名记录（status）
+
+	paths := router.Group("/learning-paths/:id")
+	{
+		paths.PUT("/visibility", requireAdmin, enrollmentHandler.SetPathVisibility)                    // 设置是否公开
+		paths.POST("/enroll", requireAuth, enrollmentHandler.Enroll)                                   // 报名
+		paths.DELETE("/enroll", requireAuth, enrollmentHandler.Unenroll)                               // 取消报名
+		paths.GET("/progress", requireAuth, enrollmentHandler.GetProgress)                             // 我的学习进度
+		paths.POST("/steps/:knowledge_point_id/start", requireAuth, enrollmentHandler.StartStep)       // 开始步骤
+		paths.POST("/steps/:knowledge_point_id/complete", requireAuth, enrollmentHandler.CompleteStep) // 完成步骤（time_spent）
+		paths.POST("/steps/:knowledge_point_id/time", requireAuth, enrollmentHandler.RecordStudyTime)  // 记录学习时长（time_spent）
+	}
+}
@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+	"sical-go-backend/pkg/storage"
+)
+
+// SetupFlashcardRoutes 设置闪卡路由；Anki牌组中的媒体以附件形式保存在store中
+func SetupFlashcardRoutes(router *gin.RouterGroup, db *gorm.DB, store storage.Storage, urlExpiry time.Duration) {
+	// 初始化仓储层
+	flashcardRepo := repositories.NewFlashcardRepository(db)
+	flashcardReviewRepo := repositories.NewFlashcardReviewRepository(db)
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	learningPathRepo := repositories.NewLearningPathRepository(db)
+	glossaryRepo := repositories.NewGlossaryRepository(db)
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	txManager := repositories.NewTransactionManager(db)
+	knowledgePointService := services.NewKnowledgePointService(
+		knowledgePointRepo,
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		attachmentRepo,
+		repositories.NewKnowledgePointLinkRepository(db),
+		glossaryRepo,
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		txManager,
+	)
+	attachmentService := services.NewAttachmentService(attachmentRepo, repositories.NewUserProfileRepository(db), store, urlExpiry)
+
+	// 初始化服务层
+	flashcardService := services.NewFlashcardService(
+		flashcardRepo,
+		flashcardReviewRepo,
+		knowledgePointRepo,
+		glossaryRepo,
+		knowledgePointService,
+	)
+	ankiService := services.NewAnkiService(
+		flashcardRepo,
+		flashcardReviewRepo,
+		knowledgePointRepo,
+		learningPathRepo,
+		repositories.NewEnrollmentRepository(db),
+		attachmentRepo,
+		knowledgePointService,
+		attachmentService,
+		txManager,
+	)
+
+	// 初始化处理器
+	flashcardHandler := handlers.NewFlashcardHandler(flashcardService)
+	ankiHandler := handlers.NewAnkiHandler(ankiService)
+
+	// 知识点下的闪卡路由
+	pointCards := router.Group("/knowledge-points/:id/flashcards")
+	{
+		pointCards.POST("", flashcardHandler.CreateFlashcard)               // 创建闪卡
+		pointCards.GET("", flashcardHandler.GetKnowledgePointFlashcards)    // 获取知识点闪卡
+		pointCards.POST("/cloze", flashcardHandler.GenerateClozeFlashcards) // 从内容挖空标记生成闪卡
+		pointCards.POST("/study", flashcardHandler.StudyKnowledgePoint)     // 加入复习计划
+	}
+
+	// 闪卡路由组
+	cards := router.Group("/flashcards")
+	{
+		cards.GET("/due", flashcardHandler.GetDueFlashcards)        // 获取到期闪卡
+		cards.GET("/export/apkg", ankiHandler.ExportApkg)           // 导出Anki牌组
+		cards.POST("/import/apkg", ankiHandler.ImportApkg)          // 导入Anki牌组
+		cards.GET("/:id", flashcardHandler.GetFlashcard)            // 获取单张闪卡
+		cards.PUT("/:id", flashcardHandler.UpdateFlashcard)         // 更新闪卡
+		cards.DELETE("/:id", flashcardHandler.DeleteFlashcard)      // 删除闪卡
+		cards.POST("/:id/check", flashcardHandler.CheckClozeAnswer) // 判定挖空作答
+		cards.POST("/:id/review", flashcardHandler.ReviewFlashcard) // 提交复习结果
+	}
+}
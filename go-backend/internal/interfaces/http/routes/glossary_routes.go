@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupGlossaryRoutes 设置术语表路由；查询接口公开，维护接口仅限管理员
+func SetupGlossaryRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化仓储层
+	glossaryRepo := repositories.NewGlossaryRepository(db)
+
+	// 初始化服务层（术语变更后需更新知识点提及关联）
+	knowledgePointService := services.NewKnowledgePointService(
+		repositories.NewKnowledgePointRepository(db),
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		glossaryRepo,
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		repositories.NewTransactionManager(db),
+	)
+	glossaryService := services.NewGlossaryService(glossaryRepo, knowledgePointService)
+
+	// 初始化处理器
+	glossaryHandler := handlers.NewGlossaryHandler(glossaryService)
+
+	// 术语表路由组
+	glossary := router.Group("/glossary")
+	requireAdmin := authMiddleware.RequireAdmin()
+	{
+		glossary.GET("", glossaryHandler.ListTerms)                       // 分页查询术语
+		glossary.GET("/:id", glossaryHandler.GetTerm)                     // 获取术语
+		glossary.POST("", requireAdmin, glossaryHandler.CreateTerm)       // 创建术语
+		glossary.PUT("/:id", requireAdmin, glossaryHandler.UpdateTerm)    // 更新术语
+		glossary.DELETE("/:id", requireAdmin, glossaryHandler.DeleteTerm) // 删除术语
+	}
+}
@@ -3,24 +3,87 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
 	"sical-go-backend/internal/infrastructure/repositories"
 	"sical-go-backend/internal/interfaces/http/handlers"
 )
 
-// SetupKnowledgePointRoutes 设置知识点路由
-func SetupKnowledgePointRoutes(router *gin.Engine, db *gorm.DB) {
+// SetupKnowledgePointRoutes 设置知识点路由；读取接口可匿名访问，仅返回已发布内容
+func SetupKnowledgePointRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
 	// 初始化仓储层
 	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	revisionRepo := repositories.NewKnowledgePointRevisionRepository(db)
+	reviewRepo := repositories.NewKnowledgePointReviewRepository(db)
+	flashcardRepo := repositories.NewFlashcardRepository(db)
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	linkRepo := repositories.NewKnowledgePointLinkRepository(db)
+	glossaryRepo := repositories.NewGlossaryRepository(db)
+	fingerprintRepo := repositories.NewKnowledgePointFingerprintRepository(db)
+	translationRepo := repositories.NewKnowledgePointTranslationRepository(db)
+	profileRepo := repositories.NewUserProfileRepository(db)
+	txManager := repositories.NewTransactionManager(db)
+
+	// 初始化服务层
+	knowledgePointService := services.NewKnowledgePointService(knowledgePointRepo, revisionRepo, reviewRepo, attachmentRepo, linkRepo, glossaryRepo, fingerprintRepo, txManager)
+	importService := services.NewKnowledgeImportService(knowledgePointRepo, knowledgePointService, txManager)
+	bundleService := services.NewKnowledgeBundleService(knowledgePointRepo, flashcardRepo, knowledgePointService, txManager)
+	graphService := services.NewKnowledgeGraphService(knowledgePointRepo, linkRepo, knowledgePointService)
+	similarityService := services.NewKnowledgeSimilarityService(knowledgePointRepo, knowledgePointService)
+	translationService := services.NewKnowledgeTranslationService(translationRepo, revisionRepo, profileRepo, glossaryRepo, knowledgePointService)
 
 	// 初始化处理器
-	knowledgePointHandler := handlers.NewKnowledgePointHandler(knowledgePointRepo)
+	knowledgePointHandler := handlers.NewKnowledgePointHandler(knowledgePointRepo, knowledgePointService, translationService)
+	importHandler := handlers.NewKnowledgeImportHandler(importService)
+	bundleHandler := handlers.NewKnowledgeBundleHandler(bundleService)
+	graphHandler := handlers.NewKnowledgeGraphHandler(graphService)
+	similarityHandler := handlers.NewKnowledgeSimilarityHandler(similarityService)
 
 	// 知识点路由组
-	knowledgeGroup := router.Group("/api/v1/knowledge-points")
-	// TODO: 添加认证中间件
+	knowledgeGroup := router.Group("/knowledge-points")
+	requireAuth := authMiddleware.RequireAuth()
+	requireModerator := authMiddleware.RequireRole(string(entities.RoleModerator), string(entities.RoleAdmin), "super_admin")
+	requireAdmin := authMiddleware.RequireAdmin()
 	{
-		// 创建知识点
-		knowledgeGroup.POST("/", knowledgePointHandler.CreateKnowledgePoint)
+		// 创建知识点（草稿）
+		knowledgeGroup.POST("/", requireAuth, knowledgePointHandler.CreateKnowledgePoint)
+		
+		// 知识点目录（组合筛选、排序、游标分页、分面统计）
+		knowledgeGroup.GET("/", knowledgePointHandler.ListKnowledgePoints)
+		
+		// 批量导入（CSV、JSON Lines、Markdown）
+		knowledgeGroup.POST("/import", requireAuth, importHandler.ImportKnowledgePoints)
+		
+		// 知识库导出包（跨环境迁移、共享）
+		knowledgeGroup.GET("/export", requireModerator, bundleHandler.ExportBundle)     // 导出知识库（可按category导出）
+		knowledgeGroup.GET("/export/schema", bundleHandler.GetBundleSchema)             // 导出包JSON Schema
+		knowledgeGroup.POST("/import/bundle", requireAdmin, bundleHandler.ImportBundle) // 导入导出包（按ID幂等更新）
+		
+		// 重建全部知识点的提及关联
+		knowledgeGroup.POST("/links/rebuild", requireAdmin, knowledgePointHandler.RebuildLinks)
+		
+		// 知识图谱（format=json|graphml|dot，related=true时包含内容关联边）
+		knowledgeGroup.GET("/graph", graphHandler.GetCategoryGraph)             // 分类子图（category）
+		knowledgeGroup.GET("/graph/chain", graphHandler.GetPrerequisiteChain)   // 最短前置关系链（from、to）
+		knowledgeGroup.GET("/:id/graph/ancestors", graphHandler.GetAncestors)     // 前置知识点（depth）
+		knowledgeGroup.GET("/:id/graph/descendants", graphHandler.GetDescendants) // 后续知识点（depth）
+		
+		// 语义相似（进程内TF-IDF向量索引，limit为结果数量）
+		knowledgeGroup.GET("/similar", similarityHandler.GetSimilarToText) // 与文本相似的知识点（text）
+		knowledgeGroup.GET("/:id/similar", similarityHandler.GetSimilar)   // 更多类似内容
+		
+		// 重复检测与合并
+		knowledgeGroup.GET("/duplicates", requireAdmin, knowledgePointHandler.GetDuplicateReport)             // 疑似重复簇报告（threshold）
+		knowledgeGroup.POST("/duplicates/rebuild", requireAdmin, knowledgePointHandler.RebuildFingerprints)   // 重建查重指纹
+		knowledgeGroup.GET("/:id/duplicates", knowledgePointHandler.GetDuplicates)                            // 与该知识点疑似重复的知识点
+		knowledgeGroup.POST("/:id/merge", requireAdmin, knowledgePointHandler.MergeKnowledgePoint)            // 合并重复知识点到该知识点
+		
+		// 我的知识点（含草稿）
+		knowledgeGroup.GET("/mine", requireAuth, knowledgePointHandler.GetMyKnowledgePoints)
+		
+		// 待审核队列
+		knowledgeGroup.GET("/review-queue", requireModerator, knowledgePointHandler.GetReviewQueue)
 		
 		// 获取单个知识点
 		knowledgeGroup.GET("/:id", knowledgePointHandler.GetKnowledgePoint)
@@ -35,9 +98,36 @@ func SetupKnowledgePointRoutes(router *gin.Engine, db *gorm.DB) {
 		knowledgeGroup.GET("/search", knowledgePointHandler.SearchKnowledgePoints)
 		
 		// 更新知识点
-		knowledgeGroup.PUT("/:id", knowledgePointHandler.UpdateKnowledgePoint)
+		knowledgeGroup.PUT("/:id", requireAuth, knowledgePointHandler.UpdateKnowledgePoint)
 		
 		// 删除知识点
-		knowledgeGroup.DELETE("/:id", knowledgePointHandler.DeleteKnowledgePoint)
+		knowledgeGroup.DELETE("/:id", requireAuth, knowledgePointHandler.DeleteKnowledgePoint)
+		
+		// 修订历史
+		knowledgeGroup.GET("/:id/revisions", knowledgePointHandler.ListRevisions)                       // 获取修订历史
+		knowledgeGroup.GET("/:id/revisions/compare", knowledgePointHandler.CompareRevisions)            // 比较两个版本（from、to）
+		knowledgeGroup.GET("/:id/revisions/:version", knowledgePointHandler.GetRevision)                // 获取指定版本
+		knowledgeGroup.POST("/:id/revisions/:version/restore", requireAuth, knowledgePointHandler.RestoreRevision) // 恢复到指定版本
+		
+		// 学习资源
+		knowledgeGroup.GET("/:id/resources", knowledgePointHandler.ListResources)                               // 获取学习资源
+		knowledgeGroup.POST("/:id/resources", requireAuth, knowledgePointHandler.AddResource)                   // 添加学习资源
+		knowledgeGroup.PUT("/:id/resources/:resource_id", requireAuth, knowledgePointHandler.UpdateResource)    // 更新学习资源
+		knowledgeGroup.DELETE("/:id/resources/:resource_id", requireAuth, knowledgePointHandler.DeleteResource) // 删除学习资源
+		
+		// 多语言译文（lang参数、用户资料语言或Accept-Language决定读取接口返回的语言）
+		knowledgeGroup.GET("/:id/translations", knowledgePointHandler.ListTranslations)                        // 各语言翻译状态
+		knowledgeGroup.GET("/:id/translations/:locale", knowledgePointHandler.GetTranslation)                  // 获取指定语言译文
+		knowledgeGroup.PUT("/:id/translations/:locale", requireAuth, knowledgePointHandler.SaveTranslation)    // 创建或更新译文
+		knowledgeGroup.DELETE("/:id/translations/:locale", requireAuth, knowledgePointHandler.DeleteTranslation) // 删除译文
+		
+		// 内容提及关联（与前置关系相互独立）
+		knowledgeGroup.GET("/:id/related", knowledgePointHandler.GetRelatedKnowledgePoints) // 内容中提及的知识点
+		knowledgeGroup.GET("/:id/backlinks", knowledgePointHandler.GetBacklinks)             // 提及该知识点的知识点
+		
+		// 审核流程
+		knowledgeGroup.POST("/:id/transitions", requireAuth, knowledgePointHandler.TransitionKnowledgePoint) // 状态流转（提交、通过、退回、发布、归档、重新打开）
+		knowledgeGroup.GET("/:id/reviews", knowledgePointHandler.ListReviews)                               // 获取审核记录
+		knowledgeGroup.POST("/:id/reviews", requireAuth, knowledgePointHandler.AddReviewComment)            // 添加审核意见
 	}
 }
\ No newline at end of file
@@ -15,11 +15,24 @@ func SetupLearningPathRoutes(router *gin.Engine, db *gorm.DB) {
 	learningPathRepo := repositories.NewLearningPathRepository(db)
 	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
 
-	// 初始化服务层
+	// 初始化服务层（关注领域按语义相似度匹配知识点）
+	knowledgePointService := services.NewKnowledgePointService(
+		knowledgePointRepo,
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		repositories.NewTransactionManager(db),
+	)
+	similarityService := services.NewKnowledgeSimilarityService(knowledgePointRepo, knowledgePointService)
 	pathService := services.NewLearningPathService(
 		learningPathRepo,
 		learningGoalRepo,
 		knowledgePointRepo,
+		repositories.NewEnrollmentRepository(db),
+		similarityService,
 	)
 
 	// 初始化处理器
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupLearningPathTemplateRoutes 设置学习路径模板路由；目录可匿名浏览，实例化需登录，模板管理仅限管理员
+func SetupLearningPathTemplateRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	templateService := services.NewLearningPathTemplateService(
+		repositories.NewLearningPathTemplateRepository(db),
+		repositories.NewLearningGoalRepository(db),
+		repositories.NewLearningPathRepository(db),
+		repositories.NewKnowledgePointRepository(db),
+		repositories.NewKnowledgeRecommendationRepository(db),
+	)
+
+	// 初始化处理器
+	templateHandler := handlers.NewLearningPathTemplateHandler(templateService)
+
+	requireAuth := authMiddleware.RequireAuth()
+	requireAdmin := authMiddleware.RequireRole(string(entities.RoleAdmin), "super_admin")
+
+	templates := router.Group("/learning-path-templates")
+	{
+		templates.GET("", templateHandler.ListTemplates)                                              // 模板目录（category、difficulty、q）
+		templates.GET("/:template_id", templateHandler.GetTemplate)                                   // 模板详情与步骤
+		templates.POST("/:template_id/instantiate", requireAuth, templateHandler.InstantiateTemplate) // 为学习目标实例化（可跳过已掌握的知识点）
+		templates.POST("", requireAdmin, templateHandler.CreateTemplate)                              // 创建模板
+		templates.PUT("/:template_id", requireAdmin, templateHandler.UpdateTemplate)                  // 更新模板
+		templates.DELETE("/:template_id", requireAdmin, templateHandler.DeleteTemplate)               // 删除模板
+	}
+}
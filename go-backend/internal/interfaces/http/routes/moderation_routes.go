@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupModerationRoutes 设置内容举报与审核路由；举报需登录，审核队列与审核操作仅限审核人员
+func SetupModerationRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	txManager := repositories.NewTransactionManager(db)
+	knowledgePointService := services.NewKnowledgePointService(
+		repositories.NewKnowledgePointRepository(db),
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		txManager,
+	)
+	moderationService := services.NewModerationService(
+		repositories.NewModerationRepository(db),
+		repositories.NewCommentRepository(db),
+		repositories.NewRatingRepository(db),
+		repositories.NewNotificationRepository(db),
+		repositories.NewUserRepository(db),
+		knowledgePointService,
+		txManager,
+	)
+
+	// 初始化处理器
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+
+	requireModerator := authMiddleware.RequireRole(string(entities.RoleModerator), string(entities.RoleAdmin), "super_admin")
+
+	// 用户举报
+	reports := router.Group("/reports")
+	{
+		reports.POST("", moderationHandler.CreateReport)      // 举报评论、评价或知识点
+		reports.GET("/mine", moderationHandler.ListMyReports) // 我的举报及处理状态
+	}
+
+	// 审核
+	moderation := router.Group("/moderation", requireModerator)
+	{
+		moderation.GET("/queue", moderationHandler.GetQueue)                         // 审核队列（target_type）
+		moderation.POST("/reports/:report_id/actions", moderationHandler.TakeAction) // 处理举报
+		moderation.GET("/actions", moderationHandler.ListActions)                    // 审核记录（moderator_id、user_id、target_type、target_id）
+	}
+}
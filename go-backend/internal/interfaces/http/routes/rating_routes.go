@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupRatingRoutes 设置评分与评价路由；评价列表可匿名访问，评分需登录，隐藏评价仅限审核人员
+func SetupRatingRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	knowledgePointService := services.NewKnowledgePointService(
+		repositories.NewKnowledgePointRepository(db),
+		repositories.NewKnowledgePointRevisionRepository(db),
+		repositories.NewKnowledgePointReviewRepository(db),
+		repositories.NewAttachmentRepository(db),
+		repositories.NewKnowledgePointLinkRepository(db),
+		repositories.NewGlossaryRepository(db),
+		repositories.NewKnowledgePointFingerprintRepository(db),
+		repositories.NewTransactionManager(db),
+	)
+	ratingService := services.NewRatingService(
+		repositories.NewRatingRepository(db),
+		repositories.NewLearningPathRepository(db),
+		knowledgePointService,
+	)
+
+	// 初始化处理器
+	ratingHandler := handlers.NewRatingHandler(ratingService)
+
+	requireAuth := authMiddleware.RequireAuth()
+	requireModerator := authMiddleware.RequireRole(string(entities.RoleModerator), string(entities.RoleAdmin), "super_admin")
+
+	// 知识点评分
+	pointRatings := router.Group("/knowledge-points/:id")
+	{
+		pointRatings.GET("/rating", requireAuth, ratingHandler.GetMyKnowledgePointRating)     // 我的评分
+		pointRatings.PUT("/rating", requireAuth, ratingHandler.SaveKnowledgePointRating)      // 评分（1-5星）与文字评价
+		pointRatings.DELETE("/rating", requireAuth, ratingHandler.DeleteKnowledgePointRating) // 删除我的评分
+		pointRatings.GET("/ratings", ratingHandler.ListKnowledgePointReviews)                 // 文字评价与评分汇总
+	}
+
+	// 学习路径评分
+	pathRatings := router.Group("/learning-paths/:id")
+	{
+		pathRatings.GET("/rating", requireAuth, ratingHandler.GetMyLearningPathRating)     // 我的评分
+		pathRatings.PUT("/rating", requireAuth, ratingHandler.SaveLearningPathRating)      // 评分（1-5星）与文字评价
+		pathRatings.DELETE("/rating", requireAuth, ratingHandler.DeleteLearningPathRating) // 删除我的评分
+		pathRatings.GET("/ratings", ratingHandler.ListLearningPathReviews)                 // 文字评价与评分汇总
+	}
+
+	// 评价审核
+	reviews := router.Group("/ratings", requireModerator)
+	{
+		reviews.GET("", ratingHandler.ListReviewsForModeration)        // 全部文字评价（target_type、hidden）
+		reviews.POST("/:review_id/hide", ratingHandler.HideReview)     // 隐藏评价
+		reviews.POST("/:review_id/unhide", ratingHandler.UnhideReview) // 恢复评价
+	}
+}
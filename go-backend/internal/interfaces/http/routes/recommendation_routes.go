@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupRecommendationRoutes 设置学习推荐路由
+func SetupRecommendationRoutes(router *gin.RouterGroup, db *gorm.DB, authMiddleware *middleware.AuthMiddleware) {
+	// 初始化服务层
+	recommendationService := services.NewKnowledgeRecommendationService(
+		repositories.NewKnowledgeRecommendationRepository(db),
+		repositories.NewKnowledgePointRepository(db),
+	)
+
+	// 初始化处理器
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+
+	// 推荐路由组
+	recommendations := router.Group("/recommendations")
+	{
+		recommendations.GET("/knowledge-points", recommendationHandler.GetKnowledgePointRecommendations)                  // 下一步学习的知识点
+		recommendations.POST("/recompute", authMiddleware.RequireAdmin(), recommendationHandler.RecomputeRecommendations) // 立即重新计算推荐模型
+	}
+}
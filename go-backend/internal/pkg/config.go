@@ -7,16 +7,20 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+
+	"sical-go-backend/pkg/storage"
 )
 
 // Config 应用配置结构
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Database DatabaseConfig `json:"database"`
-	Redis    RedisConfig    `json:"redis"`
-	JWT      JWTConfig      `json:"jwt"`
-	App      AppConfig      `json:"app"`
-	Log      LogConfig      `json:"log"`
+	Server         ServerConfig         `json:"server"`
+	Database       DatabaseConfig       `json:"database"`
+	Redis          RedisConfig          `json:"redis"`
+	JWT            JWTConfig            `json:"jwt"`
+	App            AppConfig            `json:"app"`
+	Log            LogConfig            `json:"log"`
+	Storage        StorageConfig        `json:"storage"`
+	Recommendation RecommendationConfig `json:"recommendation"`
 }
 
 // ServerConfig 服务器配置
@@ -85,6 +89,28 @@ type LogConfig struct {
 	Compress   bool   `json:"compress"`
 }
 
+// StorageConfig 附件存储配置
+type StorageConfig struct {
+	Backend       string        `json:"backend"`         // local, s3
+	LocalPath     string        `json:"local_path"`
+	PublicBaseURL string        `json:"public_base_url"` // 本地存储的下载地址前缀
+	SigningSecret string        `json:"-"`               // 本地下载地址签名密钥，默认使用JWT密钥
+	URLExpiry     time.Duration `json:"url_expiry"`      // 下载地址有效期
+	GCInterval    time.Duration `json:"gc_interval"`     // 未引用附件清理间隔，0表示不清理
+	GCGracePeriod time.Duration `json:"gc_grace_period"` // 上传后未被引用的保留时长
+	S3Endpoint    string        `json:"s3_endpoint"`
+	S3Region      string        `json:"s3_region"`
+	S3Bucket      string        `json:"s3_bucket"`
+	S3AccessKey   string        `json:"-"`
+	S3SecretKey   string        `json:"-"`
+	S3PathStyle   bool          `json:"s3_path_style"`
+}
+
+// RecommendationConfig 学习推荐配置
+type RecommendationConfig struct {
+	Interval time.Duration `json:"interval"` // 推荐模型重新计算间隔，0表示不定期计算
+}
+
 // LoadConfig 加载配置
 func LoadConfig() (*Config, error) {
 	// 加载.env文件
@@ -148,6 +174,24 @@ func LoadConfig() (*Config, error) {
 			MaxAge:     getEnvAsInt("LOG_MAX_AGE", 28),
 			Compress:   getEnvAsBool("LOG_COMPRESS", true),
 		},
+		Storage: StorageConfig{
+			Backend:       getEnv("STORAGE_BACKEND", "local"),
+			LocalPath:     getEnv("STORAGE_LOCAL_PATH", "uploads"),
+			PublicBaseURL: getEnv("STORAGE_PUBLIC_BASE_URL", "/api/v1/files"),
+			SigningSecret: getEnv("STORAGE_SIGNING_SECRET", getEnv("JWT_SECRET", "")),
+			URLExpiry:     getEnvAsDuration("STORAGE_URL_EXPIRY", "15m"),
+			GCInterval:    getEnvAsDuration("STORAGE_GC_INTERVAL", "1h"),
+			GCGracePeriod: getEnvAsDuration("STORAGE_GC_GRACE_PERIOD", "24h"),
+			S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+			S3Region:      getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("S3_BUCKET", ""),
+			S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:   getEnvAsBool("S3_PATH_STYLE", false),
+		},
+		Recommendation: RecommendationConfig{
+			Interval: getEnvAsDuration("RECOMMENDATION_INTERVAL", "6h"),
+		},
 	}
 
 	// 验证配置
@@ -205,6 +249,22 @@ func (c *Config) GetDSN() string {
 	}
 }
 
+// GetStorageConfig 获取附件存储配置
+func (c *Config) GetStorageConfig() *storage.Config {
+	return &storage.Config{
+		Backend:       c.Storage.Backend,
+		LocalPath:     c.Storage.LocalPath,
+		PublicBaseURL: c.Storage.PublicBaseURL,
+		SigningSecret: c.Storage.SigningSecret,
+		S3Endpoint:    c.Storage.S3Endpoint,
+		S3Region:      c.Storage.S3Region,
+		S3Bucket:      c.Storage.S3Bucket,
+		S3AccessKey:   c.Storage.S3AccessKey,
+		S3SecretKey:   c.Storage.S3SecretKey,
+		S3PathStyle:   c.Storage.S3PathStyle,
+	}
+}
+
 // GetRedisAddr 获取Redis地址
 func (c *Config) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
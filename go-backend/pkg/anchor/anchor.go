@@ -0,0 +1,147 @@
+// Package anchor 实现文本引文锚定（text-quote anchoring）：以选中的原文及其前后文记录文本区间，
+// 内容修订后按引文与上下文重新定位，位置偏移仅作为提示。
+// 所有文本先经Normalize折叠空白，位置均为规范化文本中的字符（rune）偏移。
+package anchor
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ContextLength 记录的前后文长度（字符）
+const ContextLength = 32
+
+// ErrInvalidRange 区间无效
+var ErrInvalidRange = errors.New("文本区间无效")
+
+// Selector 引文选择器
+type Selector struct {
+	Exact  string `json:"exact"`  // 选中的原文
+	Prefix string `json:"prefix"` // 原文之前的上下文
+	Suffix string `json:"suffix"` // 原文之后的上下文
+	Start  int    `json:"start"`  // 创建时的起始位置，仅作为定位提示
+	End    int    `json:"end"`
+}
+
+// Normalize 将连续空白折叠为一个空格并去除首尾空白
+func Normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(text, unicode.IsSpace), " ")
+}
+
+// collapse 将连续空白折叠为一个空格，保留首尾的空格
+func collapse(text string) string {
+	var buf strings.Builder
+	space := false
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			buf.WriteByte(' ')
+			space = false
+		}
+		buf.WriteRune(r)
+	}
+	if space {
+		buf.WriteByte(' ')
+	}
+	return buf.String()
+}
+
+// FromRange 根据规范化文本中的[start, end)区间创建选择器，区间两端的空白不计入引文
+func FromRange(text string, start, end int) (*Selector, error) {
+	runes := []rune(text)
+	if start < 0 || end > len(runes) || start >= end {
+		return nil, ErrInvalidRange
+	}
+	for start < end && unicode.IsSpace(runes[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+	if start == end {
+		return nil, ErrInvalidRange
+	}
+	return &Selector{
+		Exact:  string(runes[start:end]),
+		Prefix: string(runes[max(0, start-ContextLength):start]),
+		Suffix: string(runes[end:min(len(runes), end+ContextLength)]),
+		Start:  start,
+		End:    end,
+	}, nil
+}
+
+// Locate 在规范化文本中定位选择器，返回区间与是否找到。
+// 引文出现多次时选取前后文最吻合的一处，吻合程度相同时选取离原位置最近的一处
+func Locate(text string, selector *Selector) (int, int, bool) {
+	exact := Normalize(selector.Exact)
+	if exact == "" {
+		return 0, 0, false
+	}
+	length := utf8.RuneCountInString(exact)
+	runes := []rune(text)
+
+	// 原位置未变化
+	if selector.Start >= 0 && selector.Start+length <= len(runes) &&
+		string(runes[selector.Start:selector.Start+length]) == exact {
+		if score(runes, selector.Start, selector.Start+length, selector) == contextScore(selector) {
+			return selector.Start, selector.Start + length, true
+		}
+	}
+
+	bestStart, bestScore, bestDistance := -1, -1, 0
+	offset, runeOffset := 0, 0
+	for {
+		index := strings.Index(text[offset:], exact)
+		if index < 0 {
+			break
+		}
+		runeOffset += utf8.RuneCountInString(text[offset : offset+index])
+		start := runeOffset
+		candidate := score(runes, start, start+length, selector)
+		distance := abs(start - selector.Start)
+		if candidate > bestScore || (candidate == bestScore && distance < bestDistance) {
+			bestStart, bestScore, bestDistance = start, candidate, distance
+		}
+
+		// 从下一个字符继续查找，允许重叠出现
+		_, size := utf8.DecodeRuneInString(text[offset+index:])
+		offset += index + size
+		runeOffset++
+	}
+	if bestStart < 0 {
+		return 0, 0, false
+	}
+	return bestStart, bestStart + length, true
+}
+
+// score 计算候选位置的前后文吻合字符数
+func score(runes []rune, start, end int, selector *Selector) int {
+	prefix := []rune(collapse(selector.Prefix))
+	suffix := []rune(collapse(selector.Suffix))
+	matched := 0
+	for i := 1; i <= len(prefix) && start-i >= 0 && runes[start-i] == prefix[len(prefix)-i]; i++ {
+		matched++
+	}
+	for i := 0; i < len(suffix) && end+i < len(runes) && runes[end+i] == suffix[i]; i++ {
+		matched++
+	}
+	return matched
+}
+
+// contextScore 前后文完全吻合时的得分
+func contextScore(selector *Selector) int {
+	return utf8.RuneCountInString(collapse(selector.Prefix)) + utf8.RuneCountInString(collapse(selector.Suffix))
+}
+
+// abs 整数绝对值
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,141 @@
+package anchor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		selector  Selector
+		wantStart int
+		wantEnd   int
+		wantFound bool
+	}{
+		{
+			name:      "原位置未变化",
+			text:      "the quick brown fox",
+			selector:  Selector{Exact: "quick", Prefix: "the ", Suffix: " brown fox", Start: 4, End: 9},
+			wantStart: 4, wantEnd: 9, wantFound: true,
+		},
+		{
+			name:      "前方插入内容后偏移",
+			text:      "oh the quick brown fox",
+			selector:  Selector{Exact: "quick", Prefix: "the ", Suffix: " brown fox", Start: 4, End: 9},
+			wantStart: 7, wantEnd: 12, wantFound: true,
+		},
+		{
+			name:      "多次出现时按前后文选取",
+			text:      "x a cat sat. a cat ran.",
+			selector:  Selector{Exact: "cat", Prefix: "a cat sat. a ", Suffix: " ran.", Start: 13, End: 16},
+			wantStart: 15, wantEnd: 18, wantFound: true,
+		},
+		{
+			name:      "原位置前后文已变化时重新定位",
+			text:      "x foo y foo z",
+			selector:  Selector{Exact: "foo", Prefix: "y ", Suffix: " z", Start: 2, End: 5},
+			wantStart: 8, wantEnd: 11, wantFound: true,
+		},
+		{
+			name:      "前后文相同时选取离原位置最近的一处",
+			text:      "ab ab ab",
+			selector:  Selector{Exact: "ab", Start: 4, End: 6},
+			wantStart: 3, wantEnd: 5, wantFound: true,
+		},
+		{
+			name:      "允许重叠出现",
+			text:      "aaa",
+			selector:  Selector{Exact: "aa", Start: 1, End: 3},
+			wantStart: 1, wantEnd: 3, wantFound: true,
+		},
+		{
+			name:      "按字符计算中文位置",
+			text:      "知识点：二叉树的遍历",
+			selector:  Selector{Exact: "二叉树", Prefix: "知识点：", Suffix: "的遍历", Start: 0, End: 3},
+			wantStart: 4, wantEnd: 7, wantFound: true,
+		},
+		{
+			name:      "引文中的空白被折叠",
+			text:      "the quick brown fox",
+			selector:  Selector{Exact: "quick \n\t brown", Start: 4, End: 15},
+			wantStart: 4, wantEnd: 15, wantFound: true,
+		},
+		{
+			name:      "引文已被删除",
+			text:      "the lazy dog",
+			selector:  Selector{Exact: "quick", Start: 4, End: 9},
+			wantFound: false,
+		},
+		{
+			name:      "空引文",
+			text:      "the quick brown fox",
+			selector:  Selector{Exact: " \n", Start: 0, End: 1},
+			wantFound: false,
+		},
+		{
+			name:      "原位置超出文本长度",
+			text:      "fox",
+			selector:  Selector{Exact: "fox", Start: 40, End: 43},
+			wantStart: 0, wantEnd: 3, wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, found := Locate(tt.text, &tt.selector)
+			if found != tt.wantFound {
+				t.Fatalf("Locate() found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Fatalf("Locate() = [%d, %d), want [%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFromRangeLocateRoundTrip(t *testing.T) {
+	text := Normalize("第一章  绪论\n\n本章介绍 二叉树 的基本概念，二叉树 是一种常用的数据结构。")
+	tests := []struct {
+		name       string
+		start, end int
+		wantExact  string
+		wantErr    error
+	}{
+		{name: "中文区间", start: 12, end: 15, wantExact: "二叉树"},
+		{name: "去除两端空白", start: 11, end: 16, wantExact: "二叉树"},
+		{name: "第二次出现", start: 22, end: 25, wantExact: "二叉树"},
+		{name: "空区间", start: 5, end: 5, wantErr: ErrInvalidRange},
+		{name: "仅含空白", start: 11, end: 12, wantErr: ErrInvalidRange},
+		{name: "越界", start: 0, end: 1000, wantErr: ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := FromRange(text, tt.start, tt.end)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FromRange() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromRange() error = %v", err)
+			}
+			if selector.Exact != tt.wantExact {
+				t.Fatalf("FromRange() Exact = %q, want %q", selector.Exact, tt.wantExact)
+			}
+
+			// 在前方插入内容后仍定位到同一处引文
+			edited := "前言 " + text
+			start, end, found := Locate(edited, selector)
+			if !found || start != selector.Start+3 || end != selector.End+3 {
+				t.Fatalf("Locate() = [%d, %d) %v, want [%d, %d) true", start, end, found, selector.Start+3, selector.End+3)
+			}
+		})
+	}
+}
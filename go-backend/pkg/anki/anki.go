@@ -0,0 +1,145 @@
+package anki
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CardState 卡片学习状态
+type CardState int
+
+const (
+	CardStateNew      CardState = 0
+	CardStateLearning CardState = 1
+	CardStateReview   CardState = 2
+)
+
+// 字段分隔符与默认值
+const (
+	fieldSeparator = "\x1f"
+	defaultFactor  = 2500
+	defaultDeckID  = int64(1)
+	schemaVersion  = 11
+)
+
+// Package Anki牌组包（.apkg）的内存表示
+type Package struct {
+	Notes []Note
+	Media map[string][]byte // 文件名 -> 内容；读取时仅包含笔记引用且未超出大小上限的文件
+
+	MediaCount int // 读取时媒体清单中的文件数
+}
+
+// Note 笔记；一条笔记可生成多张卡片
+type Note struct {
+	GUID   string
+	Cloze  bool
+	Fields []string // 基础笔记为[正面, 背面]，cloze笔记为[文本, 补充]
+	Tags   []string
+	Deck   string // 牌组名称，层级以"::"分隔
+	Cards  []Card
+}
+
+// Card 卡片及其调度状态
+type Card struct {
+	Ord        int // 模板序号；cloze卡片为挖空序号减一
+	State      CardState
+	Suspended  bool
+	DueAt      time.Time
+	Interval   int     // 复习间隔(天)
+	EaseFactor float64 // 难度系数，如2.5
+	Reps       int
+	Lapses     int
+}
+
+// GUID 根据给定键生成稳定的笔记GUID，便于重复导入时Anki识别为同一笔记
+func GUID(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// htmlTagRegex 匹配HTML标签
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// StripHTML 去除HTML标签
+func StripHTML(s string) string {
+	return strings.TrimSpace(htmlTagRegex.ReplaceAllString(s, ""))
+}
+
+// mediaRefRegex 匹配字段中<img>标签引用的媒体文件名
+var mediaRefRegex = regexp.MustCompile(`(?i)(<img\b[^>]*?\bsrc=)(["']?)([^"'>\s]+)`)
+
+// MediaRefs 提取字段中引用的媒体文件名（去重）
+func MediaRefs(field string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range mediaRefRegex.FindAllStringSubmatch(field, -1) {
+		if name := match[3]; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ReplaceMediaRefs 将字段中引用的媒体文件名替换为replace的返回值，返回空字符串时保持原样
+func ReplaceMediaRefs(field string, replace func(name string) string) string {
+	return mediaRefRegex.ReplaceAllStringFunc(field, func(match string) string {
+		parts := mediaRefRegex.FindStringSubmatch(match)
+		name := replace(parts[3])
+		if name == "" {
+			return match
+		}
+		return parts[1] + parts[2] + name
+	})
+}
+
+// checksum 计算字段校验和（sha1前8位十六进制转整数），与Anki一致
+func checksum(field string) int64 {
+	sum := sha1.Sum([]byte(StripHTML(field)))
+	value, _ := strconv.ParseInt(hex.EncodeToString(sum[:])[:8], 16, 64)
+	return value
+}
+
+// dayStart 获取给定时间当天零点
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// schemaSQL Anki collection（schema 11）建表语句
+const schemaSQL = `
+CREATE TABLE col (
+    id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+    ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+    conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null,
+    csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null,
+    due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+    lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+    flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+    id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+    ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+    type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
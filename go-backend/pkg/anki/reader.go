@@ -0,0 +1,270 @@
+package anki
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 解压大小上限，防止压缩炸弹
+const (
+	maxCollectionSize = 512 << 20
+	maxManifestSize   = 16 << 20
+	maxMediaFileSize  = 10 << 20
+	maxMediaTotalSize = 256 << 20
+)
+
+// Read 读取.apkg牌组包
+func Read(r io.ReaderAt, size int64) (*Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的apkg文件: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	// 优先使用anki21（2.1版本调度），其次anki2
+	var collection *zip.File
+	for _, name := range []string{"collection.anki21", "collection.anki2"} {
+		if file, ok := files[name]; ok {
+			collection = file
+			break
+		}
+	}
+	if collection == nil {
+		if _, ok := files["collection.anki21b"]; ok {
+			return nil, fmt.Errorf("不支持新版压缩格式，请在Anki导出时勾选\"支持旧版Anki\"")
+		}
+		return nil, fmt.Errorf("apkg文件中缺少collection")
+	}
+
+	dir, err := os.MkdirTemp("", "apkg-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	collectionPath := filepath.Join(dir, "collection.db")
+	if err := extractFile(collection, collectionPath); err != nil {
+		return nil, err
+	}
+
+	notes, err := readCollection(collectionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	media, mediaCount, err := readMedia(files, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Package{Notes: notes, Media: media, MediaCount: mediaCount}, nil
+}
+
+// readCollection 从SQLite collection读取笔记与卡片
+func readCollection(path string) ([]Note, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("打开collection失败: %w", err)
+	}
+	defer db.Close()
+
+	var crtUnix int64
+	var modelsJSON, decksJSON string
+	if err := db.QueryRow(`SELECT crt, models, decks FROM col LIMIT 1`).Scan(&crtUnix, &modelsJSON, &decksJSON); err != nil {
+		return nil, fmt.Errorf("读取集合信息失败: %w", err)
+	}
+	crt := dayStart(time.Unix(crtUnix, 0))
+
+	var models map[string]struct {
+		Type int `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(modelsJSON), &models); err != nil {
+		return nil, fmt.Errorf("解析笔记类型失败: %w", err)
+	}
+	var decks map[string]struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(decksJSON), &decks); err != nil {
+		return nil, fmt.Errorf("解析牌组失败: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT id, guid, mid, tags, flds FROM notes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("读取笔记失败: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []Note
+	index := make(map[int64]int)
+	for rows.Next() {
+		var id, mid int64
+		var guid, tags, flds string
+		if err := rows.Scan(&id, &guid, &mid, &tags, &flds); err != nil {
+			return nil, fmt.Errorf("读取笔记失败: %w", err)
+		}
+		model := models[strconv.FormatInt(mid, 10)]
+		index[id] = len(notes)
+		notes = append(notes, Note{
+			GUID:   guid,
+			Cloze:  model.Type == 1,
+			Fields: strings.Split(flds, fieldSeparator),
+			Tags:   strings.Fields(tags),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取笔记失败: %w", err)
+	}
+
+	cardRows, err := db.Query(`SELECT nid, did, ord, type, queue, due, ivl, factor, reps, lapses FROM cards ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("读取卡片失败: %w", err)
+	}
+	defer cardRows.Close()
+
+	for cardRows.Next() {
+		var nid, did, due int64
+		var ord, cardType, queue, ivl, factor, reps, lapses int
+		if err := cardRows.Scan(&nid, &did, &ord, &cardType, &queue, &due, &ivl, &factor, &reps, &lapses); err != nil {
+			return nil, fmt.Errorf("读取卡片失败: %w", err)
+		}
+		i, ok := index[nid]
+		if !ok {
+			continue
+		}
+		if notes[i].Deck == "" {
+			notes[i].Deck = decks[strconv.FormatInt(did, 10)].Name
+		}
+		notes[i].Cards = append(notes[i].Cards, fromAnkiSchedule(ord, cardType, queue, due, ivl, factor, reps, lapses, crt))
+	}
+	if err := cardRows.Err(); err != nil {
+		return nil, fmt.Errorf("读取卡片失败: %w", err)
+	}
+
+	return notes, nil
+}
+
+// fromAnkiSchedule 将Anki的调度字段转换为卡片调度状态
+func fromAnkiSchedule(ord, cardType, queue int, due int64, ivl, factor, reps, lapses int, crt time.Time) Card {
+	card := Card{
+		Ord:       ord,
+		State:     CardStateNew,
+		Suspended: queue == -1,
+		Reps:      reps,
+		Lapses:    lapses,
+	}
+	if factor > 0 {
+		card.EaseFactor = float64(factor) / 1000
+	}
+
+	switch cardType {
+	case 2, 3: // 复习中，或重新学习中
+		card.State = CardStateReview
+		card.Interval = ivl
+		if queue == 1 || queue == 3 {
+			// 重新学习队列的due为时间戳或天数，保守处理为今天到期
+			card.DueAt = dayStart(time.Now())
+		} else {
+			card.DueAt = crt.AddDate(0, 0, int(due))
+		}
+	case 1: // 学习中，due为Unix时间戳
+		card.State = CardStateLearning
+		card.DueAt = time.Unix(due, 0)
+	}
+	return card
+}
+
+// readMedia 读取笔记引用的媒体文件，返回文件名到内容的映射及媒体清单中的文件数；
+// 未被引用或超出大小上限的文件不解压
+func readMedia(files map[string]*zip.File, notes []Note) (map[string][]byte, int, error) {
+	media := make(map[string][]byte)
+	manifest, ok := files["media"]
+	if !ok {
+		return media, 0, nil
+	}
+
+	rc, err := manifest.Open()
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取媒体清单失败: %w", err)
+	}
+	defer rc.Close()
+
+	var mapping map[string]string
+	if err := json.NewDecoder(io.LimitReader(rc, maxManifestSize)).Decode(&mapping); err != nil {
+		return nil, 0, fmt.Errorf("解析媒体清单失败: %w", err)
+	}
+
+	referenced := make(map[string]bool)
+	for _, note := range notes {
+		for _, field := range note.Fields {
+			for _, name := range MediaRefs(field) {
+				referenced[name] = true
+			}
+		}
+	}
+
+	var total uint64
+	for key, name := range mapping {
+		file, ok := files[key]
+		if !ok || !referenced[name] || file.UncompressedSize64 > maxMediaFileSize || total+file.UncompressedSize64 > maxMediaTotalSize {
+			continue
+		}
+		// 清单声明的大小可能与实际不符，多读一个字节以识别超限文件
+		data, err := readZipFile(file, maxMediaFileSize+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(data) > maxMediaFileSize {
+			continue
+		}
+		total += uint64(len(data))
+		media[name] = data
+	}
+	return media, len(mapping), nil
+}
+
+// readZipFile 读取zip中的文件内容，最多读取limit字节
+func readZipFile(file *zip.File, limit int64) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取媒体文件失败: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit))
+	if err != nil {
+		return nil, fmt.Errorf("读取媒体文件失败: %w", err)
+	}
+	return data, nil
+}
+
+// extractFile 将zip中的文件解压到本地路径
+func extractFile(file *zip.File, path string) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("解压collection失败: %w", err)
+	}
+	defer rc.Close()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("解压collection失败: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, io.LimitReader(rc, maxCollectionSize)); err != nil {
+		return fmt.Errorf("解压collection失败: %w", err)
+	}
+	return nil
+}
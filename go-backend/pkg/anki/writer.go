@@ -0,0 +1,337 @@
+package anki
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite驱动
+)
+
+// 笔记类型ID（固定值，保证多次导出的笔记类型一致）
+const (
+	basicModelID = int64(1700000000001)
+	clozeModelID = int64(1700000000002)
+)
+
+// Write 将牌组包写出为.apkg格式
+func Write(w io.Writer, pkg *Package) error {
+	dir, err := os.MkdirTemp("", "apkg-export-*")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	collectionPath := filepath.Join(dir, "collection.anki2")
+	if err := writeCollection(collectionPath, pkg.Notes); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	if err := addFileToZip(zw, "collection.anki2", collectionPath); err != nil {
+		return err
+	}
+
+	// 媒体文件以序号命名，media清单记录序号到原文件名的映射
+	names := make([]string, 0, len(pkg.Media))
+	for name := range pkg.Media {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mediaMap := make(map[string]string, len(names))
+	for i, name := range names {
+		key := strconv.Itoa(i)
+		mediaMap[key] = name
+		entry, err := zw.Create(key)
+		if err != nil {
+			return fmt.Errorf("写入媒体文件失败: %w", err)
+		}
+		if _, err := entry.Write(pkg.Media[name]); err != nil {
+			return fmt.Errorf("写入媒体文件失败: %w", err)
+		}
+	}
+
+	mediaJSON, err := json.Marshal(mediaMap)
+	if err != nil {
+		return fmt.Errorf("序列化媒体清单失败: %w", err)
+	}
+	entry, err := zw.Create("media")
+	if err != nil {
+		return fmt.Errorf("写入媒体清单失败: %w", err)
+	}
+	if _, err := entry.Write(mediaJSON); err != nil {
+		return fmt.Errorf("写入媒体清单失败: %w", err)
+	}
+
+	return zw.Close()
+}
+
+// writeCollection 生成SQLite collection文件
+func writeCollection(path string, notes []Note) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("创建collection失败: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(schemaSQL); err != nil {
+		return fmt.Errorf("创建collection表结构失败: %w", err)
+	}
+
+	now := time.Now()
+	crt := dayStart(now)
+	for _, note := range notes {
+		for _, card := range note.Cards {
+			if card.State == CardStateReview && !card.DueAt.IsZero() && card.DueAt.Before(crt) {
+				crt = dayStart(card.DueAt)
+			}
+		}
+	}
+
+	// 分配牌组ID
+	deckIDs := map[string]int64{"Default": defaultDeckID}
+	nextDeckID := now.UnixMilli()
+	for _, note := range notes {
+		deck := note.Deck
+		if deck == "" {
+			continue
+		}
+		// 父牌组也需要存在
+		parts := strings.Split(deck, "::")
+		for i := range parts {
+			name := strings.Join(parts[:i+1], "::")
+			if _, ok := deckIDs[name]; !ok {
+				deckIDs[name] = nextDeckID
+				nextDeckID++
+			}
+		}
+	}
+
+	decksJSON, err := json.Marshal(buildDecks(deckIDs, now))
+	if err != nil {
+		return fmt.Errorf("序列化牌组失败: %w", err)
+	}
+	modelsJSON, err := json.Marshal(buildModels(now))
+	if err != nil {
+		return fmt.Errorf("序列化笔记类型失败: %w", err)
+	}
+	dconfJSON, err := json.Marshal(map[string]interface{}{"1": defaultDeckConfig(now)})
+	if err != nil {
+		return fmt.Errorf("序列化牌组配置失败: %w", err)
+	}
+	confJSON, err := json.Marshal(map[string]interface{}{
+		"nextPos":       1,
+		"estTimes":      true,
+		"activeDecks":   []int64{defaultDeckID},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       defaultDeckID,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      strconv.FormatInt(basicModelID, 10),
+		"collapseTime":  1200,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化集合配置失败: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT INTO col VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		crt.Unix(), now.UnixMilli(), now.UnixMilli(), schemaVersion,
+		string(confJSON), string(modelsJSON), string(decksJSON), string(dconfJSON),
+	); err != nil {
+		return fmt.Errorf("写入集合信息失败: %w", err)
+	}
+
+	noteID := now.UnixMilli()
+	cardID := now.UnixMilli()
+	newPosition := 0
+	for _, note := range notes {
+		noteID++
+		modelID := basicModelID
+		fields := make([]string, 2)
+		copy(fields, note.Fields)
+		if note.Cloze {
+			modelID = clozeModelID
+		}
+
+		tags := ""
+		if len(note.Tags) > 0 {
+			tags = " " + strings.Join(note.Tags, " ") + " "
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			noteID, note.GUID, modelID, now.Unix(), tags,
+			strings.Join(fields, fieldSeparator), StripHTML(fields[0]), checksum(fields[0]),
+		); err != nil {
+			return fmt.Errorf("写入笔记失败: %w", err)
+		}
+
+		deckID := defaultDeckID
+		if id, ok := deckIDs[note.Deck]; ok {
+			deckID = id
+		}
+
+		for _, card := range note.Cards {
+			cardID++
+			cardType, queue, due, ivl, factor := toAnkiSchedule(card, crt, &newPosition)
+			if _, err := tx.Exec(
+				`INSERT INTO cards VALUES (?, ?, ?, ?, ?, -1, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, '')`,
+				cardID, noteID, deckID, card.Ord, now.Unix(),
+				cardType, queue, due, ivl, factor, card.Reps, card.Lapses,
+			); err != nil {
+				return fmt.Errorf("写入卡片失败: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
+// toAnkiSchedule 将调度状态转换为Anki的type/queue/due/ivl/factor
+func toAnkiSchedule(card Card, crt time.Time, newPosition *int) (int, int, int64, int, int) {
+	factor := defaultFactor
+	if card.EaseFactor > 0 {
+		factor = int(card.EaseFactor * 1000)
+	}
+
+	var queue int
+	var due int64
+	switch card.State {
+	case CardStateReview:
+		queue = int(CardStateReview)
+		due = int64(dayStart(card.DueAt).Sub(crt).Hours() / 24)
+	case CardStateLearning:
+		queue = int(CardStateLearning)
+		due = card.DueAt.Unix()
+	default:
+		*newPosition++
+		return int(CardStateNew), int(CardStateNew), int64(*newPosition), 0, factor
+	}
+
+	if card.Suspended {
+		queue = -1
+	}
+	return int(card.State), queue, due, card.Interval, factor
+}
+
+// addFileToZip 将本地文件写入zip
+func addFileToZip(zw *zip.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("读取collection失败: %w", err)
+	}
+	defer file.Close()
+
+	entry, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("写入collection失败: %w", err)
+	}
+	if _, err := io.Copy(entry, file); err != nil {
+		return fmt.Errorf("写入collection失败: %w", err)
+	}
+	return nil
+}
+
+// buildDecks 构建牌组定义
+func buildDecks(deckIDs map[string]int64, now time.Time) map[string]interface{} {
+	decks := make(map[string]interface{}, len(deckIDs))
+	for name, id := range deckIDs {
+		decks[strconv.FormatInt(id, 10)] = map[string]interface{}{
+			"id":               id,
+			"name":             name,
+			"desc":             "",
+			"mod":              now.Unix(),
+			"usn":              -1,
+			"collapsed":        false,
+			"browserCollapsed": false,
+			"newToday":         []int{0, 0},
+			"revToday":         []int{0, 0},
+			"lrnToday":         []int{0, 0},
+			"timeToday":        []int{0, 0},
+			"dyn":              0,
+			"conf":             1,
+			"extendNew":        10,
+			"extendRev":        50,
+		}
+	}
+	return decks
+}
+
+// buildModels 构建基础与挖空两种笔记类型
+func buildModels(now time.Time) map[string]interface{} {
+	css := ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n.cloze {\n font-weight: bold;\n color: blue;\n}"
+	field := func(name string, ord int) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name, "ord": ord, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+	template := func(name string, ord int, qfmt, afmt string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name, "ord": ord, "qfmt": qfmt, "afmt": afmt,
+			"bqfmt": "", "bafmt": "", "did": nil,
+		}
+	}
+	model := func(id int64, name string, modelType int, flds, tmpls []map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "name": name, "type": modelType, "mod": now.Unix(), "usn": -1,
+			"sortf": 0, "did": defaultDeckID, "tmpls": tmpls, "flds": flds, "css": css,
+			"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+			"latexPost": "\\end{document}", "latexsvg": false,
+			"req":  [][]interface{}{{0, "any", []int{0}}},
+			"tags": []string{}, "vers": []interface{}{},
+		}
+	}
+
+	return map[string]interface{}{
+		strconv.FormatInt(basicModelID, 10): model(basicModelID, "SiCal Basic", 0,
+			[]map[string]interface{}{field("Front", 0), field("Back", 1)},
+			[]map[string]interface{}{template("Card 1", 0, "{{Front}}", "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}")},
+		),
+		strconv.FormatInt(clozeModelID, 10): model(clozeModelID, "SiCal Cloze", 1,
+			[]map[string]interface{}{field("Text", 0), field("Extra", 1)},
+			[]map[string]interface{}{template("Cloze", 0, "{{cloze:Text}}", "{{cloze:Text}}<br>\n{{Extra}}")},
+		),
+	}
+}
+
+// defaultDeckConfig 默认牌组配置
+func defaultDeckConfig(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id": 1, "name": "Default", "mod": now.Unix(), "usn": -1,
+		"maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+		"new": map[string]interface{}{
+			"bury": true, "delays": []float64{1, 10}, "initialFactor": defaultFactor,
+			"ints": []int{1, 4, 7}, "order": 1, "perDay": 20, "separate": true,
+		},
+		"lapse": map[string]interface{}{
+			"delays": []float64{10}, "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0,
+		},
+		"rev": map[string]interface{}{
+			"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500,
+			"minSpace": 1, "perDay": 100,
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package cloze
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// clozeRegex 匹配Anki风格的挖空标记: {{c1::答案}} 或 {{c1::答案::提示}}
+var clozeRegex = regexp.MustCompile(`\{\{c(\d+)::(.*?)(?:::(.*?))?\}\}`)
+
+// paragraphRegex 匹配段落分隔（空行）
+var paragraphRegex = regexp.MustCompile(`\n\s*\n`)
+
+// Deletion 挖空项
+type Deletion struct {
+	Ordinal int    `json:"ordinal"`
+	Answer  string `json:"answer"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// HasCloze 检查文本是否包含挖空标记
+func HasCloze(text string) bool {
+	return clozeRegex.MatchString(text)
+}
+
+// Parse 解析文本中的所有挖空项
+func Parse(text string) []Deletion {
+	var deletions []Deletion
+	for _, match := range clozeRegex.FindAllStringSubmatch(text, -1) {
+		ordinal, err := strconv.Atoi(match[1])
+		if err != nil || ordinal <= 0 {
+			continue
+		}
+		deletions = append(deletions, Deletion{
+			Ordinal: ordinal,
+			Answer:  match[2],
+			Hint:    match[3],
+		})
+	}
+	return deletions
+}
+
+// Ordinals 获取文本中出现的挖空序号（去重并升序）
+func Ordinals(text string) []int {
+	seen := make(map[int]bool)
+	var ordinals []int
+	for _, deletion := range Parse(text) {
+		if !seen[deletion.Ordinal] {
+			seen[deletion.Ordinal] = true
+			ordinals = append(ordinals, deletion.Ordinal)
+		}
+	}
+	sort.Ints(ordinals)
+	return ordinals
+}
+
+// HasOrdinal 检查文本中是否包含指定序号的挖空
+func HasOrdinal(text string, ordinal int) bool {
+	for _, deletion := range Parse(text) {
+		if deletion.Ordinal == ordinal {
+			return true
+		}
+	}
+	return false
+}
+
+// Answers 获取指定序号的全部挖空答案（按出现顺序）
+func Answers(text string, ordinal int) []string {
+	var answers []string
+	for _, deletion := range Parse(text) {
+		if deletion.Ordinal == ordinal {
+			answers = append(answers, deletion.Answer)
+		}
+	}
+	return answers
+}
+
+// NormalizeAnswer 规范化作答内容以便比较：全角字符转半角、转为小写，并去除空白与标点
+func NormalizeAnswer(answer string) string {
+	var b strings.Builder
+	for _, r := range answer {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// RenderQuestion 渲染指定序号的问题面：该序号的挖空显示为[...]或[提示]，其余挖空显示答案
+func RenderQuestion(text string, ordinal int) string {
+	return render(text, func(d Deletion) string {
+		if d.Ordinal != ordinal {
+			return d.Answer
+		}
+		if d.Hint != "" {
+			return "[" + d.Hint + "]"
+		}
+		return "[...]"
+	})
+}
+
+// RenderAnswer 渲染答案面：所有挖空均显示答案
+func RenderAnswer(text string) string {
+	return render(text, func(d Deletion) string {
+		return d.Answer
+	})
+}
+
+// Split 将文本按空行拆分为段落，仅保留包含挖空标记的段落
+func Split(text string) []string {
+	var paragraphs []string
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+	for _, paragraph := range paragraphRegex.Split(normalized, -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph != "" && HasCloze(paragraph) {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
+
+// render 使用替换函数渲染挖空标记
+func render(text string, replace func(Deletion) string) string {
+	return clozeRegex.ReplaceAllStringFunc(text, func(match string) string {
+		sub := clozeRegex.FindStringSubmatch(match)
+		ordinal, _ := strconv.Atoi(sub[1])
+		return replace(Deletion{Ordinal: ordinal, Answer: sub[2], Hint: sub[3]})
+	})
+}
@@ -0,0 +1,177 @@
+// Package graph 提供有向图的遍历算法以及JSON、GraphML与DOT格式的输出，
+// 用于知识图谱（前置关系、内容关联）的查询与前端概念图渲染。
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Node 图节点
+type Node struct {
+	ID    string            `json:"id"`
+	Label string            `json:"label"`
+	Attrs map[string]string `json:"attrs,omitempty"` // 附加属性，如分类、难度、遍历深度
+}
+
+// Edge 有向边
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"` // 边的类型，如 prerequisite、related
+}
+
+// Graph 有向图
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Neighbors 获取节点的相邻节点
+type Neighbors func(id string) []string
+
+// Traverse 从起点出发按广度优先遍历，返回可达节点（不含起点）及其距离；maxDepth<=0时不限深度
+func Traverse(start string, maxDepth int, next Neighbors) map[string]int {
+	depths := map[string]int{start: 0}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if maxDepth > 0 && depths[current] >= maxDepth {
+			continue
+		}
+		for _, neighbor := range next(current) {
+			if _, seen := depths[neighbor]; seen {
+				continue
+			}
+			depths[neighbor] = depths[current] + 1
+			queue = append(queue, neighbor)
+		}
+	}
+	delete(depths, start)
+	return depths
+}
+
+// ShortestPath 按广度优先搜索起点到终点的最短路径（含两端），不可达时返回nil
+func ShortestPath(from, to string, next Neighbors) []string {
+	if from == to {
+		return []string{from}
+	}
+	parents := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range next(current) {
+			if _, seen := parents[neighbor]; seen {
+				continue
+			}
+			parents[neighbor] = current
+			if neighbor == to {
+				path := []string{to}
+				for node := current; node != ""; node = parents[node] {
+					path = append(path, node)
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			queue = append(queue, neighbor)
+		}
+	}
+	return nil
+}
+
+// attrKeys 获取全部节点附加属性的名称（升序）
+func (g *Graph) attrKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, node := range g.Nodes {
+		for key := range node.Attrs {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WriteGraphML 以GraphML格式输出
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	b.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	keys := g.attrKeys()
+	for _, key := range keys {
+		fmt.Fprintf(&b, `  <key id="%s" for="node" attr.name="%s" attr.type="string"/>`+"\n", escapeXML(key), escapeXML(key))
+	}
+	b.WriteString(`  <key id="kind" for="edge" attr.name="kind" attr.type="string"/>` + "\n")
+	b.WriteString(`  <graph id="G" edgedefault="directed">` + "\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, `    <node id="%s">`+"\n", escapeXML(node.ID))
+		fmt.Fprintf(&b, `      <data key="label">%s</data>`+"\n", escapeXML(node.Label))
+		for _, key := range keys {
+			if value, ok := node.Attrs[key]; ok {
+				fmt.Fprintf(&b, `      <data key="%s">%s</data>`+"\n", escapeXML(key), escapeXML(value))
+			}
+		}
+		b.WriteString("    </node>\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, `    <edge source="%s" target="%s">`+"\n", escapeXML(edge.Source), escapeXML(edge.Target))
+		fmt.Fprintf(&b, `      <data key="kind">%s</data>`+"\n", escapeXML(edge.Kind))
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDOT 以Graphviz DOT格式输出；前置关系为实线，其他类型的边为虚线
+func (g *Graph) WriteDOT(w io.Writer, name string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quoteDOT(name))
+	b.WriteString("  node [shape=box];\n")
+	keys := g.attrKeys()
+	for _, node := range g.Nodes {
+		attrs := []string{"label=" + quoteDOT(node.Label)}
+		for _, key := range keys {
+			if value, ok := node.Attrs[key]; ok {
+				attrs = append(attrs, quoteDOT(key)+"="+quoteDOT(value))
+			}
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", quoteDOT(node.ID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.Edges {
+		style := "solid"
+		if edge.Kind != "prerequisite" {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [kind=%s, style=%s];\n", quoteDOT(edge.Source), quoteDOT(edge.Target), quoteDOT(edge.Kind), style)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeXML 转义XML文本与属性值
+func escapeXML(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// quoteDOT 将标识符转换为DOT双引号字符串
+func quoteDOT(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + replacer.Replace(value) + `"`
+}
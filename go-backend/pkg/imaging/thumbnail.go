@@ -0,0 +1,119 @@
+// Package imaging 提供图片尺寸读取与缩略图生成。
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxPixels 允许解码的最大像素数，防止解压炸弹
+const MaxPixels = 50_000_000
+
+// thumbnailQuality JPEG缩略图质量
+const thumbnailQuality = 85
+
+// ErrTooLarge 图片像素数超过上限
+var ErrTooLarge = errors.New("图片尺寸过大")
+
+// Size 读取图片尺寸与格式（jpeg、png、gif）
+func Size(data []byte) (width, height int, format string, err error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("无法识别的图片: %w", err)
+	}
+	return config.Width, config.Height, format, nil
+}
+
+// Thumbnail 生成不超过maxSize×maxSize的缩略图，保持宽高比且不放大；
+// PNG与GIF输出为PNG以保留透明度，其余输出为JPEG
+func Thumbnail(data []byte, maxSize int) ([]byte, string, error) {
+	width, height, format, err := Size(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if width*height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	dstWidth, dstHeight := fit(width, height, maxSize)
+	thumb := resize(src, dstWidth, dstHeight)
+
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		if err := png.Encode(&buf, thumb); err != nil {
+			return nil, "", fmt.Errorf("编码缩略图失败: %w", err)
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, "", fmt.Errorf("编码缩略图失败: %w", err)
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// fit 计算等比缩放后的尺寸
+func fit(width, height, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
+
+// resize 使用区域平均缩放图片
+func resize(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth == width && srcHeight == height {
+		return rgba
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*rgba.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					pa := int(rgba.Pix[offset+3])
+					r += int(rgba.Pix[offset]) * pa
+					g += int(rgba.Pix[offset+1]) * pa
+					b += int(rgba.Pix[offset+2]) * pa
+					a += pa
+					n++
+					offset += 4
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			if a > 0 {
+				dst.Pix[i] = uint8(r / a)
+				dst.Pix[i+1] = uint8(g / a)
+				dst.Pix[i+2] = uint8(b / a)
+			}
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
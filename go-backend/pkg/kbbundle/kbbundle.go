@@ -0,0 +1,206 @@
+// Package kbbundle 定义知识库导出包的格式。
+//
+// 导出包为zip压缩文件，包含以下条目：
+//
+//	manifest.json          包信息：格式标识、格式版本、导出时间、范围与各类数据条数
+//	knowledge_points.json  知识点数组
+//	prerequisites.json     前置关系数组，from为前置知识点ID，to为依赖它的知识点ID
+//	flashcards.json        闪卡数组
+//	schema.json            描述以上文件结构的JSON Schema
+//
+// 知识点与闪卡保留原始ID，重复导入同一导出包时按ID更新，结果保持不变。
+package kbbundle
+
+import (
+	"archive/zip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FormatName 导出包格式标识
+const FormatName = "sical-knowledge-bundle"
+
+// SchemaVersion 当前格式版本；格式发生不兼容变更时递增
+const SchemaVersion = 1
+
+// 导出包内的文件名
+const (
+	manifestFile        = "manifest.json"
+	knowledgePointsFile = "knowledge_points.json"
+	prerequisitesFile   = "prerequisites.json"
+	flashcardsFile      = "flashcards.json"
+	schemaFile          = "schema.json"
+)
+
+// maxEntrySize 单个条目解压后的大小上限
+const maxEntrySize = 256 << 20
+
+// Schema 导出包的JSON Schema文档
+//
+//go:embed schema.json
+var Schema []byte
+
+// Manifest 导出包信息
+type Manifest struct {
+	Format        string    `json:"format"`
+	SchemaVersion int       `json:"schema_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Category      string    `json:"category,omitempty"` // 为空表示整个知识库
+	Counts        Counts    `json:"counts"`
+}
+
+// Counts 各类数据条数
+type Counts struct {
+	KnowledgePoints int `json:"knowledge_points"`
+	Prerequisites   int `json:"prerequisites"`
+	Flashcards      int `json:"flashcards"`
+}
+
+// KnowledgePoint 导出的知识点
+type KnowledgePoint struct {
+	ID          string          `json:"id"`
+	ExternalKey string          `json:"external_key,omitempty"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Difficulty  string          `json:"difficulty"`
+	Content     string          `json:"content"`
+	Tags        []string        `json:"tags"`
+	Aliases     []string        `json:"aliases,omitempty"`
+	Resources   json.RawMessage `json:"resources"`
+	Status      string          `json:"status"`
+}
+
+// Prerequisite 前置关系：学习To之前需要先掌握From
+type Prerequisite struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Flashcard 导出的闪卡
+type Flashcard struct {
+	ID               string `json:"id"`
+	KnowledgePointID string `json:"knowledge_point_id"`
+	CardType         string `json:"card_type"`
+	Front            string `json:"front"`
+	Back             string `json:"back"`
+	ClozeIndex       int    `json:"cloze_index"`
+}
+
+// Bundle 导出包的内存表示
+type Bundle struct {
+	Manifest        Manifest
+	KnowledgePoints []KnowledgePoint
+	Prerequisites   []Prerequisite
+	Flashcards      []Flashcard
+}
+
+// Write 将导出包写入w，自动填充格式标识、版本与条数
+func Write(w io.Writer, bundle *Bundle) error {
+	bundle.Manifest.Format = FormatName
+	bundle.Manifest.SchemaVersion = SchemaVersion
+	bundle.Manifest.Counts = Counts{
+		KnowledgePoints: len(bundle.KnowledgePoints),
+		Prerequisites:   len(bundle.Prerequisites),
+		Flashcards:      len(bundle.Flashcards),
+	}
+
+	archive := zip.NewWriter(w)
+	entries := []struct {
+		name  string
+		value interface{}
+	}{
+		{manifestFile, bundle.Manifest},
+		{knowledgePointsFile, nonNil(bundle.KnowledgePoints)},
+		{prerequisitesFile, nonNil(bundle.Prerequisites)},
+		{flashcardsFile, nonNil(bundle.Flashcards)},
+	}
+	for _, entry := range entries {
+		file, err := archive.Create(entry.name)
+		if err != nil {
+			return fmt.Errorf("写入%s失败: %w", entry.name, err)
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entry.value); err != nil {
+			return fmt.Errorf("写入%s失败: %w", entry.name, err)
+		}
+	}
+
+	file, err := archive.Create(schemaFile)
+	if err != nil {
+		return fmt.Errorf("写入%s失败: %w", schemaFile, err)
+	}
+	if _, err := file.Write(Schema); err != nil {
+		return fmt.Errorf("写入%s失败: %w", schemaFile, err)
+	}
+	return archive.Close()
+}
+
+// Read 读取并校验导出包
+func Read(r io.ReaderAt, size int64) (*Bundle, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的导出包: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	bundle := &Bundle{}
+	if err := readEntry(files, manifestFile, &bundle.Manifest); err != nil {
+		return nil, err
+	}
+	if bundle.Manifest.Format != FormatName {
+		return nil, fmt.Errorf("不支持的导出包格式: %s", bundle.Manifest.Format)
+	}
+	if bundle.Manifest.SchemaVersion < 1 || bundle.Manifest.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("不支持的导出包版本: %d", bundle.Manifest.SchemaVersion)
+	}
+
+	if err := readEntry(files, knowledgePointsFile, &bundle.KnowledgePoints); err != nil {
+		return nil, err
+	}
+	if err := readEntry(files, prerequisitesFile, &bundle.Prerequisites); err != nil {
+		return nil, err
+	}
+	if err := readEntry(files, flashcardsFile, &bundle.Flashcards); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// readEntry 读取并解析压缩包中的JSON条目
+func readEntry(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("导出包缺少%s", name)
+	}
+	if file.UncompressedSize64 > maxEntrySize {
+		return fmt.Errorf("%s过大", name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("读取%s失败: %w", name, err)
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(io.LimitReader(rc, maxEntrySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("解析%s失败: %w", name, err)
+	}
+	return nil
+}
+
+// nonNil 将nil切片替换为空切片，使输出为[]而非null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
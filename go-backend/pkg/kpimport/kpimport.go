@@ -0,0 +1,240 @@
+package kpimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的导入格式
+const (
+	FormatCSV      = "csv"
+	FormatJSONL    = "jsonl"
+	FormatMarkdown = "markdown"
+)
+
+// listSeparator CSV中多值字段的分隔符
+const listSeparator = ";"
+
+// maxLineSize JSON Lines单行大小上限
+const maxLineSize = 4 << 20
+
+// byteOrderMark UTF-8 BOM，部分表格软件导出的文件会带有该前缀
+const byteOrderMark = "\uFEFF"
+
+// Record 待导入的知识点记录
+type Record struct {
+	Source        string   `json:"-"` // 来源文件或格式
+	Line          int      `json:"-"` // 来源行号（Markdown为0）
+	ExternalKey   string   `json:"external_key" yaml:"external_key"`
+	Title         string   `json:"title" yaml:"title"`
+	Description   string   `json:"description" yaml:"description"`
+	Category      string   `json:"category" yaml:"category"`
+	Difficulty    string   `json:"difficulty" yaml:"difficulty"`
+	Content       string   `json:"content" yaml:"-"`
+	Tags          []string `json:"tags" yaml:"tags"`
+	Aliases       []string `json:"aliases" yaml:"aliases"`
+	Prerequisites []string `json:"prerequisites" yaml:"prerequisites"` // 前置知识点的标题或外部键
+	Resources     []string `json:"resources" yaml:"resources"`
+}
+
+// RowError 行级错误
+type RowError struct {
+	Source  string `json:"source"`
+	Line    int    `json:"line,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error 实现error接口
+func (e RowError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Source, e.Message)
+}
+
+// ParseCSV 解析带表头的CSV，多值字段以分号分隔
+func ParseCSV(r io.Reader, source string) ([]Record, []RowError) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []RowError{{Source: source, Line: 1, Message: "读取表头失败: " + err.Error()}}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, byteOrderMark)))] = i
+	}
+	for _, required := range []string{"title", "category", "difficulty"} {
+		if _, ok := columns[required]; !ok {
+			return nil, []RowError{{Source: source, Line: 1, Message: "缺少必需的列: " + required}}
+		}
+	}
+
+	var records []Record
+	var errs []RowError
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 解析失败时没有可用的字段位置，行号取自解析错误
+			var parseErr *csv.ParseError
+			line := 0
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			errs = append(errs, RowError{Source: source, Line: line, Message: "解析CSV失败: " + err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		records = append(records, Record{
+			Source:        source,
+			Line:          line,
+			ExternalKey:   get("external_key"),
+			Title:         get("title"),
+			Description:   get("description"),
+			Category:      get("category"),
+			Difficulty:    get("difficulty"),
+			Content:       get("content"),
+			Tags:          splitList(get("tags")),
+			Aliases:       splitList(get("aliases")),
+			Prerequisites: splitList(get("prerequisites")),
+			Resources:     splitList(get("resources")),
+		})
+	}
+	return records, errs
+}
+
+// ParseJSONL 解析JSON Lines，每行一个知识点对象，空行忽略
+func ParseJSONL(r io.Reader, source string) ([]Record, []RowError) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var records []Record
+	var errs []RowError
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var record Record
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			errs = append(errs, RowError{Source: source, Line: line, Message: "解析JSON失败: " + err.Error()})
+			continue
+		}
+		record.Source = source
+		record.Line = line
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, RowError{Source: source, Line: line + 1, Message: "读取文件失败: " + err.Error()})
+	}
+	return records, errs
+}
+
+// ParseMarkdown 解析带YAML front matter的Markdown文件，正文作为知识点内容；
+// 未指定external_key时使用去除扩展名的文件路径
+func ParseMarkdown(data []byte, source string) (Record, error) {
+	text := strings.TrimPrefix(string(data), byteOrderMark)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return Record{}, RowError{Source: source, Message: "缺少front matter"}
+	}
+	rest := text[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return Record{}, RowError{Source: source, Message: "front matter未闭合"}
+	}
+
+	var record Record
+	if err := yaml.Unmarshal([]byte(rest[:end]), &record); err != nil {
+		return Record{}, RowError{Source: source, Message: "解析front matter失败: " + err.Error()}
+	}
+
+	body := rest[end+len("\n---"):]
+	if i := strings.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = ""
+	}
+	record.Content = strings.TrimSpace(body)
+	record.Source = source
+	if record.ExternalKey == "" {
+		record.ExternalKey = strings.TrimSuffix(source, path.Ext(source))
+	}
+	return record, nil
+}
+
+// ParseMarkdownFS 解析目录下全部.md文件
+func ParseMarkdownFS(fsys fs.FS) ([]Record, []RowError) {
+	var records []Record
+	var errs []RowError
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(path.Ext(name), ".md") {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			errs = append(errs, RowError{Source: name, Message: "读取文件失败: " + err.Error()})
+			return nil
+		}
+		record, err := ParseMarkdown(data, name)
+		if err != nil {
+			errs = append(errs, AsRowError(err, name))
+			return nil
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, RowError{Source: ".", Message: "遍历目录失败: " + err.Error()})
+	}
+	return records, errs
+}
+
+// splitList 拆分多值字段
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// AsRowError 将错误转换为行级错误
+func AsRowError(err error, source string) RowError {
+	if rowErr, ok := err.(RowError); ok {
+		return rowErr
+	}
+	return RowError{Source: source, Message: err.Error()}
+}
@@ -0,0 +1,221 @@
+package kpimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// errorLines 提取行级错误的行号
+func errorLines(errs []RowError) []int {
+	var lines []int
+	for _, err := range errs {
+		lines = append(lines, err.Line)
+	}
+	return lines
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      []Record
+		wantLines []int
+	}{
+		{
+			name: "多值字段与可选列",
+			input: "title,category,difficulty,tags,prerequisites,external_key\n" +
+				"二叉树,数据结构,beginner, 树; 递归 ;,链表;数组,ds-tree\n",
+			want: []Record{{
+				Source: "kp.csv", Line: 2, ExternalKey: "ds-tree",
+				Title: "二叉树", Category: "数据结构", Difficulty: "beginner",
+				Tags: []string{"树", "递归"}, Prerequisites: []string{"链表", "数组"},
+			}},
+		},
+		{
+			name:  "表头带BOM且大小写不敏感",
+			input: byteOrderMark + "Title,Category,DIFFICULTY\n栈,数据结构,beginner\n",
+			want: []Record{{
+				Source: "kp.csv", Line: 2, Title: "栈", Category: "数据结构", Difficulty: "beginner",
+			}},
+		},
+		{
+			name:  "多行内容字段的行号",
+			input: "title,category,difficulty,content\n队列,数据结构,beginner,\"第一行\n第二行\"\n堆,数据结构,advanced,x\n",
+			want: []Record{
+				{Source: "kp.csv", Line: 2, Title: "队列", Category: "数据结构", Difficulty: "beginner", Content: "第一行\n第二行"},
+				{Source: "kp.csv", Line: 4, Title: "堆", Category: "数据结构", Difficulty: "advanced", Content: "x"},
+			},
+		},
+		{
+			name:      "缺少必需列",
+			input:     "title,category\n栈,数据结构\n",
+			wantLines: []int{1},
+		},
+		{
+			name:      "空文件",
+			input:     "",
+			wantLines: []int{1},
+		},
+		{
+			name:  "格式错误的行不影响其他行",
+			input: "title,category,difficulty\n栈,数据\"结构,beginner\n堆,数据结构,advanced\n",
+			want: []Record{
+				{Source: "kp.csv", Line: 3, Title: "堆", Category: "数据结构", Difficulty: "advanced"},
+			},
+			wantLines: []int{2},
+		},
+		{
+			name:      "未闭合的引号",
+			input:     "title,category,difficulty\n\"未闭合,数据结构,beginner\n堆,数据结构,advanced\n",
+			wantLines: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, errs := ParseCSV(strings.NewReader(tt.input), "kp.csv")
+			if !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("ParseCSV() records = %+v, want %+v", records, tt.want)
+			}
+			if lines := errorLines(errs); !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("ParseCSV() error lines = %v (%v), want %v", lines, errs, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestParseJSONL(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      []Record
+		wantLines []int
+	}{
+		{
+			name:  "跳过空行并记录行号",
+			input: "{\"title\":\"栈\",\"category\":\"数据结构\",\"difficulty\":\"beginner\",\"aliases\":[\"stack\"]}\n\n  \n{\"title\":\"堆\",\"category\":\"数据结构\",\"difficulty\":\"advanced\"}\n",
+			want: []Record{
+				{Source: "kp.jsonl", Line: 1, Title: "栈", Category: "数据结构", Difficulty: "beginner", Aliases: []string{"stack"}},
+				{Source: "kp.jsonl", Line: 4, Title: "堆", Category: "数据结构", Difficulty: "advanced"},
+			},
+		},
+		{
+			name:  "未知字段与无效JSON按行报错",
+			input: "{\"title\":\"栈\",\"unknown\":1}\n{not json}\n{\"title\":\"堆\"}\n",
+			want: []Record{
+				{Source: "kp.jsonl", Line: 3, Title: "堆"},
+			},
+			wantLines: []int{1, 2},
+		},
+		{
+			name:      "类型错误",
+			input:     "{\"title\":\"栈\",\"tags\":\"树\"}\n",
+			wantLines: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, errs := ParseJSONL(strings.NewReader(tt.input), "kp.jsonl")
+			if !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("ParseJSONL() records = %+v, want %+v", records, tt.want)
+			}
+			if lines := errorLines(errs); !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("ParseJSONL() error lines = %v (%v), want %v", lines, errs, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		input   string
+		want    Record
+		wantErr bool
+	}{
+		{
+			name:   "front matter与正文",
+			source: "ds/tree.md",
+			input:  "---\ntitle: 二叉树\ncategory: 数据结构\ndifficulty: beginner\ntags: [树, 递归]\n---\n\n# 二叉树\n\n正文\n",
+			want: Record{
+				Source: "ds/tree.md", ExternalKey: "ds/tree",
+				Title: "二叉树", Category: "数据结构", Difficulty: "beginner",
+				Tags: []string{"树", "递归"}, Content: "# 二叉树\n\n正文",
+			},
+		},
+		{
+			name:   "指定外部键、CRLF换行与BOM",
+			source: "stack.md",
+			input:  byteOrderMark + "---\r\nexternal_key: ds-stack\r\ntitle: 栈\r\n---\r\n正文\r\n",
+			want:   Record{Source: "stack.md", ExternalKey: "ds-stack", Title: "栈", Content: "正文"},
+		},
+		{
+			name:   "front matter中的content字段被忽略",
+			source: "heap.md",
+			input:  "---\ntitle: 堆\ncontent: 不应使用\n---",
+			want:   Record{Source: "heap.md", ExternalKey: "heap", Title: "堆"},
+		},
+		{
+			name:    "缺少front matter",
+			source:  "a.md",
+			input:   "# 标题\n",
+			wantErr: true,
+		},
+		{
+			name:    "front matter未闭合",
+			source:  "a.md",
+			input:   "---\ntitle: 栈\n",
+			wantErr: true,
+		},
+		{
+			name:    "无效YAML",
+			source:  "a.md",
+			input:   "---\ntitle: [未闭合\n---\n正文",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := ParseMarkdown([]byte(tt.input), tt.source)
+			if tt.wantErr {
+				rowErr, ok := err.(RowError)
+				if !ok || rowErr.Source != tt.source {
+					t.Fatalf("ParseMarkdown() error = %v, want RowError for %s", err, tt.source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMarkdown() error = %v", err)
+			}
+			if !reflect.DeepEqual(record, tt.want) {
+				t.Fatalf("ParseMarkdown() = %+v, want %+v", record, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ds/tree.md":   {Data: []byte("---\ntitle: 二叉树\n---\n正文")},
+		"ds/STACK.MD":  {Data: []byte("---\ntitle: 栈\n---\n")},
+		"ds/broken.md": {Data: []byte("没有front matter")},
+		"README.txt":   {Data: []byte("忽略")},
+	}
+
+	records, errs := ParseMarkdownFS(fsys)
+	var keys []string
+	for _, record := range records {
+		keys = append(keys, record.ExternalKey)
+	}
+	if want := []string{"ds/STACK", "ds/tree"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ParseMarkdownFS() keys = %v, want %v", keys, want)
+	}
+	if len(errs) != 1 || errs[0].Source != "ds/broken.md" {
+		t.Errorf("ParseMarkdownFS() errors = %v, want one error for ds/broken.md", errs)
+	}
+}
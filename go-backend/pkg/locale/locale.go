@@ -0,0 +1,98 @@
+// Package locale 处理BCP 47语言标签的规范化、Accept-Language解析与语言协商。
+package locale
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale 默认语言，即知识点原文的语言
+const DefaultLocale = "zh-CN"
+
+// tagRegex 匹配BCP 47语言标签
+var tagRegex = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// IsValid 检查语言标签格式是否有效
+func IsValid(tag string) bool {
+	return tagRegex.MatchString(strings.ReplaceAll(tag, "_", "-"))
+}
+
+// Canonicalize 规范化语言标签：语言小写、四位书写系统首字母大写、两位地区大写，如 zh_cn -> zh-CN
+func Canonicalize(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Base 获取语言标签的基础语言，如 zh-CN -> zh
+func Base(tag string) string {
+	base, _, _ := strings.Cut(Canonicalize(tag), "-")
+	return base
+}
+
+// ParseAcceptLanguage 解析Accept-Language请求头，按权重降序返回规范化的语言标签，忽略通配符与权重为0的项
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" || !IsValid(tag) {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		items = append(items, weighted{tag: Canonicalize(tag), quality: quality})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].quality > items[j].quality })
+
+	tags := make([]string, 0, len(items))
+	for _, item := range items {
+		tags = append(tags, item.tag)
+	}
+	return tags
+}
+
+// Match 按偏好顺序选择可用的语言：优先完全匹配，其次基础语言相同（如偏好zh-TW时可匹配zh-CN），
+// 均无匹配时返回false
+func Match(preferred, available []string) (string, bool) {
+	for _, tag := range preferred {
+		tag = Canonicalize(tag)
+		for _, candidate := range available {
+			if Canonicalize(candidate) == tag {
+				return candidate, true
+			}
+		}
+		for _, candidate := range available {
+			if Base(candidate) == Base(tag) {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,335 @@
+// Package markdown 将知识点Markdown内容渲染为经过白名单净化的HTML，
+// 支持表格、LaTeX公式与附件图片引用，并在写入前拒绝不安全的内容。
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	nethtml "golang.org/x/net/html"
+)
+
+// AttachmentScheme 附件引用前缀，如 ![图示](attachment:<附件ID>)
+const AttachmentScheme = "attachment:"
+
+// ErrUnsafeContent 内容包含不允许的HTML或链接
+var ErrUnsafeContent = errors.New("内容包含不安全的HTML或链接")
+
+// allowedHTMLTags 允许在Markdown中直接书写的HTML标签及其属性
+var allowedHTMLTags = map[string]map[string]bool{
+	"abbr":    {"title": true},
+	"br":      {},
+	"del":     {},
+	"details": {},
+	"ins":     {},
+	"kbd":     {},
+	"mark":    {},
+	"s":       {},
+	"small":   {},
+	"sub":     {},
+	"summary": {},
+	"sup":     {},
+	"u":       {},
+}
+
+// allowedURLSchemes 允许的链接协议；无协议的相对地址同样允许
+var allowedURLSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// mathClassPattern 公式与代码块允许的class
+var mathClassPattern = regexp.MustCompile(`^(math math-inline|math math-display|language-[\w+#-]+)$`)
+
+// DefaultAttachmentURL 附件引用的默认访问地址，由服务端重定向到限时签名地址
+func DefaultAttachmentURL(id uuid.UUID) string {
+	return "/api/v1/attachments/" + id.String() + "/content"
+}
+
+// Renderer Markdown渲染器
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewRenderer 创建渲染器；attachmentURL将附件ID转换为可访问的地址
+func NewRenderer(attachmentURL func(id uuid.UUID) string) *Renderer {
+	md := newMarkdown(
+		goldmark.WithParserOptions(parser.WithASTTransformers(
+			util.Prioritized(&attachmentTransformer{attachmentURL: attachmentURL}, 100),
+		)),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+	return &Renderer{markdown: md, policy: newPolicy()}
+}
+
+// defaultRenderer 使用默认附件地址的渲染器
+var defaultRenderer = NewRenderer(DefaultAttachmentURL)
+
+// Render 使用默认附件地址渲染Markdown
+func Render(source string) (string, error) {
+	return defaultRenderer.Render(source)
+}
+
+// Render 将Markdown渲染为净化后的HTML；历史内容中的不安全片段在此被移除
+func (r *Renderer) Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("渲染Markdown失败: %w", err)
+	}
+	return r.policy.SanitizeReader(&buf).String(), nil
+}
+
+// Validate 校验Markdown内容：仅允许白名单内的HTML标签与属性，
+// 链接与图片只能使用http、https、mailto、相对地址或有效的附件引用
+func Validate(source string) error {
+	src := []byte(source)
+	doc := newMarkdown().Parser().Parse(text.NewReader(src))
+
+	return ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			return ast.WalkContinue, checkURL(string(n.Destination))
+		case *ast.Image:
+			return ast.WalkContinue, checkURL(string(n.Destination))
+		case *ast.AutoLink:
+			if n.AutoLinkType == ast.AutoLinkEmail {
+				return ast.WalkContinue, nil
+			}
+			return ast.WalkContinue, checkURL(string(n.URL(src)))
+		case *ast.RawHTML:
+			var raw bytes.Buffer
+			for i := 0; i < n.Segments.Len(); i++ {
+				segment := n.Segments.At(i)
+				raw.Write(segment.Value(src))
+			}
+			return ast.WalkContinue, checkHTML(raw.String())
+		case *ast.HTMLBlock:
+			var raw bytes.Buffer
+			for i := 0; i < n.Lines().Len(); i++ {
+				segment := n.Lines().At(i)
+				raw.Write(segment.Value(src))
+			}
+			if n.HasClosure() {
+				raw.Write(n.ClosureLine.Value(src))
+			}
+			return ast.WalkContinue, checkHTML(raw.String())
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// newMarkdown 创建启用表格、删除线、自动链接与公式扩展的解析器
+func newMarkdown(options ...goldmark.Option) goldmark.Markdown {
+	options = append([]goldmark.Option{
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+			&mathExtension{},
+		),
+	}, options...)
+	return goldmark.New(options...)
+}
+
+// newPolicy 创建HTML白名单策略
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	for tag, attrs := range allowedHTMLTags {
+		policy.AllowElements(tag)
+		for attr := range attrs {
+			policy.AllowAttrs(attr).OnElements(tag)
+		}
+	}
+	policy.AllowAttrs("class").Matching(mathClassPattern).OnElements("span", "div", "code")
+	policy.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+// checkURL 检查链接地址
+func checkURL(raw string) error {
+	if strings.HasPrefix(raw, AttachmentScheme) {
+		if _, err := uuid.Parse(strings.TrimPrefix(raw, AttachmentScheme)); err != nil {
+			return fmt.Errorf("%w: 无效的附件引用 %q", ErrUnsafeContent, raw)
+		}
+		return nil
+	}
+
+	// 去除实体编码与控制字符后再判断协议，防止 jav&#x61;script: 之类的绕过
+	normalized := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(raw))
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return fmt.Errorf("%w: 无效的链接 %q", ErrUnsafeContent, raw)
+	}
+	if parsed.Scheme != "" && !allowedURLSchemes[strings.ToLower(parsed.Scheme)] {
+		return fmt.Errorf("%w: 不允许的链接协议 %q", ErrUnsafeContent, parsed.Scheme)
+	}
+	return nil
+}
+
+// checkHTML 检查内嵌HTML片段中的标签与属性
+func checkHTML(fragment string) error {
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case nethtml.ErrorToken:
+			if errors.Is(tokenizer.Err(), io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%w: 无法解析的HTML", ErrUnsafeContent)
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attrs, ok := allowedHTMLTags[token.Data]
+			if !ok {
+				return fmt.Errorf("%w: 不允许的HTML标签 <%s>", ErrUnsafeContent, token.Data)
+			}
+			for _, attr := range token.Attr {
+				if !attrs[attr.Key] {
+					return fmt.Errorf("%w: 标签 <%s> 不允许属性 %s", ErrUnsafeContent, token.Data, attr.Key)
+				}
+			}
+		case nethtml.EndTagToken:
+			token := tokenizer.Token()
+			if _, ok := allowedHTMLTags[token.Data]; !ok {
+				return fmt.Errorf("%w: 不允许的HTML标签 </%s>", ErrUnsafeContent, token.Data)
+			}
+		case nethtml.DoctypeToken:
+			return fmt.Errorf("%w: 不允许的HTML声明", ErrUnsafeContent)
+		}
+	}
+}
+
+// attachmentTransformer 将附件引用改写为可访问的地址
+type attachmentTransformer struct {
+	attachmentURL func(id uuid.UUID) string
+}
+
+// Transform 改写链接与图片中的附件引用；无效引用保持原样，由净化策略移除
+func (t *attachmentTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			n.Destination = t.resolve(n.Destination)
+		case *ast.Image:
+			n.Destination = t.resolve(n.Destination)
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// resolve 解析单个附件引用
+func (t *attachmentTransformer) resolve(destination []byte) []byte {
+	if !bytes.HasPrefix(destination, []byte(AttachmentScheme)) {
+		return destination
+	}
+	id, err := uuid.Parse(string(destination[len(AttachmentScheme):]))
+	if err != nil {
+		return destination
+	}
+	return []byte(t.attachmentURL(id))
+}
+
+// PlainText 提取Markdown中的正文文本，忽略代码、链接、公式与内嵌HTML；块之间以换行分隔
+func PlainText(source string) string {
+	src := []byte(source)
+	doc := newMarkdown().Parser().Parse(text.NewReader(src))
+
+	var buf strings.Builder
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := node.(type) {
+		case *ast.CodeSpan, *ast.Link, *ast.AutoLink, *ast.Image, *ast.RawHTML, *MathInline:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				buf.Write(n.Segment.Value(src))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					buf.WriteByte('\n')
+				}
+			}
+		case *ast.String:
+			if entering {
+				buf.Write(n.Value)
+			}
+		default:
+			if !entering && node.Type() == ast.TypeBlock {
+				buf.WriteByte('\n')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+// VisibleText 提取渲染后读者可见的文本（含链接文字与代码，不含图片、公式与内嵌HTML），用于定位内容中的引文
+func VisibleText(source string) string {
+	src := []byte(source)
+	doc := newMarkdown().Parser().Parse(text.NewReader(src))
+
+	var buf strings.Builder
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := node.(type) {
+		case *ast.Image, *ast.RawHTML, *ast.HTMLBlock, *MathInline, *MathBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			if entering {
+				lines := n.Lines()
+				for i := 0; i < lines.Len(); i++ {
+					segment := lines.At(i)
+					buf.Write(segment.Value(src))
+				}
+				buf.WriteByte('\n')
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			if entering {
+				buf.Write(n.Label(src))
+			}
+		case *ast.Text:
+			if entering {
+				buf.Write(n.Segment.Value(src))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					buf.WriteByte('\n')
+				}
+			}
+		case *ast.String:
+			if entering {
+				buf.Write(n.Value)
+			}
+		default:
+			if !entering && node.Type() == ast.TypeBlock {
+				buf.WriteByte('\n')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
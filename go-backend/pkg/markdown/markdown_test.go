@@ -0,0 +1,138 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidate(t *testing.T) {
+	attachmentID := uuid.New()
+	tests := []struct {
+		name   string
+		source string
+		unsafe bool
+	}{
+		{name: "纯文本", source: "# 标题\n\n正文 **加粗**"},
+		{name: "http链接", source: "[链接](https://example.com/a?b=1)"},
+		{name: "相对链接", source: "[链接](/knowledge-points/1)"},
+		{name: "邮件链接", source: "[联系](mailto:a@example.com)"},
+		{name: "邮件自动链接", source: "<a@example.com>"},
+		{name: "附件图片", source: "![图示](attachment:" + attachmentID.String() + ")"},
+		{name: "白名单标签", source: "H<sub>2</sub>O 与 <abbr title=\"HyperText\">HTML</abbr>"},
+		{name: "表格", source: "| a | b |\n|:-|-:|\n| 1 | 2 |"},
+		{name: "公式", source: "行内 $a<b$ 与\n\n$$\n\\frac{1}{2}\n$$"},
+		{name: "script标签", source: "<script>alert(1)</script>", unsafe: true},
+		{name: "事件属性", source: "<img src=x onerror=alert(1)>", unsafe: true},
+		{name: "白名单标签的事件属性", source: "<abbr onclick=\"alert(1)\">x</abbr>", unsafe: true},
+		{name: "iframe", source: "<iframe src=\"https://example.com\"></iframe>", unsafe: true},
+		{name: "内嵌a标签", source: "<a href=\"javascript:alert(1)\">x</a>", unsafe: true},
+		{name: "javascript链接", source: "[x](javascript:alert(1))", unsafe: true},
+		{name: "大小写混合协议", source: "[x](JavaScript:alert(1))", unsafe: true},
+		{name: "实体编码协议", source: "[x](jav&#x61;script:alert(1))", unsafe: true},
+		{name: "控制字符协议", source: "[x](<java\tscript:alert(1)>)", unsafe: true},
+		{name: "vbscript链接", source: "[x](vbscript:msgbox)", unsafe: true},
+		{name: "data图片", source: "![x](data:text/html;base64,PHNjcmlwdD4=)", unsafe: true},
+		{name: "javascript自动链接", source: "<javascript:alert(1)>", unsafe: true},
+		{name: "无效附件引用", source: "![x](attachment:not-a-uuid)", unsafe: true},
+		{name: "HTML声明", source: "<!DOCTYPE html>", unsafe: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.source)
+			if tt.unsafe {
+				if !errors.Is(err, ErrUnsafeContent) {
+					t.Fatalf("Validate(%q) = %v, want ErrUnsafeContent", tt.source, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate(%q) = %v, want nil", tt.source, err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	attachmentID := uuid.New()
+	tests := []struct {
+		name      string
+		source    string
+		contains  []string
+		forbidden []string
+	}{
+		{
+			name:      "移除script标签",
+			source:    "正文<script>alert(1)</script>",
+			contains:  []string{"正文"},
+			forbidden: []string{"<script", "alert(1)"},
+		},
+		{
+			name:      "移除事件属性",
+			source:    "<img src=\"https://example.com/a.png\" onerror=\"alert(1)\">",
+			forbidden: []string{"onerror", "alert"},
+		},
+		{
+			name:      "移除javascript链接",
+			source:    "[x](javascript:alert(1))",
+			forbidden: []string{"javascript:"},
+		},
+		{
+			name:      "移除实体编码的javascript链接",
+			source:    "<a href=\"jav&#x61;script:alert(1)\">x</a>",
+			forbidden: []string{"script:", "href"},
+		},
+		{
+			name:      "移除iframe",
+			source:    "<iframe src=\"https://example.com\"></iframe>",
+			forbidden: []string{"<iframe"},
+		},
+		{
+			name:      "移除style属性",
+			source:    "<span style=\"background:url(javascript:alert(1))\">x</span>",
+			forbidden: []string{"style", "javascript"},
+		},
+		{
+			name:     "外部链接新窗口打开",
+			source:   "[x](https://example.com)",
+			contains: []string{"href=\"https://example.com\"", "target=\"_blank\""},
+		},
+		{
+			name:     "附件引用改写为访问地址",
+			source:   "![图示](attachment:" + attachmentID.String() + ")",
+			contains: []string{"src=\"" + DefaultAttachmentURL(attachmentID) + "\""},
+		},
+		{
+			name:     "保留公式class",
+			source:   "$a<b$",
+			contains: []string{"class=\"math math-inline\"", "a&lt;b"},
+		},
+		{
+			name:      "移除非白名单class",
+			source:    "<span class=\"evil\">x</span>",
+			forbidden: []string{"evil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render(%q) error = %v", tt.source, err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, got, want)
+				}
+			}
+			for _, bad := range tt.forbidden {
+				if strings.Contains(got, bad) {
+					t.Errorf("Render(%q) = %q, must not contain %q", tt.source, got, bad)
+				}
+			}
+		})
+	}
+}
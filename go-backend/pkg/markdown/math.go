@@ -0,0 +1,215 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 数学公式节点类型
+var (
+	KindMathInline = ast.NewNodeKind("MathInline")
+	KindMathBlock  = ast.NewNodeKind("MathBlock")
+)
+
+// MathInline 行内公式：$...$（$$...$$ 出现在行内时按独立公式展示）
+type MathInline struct {
+	ast.BaseInline
+	Segment text.Segment
+	Display bool
+}
+
+// Kind 返回节点类型
+func (n *MathInline) Kind() ast.NodeKind {
+	return KindMathInline
+}
+
+// Dump 输出调试信息
+func (n *MathInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.Segment.Value(source))}, nil)
+}
+
+// MathBlock 独立公式块：以单独一行的 $$ 开始和结束
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool
+}
+
+// Kind 返回节点类型
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+// IsRaw 公式内容不做行内解析
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+// Dump 输出调试信息
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// mathExtension LaTeX公式扩展；公式原样输出，由前端KaTeX渲染
+type mathExtension struct{}
+
+// Extend 注册公式解析器与渲染器
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 90)),
+		parser.WithInlineParsers(util.Prioritized(&mathInlineParser{}, 90)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{}, 500)))
+}
+
+// mathInlineParser 行内公式解析器
+type mathInlineParser struct{}
+
+// Trigger 触发字符
+func (p *mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse 解析行内公式；为避免误识别金额（如 $5 和 $10），
+// 起始$后与结束$前不得为空白，结束$后不得紧跟数字
+func (p *mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	delim := 1
+	if len(line) > 1 && line[1] == '$' {
+		delim = 2
+	}
+	body := line[delim:]
+	if len(body) == 0 || util.IsSpace(body[0]) {
+		return nil
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+			continue
+		case '$':
+		default:
+			continue
+		}
+		if delim == 2 {
+			if i == 0 || i+1 >= len(body) || body[i+1] != '$' {
+				continue
+			}
+		} else {
+			if util.IsSpace(body[i-1]) || (i+1 < len(body) && body[i+1] >= '0' && body[i+1] <= '9') {
+				continue
+			}
+		}
+		node := &MathInline{
+			Segment: text.NewSegment(segment.Start+delim, segment.Start+delim+i),
+			Display: delim == 2,
+		}
+		block.Advance(delim + i + delim)
+		return node
+	}
+	return nil
+}
+
+// mathBlockParser 独立公式块解析器
+type mathBlockParser struct{}
+
+// Trigger 触发字符
+func (b *mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Open 识别 $$ 开始行；同一行内闭合的 $$...$$ 作为单行公式块
+func (b *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockIndent()
+	if pos+1 >= len(line) || line[pos] != '$' || line[pos+1] != '$' {
+		return nil, parser.NoChildren
+	}
+
+	node := &MathBlock{}
+	rest := util.TrimRightSpace(line[pos+2:])
+	if len(rest) > 0 {
+		if len(rest) < 3 || !bytes.HasSuffix(rest, []byte("$$")) {
+			return nil, parser.NoChildren
+		}
+		start := segment.Start - segment.Padding + pos + 2
+		node.Lines().Append(text.NewSegment(start, start+len(rest)-2))
+		node.closed = true
+	}
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+// Continue 读取公式内容直到单独一行的 $$
+func (b *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	if bytes.Equal(util.TrimRightSpace(util.TrimLeftSpace(line)), []byte("$$")) {
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+// Close 关闭公式块
+func (b *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// CanInterruptParagraph 公式块可打断段落
+func (b *mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine 缩进行不作为公式块开始
+func (b *mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// mathRenderer 公式渲染器，输出KaTeX自动渲染可识别的定界符
+type mathRenderer struct{}
+
+// RegisterFuncs 注册渲染函数
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMathInline, r.renderInline)
+	reg.Register(KindMathBlock, r.renderBlock)
+}
+
+func (r *mathRenderer) renderInline(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*MathInline)
+	if n.Display {
+		_, _ = w.WriteString(`<span class="math math-display">\[`)
+		_, _ = w.Write(util.EscapeHTML(n.Segment.Value(source)))
+		_, _ = w.WriteString(`\]</span>`)
+	} else {
+		_, _ = w.WriteString(`<span class="math math-inline">\(`)
+		_, _ = w.Write(util.EscapeHTML(n.Segment.Value(source)))
+		_, _ = w.WriteString(`\)</span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<div class="math math-display">\[`)
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		_, _ = w.Write(util.EscapeHTML(segment.Value(source)))
+	}
+	_, _ = w.WriteString("\\]</div>\n")
+	return ast.WalkSkipChildren, nil
+}
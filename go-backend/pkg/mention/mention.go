@@ -0,0 +1,98 @@
+// Package mention 在文本中查找已知术语（如知识点标题与别名）的提及。
+package mention
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MinTermLength 术语的最小字符数，过短的术语容易误匹配
+const MinTermLength = 2
+
+// Term 待匹配的术语
+type Term struct {
+	Text   string
+	Target string // 术语指向的对象标识
+}
+
+// Match 一次提及
+type Match struct {
+	Target string
+	Text   string // 文本中实际出现的内容
+	Start  int    // 字节偏移
+	End    int
+}
+
+// Matcher 术语匹配器：不区分大小写，同一位置优先匹配最长的术语，匹配结果互不重叠；
+// 以字母或数字开头/结尾的西文术语要求两侧为词边界，中文术语不要求
+type Matcher struct {
+	byFirst map[rune][]Term
+}
+
+// NewMatcher 创建术语匹配器，忽略过短的术语；同一术语指向多个对象时保留先出现的
+func NewMatcher(terms []Term) *Matcher {
+	m := &Matcher{byFirst: make(map[rune][]Term)}
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		text := strings.TrimSpace(term.Text)
+		key := strings.ToLower(text)
+		if utf8.RuneCountInString(text) < MinTermLength || seen[key] {
+			continue
+		}
+		seen[key] = true
+		first, _ := utf8.DecodeRuneInString(text)
+		first = unicode.ToLower(first)
+		m.byFirst[first] = append(m.byFirst[first], Term{Text: text, Target: term.Target})
+	}
+	for _, candidates := range m.byFirst {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return len(candidates[i].Text) > len(candidates[j].Text)
+		})
+	}
+	return m
+}
+
+// FindAll 查找文本中的全部提及
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	prev := rune(-1)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if match, ok := m.matchAt(text, i, r, prev); ok {
+			matches = append(matches, match)
+			prev, _ = utf8.DecodeLastRuneInString(text[:match.End])
+			i = match.End
+			continue
+		}
+		prev = r
+		i += size
+	}
+	return matches
+}
+
+// matchAt 尝试在指定位置匹配最长的术语
+func (m *Matcher) matchAt(text string, start int, r, prev rune) (Match, bool) {
+	for _, term := range m.byFirst[unicode.ToLower(r)] {
+		end := start + len(term.Text)
+		if end > len(text) || !strings.EqualFold(text[start:end], term.Text) {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(term.Text)
+		last, _ := utf8.DecodeLastRuneInString(term.Text)
+		if isWordRune(first) && isWordRune(prev) {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(last) && isWordRune(next) {
+			continue
+		}
+		return Match{Target: term.Target, Text: text[start:end], Start: start, End: end}, true
+	}
+	return Match{}, false
+}
+
+// isWordRune 检查是否为需要词边界的西文字母或数字
+func isWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
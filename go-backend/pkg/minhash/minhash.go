@@ -0,0 +1,154 @@
+// Package minhash 基于词元分片（shingling）与MinHash估计文本之间的Jaccard相似度，
+// 并通过局部敏感哈希（LSH）分段快速筛选候选相似对，用于知识点查重。
+package minhash
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"sical-go-backend/pkg/textsearch"
+)
+
+// 签名参数
+const (
+	NumHashes   = 64 // 签名长度
+	ShingleSize = 3  // 每个分片包含的词元数
+	Bands       = 16 // LSH分段数，每段 NumHashes/Bands 个哈希值
+)
+
+// Signature MinHash签名
+type Signature []uint32
+
+// seeds 各哈希函数的种子
+var seeds = func() []uint64 {
+	values := make([]uint64, NumHashes)
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range values {
+		state = splitmix64(state)
+		values[i] = state
+	}
+	return values
+}()
+
+// splitmix64 64位整数混合函数
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// Shingles 将文本切分为词元分片的哈希集合；中日韩文字按二元组计为词元，词元不足时整体作为一个分片
+func Shingles(text string) []uint64 {
+	tokens := textsearch.Tokenize(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+	size := min(ShingleSize, len(tokens))
+	seen := make(map[uint64]bool)
+	var shingles []uint64
+	for i := 0; i+size <= len(tokens); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(tokens[i:i+size], " ")))
+		value := h.Sum64()
+		if !seen[value] {
+			seen[value] = true
+			shingles = append(shingles, value)
+		}
+	}
+	return shingles
+}
+
+// New 计算文本的MinHash签名；文本不含任何词元时返回nil
+func New(text string) Signature {
+	shingles := Shingles(text)
+	if len(shingles) == 0 {
+		return nil
+	}
+	signature := make(Signature, NumHashes)
+	for i, seed := range seeds {
+		minimum := uint32(^uint32(0))
+		for _, shingle := range shingles {
+			if value := uint32(splitmix64(shingle^seed) >> 32); value < minimum {
+				minimum = value
+			}
+		}
+		signature[i] = minimum
+	}
+	return signature
+}
+
+// Similarity 估计两个签名对应文本的Jaccard相似度，任一签名为空时返回0
+func Similarity(a, b Signature) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// BandKeys 获取签名的LSH分段键；任一分段键相同的两个签名为候选相似对
+func (s Signature) BandKeys() []string {
+	if len(s) != NumHashes {
+		return nil
+	}
+	rows := NumHashes / Bands
+	keys := make([]string, 0, Bands)
+	buf := make([]byte, 4*rows)
+	for band := 0; band < Bands; band++ {
+		for i := 0; i < rows; i++ {
+			binary.BigEndian.PutUint32(buf[4*i:], s[band*rows+i])
+		}
+		keys = append(keys, fmt.Sprintf("%d:%s", band, hex.EncodeToString(buf)))
+	}
+	return keys
+}
+
+// Encode 将签名编码为十六进制字符串
+func (s Signature) Encode() string {
+	buf := make([]byte, 4*len(s))
+	for i, value := range s {
+		binary.BigEndian.PutUint32(buf[4*i:], value)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Decode 解码Encode生成的签名，空字符串解码为nil
+func Decode(encoded string) (Signature, error) {
+	buf, err := hex.DecodeString(encoded)
+	if err != nil || len(buf)%4 != 0 {
+		return nil, fmt.Errorf("无效的MinHash签名")
+	}
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	signature := make(Signature, len(buf)/4)
+	for i := range signature {
+		signature[i] = binary.BigEndian.Uint32(buf[4*i:])
+	}
+	return signature, nil
+}
+
+// NormalizeTitle 规范化标题用于精确比较：全角字符转半角、转为小写，并去除空白与标点符号
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range title {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage 本地文件系统存储，下载由应用通过签名地址提供
+type LocalStorage struct {
+	root          string
+	publicBaseURL string
+	secret        string
+}
+
+// NewLocalStorage 创建本地文件系统存储
+func NewLocalStorage(root, publicBaseURL, secret string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, errors.New("本地存储目录不能为空")
+	}
+	if secret == "" {
+		return nil, errors.New("下载地址签名密钥不能为空")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &LocalStorage{
+		root:          root,
+		publicBaseURL: strings.TrimSuffix(publicBaseURL, "/"),
+		secret:        secret,
+	}, nil
+}
+
+// Put 写入对象，先写临时文件再重命名，避免读取到不完整的文件
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("保存文件失败: %w", err)
+	}
+	return nil
+}
+
+// Open 读取对象
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	return file, nil
+}
+
+// Delete 删除对象
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除文件失败: %w", err)
+	}
+	return nil
+}
+
+// SignedURL 生成应用下载地址，附带过期时间与签名
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", signKey(s.secret, key, expiresAt))
+	return s.publicBaseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+// VerifySignedURL 校验签名与有效期
+func (s *LocalStorage) VerifySignedURL(key string, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(signKey(s.secret, key, expires)), []byte(signature))
+}
+
+// path 将对象键转换为文件路径，拒绝越出根目录的键
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned[1:] != key {
+		return "", fmt.Errorf("无效的对象键: %s", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
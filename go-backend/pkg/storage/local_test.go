@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageVerifySignedURL(t *testing.T) {
+	const key = "attachments/2024/03/a.png"
+	store, err := NewLocalStorage(t.TempDir(), "/api/v1/files/", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	signed, err := store.SignedURL(context.Background(), key, time.Hour)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("SignedURL() = %q, not a valid URL: %v", signed, err)
+	}
+	if want := "/api/v1/files/" + key; parsed.Path != want {
+		t.Fatalf("SignedURL() path = %q, want %q", parsed.Path, want)
+	}
+	expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	if err != nil {
+		t.Fatalf("SignedURL() expires = %q: %v", parsed.Query().Get("expires"), err)
+	}
+	signature := parsed.Query().Get("signature")
+
+	past := time.Now().Add(-time.Minute).Unix()
+	tests := []struct {
+		name      string
+		key       string
+		expires   int64
+		signature string
+		want      bool
+	}{
+		{name: "有效签名", key: key, expires: expires, signature: signature, want: true},
+		{name: "签名被篡改", key: key, expires: expires, signature: strings.Repeat("0", len(signature))},
+		{name: "签名为空", key: key, expires: expires, signature: ""},
+		{name: "签名大小写不同", key: key, expires: expires, signature: strings.ToUpper(signature)},
+		{name: "对象键不同", key: "attachments/2024/03/b.png", expires: expires, signature: signature},
+		{name: "延长有效期", key: key, expires: expires + 3600, signature: signature},
+		{name: "已过期", key: key, expires: past, signature: signKey("secret", key, past)},
+		{name: "其他密钥签名", key: key, expires: expires, signature: signKey("other", key, expires)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.VerifySignedURL(tt.key, tt.expires, tt.signature); got != tt.want {
+				t.Fatalf("VerifySignedURL(%q, %d, %q) = %v, want %v", tt.key, tt.expires, tt.signature, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStorageSignedURLRejectsInvalidKeys(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir(), "/api/v1/files", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "普通键", key: "attachments/a.png"},
+		{name: "空键", key: "", wantErr: true},
+		{name: "越出根目录", key: "../secret.txt", wantErr: true},
+		{name: "中间包含上级目录", key: "attachments/../../secret.txt", wantErr: true},
+		{name: "绝对路径", key: "/etc/passwd", wantErr: true},
+		{name: "重复分隔符", key: "attachments//a.png", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.SignedURL(context.Background(), tt.key, time.Hour)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignedURL(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
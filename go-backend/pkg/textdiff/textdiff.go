@@ -0,0 +1,213 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op 差异操作类型
+type Op int
+
+// 差异操作
+const (
+	OpEqual Op = iota
+	OpInsert
+	OpDelete
+)
+
+// maxLCSCells LCS矩阵规模上限，超出时退化为整段替换
+const maxLCSCells = 4_000_000
+
+// Line 差异行
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines 按行比较两段文本
+func Lines(a, b string) []Line {
+	return diff(splitLines(a), splitLines(b))
+}
+
+// Unified 生成统一格式（unified diff）的差异文本，context为上下文行数；无差异时返回空字符串
+func Unified(a, b string, fromLabel, toLabel string, context int) string {
+	lines := Lines(a, b)
+
+	changed := false
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	// 计算每行在原文与新文中的行号
+	type numbered struct {
+		Line
+		a, b int
+	}
+	all := make([]numbered, len(lines))
+	ai, bi := 1, 1
+	for i, line := range lines {
+		all[i] = numbered{Line: line, a: ai, b: bi}
+		switch line.Op {
+		case OpEqual:
+			ai++
+			bi++
+		case OpDelete:
+			ai++
+		case OpInsert:
+			bi++
+		}
+	}
+
+	for i := 0; i < len(all); {
+		if all[i].Op == OpEqual {
+			i++
+			continue
+		}
+
+		// 向前扩展上下文，并合并相距不超过2*context的变更
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(all) {
+			if all[end].Op != OpEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(all) && all[next].Op == OpEqual {
+				next++
+			}
+			if next < len(all) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			end += context
+			if end > len(all) {
+				end = len(all)
+			}
+			break
+		}
+
+		aCount, bCount := 0, 0
+		for _, line := range all[start:end] {
+			if line.Op != OpInsert {
+				aCount++
+			}
+			if line.Op != OpDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", all[start].a, aCount, all[start].b, bCount)
+		for _, line := range all[start:end] {
+			switch line.Op {
+			case OpEqual:
+				out.WriteString(" ")
+			case OpDelete:
+				out.WriteString("-")
+			case OpInsert:
+				out.WriteString("+")
+			}
+			out.WriteString(line.Text)
+			out.WriteString("\n")
+		}
+		i = end
+	}
+
+	return out.String()
+}
+
+// splitLines 拆分文本行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diff 基于最长公共子序列计算行差异
+func diff(a, b []string) []Line {
+	// 去除公共前缀与后缀，缩小计算规模
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []Line
+	for _, text := range a[:prefix] {
+		lines = append(lines, Line{Op: OpEqual, Text: text})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Op: OpEqual, Text: text})
+	}
+	return lines
+}
+
+// diffMiddle 计算去除公共前后缀后的差异
+func diffMiddle(a, b []string) []Line {
+	var lines []Line
+	if (len(a)+1)*(len(b)+1) > maxLCSCells {
+		for _, text := range a {
+			lines = append(lines, Line{Op: OpDelete, Text: text})
+		}
+		for _, text := range b {
+			lines = append(lines, Line{Op: OpInsert, Text: text})
+		}
+		return lines
+	}
+
+	// lcs[i][j] 为a[i:]与b[j:]的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: OpInsert, Text: b[j]})
+	}
+	return lines
+}
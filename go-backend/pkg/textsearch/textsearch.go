@@ -0,0 +1,242 @@
+package textsearch
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// 高亮标记
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
+
+// run 连续的同类字符片段
+type run struct {
+	text []rune
+	cjk  bool
+}
+
+// isCJK 检查是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// splitRuns 将文本按字符类别拆分为CJK片段与字母数字片段，其余字符作为分隔
+func splitRuns(text string) []run {
+	var runs []run
+	var current []rune
+	currentCJK := false
+
+	flush := func() {
+		if len(current) > 0 {
+			runs = append(runs, run{text: current, cjk: currentCJK})
+			current = nil
+		}
+	}
+
+	for _, r := range text {
+		r = unicode.ToLower(r)
+		switch {
+		case isCJK(r):
+			if !currentCJK {
+				flush()
+			}
+			currentCJK = true
+			current = append(current, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if currentCJK {
+				flush()
+			}
+			currentCJK = false
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return runs
+}
+
+// bigrams 将CJK片段切分为重叠二元组，单字片段保留单字
+func bigrams(text []rune) []string {
+	if len(text) == 1 {
+		return []string{string(text)}
+	}
+	tokens := make([]string, 0, len(text)-1)
+	for i := 0; i+1 < len(text); i++ {
+		tokens = append(tokens, string(text[i:i+2]))
+	}
+	return tokens
+}
+
+// Tokenize 分词：中日韩文字按二元组切分，其他文字按字母数字连续片段切分
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, r := range splitRuns(text) {
+		if r.cjk {
+			tokens = append(tokens, bigrams(r.text)...)
+		} else {
+			tokens = append(tokens, string(r.text))
+		}
+	}
+	return tokens
+}
+
+// ToDocument 将文本转换为以空格分隔的词元序列，供to_tsvector('simple', ...)使用
+func ToDocument(text string) string {
+	return strings.Join(Tokenize(text), " ")
+}
+
+// ToTSQuery 将用户查询转换为to_tsquery('simple', ...)表达式；
+// 同一CJK片段内的二元组要求相邻（<->），不同片段之间为且（&），单字按前缀匹配
+func ToTSQuery(query string) string {
+	var parts []string
+	for _, r := range splitRuns(query) {
+		if !r.cjk {
+			parts = append(parts, string(r.text))
+			continue
+		}
+		if len(r.text) == 1 {
+			parts = append(parts, string(r.text)+":*")
+			continue
+		}
+		grams := bigrams(r.text)
+		if len(grams) == 1 {
+			parts = append(parts, grams[0])
+		} else {
+			parts = append(parts, "("+strings.Join(grams, " <-> ")+")")
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// ExpandTSQuery 与ToTSQuery相同，但命中同义词表的查询（整体或以空白分隔的单词）
+// 扩展为原词与各同义词之间的或（|）；synonyms的键为NormalizeTerm规范化后的词
+func ExpandTSQuery(query string, synonyms map[string][]string) string {
+	if alternatives := synonyms[NormalizeTerm(query)]; len(alternatives) > 0 {
+		return anyOf(query, alternatives)
+	}
+	var parts []string
+	for _, word := range strings.Fields(query) {
+		part := ToTSQuery(word)
+		if alternatives := synonyms[NormalizeTerm(word)]; len(alternatives) > 0 {
+			part = anyOf(word, alternatives)
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// anyOf 将原词与同义词组合为或表达式
+func anyOf(term string, alternatives []string) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, alternative := range append([]string{term}, alternatives...) {
+		q := ToTSQuery(alternative)
+		if q == "" || seen[q] {
+			continue
+		}
+		seen[q] = true
+		parts = append(parts, "("+q+")")
+	}
+	return "(" + strings.Join(parts, " | ") + ")"
+}
+
+// NormalizeTerm 规范化术语：转为小写并合并空白
+func NormalizeTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
+
+// Terms 获取查询中用于高亮的词（CJK片段与字母数字片段）
+func Terms(query string) []string {
+	var terms []string
+	for _, r := range splitRuns(query) {
+		terms = append(terms, string(r.text))
+	}
+	return terms
+}
+
+// Highlight 截取文本中首个命中词附近的片段并以<mark>标记全部命中，
+// 文本会先进行HTML转义；无命中时返回文本开头
+func Highlight(text, query string, maxRunes int) string {
+	terms := Terms(query)
+	original := []rune(text)
+	lower := make([]rune, len(original))
+	for i, r := range original {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 标记命中位置
+	marked := make([]bool, len(original))
+	first := -1
+	for _, term := range terms {
+		needle := []rune(term)
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(needle)], needle) {
+				for j := i; j < i+len(needle); j++ {
+					marked[j] = true
+				}
+				if first == -1 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+
+	// 计算截取窗口
+	start, end := 0, len(original)
+	if maxRunes > 0 && len(original) > maxRunes {
+		if first > maxRunes/4 {
+			start = first - maxRunes/4
+		}
+		end = start + maxRunes
+		if end > len(original) {
+			end = len(original)
+			start = end - maxRunes
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	inMark := false
+	for i := start; i < end; i++ {
+		if marked[i] && !inMark {
+			b.WriteString(HighlightStart)
+			inMark = true
+		} else if !marked[i] && inMark {
+			b.WriteString(HighlightEnd)
+			inMark = false
+		}
+		b.WriteString(html.EscapeString(string(original[i])))
+	}
+	if inMark {
+		b.WriteString(HighlightEnd)
+	}
+	if end < len(original) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// runesEqual 比较两个rune切片是否相等
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+// Package vector 基于哈希n-gram特征与TF-IDF加权的进程内文本向量化，
+// 用于计算知识点之间及知识点与查询文本之间的语义相似度，不依赖外部服务。
+package vector
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"sical-go-backend/pkg/textsearch"
+)
+
+// Dimensions 特征哈希空间大小
+const Dimensions = 1 << 18
+
+// Vector 稀疏向量（特征哈希 -> 权重），经L2归一化
+type Vector map[uint32]float64
+
+// Match 相似度检索结果
+type Match struct {
+	ID    string
+	Score float64
+}
+
+// hashFeature 将特征映射到哈希空间
+func hashFeature(feature string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(feature))
+	return h.Sum32() % Dimensions
+}
+
+// termFrequencies 统计文本的特征词频：词元一元组与相邻词元二元组
+func termFrequencies(text string) map[uint32]float64 {
+	tokens := textsearch.Tokenize(text)
+	counts := make(map[uint32]float64, len(tokens)*2)
+	for i, token := range tokens {
+		counts[hashFeature(token)]++
+		if i+1 < len(tokens) {
+			counts[hashFeature(token+" "+tokens[i+1])]++
+		}
+	}
+	return counts
+}
+
+// Index 文档向量索引；构建后只读，可并发使用，语料变化时应重新构建
+type Index struct {
+	idf     map[uint32]float64
+	docs    map[string]Vector
+	ids     []string
+	defIDF  float64 // 语料中未出现特征的IDF
+	corpusN int
+}
+
+// Build 根据文档（ID -> 文本）构建索引
+func Build(docs map[string]string) *Index {
+	frequencies := make(map[string]map[uint32]float64, len(docs))
+	df := make(map[uint32]int)
+	for id, text := range docs {
+		tf := termFrequencies(text)
+		frequencies[id] = tf
+		for feature := range tf {
+			df[feature]++
+		}
+	}
+
+	n := float64(len(docs))
+	index := &Index{
+		idf:     make(map[uint32]float64, len(df)),
+		docs:    make(map[string]Vector, len(docs)),
+		ids:     make([]string, 0, len(docs)),
+		defIDF:  math.Log(n+1) + 1,
+		corpusN: len(docs),
+	}
+	for feature, count := range df {
+		index.idf[feature] = math.Log((n+1)/(float64(count)+1)) + 1
+	}
+	for id, tf := range frequencies {
+		index.docs[id] = index.weigh(tf)
+		index.ids = append(index.ids, id)
+	}
+	sort.Strings(index.ids)
+	return index
+}
+
+// weigh 以亚线性词频与IDF加权并归一化
+func (x *Index) weigh(tf map[uint32]float64) Vector {
+	vector := make(Vector, len(tf))
+	var norm float64
+	for feature, count := range tf {
+		idf, ok := x.idf[feature]
+		if !ok {
+			idf = x.defIDF
+		}
+		weight := (1 + math.Log(count)) * idf
+		vector[feature] = weight
+		norm += weight * weight
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for feature := range vector {
+		vector[feature] /= norm
+	}
+	return vector
+}
+
+// Vectorize 使用索引的IDF将任意文本转换为向量
+func (x *Index) Vectorize(text string) Vector {
+	return x.weigh(termFrequencies(text))
+}
+
+// Vector 获取已索引文档的向量
+func (x *Index) Vector(id string) (Vector, bool) {
+	vector, ok := x.docs[id]
+	return vector, ok
+}
+
+// Len 获取已索引文档数
+func (x *Index) Len() int {
+	return x.corpusN
+}
+
+// Nearest 获取与向量最相似的k个文档，按相似度降序；忽略exclude中的文档与相似度不超过minScore的文档
+func (x *Index) Nearest(vector Vector, k int, minScore float64, exclude ...string) []Match {
+	skip := make(map[string]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+	var matches []Match
+	for _, id := range x.ids {
+		if skip[id] {
+			continue
+		}
+		if score := Cosine(vector, x.docs[id]); score > minScore {
+			matches = append(matches, Match{ID: id, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// Cosine 计算两个归一化向量的余弦相似度
+func Cosine(a, b Vector) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for feature, weight := range a {
+		dot += weight * b[feature]
+	}
+	return dot
+}